@@ -3,6 +3,10 @@
  *
  * Atomic operations provide low-level synchronization mechanisms that are
  * more efficient than mutexes for simple operations like incrementing counters.
+ *
+ * This example uses the original function-style API (AddInt64, CompareAndSwapInt64).
+ * Since Go 1.19, prefer the typed atomics such as atomic.Int64 shown in
+ * 33_typed_atomics.go for new code.
  */
 
 package advanced
@@ -20,7 +24,7 @@ import (
  * thread-safe access to shared variables without the overhead of mutexes.
  */
 func AtomicOperationsDemo() {
-	fmt.Println("Atomic Operations")
+	fmt.Fprintln(stdout, "Atomic Operations")
 
 	// Create an atomic counter
 	var atomicCounter int64
@@ -45,20 +49,20 @@ func AtomicOperationsDemo() {
 	// Wait for all goroutines to finish
 	wg.Wait()
 
-	fmt.Printf("Final atomic counter value: %d\n", atomicCounter)
+	fmt.Fprintf(stdout, "Final atomic counter value: %d\n", atomicCounter)
 
 	// Compare-and-swap atomic operation
-	fmt.Println("\nCompare-and-swap atomic operation:")
+	fmt.Fprintln(stdout, "\nCompare-and-swap atomic operation:")
 
 	var value int64 = 100
 
 	// Try to swap with wrong expected value
 	swapped := atomic.CompareAndSwapInt64(&value, 200, 300)
-	fmt.Printf("Swap with wrong expected value: swapped=%v, value=%d\n", swapped, value)
+	fmt.Fprintf(stdout, "Swap with wrong expected value: swapped=%v, value=%d\n", swapped, value)
 
 	// Try to swap with correct expected value
 	swapped = atomic.CompareAndSwapInt64(&value, 100, 300)
-	fmt.Printf("Swap with correct expected value: swapped=%v, value=%d\n", swapped, value)
+	fmt.Fprintf(stdout, "Swap with correct expected value: swapped=%v, value=%d\n", swapped, value)
 
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
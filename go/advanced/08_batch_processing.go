@@ -17,16 +17,18 @@ import (
  *
  * This pattern collects individual items into batches before processing them,
  * which can be more efficient for operations with high setup/teardown costs.
+ * Batches are only flushed when full; 42_batch_size_or_timeout.go also
+ * flushes on a timeout to bound latency.
  */
 func BatchProcessingDemo() {
-	fmt.Println("Batch Processing with Buffered Channels")
+	fmt.Fprintln(stdout, "Batch Processing with Buffered Channels")
 
 	// Create a source of items
 	source := make(chan int)
 	go func() {
 		for i := 1; i <= 20; i++ {
 			source <- i
-			time.Sleep(10 * time.Millisecond)
+			clk.Sleep(10 * time.Millisecond)
 		}
 		close(source)
 	}()
@@ -59,9 +61,9 @@ func BatchProcessingDemo() {
 
 	// Process the batches
 	for batch := range batches {
-		fmt.Printf("Processing batch: %v\n", batch)
-		time.Sleep(50 * time.Millisecond) // Simulate batch processing
+		fmt.Fprintf(stdout, "Processing batch: %v\n", batch)
+		clk.Sleep(50 * time.Millisecond) // Simulate batch processing
 	}
 
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
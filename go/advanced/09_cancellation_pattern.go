@@ -20,7 +20,7 @@ import (
  * standard library, but implemented with a simple done channel.
  */
 func CancellationPatternDemo() {
-	fmt.Println("Context-like Cancellation Pattern")
+	fmt.Fprintln(stdout, "Context-like Cancellation Pattern")
 
 	// Create a done channel for cancellation
 	done := make(chan struct{})
@@ -35,10 +35,10 @@ func CancellationPatternDemo() {
 			for i := 0; ; i++ {
 				select {
 				case <-done:
-					fmt.Println("Generator cancelled")
+					fmt.Fprintln(stdout, "Generator cancelled")
 					return
 				case out <- i:
-					time.Sleep(100 * time.Millisecond)
+					clk.Sleep(100 * time.Millisecond)
 				}
 			}
 		}()
@@ -51,14 +51,17 @@ func CancellationPatternDemo() {
 
 	// Receive some values
 	for i := 0; i < 5; i++ {
-		fmt.Println("Received:", <-ch)
+		fmt.Fprintln(stdout, "Received:", <-ch)
 	}
 
 	// Cancel the generator
-	fmt.Println("Cancelling generator...")
+	fmt.Fprintln(stdout, "Cancelling generator...")
 	close(done)
 
+	steps.Checkpoint("Closing done is a broadcast: every goroutine selecting on it is released at once. " +
+		"The generator sees it on its next select and returns instead of blocking on a send forever.")
+
 	// Give the generator time to exit
-	time.Sleep(200 * time.Millisecond)
-	fmt.Println()
+	clk.Sleep(200 * time.Millisecond)
+	fmt.Fprintln(stdout)
 }
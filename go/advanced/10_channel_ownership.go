@@ -9,6 +9,8 @@ package advanced
 
 import (
 	"fmt"
+
+	"threads/topology"
 )
 
 /**
@@ -19,7 +21,7 @@ import (
  * Receivers only read from the channel and check when it's closed.
  */
 func ChannelOwnershipDemo() {
-	fmt.Println("Channel Ownership Pattern")
+	fmt.Fprintln(stdout, "Channel Ownership Pattern")
 
 	// Generator function that owns and returns a channel
 	generator := func(nums ...int) <-chan int {
@@ -41,11 +43,24 @@ func ChannelOwnershipDemo() {
 
 	// Use the generator
 	ch := generator(1, 2, 3, 4, 5)
+	steps.Checkpoint("generator created the channel, started the only goroutine that sends on it and returned it " +
+		"as receive-only. The caller cannot send or close; only the owner can.")
 
 	// Receive values until the channel is closed
 	for n := range ch {
-		fmt.Println("Received:", n)
+		fmt.Fprintln(stdout, "Received:", n)
 	}
+	steps.Checkpoint("The owner closed the channel after its last value, which ended the range loop.")
+
+	fmt.Fprintln(stdout)
+}
 
-	fmt.Println()
+// ChannelOwnershipTopology describes the goroutines and channels of the demo
+func ChannelOwnershipTopology() *topology.Graph {
+	return topology.New("Channel Ownership").
+		Goroutine("gen", "generator (owns and closes out)").
+		Channel("out", "out").
+		Goroutine("main", "consumer").
+		Flow("gen", "out", "send, then close").
+		Flow("out", "main", "range")
 }
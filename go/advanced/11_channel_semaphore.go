@@ -3,13 +3,20 @@
  *
  * A semaphore is a synchronization primitive that controls access to a shared resource.
  * In Go, buffered channels can be used as semaphores to limit concurrency.
+ * syncx.Semaphore wraps the same channel in named methods and adds an
+ * Acquire that gives up when its context is done.
  */
 
 package advanced
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
+
+	"threads/clock"
+	"threads/syncx"
 )
 
 /**
@@ -17,23 +24,30 @@ import (
  *
  * This pattern uses a buffered channel to limit the number of goroutines
  * that can access a resource concurrently, similar to a counting semaphore.
+ * Then syncx.Semaphore runs six workers through three slots with a deadline
+ * on waiting, and a monitor that only uses TryAcquire.
  */
 func ChannelSemaphoreDemo() {
-	fmt.Println("Buffered Channel as a Semaphore")
+	fmt.Fprintln(stdout, "Buffered Channel as a Semaphore")
+
+	// 1. A buffered channel
+	fmt.Fprintln(stdout, "\n1. A buffered channel with 3 slots:")
 
 	// Create a buffered channel as a semaphore with 3 slots
 	semaphore := make(chan struct{}, 3)
 
 	// Function that uses the semaphore to limit concurrency
 	worker := func(id int) {
-		fmt.Printf("Worker %d waiting for semaphore\n", id)
+		log := logger.With("worker", id)
+
+		log.Info("waiting for semaphore")
 		semaphore <- struct{}{} // Acquire semaphore
 
-		fmt.Printf("Worker %d acquired semaphore\n", id)
-		time.Sleep(100 * time.Millisecond) // Simulate work
+		log.Info("acquired semaphore", "in_use", len(semaphore))
+		clk.Sleep(100 * time.Millisecond) // Simulate work
 
 		<-semaphore // Release semaphore
-		fmt.Printf("Worker %d released semaphore\n", id)
+		log.Info("released semaphore")
 	}
 
 	// Start 10 workers (but only 3 can run at a time)
@@ -42,6 +56,44 @@ func ChannelSemaphoreDemo() {
 	}
 
 	// Wait for all workers to finish
-	time.Sleep(500 * time.Millisecond)
-	fmt.Println()
+	clk.Sleep(500 * time.Millisecond)
+
+	// 2. syncx.Semaphore
+	fmt.Fprintln(stdout, "\n2. syncx.Semaphore, 6 workers holding a slot for 200ms, waiting at most 150ms:")
+	sem := syncx.NewSemaphore(3)
+
+	// The deadline runs on the examples' clock, like the workers' sleeps
+	ctx, cancel := clock.WithTimeout(context.Background(), clk, 150*time.Millisecond)
+	defer cancel()
+
+	outcomes := make([]string, 6)
+	var wg sync.WaitGroup
+	for i := range outcomes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sem.Acquire(ctx); err != nil {
+				outcomes[i] = "gave up: " + err.Error()
+				return
+			}
+			defer sem.Release()
+			outcomes[i] = "got a slot"
+			clk.Sleep(200 * time.Millisecond)
+		}()
+	}
+
+	// A monitor that must never queue behind the workers
+	clk.Sleep(50 * time.Millisecond)
+	if sem.TryAcquire() {
+		sem.Release()
+		fmt.Fprintln(stdout, "Monitor: TryAcquire got a slot")
+	} else {
+		fmt.Fprintf(stdout, "Monitor: TryAcquire returned false at once (%d of %d held)\n", sem.Held(), sem.Cap())
+	}
+
+	wg.Wait()
+	for i, outcome := range outcomes {
+		fmt.Fprintf(stdout, "Worker %d: %s\n", i+1, outcome)
+	}
+	fmt.Fprintln(stdout)
 }
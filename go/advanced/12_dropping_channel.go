@@ -19,7 +19,7 @@ import (
  * occasional message loss is acceptable.
  */
 func DroppingChannelDemo() {
-	fmt.Println("Dropping Channel Pattern (Non-blocking Sends)")
+	fmt.Fprintln(stdout, "Dropping Channel Pattern (Non-blocking Sends)")
 
 	// Create a buffered channel with limited capacity
 	messages := make(chan string, 3)
@@ -38,17 +38,17 @@ func DroppingChannelDemo() {
 	for i := 1; i <= 5; i++ {
 		msg := fmt.Sprintf("Message %d", i)
 		if trySend(msg) {
-			fmt.Printf("Sent: %s\n", msg)
+			fmt.Fprintf(stdout, "Sent: %s\n", msg)
 		} else {
-			fmt.Printf("Dropped: %s (buffer full)\n", msg)
+			fmt.Fprintf(stdout, "Dropped: %s (buffer full)\n", msg)
 		}
 	}
 
 	// Receive all messages from the channel
 	close(messages)
 	for msg := range messages {
-		fmt.Printf("Received: %s\n", msg)
+		fmt.Fprintf(stdout, "Received: %s\n", msg)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
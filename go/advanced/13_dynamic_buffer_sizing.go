@@ -10,6 +10,8 @@ package advanced
 import (
 	"fmt"
 	"time"
+
+	"threads/stats"
 )
 
 /**
@@ -17,21 +19,22 @@ import (
  *
  * This pattern demonstrates how different buffer sizes affect performance
  * when dealing with producers and consumers operating at different speeds.
+ * For proper measurements across speed ratios, run `go run . bench buffers`.
  */
 func DynamicBufferSizingDemo() {
-	fmt.Println("Dynamic Buffer Sizing")
+	fmt.Fprintln(stdout, "Dynamic Buffer Sizing")
 
 	// Function to measure channel send blocking time with different buffer sizes
 	measureBufferPerformance := func(bufferSize int, operations int) time.Duration {
 		ch := make(chan int, bufferSize)
 
-		start := time.Now()
+		start := clk.Now()
 
 		// Start a consumer that's slower than the producer
 		go func() {
 			for i := 0; i < operations; i++ {
 				<-ch
-				time.Sleep(1 * time.Millisecond) // Slow consumer
+				clk.Sleep(1 * time.Millisecond) // Slow consumer
 			}
 		}()
 
@@ -40,7 +43,7 @@ func DynamicBufferSizingDemo() {
 			ch <- i
 		}
 
-		return time.Since(start)
+		return clk.Since(start)
 	}
 
 	// Test different buffer sizes
@@ -49,8 +52,9 @@ func DynamicBufferSizingDemo() {
 
 	for _, size := range bufferSizes {
 		duration := measureBufferPerformance(size, operations)
-		fmt.Printf("Buffer size %d took %v for %d operations\n", size, duration, operations)
+		stats.Record(fmt.Sprintf("buffer size %d", size), duration)
+		fmt.Fprintf(stdout, "Buffer size %d took %v for %d operations\n", size, duration, operations)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
@@ -2,15 +2,23 @@
  * This file demonstrates Dynamic Task Creation with WaitGroup in Go.
  *
  * This pattern shows how to handle dynamically created tasks with WaitGroup,
- * where the number of goroutines is not known in advance.
+ * where the number of goroutines is not known in advance. Each parent calls
+ * wg.Add for its children before starting them; see the quiescence example
+ * for what goes wrong when goroutines count themselves. syncx.TaskTracker
+ * makes the rule impossible to break: its Go method counts a task before
+ * starting it, and its Done channel can be used in a select.
  */
 
 package advanced
 
 import (
 	"fmt"
-	"math/rand"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/rng"
+	"threads/syncx"
 )
 
 /**
@@ -18,10 +26,13 @@ import (
  *
  * This pattern demonstrates how to use WaitGroup with recursively spawned
  * goroutines, ensuring all dynamically created tasks complete before continuing.
+ * The same tree is then run with a syncx.TaskTracker.
  */
 func DynamicWaitGroupDemo() {
-	fmt.Println("Dynamic Task Creation with WaitGroup")
+	fmt.Fprintln(stdout, "Dynamic Task Creation with WaitGroup")
 
+	// 1. WaitGroup
+	fmt.Fprintln(stdout, "\n1. sync.WaitGroup, with each parent adding its children:")
 	var wg sync.WaitGroup
 
 	// Declare the parent worker function
@@ -31,17 +42,18 @@ func DynamicWaitGroupDemo() {
 	parentWorker = func(id int, depth int) {
 		defer wg.Done()
 
-		fmt.Printf("Parent worker %d (depth %d) starting\n", id, depth)
+		log := logger.With("worker", id, "depth", depth)
+		log.Info("parent worker starting")
 
 		// Base case for recursion
 		if depth <= 0 {
-			fmt.Printf("Parent worker %d reached max depth\n", id)
+			log.Info("reached max depth")
 			return
 		}
 
 		// Spawn child workers
-		numChildren := rand.Intn(3) + 1 // 1 to 3 children
-		fmt.Printf("Parent worker %d spawning %d children\n", id, numChildren)
+		numChildren := rng.IntN(3) + 1 // 1 to 3 children
+		log.Info("spawning children", "children", numChildren)
 
 		for i := 0; i < numChildren; i++ {
 			childID := id*10 + i
@@ -49,7 +61,7 @@ func DynamicWaitGroupDemo() {
 			go parentWorker(childID, depth-1)
 		}
 
-		fmt.Printf("Parent worker %d done\n", id)
+		log.Info("parent worker done")
 	}
 
 	// Start the initial parent workers
@@ -60,5 +72,33 @@ func DynamicWaitGroupDemo() {
 
 	// Wait for all workers (parents and children) to finish
 	wg.Wait()
-	fmt.Println()
+
+	// 2. TaskTracker
+	fmt.Fprintln(stdout, "\n2. syncx.TaskTracker, where Go counts each task before starting it:")
+	tracker := syncx.NewTaskTracker()
+	var started, finished atomic.Int32
+	var task func(depth int)
+	task = func(depth int) {
+		defer finished.Add(1)
+		started.Add(1)
+		clk.Sleep(time.Millisecond)
+		if depth > 0 {
+			for range rng.IntN(3) + 1 {
+				tracker.Go(func() { task(depth - 1) })
+			}
+		}
+	}
+	for range 2 {
+		tracker.Go(func() { task(3) })
+	}
+
+	// Done is a channel, so the wait can be bounded
+	select {
+	case <-tracker.Done():
+		fmt.Fprintf(stdout, "All %d of %d tasks finished\n", finished.Load(), started.Load())
+	case <-clk.After(5 * time.Second):
+		fmt.Fprintf(stdout, "Gave up with %d tasks still running\n", tracker.Count())
+	}
+
+	fmt.Fprintln(stdout)
 }
@@ -4,6 +4,8 @@
  * Fan-out: Multiple goroutines read from the same channel, distributing work.
  * Fan-in: Multiple goroutines write to the same channel, combining results.
  * This pattern is useful for parallel processing of data.
+ * When the workers only compute one value per input, parallel.Group does the
+ * same job without hand-written channels and keeps the results in input order.
  */
 
 package advanced
@@ -11,6 +13,10 @@ package advanced
 import (
 	"fmt"
 	"time"
+
+	"threads/chanx"
+	"threads/parallel"
+	"threads/topology"
 )
 
 /**
@@ -19,9 +25,14 @@ import (
  * This pattern allows for parallel processing of data:
  * - Fan-out: Distribute work across multiple goroutines
  * - Fan-in: Collect and combine results from multiple goroutines
+ * The same squares are then computed with a parallel.Group limited to three
+ * goroutines.
  */
 func FanOutFanInDemo() {
-	fmt.Println("Fan-out, Fan-in Pattern")
+	fmt.Fprintln(stdout, "Fan-out, Fan-in Pattern")
+
+	// 1. Channels
+	fmt.Fprintln(stdout, "\n1. Workers and forwarders connected by channels:")
 
 	// Generator function
 	gen := func(nums ...int) <-chan int {
@@ -30,20 +41,21 @@ func FanOutFanInDemo() {
 			defer close(out)
 			for _, n := range nums {
 				out <- n
-				time.Sleep(100 * time.Millisecond) // Simulate slow generation
+				clk.Sleep(100 * time.Millisecond) // Simulate slow generation
 			}
 		}()
 		return out
 	}
 
 	// Worker function that squares its input
-	square := func(in <-chan int) <-chan int {
+	square := func(id int, in <-chan int) <-chan int {
 		out := make(chan int)
 		go func() {
 			defer close(out)
+			log := logger.With("worker", id)
 			for n := range in {
-				fmt.Printf("Worker squaring %d\n", n)
-				time.Sleep(200 * time.Millisecond) // Simulate processing time
+				log.Info("squaring", "n", n)
+				clk.Sleep(200 * time.Millisecond) // Simulate processing time
 				out <- n * n
 			}
 		}()
@@ -54,19 +66,20 @@ func FanOutFanInDemo() {
 	fanIn := func(channels ...<-chan int) <-chan int {
 		out := make(chan int)
 
+		// The forwarders share out, so none of them may close it; the
+		// coordinator closes it once every forwarder is done
+		closer := chanx.NewCloseCoordinator(out, len(channels))
+
 		// For each input channel, start a goroutine that forwards values
 		for _, ch := range channels {
 			go func(c <-chan int) {
+				defer closer.Done()
 				for n := range c {
 					out <- n
 				}
 			}(ch)
 		}
 
-		// We need a way to close the output channel when all input channels are done
-		// This is a simplified version that doesn't close the output channel
-		// In a real application; you would use a WaitGroup to track when to close
-
 		return out
 	}
 
@@ -74,19 +87,58 @@ func FanOutFanInDemo() {
 	input := gen(1, 2, 3, 4, 5)
 
 	// Distribute work to 3 workers (fan-out)
-	c1 := square(input)
-	c2 := square(input)
-	c3 := square(input)
+	c1 := square(1, input)
+	c2 := square(2, input)
+	c3 := square(3, input)
+	steps.Checkpoint("Three square workers now receive from the same input channel (fan-out). " +
+		"Each value goes to exactly one of them, whichever is free.")
 
 	// Combine results (fan-in)
-	for n := range fanIn(c1, c2, c3) {
-		fmt.Println("Result:", n)
+	merged := fanIn(c1, c2, c3)
+	steps.Checkpoint("fanIn started one forwarder per worker channel, all sending into one merged channel (fan-in). " +
+		"Results arrive in completion order, not input order.")
+	for n := range merged {
+		fmt.Fprintln(stdout, "Result:", n)
+	}
+	fmt.Fprintln(stdout, "merged was closed after the last forwarder finished")
 
-		// Break after receiving 5 results
-		if n > 20 {
-			break
-		}
+	// 2. parallel.Group
+	fmt.Fprintln(stdout, "\n2. The same work with parallel.Group and a limit of 3:")
+	var g parallel.Group[int]
+	g.SetLimit(3) // Three workers, as above
+	for _, n := range []int{1, 2, 3, 4, 5} {
+		g.Go(func() (int, error) {
+			clk.Sleep(200 * time.Millisecond) // Simulate processing time
+			return n * n, nil
+		})
 	}
+	squares, err := g.Wait()
+	fmt.Fprintf(stdout, "Results in input order: %v (error: %v)\n", squares, err)
+	fmt.Fprintln(stdout, "No channels to create, close or drain: Go fans out, Wait fans in.")
+
+	fmt.Fprintln(stdout)
+}
 
-	fmt.Println()
+// FanOutFanInTopology describes the goroutines and channels of the demo
+func FanOutFanInTopology() *topology.Graph {
+	g := topology.New("Fan-out, Fan-in").
+		Goroutine("gen", "generator").
+		Channel("input", "input").
+		Channel("merged", "merged").
+		Goroutine("main", "consumer").
+		Goroutine("closer", "close coordinator (wg.Wait, then close)").
+		Flow("gen", "input", "").
+		Flow("closer", "merged", "close").
+		Flow("merged", "main", "")
+	for i := 1; i <= 3; i++ {
+		sq, c, fwd := fmt.Sprintf("square%d", i), fmt.Sprintf("c%d", i), fmt.Sprintf("fanin%d", i)
+		g.Goroutine(sq, fmt.Sprintf("square worker %d", i)).
+			Channel(c, fmt.Sprintf("c%d", i)).
+			Goroutine(fwd, fmt.Sprintf("fan-in forwarder %d", i)).
+			Flow("input", sq, "fan-out").
+			Flow(sq, c, "").
+			Flow(c, fwd, "").
+			Flow(fwd, "merged", "fan-in")
+	}
+	return g
 }
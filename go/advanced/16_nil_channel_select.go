@@ -19,7 +19,7 @@ import (
  * allowing you to dynamically disable select cases by setting channels to nil.
  */
 func NilChannelSelectDemo() {
-	fmt.Println("Select with Nil Channel Pattern")
+	fmt.Fprintln(stdout, "Select with Nil Channel Pattern")
 
 	// Create channels
 	var input chan string = make(chan string)
@@ -28,7 +28,7 @@ func NilChannelSelectDemo() {
 	// Start a goroutine that sends values on the input channel
 	go func() {
 		for i := 1; i <= 3; i++ {
-			time.Sleep(100 * time.Millisecond)
+			clk.Sleep(100 * time.Millisecond)
 			input <- fmt.Sprintf("Input %d", i)
 		}
 		close(input)
@@ -60,12 +60,12 @@ func NilChannelSelectDemo() {
 				input = nil
 				// If we have no more input and no pending value, we're done
 				if inputVal == "" {
-					fmt.Println("All processing complete")
+					fmt.Fprintln(stdout, "All processing complete")
 					goto Done
 				}
 			} else {
 				// Got a new input value
-				fmt.Println("Received:", val)
+				fmt.Fprintln(stdout, "Received:", val)
 				inputVal = val
 				// Enable output channel for next iteration
 				outputCh = output
@@ -73,11 +73,11 @@ func NilChannelSelectDemo() {
 
 		case outputCh <- inputVal:
 			// Value sent to output, clear pending value
-			fmt.Println("Sent:", inputVal)
+			fmt.Fprintln(stdout, "Sent:", inputVal)
 			inputVal = ""
 		}
 	}
 
 Done:
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
@@ -10,7 +10,10 @@ package advanced
 
 import (
 	"fmt"
+	"sync"
 	"time"
+
+	"threads/topology"
 )
 
 /**
@@ -20,14 +23,14 @@ import (
  * It's useful for implementing timeouts, cancellation, or taking the fastest result.
  */
 func OrChannelPatternDemo() {
-	fmt.Println("Or-channel Pattern (First Response Wins)")
+	fmt.Fprintln(stdout, "Or-channel Pattern (First Response Wins)")
 
 	// Function that creates a channel that closes after a specified duration
 	sig := func(after time.Duration) <-chan struct{} {
 		c := make(chan struct{})
 		go func() {
 			defer close(c)
-			time.Sleep(after)
+			clk.Sleep(after)
 		}()
 		return c
 	}
@@ -35,13 +38,14 @@ func OrChannelPatternDemo() {
 	// Or function combines multiple channels into one that closes when any input channel closes
 	or := func(channels ...<-chan struct{}) <-chan struct{} {
 		out := make(chan struct{})
+		var once sync.Once // Two inputs may close at the same moment; out must be closed only once
 
 		// Start a goroutine for each input channel
 		for _, c := range channels {
 			go func(ch <-chan struct{}) {
 				select {
 				case <-ch:
-					close(out) // First channel to close triggers output channel to close
+					once.Do(func() { close(out) }) // First channel to close triggers output channel to close
 				case <-out:
 					// Another channel already triggered the close
 				}
@@ -52,12 +56,29 @@ func OrChannelPatternDemo() {
 	}
 
 	// Create some signal channels with different timeouts
-	start := time.Now()
+	start := clk.Now()
 	<-or(
 		sig(100*time.Millisecond),
 		sig(200*time.Millisecond),
 		sig(300*time.Millisecond),
 	)
-	fmt.Printf("Done after %v\n", time.Since(start))
-	fmt.Println()
+	fmt.Fprintf(stdout, "Done after %v\n", clk.Since(start))
+	fmt.Fprintln(stdout)
+}
+
+// OrChannelTopology describes the goroutines and channels of the demo
+func OrChannelTopology() *topology.Graph {
+	g := topology.New("Or-channel").
+		Channel("out", "out (closed by the first signal)").
+		Goroutine("main", "waiter")
+	for i, after := range []string{"100ms", "200ms", "300ms"} {
+		sig, c, watch := fmt.Sprintf("sig%d", i), fmt.Sprintf("c%d", i), fmt.Sprintf("or%d", i)
+		g.Goroutine(sig, "sig("+after+")").
+			Channel(c, "done "+after).
+			Goroutine(watch, fmt.Sprintf("or watcher %d", i)).
+			Flow(sig, c, "close").
+			Flow(c, watch, "").
+			Flow(watch, "out", "close")
+	}
+	return g.Flow("out", "main", "")
 }
@@ -20,7 +20,7 @@ import (
  * It checks higher priority channels first before moving to lower priority ones.
  */
 func PrioritySelectDemo() {
-	fmt.Println("Priority Select Pattern")
+	fmt.Fprintln(stdout, "Priority Select Pattern")
 
 	// Create channels with different priorities
 	highPriority := make(chan string)
@@ -29,7 +29,7 @@ func PrioritySelectDemo() {
 
 	// Send data on all channels
 	go func() {
-		time.Sleep(100 * time.Millisecond)
+		clk.Sleep(100 * time.Millisecond)
 		highPriority <- "High priority message"
 		mediumPriority <- "Medium priority message"
 		lowPriority <- "Low priority message"
@@ -40,7 +40,7 @@ func PrioritySelectDemo() {
 		// First check high priority channel
 		select {
 		case msg := <-highPriority:
-			fmt.Println("High priority:", msg)
+			fmt.Fprintln(stdout, "High priority:", msg)
 			return
 		default:
 			// Continue to next priority level
@@ -49,7 +49,7 @@ func PrioritySelectDemo() {
 		// Then check medium priority channel
 		select {
 		case msg := <-mediumPriority:
-			fmt.Println("Medium priority:", msg)
+			fmt.Fprintln(stdout, "Medium priority:", msg)
 			return
 		default:
 			// Continue to next priority level
@@ -58,41 +58,41 @@ func PrioritySelectDemo() {
 		// Finally check low priority channel
 		select {
 		case msg := <-lowPriority:
-			fmt.Println("Low priority:", msg)
+			fmt.Fprintln(stdout, "Low priority:", msg)
 			return
 		default:
-			fmt.Println("No messages available")
+			fmt.Fprintln(stdout, "No messages available")
 		}
 	}
 
 	// Wait for messages to be sent
-	time.Sleep(200 * time.Millisecond)
+	clk.Sleep(200 * time.Millisecond)
 
 	// Run the priority select
 	prioritySelect()
 
 	// Drain remaining channels
-	fmt.Println("Draining remaining channels:")
+	fmt.Fprintln(stdout, "Draining remaining channels:")
 	select {
 	case msg := <-highPriority:
-		fmt.Println("Remaining high priority:", msg)
+		fmt.Fprintln(stdout, "Remaining high priority:", msg)
 	default:
-		fmt.Println("No high priority messages left")
+		fmt.Fprintln(stdout, "No high priority messages left")
 	}
 
 	select {
 	case msg := <-mediumPriority:
-		fmt.Println("Remaining medium priority:", msg)
+		fmt.Fprintln(stdout, "Remaining medium priority:", msg)
 	default:
-		fmt.Println("No medium priority messages left")
+		fmt.Fprintln(stdout, "No medium priority messages left")
 	}
 
 	select {
 	case msg := <-lowPriority:
-		fmt.Println("Remaining low priority:", msg)
+		fmt.Fprintln(stdout, "Remaining low priority:", msg)
 	default:
-		fmt.Println("No low priority messages left")
+		fmt.Fprintln(stdout, "No low priority messages left")
 	}
 
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
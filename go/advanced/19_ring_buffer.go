@@ -18,7 +18,7 @@ import (
  * When the buffer is full, adding a new item removes the oldest item.
  */
 func RingBufferDemo() {
-	fmt.Println("Ring Buffer Pattern")
+	fmt.Fprintln(stdout, "Ring Buffer Pattern")
 
 	// Create a ring buffer using a buffered channel
 	ringBuffer := make(chan int, 5)
@@ -42,15 +42,15 @@ func RingBufferDemo() {
 	// Rotate the buffer a few times
 	for i := 6; i <= 10; i++ {
 		oldest := rotate(i)
-		fmt.Printf("Added %d, removed %d\n", i, oldest)
+		fmt.Fprintf(stdout, "Added %d, removed %d\n", i, oldest)
 	}
 
 	// Print the final state of the buffer
-	fmt.Print("Final buffer state: ")
+	fmt.Fprint(stdout, "Final buffer state: ")
 	close(ringBuffer)
 	for n := range ringBuffer {
-		fmt.Printf("%d ", n)
+		fmt.Fprintf(stdout, "%d ", n)
 	}
-	fmt.Println()
-	fmt.Println()
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout)
 }
@@ -21,7 +21,7 @@ import (
  * reads are more common than writes.
  */
 func RWMutexDemo() {
-	fmt.Println("RWMutex (Read-Write Mutex)")
+	fmt.Fprintln(stdout, "RWMutex (Read-Write Mutex)")
 
 	// Create a shared resource
 	var sharedData = make(map[string]int)
@@ -36,9 +36,9 @@ func RWMutexDemo() {
 		rwMutex.Lock()
 		defer rwMutex.Unlock()
 
-		fmt.Printf("Writing %s = %d\n", key, value)
+		logger.Info("writing", "key", key, "value", value)
 		sharedData[key] = value
-		time.Sleep(100 * time.Millisecond) // Simulate work
+		clk.Sleep(100 * time.Millisecond) // Simulate work
 	}
 
 	// Reader function
@@ -49,12 +49,9 @@ func RWMutexDemo() {
 		rwMutex.RLock()
 		defer rwMutex.RUnlock()
 
-		fmt.Printf("Reader %d: ", id)
-		for k, v := range sharedData {
-			fmt.Printf("%s=%d ", k, v)
-		}
-		fmt.Println()
-		time.Sleep(50 * time.Millisecond) // Simulate work
+		// The record is written before RUnlock, so no writer changes the map meanwhile
+		logger.Info("reading", "reader", id, "data", sharedData)
+		clk.Sleep(50 * time.Millisecond) // Simulate work
 	}
 
 	// Start some writers
@@ -71,5 +68,5 @@ func RWMutexDemo() {
 
 	// Wait for all goroutines to finish
 	wg.Wait()
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
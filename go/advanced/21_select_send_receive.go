@@ -3,49 +3,83 @@
  *
  * The select statement can handle both send and receive operations,
  * allowing for bidirectional communication with timeouts.
+ *
+ * A shared responses channel only works while one request is outstanding at
+ * a time: with several in flight, a caller may receive somebody else's
+ * response. Giving every request its own reply channel correlates each
+ * response with its request, so many requests can be outstanding at once,
+ * each with its own timeout.
  */
 
 package advanced
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
+
+	"threads/clock"
+	"threads/rng"
 )
 
+// correlatedRequest carries its own reply channel. The channel is buffered
+// so that the server never blocks on a caller that has timed out.
+type correlatedRequest struct {
+	id    int
+	query string
+	reply chan string
+}
+
 /**
  * Select with Send and Receive Cases
  *
  * This pattern demonstrates how to use select to handle both sending and
- * receiving operations, with timeout handling for both directions.
+ * receiving operations, with timeout handling for both directions. It then
+ * extends the exchange so that each request carries its reply channel,
+ * allowing concurrent outstanding requests with per-request timeouts.
  */
 func SelectSendReceiveDemo() {
-	fmt.Println("Select with Send and Receive Cases")
+	fmt.Fprintln(stdout, "Select with Send and Receive Cases")
 
 	// Create channels for sending and receiving
 	requests := make(chan string)
 	responses := make(chan string)
+	done := make(chan struct{})
+	defer close(done)
 
 	// Start a worker that processes requests
 	go func() {
 		for {
 			// Wait for a request
-			req := <-requests
+			var req string
+			select {
+			case req = <-requests:
+			case <-done:
+				return
+			}
 
 			// Process the request
 			resp := "Response to: " + req
 
 			// Send the response
-			responses <- resp
+			select {
+			case responses <- resp:
+			case <-done:
+				return
+			}
 		}
 	}()
 
 	// Function to send a request and get a response with timeout
+	// time.After creates a new timer per call; that is fine for a few requests,
+	// but in hot loops reuse a time.Timer instead (see 37_timer_ticker_pitfalls.go)
 	sendRequest := func(req string, timeout time.Duration) (string, bool) {
 		// Send the request
 		select {
 		case requests <- req:
 			// Request sent successfully
-		case <-time.After(timeout):
+		case <-clk.After(timeout):
 			return "", false // Timeout sending request
 		}
 
@@ -53,22 +87,95 @@ func SelectSendReceiveDemo() {
 		select {
 		case resp := <-responses:
 			return resp, true
-		case <-time.After(timeout):
+		case <-clk.After(timeout):
 			return "", false // Timeout waiting for response
 		}
 	}
 
-	// Send some requests
+	// 1. One request at a time over shared channels
+	fmt.Fprintln(stdout, "\n1. Serialized request/response pairs:")
 	for i := 1; i <= 3; i++ {
 		req := fmt.Sprintf("Request %d", i)
 		resp, ok := sendRequest(req, 500*time.Millisecond)
 
 		if ok {
-			fmt.Printf("Request: %s, Response: %s\n", req, resp)
+			fmt.Fprintf(stdout, "Request: %s, Response: %s\n", req, resp)
 		} else {
-			fmt.Printf("Request: %s timed out\n", req)
+			fmt.Fprintf(stdout, "Request: %s timed out\n", req)
+		}
+	}
+
+	steps.Checkpoint("The responses channel is shared, so this only works because each caller waits for " +
+		"its response before the next request is sent.")
+
+	// 2. Every request carries its own reply channel
+	fmt.Fprintln(stdout, "\n2. Correlated requests, all outstanding at once:")
+	calls := make(chan correlatedRequest)
+	for range 3 {
+		go func() {
+			for {
+				select {
+				case req := <-calls:
+					clk.Sleep(time.Duration(10+rng.IntN(50)) * time.Millisecond) // Replies finish out of order
+					req.reply <- fmt.Sprintf("answer to %s", req.query)
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	// call sends a request and waits for its reply until ctx is done
+	call := func(ctx context.Context, id int, query string) (string, error) {
+		req := correlatedRequest{id: id, query: query, reply: make(chan string, 1)}
+		select {
+		case calls <- req:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		select {
+		case resp := <-req.reply:
+			return resp, nil
+		case <-ctx.Done():
+			return "", ctx.Err() // A late reply goes into the buffer and is collected with req
 		}
 	}
 
-	fmt.Println()
+	const outstanding = 6
+	results := make([]string, outstanding)
+	var wg sync.WaitGroup
+	start := clk.Now()
+	for i := range outstanding {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timeout := 200 * time.Millisecond
+			if i == outstanding-1 {
+				timeout = 5 * time.Millisecond // Too short for any reply
+			}
+			ctx, cancel := clock.WithTimeout(context.Background(), clk, timeout)
+			defer cancel()
+
+			query := fmt.Sprintf("query %d", i+1)
+			resp, err := call(ctx, i+1, query)
+			switch {
+			case err != nil:
+				results[i] = fmt.Sprintf("%s (timeout %v): %v", query, timeout, err)
+			case resp != "answer to "+query:
+				results[i] = fmt.Sprintf("%s: MISMATCHED %q", query, resp)
+			default:
+				results[i] = fmt.Sprintf("%s: %q", query, resp)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, r := range results {
+		fmt.Fprintln(stdout, r)
+	}
+	fmt.Fprintf(stdout, "%d requests over 3 servers took %v\n", outstanding, clk.Since(start).Round(10*time.Millisecond))
+
+	steps.Checkpoint("Each reply went down the channel that came with its request, so answers that finished " +
+		"out of order still reached the right caller, and a caller that timed out left nobody blocked.")
+
+	fmt.Fprintln(stdout)
 }
@@ -2,7 +2,8 @@
  * This file demonstrates Sync.Once in Go.
  *
  * Sync.Once ensures that a function is executed only once, regardless of
- * how many goroutines attempt to execute it.
+ * how many goroutines attempt to execute it. See 54_once_helpers.go for the
+ * Go 1.21 OnceFunc, OnceValue and OnceValues helpers.
  */
 
 package advanced
@@ -19,14 +20,14 @@ import (
  * even when called from multiple goroutines concurrently.
  */
 func SyncOnceDemo() {
-	fmt.Println("Sync.Once for One-time Initialization")
+	fmt.Fprintln(stdout, "Sync.Once for One-time Initialization")
 
 	var once sync.Once
 	var onceValue int
 
 	// Function that will only execute once
 	initialize := func() {
-		fmt.Println("Initializing...")
+		fmt.Fprintln(stdout, "Initializing...")
 		onceValue = 42
 	}
 
@@ -37,12 +38,13 @@ func SyncOnceDemo() {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
-			fmt.Printf("Goroutine %d trying to initialize\n", id)
+			log := logger.With("id", id)
+			log.Info("trying to initialize")
 			once.Do(initialize)
-			fmt.Printf("Goroutine %d sees value: %d\n", id, onceValue)
+			log.Info("sees value", "value", onceValue)
 		}(i)
 	}
 
 	wg.Wait()
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
@@ -10,6 +10,8 @@ package advanced
 import (
 	"fmt"
 	"time"
+
+	"threads/topology"
 )
 
 /**
@@ -19,7 +21,7 @@ import (
  * to multiple output channels, effectively duplicating the data stream.
  */
 func TeeChannelPatternDemo() {
-	fmt.Println("Tee Channel Pattern (One Input, Multiple Outputs)")
+	fmt.Fprintln(stdout, "Tee Channel Pattern (One Input, Multiple Outputs)")
 
 	// Generator function
 	gen := func(nums ...int) <-chan int {
@@ -28,7 +30,7 @@ func TeeChannelPatternDemo() {
 			defer close(out)
 			for _, n := range nums {
 				out <- n
-				time.Sleep(100 * time.Millisecond) // Simulate slow generation
+				clk.Sleep(100 * time.Millisecond) // Simulate slow generation
 			}
 		}()
 		return out
@@ -70,8 +72,25 @@ func TeeChannelPatternDemo() {
 
 	// Receive from both output channels
 	for i := 0; i < 3; i++ {
-		fmt.Printf("out1: %d, out2: %d\n", <-out1, <-out2)
+		fmt.Fprintf(stdout, "out1: %d, out2: %d\n", <-out1, <-out2)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(stdout)
+}
+
+// TeeChannelTopology describes the goroutines and channels of the demo
+func TeeChannelTopology() *topology.Graph {
+	return topology.New("Tee Channel").
+		Goroutine("gen", "generator").
+		Channel("in", "in").
+		Goroutine("tee", "tee").
+		Channel("out1", "out1").
+		Channel("out2", "out2").
+		Goroutine("main", "consumer").
+		Flow("gen", "in", "").
+		Flow("in", "tee", "").
+		Flow("tee", "out1", "every value").
+		Flow("tee", "out2", "every value").
+		Flow("out1", "main", "").
+		Flow("out2", "main", "")
 }
@@ -3,6 +3,7 @@
  *
  * While Go's standard mutex doesn't have a built-in "try lock" feature,
  * this pattern implements a non-blocking lock attempt using channels.
+ * Since Go 1.18 the standard mutexes have TryLock; see 55_mutex_trylock.go.
  */
 
 package advanced
@@ -18,7 +19,7 @@ import (
  * lock acquisition attempts, returning success or failure immediately.
  */
 func TryLockDemo() {
-	fmt.Println("Try Lock Pattern")
+	fmt.Fprintln(stdout, "Try Lock Pattern")
 
 	// Create a channel-based try lock
 	tryLock := make(chan struct{}, 1)
@@ -46,19 +47,19 @@ func TryLockDemo() {
 
 	// Try to acquire the lock
 	if acquireLock() {
-		fmt.Println("Lock acquired")
+		fmt.Fprintln(stdout, "Lock acquired")
 		// Do something with the locked resource
 		releaseLock()
-		fmt.Println("Lock released")
+		fmt.Fprintln(stdout, "Lock released")
 	} else {
-		fmt.Println("Failed to acquire lock")
+		fmt.Fprintln(stdout, "Failed to acquire lock")
 	}
 
 	// Try again (should succeed)
 	if acquireLock() {
-		fmt.Println("Lock acquired again")
+		fmt.Fprintln(stdout, "Lock acquired again")
 		releaseLock()
 	}
 
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
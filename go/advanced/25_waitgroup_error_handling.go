@@ -3,25 +3,34 @@
  *
  * This pattern shows how to collect errors from multiple goroutines
  * while still using a WaitGroup to synchronize their completion.
+ * syncx.WaitGroup packages the pattern: Go does the Add and Done, and Wait
+ * returns the errors, including panics recovered as errors.
  */
 
 package advanced
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
 	"sync"
 	"time"
+
+	"threads/rng"
+	"threads/syncx"
 )
 
 /**
  * Error Handling with WaitGroup
  *
  * This pattern uses a buffered channel to collect errors from multiple
- * goroutines, allowing for proper error handling in concurrent code.
+ * goroutines, allowing for proper error handling in concurrent code. The
+ * same workers then run in a syncx.WaitGroup, where one of them panics.
  */
 func WaitGroupErrorHandlingDemo() {
-	fmt.Println("Error Handling with WaitGroup")
+	fmt.Fprintln(stdout, "Error Handling with WaitGroup")
+
+	// 1. WaitGroup and an error channel
+	fmt.Fprintln(stdout, "\n1. sync.WaitGroup with a buffered error channel:")
 
 	// Create a WaitGroup and a channel for errors
 	var wg sync.WaitGroup
@@ -31,18 +40,19 @@ func WaitGroupErrorHandlingDemo() {
 	workerWithError := func(id int) {
 		defer wg.Done()
 
-		fmt.Printf("Worker %d starting\n", id)
-		time.Sleep(time.Duration(rand.Intn(500)) * time.Millisecond)
+		log := logger.With("worker", id)
+		log.Info("worker starting")
+		clk.Sleep(time.Duration(rng.IntN(500)) * time.Millisecond)
 
 		// Simulate an error in some workers
 		if id%2 == 0 {
 			err := fmt.Errorf("worker %d encountered an error", id)
 			errorChan <- err
-			fmt.Printf("Worker %d failed: %v\n", id, err)
+			log.Warn("worker failed", "err", err)
 			return
 		}
 
-		fmt.Printf("Worker %d completed successfully\n", id)
+		log.Info("worker completed")
 	}
 
 	// Launch several workers
@@ -64,13 +74,33 @@ func WaitGroupErrorHandlingDemo() {
 	}
 
 	if len(errors) > 0 {
-		fmt.Printf("Encountered %d errors:\n", len(errors))
+		fmt.Fprintf(stdout, "Encountered %d errors:\n", len(errors))
 		for _, err := range errors {
-			fmt.Printf("- %v\n", err)
+			fmt.Fprintf(stdout, "- %v\n", err)
 		}
 	} else {
-		fmt.Println("All workers completed without errors")
+		fmt.Fprintln(stdout, "All workers completed without errors")
+	}
+
+	// 2. syncx.WaitGroup
+	fmt.Fprintln(stdout, "\n2. syncx.WaitGroup, with worker 5 panicking:")
+	var g syncx.WaitGroup
+	for id := 1; id <= 5; id++ {
+		g.Go(func() error {
+			clk.Sleep(time.Duration(rng.IntN(500)) * time.Millisecond)
+			switch {
+			case id == 5:
+				panic(fmt.Sprintf("worker %d hit a bug", id))
+			case id%2 == 0:
+				return fmt.Errorf("worker %d encountered an error", id)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(context.Background()); err != nil {
+		fmt.Fprintf(stdout, "Wait returned:\n%v\n", err)
 	}
+	fmt.Fprintln(stdout, "No Add, Done or error channel to size; the panic became an error instead of a crash.")
 
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
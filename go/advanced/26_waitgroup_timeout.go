@@ -2,25 +2,35 @@
  * This file demonstrates WaitGroup with Timeout Pattern in Go.
  *
  * This pattern shows how to implement a timeout when waiting for goroutines
- * to complete, rather than waiting indefinitely.
+ * to complete, rather than waiting indefinitely. syncx.WaitGroup's Wait
+ * takes a context and does the same; it still uses a goroutine and a
+ * channel inside, but the caller no longer writes them.
  */
 
 package advanced
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
+
+	"threads/clock"
+	"threads/syncx"
 )
 
 /**
  * WaitGroup with Timeout Pattern
  *
  * This pattern combines WaitGroup with select and time.After to implement
- * a timeout when waiting for goroutines to complete.
+ * a timeout when waiting for goroutines to complete, then repeats it with
+ * syncx.WaitGroup and a context that times out.
  */
 func WaitGroupTimeoutDemo() {
-	fmt.Println("WaitGroup with Timeout Pattern")
+	fmt.Fprintln(stdout, "WaitGroup with Timeout Pattern")
+
+	// 1. select over a done channel and a timer
+	fmt.Fprintln(stdout, "\n1. sync.WaitGroup, a done channel and select:")
 
 	var wg sync.WaitGroup
 	done := make(chan struct{})
@@ -33,12 +43,12 @@ func WaitGroupTimeoutDemo() {
 
 			// Worker 2 will take longer than the timeout
 			if id == 2 {
-				time.Sleep(2 * time.Second)
+				clk.Sleep(2 * time.Second)
 			} else {
-				time.Sleep(500 * time.Millisecond)
+				clk.Sleep(500 * time.Millisecond)
 			}
 
-			fmt.Printf("Worker %d completed\n", id)
+			logger.Info("worker completed", "worker", id)
 		}(i)
 	}
 
@@ -51,12 +61,37 @@ func WaitGroupTimeoutDemo() {
 	// Wait with timeout
 	select {
 	case <-done:
-		fmt.Println("All workers completed in time")
-	case <-time.After(1 * time.Second):
-		fmt.Println("Timeout waiting for workers")
+		fmt.Fprintln(stdout, "All workers completed in time")
+	case <-clk.After(1 * time.Second):
+		fmt.Fprintln(stdout, "Timeout waiting for workers")
 	}
 
 	// Wait a bit longer to let the remaining workers finish
-	time.Sleep(1500 * time.Millisecond)
-	fmt.Println()
+	clk.Sleep(1500 * time.Millisecond)
+
+	// 2. syncx.WaitGroup
+	fmt.Fprintln(stdout, "\n2. syncx.WaitGroup.Wait with a context:")
+	var g syncx.WaitGroup
+	for id := 1; id <= 3; id++ {
+		g.Go(func() error {
+			if id == 2 {
+				clk.Sleep(2 * time.Second)
+			} else {
+				clk.Sleep(500 * time.Millisecond)
+			}
+			logger.Info("worker completed", "worker", id)
+			return nil
+		})
+	}
+
+	// The timeout runs on the examples' clock, like the workers' sleeps
+	ctx, cancel := clock.WithTimeout(context.Background(), clk, 1*time.Second)
+	defer cancel()
+	if err := g.Wait(ctx); err != nil {
+		fmt.Fprintf(stdout, "Wait gave up: %v\n", err)
+	}
+	if err := g.Wait(context.Background()); err == nil {
+		fmt.Fprintln(stdout, "A second Wait saw the slow worker finish")
+	}
+	fmt.Fprintln(stdout)
 }
@@ -9,9 +9,11 @@ package advanced
 
 import (
 	"fmt"
-	"math/rand"
 	"sync"
 	"time"
+
+	"threads/rng"
+	"threads/topology"
 )
 
 /**
@@ -21,7 +23,7 @@ import (
  * limiting concurrency while efficiently processing a stream of work items.
  */
 func WorkerPoolDemo() {
-	fmt.Println("WaitGroup with Worker Pool Pattern")
+	fmt.Fprintln(stdout, "WaitGroup with Worker Pool Pattern")
 
 	// Create a pool of worker goroutines
 	numWorkers := 3
@@ -33,15 +35,18 @@ func WorkerPoolDemo() {
 	worker := func(id int) {
 		defer wg.Done()
 
-		fmt.Printf("Pool worker %d started\n", id)
+		log := logger.With("worker", id)
+		log.Info("pool worker started")
 
 		for job := range jobs {
-			fmt.Printf("Worker %d processing job %d\n", id, job)
-			time.Sleep(time.Duration(rand.Intn(500)) * time.Millisecond)
+			log.Info("processing job", "job", job)
+			work := time.Duration(rng.IntN(500)) * time.Millisecond
+			clk.Sleep(work)
+			log.Debug("job finished", "job", job, "duration", work)
 			results <- job * 2 // Simple job: double the input
 		}
 
-		fmt.Printf("Pool worker %d finished\n", id)
+		log.Info("pool worker finished")
 	}
 
 	// Start the worker pool
@@ -50,6 +55,9 @@ func WorkerPoolDemo() {
 		go worker(i)
 	}
 
+	steps.Checkpoint("Three workers are ranging over the jobs channel. They block until jobs arrive; " +
+		"the pool size caps how many jobs run at once.")
+
 	// Send jobs to the workers
 	go func() {
 		for i := 1; i <= 10; i++ {
@@ -64,10 +72,33 @@ func WorkerPoolDemo() {
 		close(results)
 	}()
 
+	steps.Checkpoint("A separate goroutine closes results after wg.Wait, so the collector below can range " +
+		"over results and stop exactly when the last worker is done.")
+
 	// Collect and print results
 	for result := range results {
-		fmt.Printf("Got result: %d\n", result)
+		fmt.Fprintf(stdout, "Got result: %d\n", result)
 	}
 
-	fmt.Println()
+	fmt.Fprintln(stdout)
+}
+
+// WorkerPoolTopology describes the goroutines and channels of the demo
+func WorkerPoolTopology() *topology.Graph {
+	g := topology.New("Worker Pool").
+		Goroutine("producer", "job producer").
+		Channel("jobs", "jobs (cap 10)").
+		Channel("results", "results (cap 10)").
+		Goroutine("closer", "closer (wg.Wait, then close)").
+		Goroutine("main", "result collector").
+		Flow("producer", "jobs", "").
+		Flow("closer", "results", "close").
+		Flow("results", "main", "")
+	for i := 1; i <= 3; i++ {
+		w := fmt.Sprintf("worker%d", i)
+		g.Goroutine(w, fmt.Sprintf("worker %d", i)).
+			Flow("jobs", w, "").
+			Flow(w, "results", "")
+	}
+	return g
 }
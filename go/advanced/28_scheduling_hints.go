@@ -9,14 +9,19 @@ package advanced
 import (
 	"fmt"
 	"runtime"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"threads/maxprocs"
+	"threads/stats"
 )
 
 // SchedulingHintsDemo demonstrates various scheduling hints in Go
 func SchedulingHintsDemo() {
-	fmt.Println("Scheduling Hints Demo")
-	fmt.Println("=====================")
+	fmt.Fprintln(stdout, "Scheduling Hints Demo")
+	fmt.Fprintln(stdout, "=====================")
 
 	// 1. GOMAXPROCS - Controls the maximum number of OS threads that can execute Go code simultaneously
 	gomaxprocsDemo()
@@ -26,20 +31,23 @@ func SchedulingHintsDemo() {
 
 	// 3. LockOSThread/UnlockOSThread - Locks/unlocks the calling goroutine to its current OS thread
 	lockOSThreadDemo()
+
+	// 4. GOMAXPROCS above the CPUs the process actually gets, as in a container with a CPU quota
+	cpuQuotaDemo()
 }
 
 // gomaxprocsDemo demonstrates the use of GOMAXPROCS
 func gomaxprocsDemo() {
-	fmt.Println("\n1. GOMAXPROCS Example")
-	fmt.Println("--------------------")
+	fmt.Fprintln(stdout, "\n1. GOMAXPROCS Example")
+	fmt.Fprintln(stdout, "--------------------")
 
 	// Get the current value of GOMAXPROCS
 	prevMaxProcs := runtime.GOMAXPROCS(0)
-	fmt.Printf("Current GOMAXPROCS: %d\n", prevMaxProcs)
+	fmt.Fprintf(stdout, "Current GOMAXPROCS: %d\n", prevMaxProcs)
 
 	// Set GOMAXPROCS to 1 (single thread)
 	runtime.GOMAXPROCS(1)
-	fmt.Println("Set GOMAXPROCS to 1")
+	fmt.Fprintln(stdout, "Set GOMAXPROCS to 1")
 
 	// Run a CPU-bound task with multiple goroutines
 	var wg sync.WaitGroup
@@ -54,17 +62,19 @@ func gomaxprocsDemo() {
 			for j := 0; j < 100000000; j++ {
 				sum += j
 			}
-			fmt.Printf("Goroutine %d finished\n", id)
+			fmt.Fprintf(stdout, "Goroutine %d finished\n", id)
 		}(i)
 	}
 
 	wg.Wait()
-	fmt.Printf("With GOMAXPROCS=1, all goroutines took: %v\n", time.Since(start))
+	elapsed := time.Since(start)
+	stats.Record("GOMAXPROCS=1", elapsed)
+	fmt.Fprintf(stdout, "With GOMAXPROCS=1, all goroutines took: %v\n", elapsed)
 
 	// Set GOMAXPROCS back to the number of CPUs
 	numCPU := runtime.NumCPU()
 	runtime.GOMAXPROCS(numCPU)
-	fmt.Printf("Set GOMAXPROCS to %d (number of CPUs)\n", numCPU)
+	fmt.Fprintf(stdout, "Set GOMAXPROCS to %d (number of CPUs)\n", numCPU)
 
 	// Run the same task again
 	start = time.Now()
@@ -78,12 +88,14 @@ func gomaxprocsDemo() {
 			for j := 0; j < 100000000; j++ {
 				sum += j
 			}
-			fmt.Printf("Goroutine %d finished\n", id)
+			fmt.Fprintf(stdout, "Goroutine %d finished\n", id)
 		}(i)
 	}
 
 	wg.Wait()
-	fmt.Printf("With GOMAXPROCS=%d, all goroutines took: %v\n", numCPU, time.Since(start))
+	elapsed = time.Since(start)
+	stats.Record(fmt.Sprintf("GOMAXPROCS=%d", numCPU), elapsed)
+	fmt.Fprintf(stdout, "With GOMAXPROCS=%d, all goroutines took: %v\n", numCPU, elapsed)
 
 	// Restore the original GOMAXPROCS value
 	runtime.GOMAXPROCS(prevMaxProcs)
@@ -91,8 +103,8 @@ func gomaxprocsDemo() {
 
 // goschedDemo demonstrates the use of Gosched
 func goschedDemo() {
-	fmt.Println("\n2. Gosched Example")
-	fmt.Println("----------------")
+	fmt.Fprintln(stdout, "\n2. Gosched Example")
+	fmt.Fprintln(stdout, "----------------")
 
 	// Create a channel to synchronize goroutines
 	done := make(chan bool)
@@ -100,7 +112,7 @@ func goschedDemo() {
 	// Start a goroutine that prints numbers
 	go func() {
 		for i := 0; i < 5; i++ {
-			fmt.Printf("Goroutine: %d\n", i)
+			fmt.Fprintf(stdout, "Goroutine: %d\n", i)
 			// Yield the processor after each print
 			runtime.Gosched()
 		}
@@ -109,71 +121,163 @@ func goschedDemo() {
 
 	// Main goroutine prints letters
 	for i := 0; i < 5; i++ {
-		fmt.Printf("Main: %c\n", 'A'+i)
+		fmt.Fprintf(stdout, "Main: %c\n", 'A'+i)
 		// Don't yield, to demonstrate the difference
 	}
 
 	<-done
-	fmt.Println("Notice how the goroutine execution is interleaved with the main function")
-	fmt.Println("This is because Gosched() yields the processor, allowing other goroutines to run")
+	fmt.Fprintln(stdout, "Notice how the goroutine execution is interleaved with the main function")
+	fmt.Fprintln(stdout, "This is because Gosched() yields the processor, allowing other goroutines to run")
 }
 
 // lockOSThreadDemo demonstrates the use of LockOSThread and UnlockOSThread
 func lockOSThreadDemo() {
-	fmt.Println("\n3. LockOSThread/UnlockOSThread Example")
-	fmt.Println("------------------------------------")
-
-	fmt.Println("LockOSThread locks the calling goroutine to its current OS thread.")
-	fmt.Println("This is useful when you need to ensure that a goroutine always executes on the same OS thread,")
-	fmt.Println("such as when making calls to C libraries that depend on thread-local state.")
-
-	// Get the current thread ID
-	threadID := func() int {
-		// This is a hack to get a unique ID for the current thread
-		// In a real application, you might use C.GetCurrentThreadId() on Windows
-		// or C.pthread_self() on Unix systems
-		var buf [64]byte
-		return len(buf)
-	}
-
-	var wg sync.WaitGroup
-
-	// Start a goroutine that locks itself to an OS thread
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
-		fmt.Printf("Before locking: Goroutine running on thread (approx): %d\n", threadID())
+	fmt.Fprintln(stdout, "\n3. LockOSThread/UnlockOSThread Example")
+	fmt.Fprintln(stdout, "------------------------------------")
 
-		// Lock this goroutine to the current OS thread
-		runtime.LockOSThread()
-		defer runtime.UnlockOSThread() // Ensure we unlock when done
+	fmt.Fprintln(stdout, "LockOSThread locks the calling goroutine to its current OS thread.")
+	fmt.Fprintln(stdout, "This is useful when you need to ensure that a goroutine always executes on the same OS thread,")
+	fmt.Fprintln(stdout, "such as when making calls to C libraries that depend on thread-local state.")
 
-		id1 := threadID()
-		fmt.Printf("After locking: Goroutine running on thread (approx): %d\n", id1)
+	if _, ok := osThreadID(); !ok {
+		fmt.Fprintln(stdout, "This build cannot read OS thread IDs (it needs Linux, Windows or cgo), so there is nothing to compare.")
+		return
+	}
 
-		// Sleep to allow the scheduler to potentially move us
-		time.Sleep(10 * time.Millisecond)
+	// Several Ps and some busy neighbours give the scheduler reasons to
+	// resume a goroutine on a different thread after it sleeps
+	stopChurn := threadChurn()
+
+	// threadsUsed sleeps 100 times and counts the wake-ups on each thread
+	threadsUsed := func(lock bool) map[uint64]int {
+		threads := map[uint64]int{}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if lock {
+				runtime.LockOSThread()
+				defer runtime.UnlockOSThread() // Ensure we unlock when done
+			}
+			for range 100 {
+				id, _ := osThreadID()
+				threads[id]++
+				time.Sleep(100 * time.Microsecond)
+			}
+		}()
+		<-done
+		return threads
+	}
 
-		id2 := threadID()
-		fmt.Printf("After sleeping: Goroutine still on same thread: %v (thread: %d)\n", id1 == id2, id2)
+	regular := threadsUsed(false)
+	fmt.Fprintf(stdout, "Regular goroutine: 100 wake-ups on %d OS thread(s) %v\n", len(regular), regular)
+	locked := threadsUsed(true)
+	fmt.Fprintf(stdout, "Locked goroutine:  100 wake-ups on %d OS thread(s) %v\n", len(locked), locked)
+	stopChurn()
 
-		fmt.Println("This goroutine will remain on this OS thread until UnlockOSThread is called")
-	}()
+	fmt.Fprintln(stdout, "Regular goroutines can be moved between OS threads by the scheduler; a locked one")
+	fmt.Fprintln(stdout, "always runs on its thread, and no other goroutine runs there until it unlocks.")
+}
 
-	// Start a regular goroutine for comparison
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
+// quotaRequest is an allocation-heavy request: it builds and sums a few
+// hundred small buffers, so it keeps the garbage collector busy
+func quotaRequest() int {
+	bufs := make([][]byte, 0, 200)
+	sum := 0
+	for i := range 200 {
+		b := make([]byte, 1024)
+		b[i] = byte(i)
+		bufs = append(bufs, b)
+	}
+	for _, b := range bufs {
+		for j := 0; j < len(b); j += 64 {
+			sum += int(b[j])
+		}
+	}
+	return sum
+}
 
-		fmt.Printf("Regular goroutine on thread (approx): %d\n", threadID())
+// quotaLoad sends requests at rate per millisecond for d, each handled on
+// its own goroutine, and returns how long they took to finish and their
+// latencies from arrival, sorted
+func quotaLoad(procs int, rate float64, d time.Duration) (time.Duration, []time.Duration) {
+	prev := runtime.GOMAXPROCS(procs)
+	defer runtime.GOMAXPROCS(prev)
+	runtime.GC()
+
+	var mu sync.Mutex
+	var latencies []time.Duration
+	var sink atomic.Int64
+	var wg sync.WaitGroup
+	ticker := time.NewTicker(time.Millisecond)
+	start := time.Now()
+	total := int(rate * float64(d) / float64(time.Millisecond))
+	for sent := 0; sent < total; {
+		<-ticker.C
+		// Catch up on every request due by now, timed from when it was due,
+		// so a starved sender does not hide the delay
+		for due := min(total, int(rate*float64(time.Since(start))/float64(time.Millisecond))); sent < due; sent++ {
+			arrived := start.Add(time.Duration(float64(sent) / rate * float64(time.Millisecond)))
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sink.Add(int64(quotaRequest()))
+				mu.Lock()
+				latencies = append(latencies, time.Since(arrived))
+				mu.Unlock()
+			}()
+		}
+	}
+	ticker.Stop()
+	wg.Wait()
+	took := time.Since(start)
+	slices.Sort(latencies)
+	return took, latencies
+}
 
-		// Sleep to allow the scheduler to potentially move us
-		time.Sleep(10 * time.Millisecond)
+// cpuQuotaDemo shows what happens when GOMAXPROCS exceeds the CPUs the
+// process actually gets
+func cpuQuotaDemo() {
+	fmt.Fprintln(stdout, "\n4. GOMAXPROCS vs CPU Quota")
+	fmt.Fprintln(stdout, "-------------------------")
+
+	// In a container, NumCPU counts the host's CPUs; the quota is what the
+	// process may really use
+	available := runtime.NumCPU()
+	if quota, err := maxprocs.CPUQuota(); err == nil {
+		available = min(maxprocs.Procs(quota), available)
+		fmt.Fprintf(stdout, "CPU quota: %.2f CPUs of the host's %d, so GOMAXPROCS should be %d\n", quota, runtime.NumCPU(), available)
+	} else {
+		fmt.Fprintf(stdout, "No CPU quota (%v): the limit is the machine's %d CPU(s)\n", err, available)
+	}
 
-		fmt.Printf("After sleeping: Regular goroutine on thread (approx): %d\n", threadID())
-		fmt.Println("Regular goroutines can be moved between OS threads by the scheduler")
-	}()
+	// Calibrate the load to 60% of what the available CPUs can handle,
+	// taking the best of three batches
+	prev := runtime.GOMAXPROCS(available)
+	perRequest := time.Duration(1<<63 - 1)
+	for range 3 {
+		start := time.Now()
+		for range 200 {
+			quotaRequest()
+		}
+		perRequest = min(perRequest, time.Since(start)/200)
+	}
+	runtime.GOMAXPROCS(prev)
+	rate := 0.6 * float64(available) * float64(time.Millisecond) / float64(perRequest)
+
+	const duration = 300 * time.Millisecond
+	fmt.Fprintf(stdout, "Sending %.1f requests/ms (60%% of %d CPUs) for %v:\n", rate, available, duration)
+	fmt.Fprintf(stdout, "%10s %9s %10s %10s %10s\n", "GOMAXPROCS", "requests", "took", "p50", "p99")
+	for _, procs := range []int{available, 4 * available, 16 * available} {
+		took, latencies := quotaLoad(procs, rate, duration)
+		stats.Record(fmt.Sprintf("quota GOMAXPROCS=%d p99", procs), latencies[len(latencies)*99/100])
+		fmt.Fprintf(stdout, "%10d %9d %10v %10v %10v\n", procs, len(latencies), took.Round(time.Millisecond),
+			latencies[len(latencies)/2].Round(time.Microsecond), latencies[len(latencies)*99/100].Round(time.Microsecond))
+	}
 
-	wg.Wait()
+	fmt.Fprintln(stdout, "With more Ps than CPUs, the OS time-slices the runtime's threads: a goroutine can be")
+	fmt.Fprintln(stdout, "descheduled mid-request, the parallel GC runs more workers than there are CPUs, and")
+	fmt.Fprintln(stdout, "under a CFS quota the whole process is throttled once the period's budget is spent.")
+	fmt.Fprintln(stdout, "Latency suffers first, and throughput with it once the extra work eats the headroom.")
+	fmt.Fprintln(stdout, "Run with --automaxprocs to set GOMAXPROCS from the quota (package maxprocs); Go 1.25")
+	fmt.Fprintln(stdout, "does it by default for modules that declare go 1.25 or later.")
 }
@@ -0,0 +1,107 @@
+/**
+ * This file demonstrates Bounded Parallelism with parallel.ForEach in Go.
+ *
+ * Launching one goroutine per item is easy, but unbounded fan-out can exhaust
+ * memory, file descriptors or downstream services. parallel.ForEach runs a
+ * function over a slice with a fixed upper limit on concurrent goroutines.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"threads/clock"
+	"threads/parallel"
+	"threads/stats"
+)
+
+/**
+ * Bounded Parallelism with parallel.ForEach
+ *
+ * This pattern processes a slice of items with at most N goroutines at a time,
+ * collects every error instead of stopping at the first one, and stops
+ * scheduling new work as soon as the context is cancelled.
+ */
+func ParallelForEachDemo() {
+	fmt.Fprintln(stdout, "Bounded Parallelism with parallel.ForEach")
+
+	// Track how many goroutines are running at the same time
+	var running, peak int64
+	track := func() func() {
+		n := atomic.AddInt64(&running, 1)
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+		return func() { atomic.AddInt64(&running, -1) }
+	}
+
+	// 1. Process items with a limit of 3 goroutines
+	fmt.Fprintln(stdout, "\n1. Processing 10 items with limit 3:")
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	err := parallel.ForEach(context.Background(), items, 3, func(ctx context.Context, n int) error {
+		defer track()()
+		clk.Sleep(50 * time.Millisecond) // Simulate work
+		fmt.Fprintf(stdout, "Processed item %d\n", n)
+		return nil
+	})
+	fmt.Fprintf(stdout, "Finished with err=%v, peak concurrency=%d\n", err, atomic.LoadInt64(&peak))
+
+	// 2. Errors are collected rather than aborting the remaining items
+	fmt.Fprintln(stdout, "\n2. Collecting errors:")
+	err = parallel.ForEach(context.Background(), items, 4, func(ctx context.Context, n int) error {
+		if n%4 == 0 {
+			return fmt.Errorf("item %d failed", n)
+		}
+		return nil
+	})
+	fmt.Fprintf(stdout, "Collected errors:\n%v\n", err)
+
+	// 3. Cancelling the context stops scheduling new items
+	fmt.Fprintln(stdout, "\n3. Stopping early on cancellation:")
+	ctx, cancel := clock.WithTimeout(context.Background(), clk, 120*time.Millisecond)
+	defer cancel()
+
+	var processed int64
+	err = parallel.ForEach(ctx, items, 2, func(ctx context.Context, n int) error {
+		// A Timer rather than time.After, so an item cut short does not leave
+		// its timer behind
+		work := clk.NewTimer(50 * time.Millisecond)
+		defer work.Stop()
+		select {
+		case <-work.C:
+			atomic.AddInt64(&processed, 1)
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	})
+	fmt.Fprintf(stdout, "Processed %d of %d items before cancellation, err=%v\n", atomic.LoadInt64(&processed), len(items), err)
+
+	// 4. Benchmark: how the limit affects total time for I/O-like work
+	fmt.Fprintln(stdout, "\n4. Comparing limits (40 items, 10ms each):")
+	work := make([]int, 40)
+	for _, limit := range []int{1, 2, 4, 8, 16, 0} {
+		start := clk.Now()
+		parallel.ForEach(context.Background(), work, limit, func(ctx context.Context, _ int) error {
+			clk.Sleep(10 * time.Millisecond)
+			return nil
+		})
+
+		label := fmt.Sprintf("limit %d", limit)
+		if limit == 0 {
+			label = "unbounded"
+		}
+		elapsed := clk.Since(start)
+		stats.Record(label, elapsed)
+		fmt.Fprintf(stdout, "%-10s took %v\n", label, elapsed.Round(time.Millisecond))
+	}
+
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,142 @@
+/**
+ * This file demonstrates Configuration Hot-Reload with atomic.Value in Go.
+ *
+ * Read-mostly data such as configuration is read on every request but changes
+ * rarely. Instead of taking a lock on every read, a writer publishes a new
+ * immutable snapshot and readers atomically load whichever snapshot is current.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/clock"
+)
+
+// appConfig is an immutable configuration snapshot. Once published through
+// atomic.Value it must never be modified, only replaced.
+type appConfig struct {
+	Version  int
+	Features map[string]bool
+	Timeout  time.Duration
+}
+
+/**
+ * atomic.Value Configuration Hot-Reload
+ *
+ * A background reloader builds a modified copy of the current config
+ * (copy-on-write) and stores it in an atomic.Value, while many readers load
+ * the config lock-free in a tight loop. Each reader sees either the old or the
+ * new snapshot, never a half-updated one.
+ */
+func AtomicValueConfigDemo() {
+	fmt.Fprintln(stdout, "atomic.Value Configuration Hot-Reload")
+
+	var config atomic.Value
+	config.Store(&appConfig{
+		Version:  1,
+		Features: map[string]bool{"search": true},
+		Timeout:  100 * time.Millisecond,
+	})
+
+	// Writers still serialize among themselves, so concurrent updates
+	// don't overwrite each other's copy
+	var writeMu sync.Mutex
+	update := func(change func(c *appConfig)) *appConfig {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+
+		old := config.Load().(*appConfig)
+
+		// Copy-on-write: deep copy the snapshot, including the map
+		next := &appConfig{
+			Version:  old.Version + 1,
+			Features: make(map[string]bool, len(old.Features)),
+			Timeout:  old.Timeout,
+		}
+		for k, v := range old.Features {
+			next.Features[k] = v
+		}
+		change(next)
+
+		config.Store(next)
+		return next
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// Background reloader publishing a new snapshot periodically
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := clock.NewTicker(clk, 30*time.Millisecond)
+		defer ticker.Stop()
+
+		changes := []func(c *appConfig){
+			func(c *appConfig) { c.Features["recommendations"] = true },
+			func(c *appConfig) { c.Timeout = 250 * time.Millisecond },
+			func(c *appConfig) { c.Features["search"] = false },
+		}
+
+		for _, change := range changes {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				c := update(change)
+				fmt.Fprintf(stdout, "Reloader published v%d: features=%v timeout=%v\n", c.Version, c.Features, c.Timeout)
+			}
+		}
+	}()
+
+	// Many readers loading the config lock-free
+	const numReaders = 8
+	reads := make([]int, numReaders)
+	lastSeen := make([]int, numReaders)
+
+	for r := 0; r < numReaders; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				c := config.Load().(*appConfig)
+				// Versions only move forward for any single reader
+				if c.Version < lastSeen[r] {
+					fmt.Fprintf(stdout, "Reader %d went back in time: v%d after v%d\n", r, c.Version, lastSeen[r])
+				}
+				lastSeen[r] = c.Version
+				_ = c.Features["search"] // Reading the map is safe: nobody mutates it
+				reads[r]++
+				clk.Sleep(time.Millisecond)
+			}
+		}(r)
+	}
+
+	clk.Sleep(150 * time.Millisecond)
+	close(done)
+	wg.Wait()
+
+	total := 0
+	for r := 0; r < numReaders; r++ {
+		total += reads[r]
+	}
+	final := config.Load().(*appConfig)
+	fmt.Fprintf(stdout, "%d readers performed %d lock-free reads; final config is v%d\n", numReaders, total, final.Version)
+
+	fmt.Fprintln(stdout, "\nRules for the copy-on-write pattern:")
+	fmt.Fprintln(stdout, "- Never modify a snapshot after Store; always build a new one")
+	fmt.Fprintln(stdout, "- Always store the same concrete type (atomic.Value panics otherwise)")
+	fmt.Fprintln(stdout, "- Serialize writers if updates are derived from the previous snapshot")
+	fmt.Fprintln(stdout)
+}
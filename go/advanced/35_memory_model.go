@@ -0,0 +1,123 @@
+/**
+ * This file demonstrates the Go Memory Model and the happens-before relation.
+ *
+ * A write in one goroutine is only guaranteed to be visible to a read in
+ * another goroutine if the write "happens before" the read. Without a
+ * synchronizing operation linking the two, the compiler and CPU are free to
+ * reorder or cache memory accesses, and the program has a data race.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/**
+ * Happens-Before and Safe Publication
+ *
+ * This example publishes a value from a writer goroutine to a reader using a
+ * plain boolean flag (a data race), then repeats the publication using a
+ * channel, a mutex and an atomic flag, each of which creates a happens-before
+ * edge from the writer to the reader.
+ */
+func MemoryModelDemo() {
+	fmt.Fprintln(stdout, "Happens-Before and Safe Publication")
+
+	// 1. Broken: plain flag + data
+	fmt.Fprintln(stdout, "\n1. Unsynchronized flag (DATA RACE):")
+	{
+		var data int
+		var ready bool
+
+		go func() {
+			data = 42
+			ready = true // Nothing orders this write with the reader's loads
+		}()
+
+		// Spin with a deadline: without synchronization the reader might
+		// never observe ready, or observe it before data
+		deadline := time.Now().Add(100 * time.Millisecond)
+		for !ready && time.Now().Before(deadline) {
+			runtime.Gosched()
+		}
+		fmt.Fprintf(stdout, "Reader saw ready=%v data=%d\n", ready, data)
+		fmt.Fprintln(stdout, "This often prints the expected values, which is exactly why races are dangerous:")
+		fmt.Fprintln(stdout, "the Go memory model gives no guarantee here. Run with `go run -race .`")
+		fmt.Fprintln(stdout, "to have the race detector report the conflicting accesses.")
+	}
+
+	// 2. Channel: a send happens before the corresponding receive completes
+	fmt.Fprintln(stdout, "\n2. Publication via channel:")
+	{
+		var data int
+		done := make(chan struct{})
+
+		go func() {
+			data = 42
+			close(done) // The close happens before a receive that returns because of it
+		}()
+
+		<-done
+		fmt.Fprintf(stdout, "Reader saw data=%d (guaranteed)\n", data)
+	}
+
+	// 3. Mutex: Unlock happens before any later Lock returns
+	fmt.Fprintln(stdout, "\n3. Publication via mutex:")
+	{
+		var mu sync.Mutex
+		var data int
+		var ready bool
+
+		go func() {
+			mu.Lock()
+			data = 42
+			ready = true
+			mu.Unlock()
+		}()
+
+		for {
+			mu.Lock()
+			ok := ready
+			value := data
+			mu.Unlock()
+			if ok {
+				fmt.Fprintf(stdout, "Reader saw data=%d (guaranteed)\n", value)
+				break
+			}
+			runtime.Gosched()
+		}
+	}
+
+	// 4. Atomic: a Store observed by a Load synchronizes the two goroutines
+	fmt.Fprintln(stdout, "\n4. Publication via atomic flag:")
+	{
+		var data int
+		var ready atomic.Bool
+
+		go func() {
+			data = 42         // Plain write...
+			ready.Store(true) // ...published by the atomic store that follows it
+		}()
+
+		for !ready.Load() {
+			runtime.Gosched()
+		}
+		// Because Load observed the Store, the write to data is visible
+		fmt.Fprintf(stdout, "Reader saw data=%d (guaranteed)\n", data)
+	}
+
+	fmt.Fprintln(stdout, "\nWhat the Go memory model guarantees:")
+	fmt.Fprintln(stdout, "- Within a single goroutine, reads and writes behave in program order")
+	fmt.Fprintln(stdout, "- A send on a channel happens before the matching receive completes")
+	fmt.Fprintln(stdout, "- Closing a channel happens before a receive that returns because it is closed")
+	fmt.Fprintln(stdout, "- The n-th Unlock of a mutex happens before the (n+1)-th Lock returns")
+	fmt.Fprintln(stdout, "- If an atomic Load observes an atomic Store, the Store happens before the Load")
+	fmt.Fprintln(stdout, "- The go statement happens before the new goroutine starts executing")
+	fmt.Fprintln(stdout, "Anything not ordered by these rules is a data race, and its result is undefined.")
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,119 @@
+/**
+ * This file demonstrates Select Fairness and Starvation in Go.
+ *
+ * When several select cases are ready at once, Go picks one uniformly at
+ * random. That makes select fair by default, but it also means priorities
+ * have to be built on top of it, and a naive priority scheme can starve
+ * lower-priority channels entirely.
+ */
+
+package advanced
+
+import (
+	"fmt"
+)
+
+/**
+ * Select Fairness, Starvation and Weighted Selection
+ *
+ * This example measures how select distributes picks between ready channels,
+ * shows the priority select from 18_priority_select.go starving a busy
+ * low-priority channel, and replaces it with a weighted scheme that still
+ * favours high priority but guarantees progress for every channel.
+ */
+func SelectFairnessDemo() {
+	fmt.Fprintln(stdout, "Select Fairness, Starvation and Weighted Selection")
+
+	const backlog = 10000
+	const picks = 3000
+
+	// fill returns a channel with n ready values
+	fill := func(n int) chan int {
+		ch := make(chan int, n)
+		for i := 0; i < n; i++ {
+			ch <- i
+		}
+		return ch
+	}
+
+	// 1. Plain select chooses uniformly among ready cases
+	fmt.Fprintln(stdout, "\n1. Plain select with three always-ready channels:")
+	a, b, c := fill(backlog), fill(backlog), fill(backlog)
+	counts := [3]int{}
+	for i := 0; i < picks; i++ {
+		select {
+		case <-a:
+			counts[0]++
+		case <-b:
+			counts[1]++
+		case <-c:
+			counts[2]++
+		}
+	}
+	fmt.Fprintf(stdout, "a=%d b=%d c=%d (each roughly %d)\n", counts[0], counts[1], counts[2], picks/3)
+
+	// 2. Naive priority select starves the low-priority channel
+	fmt.Fprintln(stdout, "\n2. Naive priority select while high priority is always busy:")
+	high, low := fill(backlog), fill(backlog)
+	counts = [3]int{}
+	for i := 0; i < picks; i++ {
+		select {
+		case <-high:
+			counts[0]++
+			continue
+		default:
+		}
+		select {
+		case <-high:
+			counts[0]++
+		case <-low:
+			counts[1]++
+		}
+	}
+	fmt.Fprintf(stdout, "high=%d low=%d -> low priority is starved\n", counts[0], counts[1])
+
+	// 3. Weighted selection: serve up to weight items per channel per round
+	fmt.Fprintln(stdout, "\n3. Weighted selection (weights high=3, medium=2, low=1):")
+	queues := []struct {
+		name   string
+		ch     chan int
+		weight int
+	}{
+		{"high", fill(backlog), 3},
+		{"medium", fill(backlog), 2},
+		{"low", fill(backlog), 1},
+	}
+	served := make([]int, len(queues))
+
+	for total := 0; total < picks; {
+		progressed := false
+
+		// One round: visit channels in priority order, taking up to weight items
+		for i, q := range queues {
+		take:
+			for taken := 0; taken < q.weight && total < picks; taken++ {
+				select {
+				case <-q.ch:
+					served[i]++
+					total++
+					progressed = true
+				default:
+					break take // Empty: move on to the next channel
+				}
+			}
+		}
+
+		if !progressed {
+			break
+		}
+	}
+	for i, q := range queues {
+		fmt.Fprintf(stdout, "%-6s served %4d (%.0f%%)\n", q.name, served[i], 100*float64(served[i])/picks)
+	}
+
+	fmt.Fprintln(stdout, "\nTakeaways:")
+	fmt.Fprintln(stdout, "- select is deliberately random: never rely on case order for priority")
+	fmt.Fprintln(stdout, "- Strict priority is only safe if the high-priority channel is sometimes idle")
+	fmt.Fprintln(stdout, "- Weighted rounds bound how long any ready channel can wait")
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,128 @@
+/**
+ * This file demonstrates time.Timer and time.Ticker Pitfalls in Go.
+ *
+ * time.After is convenient in a one-off select, but inside a loop it creates
+ * a brand new timer on every iteration. Before Go 1.23 each of those timers
+ * stayed alive until it fired, so a loop with a long timeout could pile up
+ * thousands of pending timers. Go 1.23 made unreferenced timers collectable,
+ * but every iteration still allocates; a single reusable Timer or Ticker is
+ * the idiomatic fix.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+/**
+ * Timer and Ticker Pitfalls
+ *
+ * This example compares allocations of time.After in a hot loop against a
+ * reused Timer, shows the correct way to Reset a Timer, and stops a Ticker so
+ * its resources are released.
+ */
+func TimerTickerPitfallsDemo() {
+	fmt.Fprintln(stdout, "Timer and Ticker Pitfalls")
+
+	const iterations = 100000
+
+	// Both loops receive from an always-ready channel, so the timeout never
+	// fires. This is the typical "read with timeout" loop.
+	ready := make(chan int, 1)
+
+	measure := func(loop func()) (allocs uint64, bytes uint64) {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		loop()
+		runtime.ReadMemStats(&after)
+		return after.Mallocs - before.Mallocs, after.TotalAlloc - before.TotalAlloc
+	}
+
+	// 1. time.After in a loop
+	fmt.Fprintln(stdout, "\n1. time.After inside a loop:")
+	allocs, bytes := measure(func() {
+		for i := 0; i < iterations; i++ {
+			ready <- i
+			select {
+			case <-ready:
+			case <-time.After(time.Minute): // New timer every iteration
+			}
+		}
+	})
+	fmt.Fprintf(stdout, "%d iterations: %d allocations, %d KB allocated\n", iterations, allocs, bytes/1024)
+	fmt.Fprintln(stdout, "Before Go 1.23 every one of those one-minute timers stayed live until it fired.")
+
+	// 2. A single Timer reused with Reset
+	fmt.Fprintln(stdout, "\n2. One Timer reused with Reset:")
+	allocs, bytes = measure(func() {
+		timer := time.NewTimer(time.Minute)
+		defer timer.Stop()
+
+		for i := 0; i < iterations; i++ {
+			ready <- i
+			timer.Reset(time.Minute)
+			select {
+			case <-ready:
+			case <-timer.C:
+			}
+		}
+	})
+	fmt.Fprintf(stdout, "%d iterations: %d allocations, %d KB allocated\n", iterations, allocs, bytes/1024)
+
+	// 3. Correct Reset semantics
+	fmt.Fprintln(stdout, "\n3. Resetting a Timer that already fired:")
+	timer := time.NewTimer(10 * time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // The timer expires but nobody receives
+
+	// Since Go 1.23 (with a go 1.23+ go.mod) Reset and Stop discard a pending
+	// expiry, so no stale value is received after Reset. On older versions
+	// you had to write:
+	//   if !timer.Stop() { <-timer.C }
+	//   timer.Reset(d)
+	start := time.Now()
+	timer.Reset(50 * time.Millisecond)
+	<-timer.C
+	fmt.Fprintf(stdout, "After Reset(50ms) the timer fired after %v (not immediately)\n", time.Since(start).Round(10*time.Millisecond))
+
+	// Stop reports whether it prevented the timer from firing
+	timer.Reset(time.Hour)
+	fmt.Fprintf(stdout, "Stop on a pending timer: %v\n", timer.Stop())
+	fmt.Fprintf(stdout, "Stop on an already stopped timer: %v\n", timer.Stop())
+
+	// 4. Tickers must be stopped
+	fmt.Fprintln(stdout, "\n4. Ticker with Stop:")
+	ticker := time.NewTicker(20 * time.Millisecond)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		defer ticker.Stop() // Release the ticker when the loop exits
+
+		ticks := 0
+		for {
+			select {
+			case <-ticker.C:
+				ticks++
+				fmt.Fprintf(stdout, "Tick %d\n", ticks)
+			case <-done:
+				fmt.Fprintln(stdout, "Ticker loop stopped")
+				return
+			}
+		}
+	}()
+
+	time.Sleep(70 * time.Millisecond)
+	close(done)
+	<-finished
+
+	fmt.Fprintln(stdout, "\nGuidelines:")
+	fmt.Fprintln(stdout, "- time.After is fine for a one-off select; in loops reuse a Timer")
+	fmt.Fprintln(stdout, "- Use time.NewTicker (not time.Tick) and always defer ticker.Stop()")
+	fmt.Fprintln(stdout, "- On Go < 1.23, Stop and drain a Timer's channel before calling Reset")
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,135 @@
+/**
+ * This file demonstrates an Adaptive Worker Pool in Go.
+ *
+ * The worker pool in 27_worker_pool.go has a fixed size. Real workloads are
+ * bursty: a fixed pool is either too small during spikes or wastes resources
+ * when idle. An adaptive pool watches its backlog and grows or shrinks the
+ * number of workers between a minimum and a maximum.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/clock"
+)
+
+/**
+ * Adaptive Worker Pool (Scaling on Queue Depth)
+ *
+ * A monitor goroutine samples the number of pending jobs on a ticker. When the
+ * backlog per worker is high it spawns a worker (up to max); when the queue is
+ * empty it retires one (down to min) by sending on a quit channel that idle
+ * workers select on.
+ */
+func AdaptiveWorkerPoolDemo() {
+	fmt.Fprintln(stdout, "Adaptive Worker Pool (Scaling on Queue Depth)")
+
+	const (
+		minWorkers       = 1
+		maxWorkers       = 8
+		backlogPerWorker = 5 // Scale up when pending jobs per worker exceed this
+		checkInterval    = 20 * time.Millisecond
+		jobDuration      = 15 * time.Millisecond
+	)
+
+	jobs := make(chan int, 200)
+	quit := make(chan struct{}) // Each receive retires exactly one worker
+
+	var (
+		workers   sync.WaitGroup
+		processed atomic.Int64
+		active    int // Only touched by the monitor goroutine
+		nextID    int
+	)
+
+	worker := func(id int) {
+		defer workers.Done()
+		for {
+			select {
+			case _, ok := <-jobs:
+				if !ok {
+					return
+				}
+				clk.Sleep(jobDuration) // Simulate work
+				processed.Add(1)
+			case <-quit:
+				logger.Info("worker retired", "worker", id)
+				return
+			}
+		}
+	}
+
+	spawn := func() {
+		nextID++
+		active++
+		workers.Add(1)
+		go worker(nextID)
+	}
+
+	for i := 0; i < minWorkers; i++ {
+		spawn()
+	}
+
+	// Monitor goroutine making scaling decisions
+	stopMonitor := make(chan struct{})
+	monitorDone := make(chan struct{})
+	go func() {
+		defer close(monitorDone)
+		ticker := clock.NewTicker(clk, checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopMonitor:
+				return
+			case <-ticker.C:
+				pending := len(jobs)
+				switch {
+				case pending > backlogPerWorker*active && active < maxWorkers:
+					spawn()
+					logger.Info("scaled up", "pending", pending, "workers", active)
+				case pending == 0 && active > minWorkers:
+					select {
+					case quit <- struct{}{}:
+						active--
+						logger.Info("scaled down", "pending", pending, "workers", active)
+					default:
+						// Every worker is busy; try again on the next tick
+					}
+				}
+			}
+		}
+	}()
+
+	// Bursty producer: a spike, a quiet period, then a trickle
+	fmt.Fprintln(stdout, "Phase 1: burst of 100 jobs")
+	for i := 0; i < 100; i++ {
+		jobs <- i
+	}
+	for len(jobs) > 0 {
+		clk.Sleep(checkInterval)
+	}
+
+	fmt.Fprintln(stdout, "Phase 2: quiet period")
+	clk.Sleep(300 * time.Millisecond)
+
+	fmt.Fprintln(stdout, "Phase 3: trickle of 10 jobs")
+	for i := 0; i < 10; i++ {
+		jobs <- 100 + i
+		clk.Sleep(30 * time.Millisecond)
+	}
+
+	// Shut down: stop scaling, then let the remaining workers drain the queue
+	close(stopMonitor)
+	<-monitorDone
+	close(jobs)
+	workers.Wait()
+
+	fmt.Fprintf(stdout, "Processed %d jobs; pool ended with %d workers before shutdown\n", processed.Load(), active)
+	fmt.Fprintln(stdout)
+}
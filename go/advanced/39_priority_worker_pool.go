@@ -0,0 +1,180 @@
+/**
+ * This file demonstrates a Priority-aware Worker Pool in Go.
+ *
+ * Building on the priority select (18_priority_select.go) and the nil channel
+ * trick (16_nil_channel_select.go), a dispatcher goroutine buffers jobs from
+ * high, medium and low priority queues and hands the most urgent one to the
+ * next free worker. Aging raises the priority of jobs the longer they wait,
+ * so a steady stream of high-priority work cannot starve everything else.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Job priorities, from most to least urgent
+const (
+	priorityHigh = iota
+	priorityMedium
+	priorityLow
+)
+
+var priorityNames = []string{"high", "medium", "low"}
+
+// priorityJob is a unit of work tagged with its priority and arrival time
+type priorityJob struct {
+	id       int
+	priority int
+	enqueued time.Time
+}
+
+/**
+ * Priority-aware Worker Pool with Aging
+ *
+ * The dispatcher only enables its send case when it has a job to hand out
+ * (otherwise the channel variable is nil and the case is disabled). Each time
+ * it picks the job with the best effective priority, where every agingStep of
+ * waiting promotes a job by one level.
+ */
+func PriorityWorkerPoolDemo() {
+	fmt.Fprintln(stdout, "Priority-aware Worker Pool with Aging")
+
+	const (
+		numWorkers = 2
+		jobTime    = 10 * time.Millisecond
+		agingStep  = 60 * time.Millisecond
+	)
+
+	queues := []chan priorityJob{
+		make(chan priorityJob), // high
+		make(chan priorityJob), // medium
+		make(chan priorityJob), // low
+	}
+	work := make(chan priorityJob)
+
+	// effective returns the aged priority of a job (lower is more urgent)
+	effective := func(j priorityJob, now time.Time) int {
+		return j.priority - int(now.Sub(j.enqueued)/agingStep)
+	}
+
+	// Dispatcher
+	var dispatcher sync.WaitGroup
+	dispatcher.Add(1)
+	go func() {
+		defer dispatcher.Done()
+		defer close(work)
+
+		pending := make([][]priorityJob, len(queues))
+		inputs := append([]chan priorityJob(nil), queues...)
+		open := len(inputs)
+
+		for open > 0 || len(pending[0])+len(pending[1])+len(pending[2]) > 0 {
+			// Choose the most urgent pending job, comparing queue heads only:
+			// within one priority the oldest job is always the most aged
+			now := clk.Now()
+			best := -1
+			for p := range pending {
+				if len(pending[p]) == 0 {
+					continue
+				}
+				if best == -1 || effective(pending[p][0], now) < effective(pending[best][0], now) {
+					best = p
+				}
+			}
+
+			// Nil channel trick: no candidate means the send case is disabled
+			var out chan priorityJob
+			var next priorityJob
+			if best >= 0 {
+				out = work
+				next = pending[best][0]
+			}
+
+			select {
+			case j, ok := <-inputs[priorityHigh]:
+				if !ok {
+					inputs[priorityHigh] = nil
+					open--
+					continue
+				}
+				pending[priorityHigh] = append(pending[priorityHigh], j)
+			case j, ok := <-inputs[priorityMedium]:
+				if !ok {
+					inputs[priorityMedium] = nil
+					open--
+					continue
+				}
+				pending[priorityMedium] = append(pending[priorityMedium], j)
+			case j, ok := <-inputs[priorityLow]:
+				if !ok {
+					inputs[priorityLow] = nil
+					open--
+					continue
+				}
+				pending[priorityLow] = append(pending[priorityLow], j)
+			case out <- next:
+				pending[best] = pending[best][1:]
+			}
+		}
+	}()
+
+	// Workers
+	var mu sync.Mutex
+	waits := make([][]time.Duration, len(queues))
+	var workers sync.WaitGroup
+	for w := 1; w <= numWorkers; w++ {
+		workers.Add(1)
+		go func(w int) {
+			defer workers.Done()
+			log := logger.With("worker", w)
+			for j := range work {
+				wait := clk.Since(j.enqueued)
+				mu.Lock()
+				waits[j.priority] = append(waits[j.priority], wait)
+				mu.Unlock()
+
+				log.Info("processing job", "job", j.id, "priority", priorityNames[j.priority], "waited", wait.Round(time.Millisecond))
+				clk.Sleep(jobTime)
+			}
+		}(w)
+	}
+
+	// Producers: a backlog of low and medium jobs, then a sustained stream of
+	// high-priority jobs that would starve them under strict priority
+	submit := func(id, p int) {
+		queues[p] <- priorityJob{id: id, priority: p, enqueued: clk.Now()}
+	}
+	for i := 1; i <= 4; i++ {
+		submit(i, priorityLow)
+	}
+	for i := 5; i <= 8; i++ {
+		submit(i, priorityMedium)
+	}
+	for i := 9; i <= 40; i++ {
+		submit(i, priorityHigh)
+		clk.Sleep(jobTime / numWorkers) // High-priority work arrives as fast as it is served
+	}
+	for _, q := range queues {
+		close(q)
+	}
+
+	dispatcher.Wait()
+	workers.Wait()
+
+	fmt.Fprintln(stdout, "\nAverage wait per priority:")
+	for p, ws := range waits {
+		var total time.Duration
+		for _, w := range ws {
+			total += w
+		}
+		if len(ws) > 0 {
+			fmt.Fprintf(stdout, "%-6s %2d jobs, avg wait %v\n", priorityNames[p], len(ws), (total / time.Duration(len(ws))).Round(time.Millisecond))
+		}
+	}
+	fmt.Fprintln(stdout, "Without aging the low and medium jobs would wait until the high stream ended.")
+	fmt.Fprintln(stdout)
+}
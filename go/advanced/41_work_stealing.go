@@ -0,0 +1,161 @@
+/**
+ * This file demonstrates a Work-Stealing Scheduler in Go.
+ *
+ * The Go runtime gives every P (processor) its own local run queue. When a P
+ * runs out of goroutines it steals half of another P's queue. This toy
+ * scheduler applies the same idea to tasks: each worker owns a deque, pushes
+ * and pops at the bottom, and idle workers steal from the top of others.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/rng"
+)
+
+// stealTask is a unit of work that may spawn more work on the same worker
+type stealTask func(spawn func(stealTask))
+
+// workDeque is a double-ended queue owned by one worker. The owner works at
+// the bottom (LIFO, good cache locality), thieves take from the top (FIFO,
+// usually the biggest, oldest tasks).
+type workDeque struct {
+	mu    sync.Mutex
+	tasks []stealTask
+}
+
+func (d *workDeque) pushBottom(t stealTask) {
+	d.mu.Lock()
+	d.tasks = append(d.tasks, t)
+	d.mu.Unlock()
+}
+
+func (d *workDeque) popBottom() (stealTask, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.tasks) == 0 {
+		return nil, false
+	}
+	t := d.tasks[len(d.tasks)-1]
+	d.tasks = d.tasks[:len(d.tasks)-1]
+	return t, true
+}
+
+func (d *workDeque) stealTop() (stealTask, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.tasks) == 0 {
+		return nil, false
+	}
+	t := d.tasks[0]
+	d.tasks = d.tasks[1:]
+	return t, true
+}
+
+/**
+ * Work-Stealing Scheduler
+ *
+ * All root tasks are submitted to worker 0, so without stealing one worker
+ * would do everything. Each task recursively splits itself, and the other
+ * workers keep themselves busy by stealing. The demo prints, per worker, how
+ * many tasks came from its own deque versus stolen from others.
+ */
+func WorkStealingDemo() {
+	fmt.Fprintln(stdout, "Work-Stealing Scheduler")
+
+	const numWorkers = 4
+
+	deques := make([]*workDeque, numWorkers)
+	for i := range deques {
+		deques[i] = &workDeque{}
+	}
+
+	var pending atomic.Int64 // Tasks submitted but not yet finished
+	var leaves atomic.Int64
+
+	type workerStats struct {
+		local, stolen, failedSteals int
+		busy                        time.Duration
+	}
+	perWorker := make([]workerStats, numWorkers)
+
+	// split creates a task that divides a range until it is small enough
+	var split func(lo, hi int) stealTask
+	split = func(lo, hi int) stealTask {
+		return func(spawn func(stealTask)) {
+			if hi-lo <= 4 {
+				clk.Sleep(time.Duration(hi-lo) * time.Millisecond) // Leaf work
+				leaves.Add(int64(hi - lo))
+				return
+			}
+			mid := (lo + hi) / 2
+			spawn(split(lo, mid))
+			spawn(split(mid, hi))
+		}
+	}
+
+	// Submit every root task to worker 0
+	for r := 0; r < 4; r++ {
+		pending.Add(1)
+		deques[0].pushBottom(split(r*64, (r+1)*64))
+	}
+
+	var wg sync.WaitGroup
+	start := clk.Now()
+	for id := 0; id < numWorkers; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			own := deques[id]
+			spawn := func(t stealTask) {
+				pending.Add(1)
+				own.pushBottom(t)
+			}
+
+			for pending.Load() > 0 {
+				// 1. Prefer local work
+				task, ok := own.popBottom()
+				if ok {
+					perWorker[id].local++
+				} else {
+					// 2. Otherwise steal from a random victim
+					victim := rng.IntN(numWorkers)
+					if victim != id {
+						task, ok = deques[victim].stealTop()
+					}
+					if ok {
+						perWorker[id].stolen++
+					} else {
+						perWorker[id].failedSteals++
+						clk.Sleep(time.Millisecond) // Back off before trying again
+						continue
+					}
+				}
+
+				t := clk.Now()
+				task(spawn)
+				perWorker[id].busy += clk.Since(t)
+				pending.Add(-1)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	fmt.Fprintf(stdout, "Processed %d leaf items in %v\n\n", leaves.Load(), clk.Since(start).Round(time.Millisecond))
+	fmt.Fprintf(stdout, "%-8s %10s %10s %14s %10s\n", "worker", "local", "stolen", "failed steals", "busy")
+	for id, s := range perWorker {
+		fmt.Fprintf(stdout, "%-8d %10d %10d %14d %10v\n", id, s.local, s.stolen, s.failedSteals, s.busy.Round(time.Millisecond))
+	}
+
+	fmt.Fprintln(stdout, "\nEvery root task started on worker 0, yet all workers ended up busy.")
+	fmt.Fprintln(stdout, "The Go scheduler does the same with goroutines across Ps, which is why")
+	fmt.Fprintln(stdout, "spawning goroutines from a single goroutine still uses every core.")
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,71 @@
+/**
+ * This file demonstrates Batching by Size or Timeout in Go.
+ *
+ * The batch processing example (08_batch_processing.go) only flushes when a
+ * batch is full, so during quiet periods items can wait indefinitely. Real
+ * pipelines flush on whichever comes first: a full batch or a maximum wait.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"time"
+
+	"threads/chanx"
+)
+
+/**
+ * Batching by Size or Timeout
+ *
+ * A producer alternates between bursts and quiet periods. chanx.Batcher
+ * emits full batches during bursts and partial batches when the oldest item
+ * has waited MaxWait, so no item's latency exceeds that bound.
+ */
+func BatchSizeOrTimeoutDemo() {
+	fmt.Fprintln(stdout, "Batching by Size or Timeout")
+
+	type item struct {
+		id      int
+		created time.Time
+	}
+
+	source := make(chan item)
+	go func() {
+		defer close(source)
+		id := 0
+		send := func(n int, gap time.Duration) {
+			for i := 0; i < n; i++ {
+				id++
+				source <- item{id, clk.Now()}
+				clk.Sleep(gap)
+			}
+		}
+
+		send(12, 2*time.Millisecond) // Burst: fills batches quickly
+		send(3, 40*time.Millisecond) // Trickle: batches flushed by timeout
+		send(7, 1*time.Millisecond)  // Burst again
+	}()
+
+	batcher := chanx.Batcher[item]{Size: 5, MaxWait: 50 * time.Millisecond, Clock: clk}
+	start := clk.Now()
+
+	for batch := range batcher.Batch(source) {
+		ids := make([]int, len(batch))
+		var oldest time.Duration
+		for i, it := range batch {
+			ids[i] = it.id
+			oldest = max(oldest, clk.Since(it.created))
+		}
+
+		reason := "size"
+		if len(batch) < batcher.Size {
+			reason = "timeout/close"
+		}
+		fmt.Fprintf(stdout, "t=%4dms flush (%-13s) items=%v oldest waited %v\n",
+			clk.Since(start).Milliseconds(), reason, ids, oldest.Round(time.Millisecond))
+	}
+
+	fmt.Fprintf(stdout, "No item waited much longer than MaxWait=%v\n", batcher.MaxWait)
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,110 @@
+/**
+ * This file demonstrates Tumbling and Sliding Window Aggregation in Go.
+ *
+ * Stream processors summarise unbounded event streams over time windows.
+ * A tumbling window covers fixed, non-overlapping intervals; a sliding window
+ * has a fixed length but advances in smaller steps, so windows overlap.
+ * Both can be driven by a ticker inside a single select loop.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"time"
+
+	"threads/clock"
+	"threads/rng"
+)
+
+// windowAgg is the aggregate of the events that fell into one window
+type windowAgg struct {
+	count int
+	sum   int
+}
+
+func (a *windowAgg) add(v int) {
+	a.count++
+	a.sum += v
+}
+
+/**
+ * Tumbling and Sliding Windows over a Channel
+ *
+ * One goroutine consumes an event channel and maintains a 100ms tumbling
+ * window plus a 200ms sliding window that advances every 50ms. The sliding
+ * window is stored as a ring of 50ms buckets, so each slide only drops the
+ * oldest bucket instead of re-scanning events.
+ */
+func StreamWindowsDemo() {
+	fmt.Fprintln(stdout, "Tumbling and Sliding Window Aggregation")
+
+	const (
+		step         = 50 * time.Millisecond // Sliding window step (bucket width)
+		buckets      = 4                     // Sliding window = 4 * 50ms = 200ms
+		tumblingSize = 2                     // Tumbling window = 2 * 50ms = 100ms
+		duration     = 600 * time.Millisecond
+	)
+
+	// Event source: values arrive at random intervals with a quieter middle phase
+	events := make(chan int)
+	go func() {
+		defer close(events)
+		deadline := clk.Now().Add(duration)
+		for clk.Now().Before(deadline) {
+			events <- rng.IntN(10) + 1
+			gap := time.Duration(rng.IntN(10)+2) * time.Millisecond
+			if time.Until(deadline) < duration*2/3 && time.Until(deadline) > duration/3 {
+				gap *= 4 // Quiet phase
+			}
+			clk.Sleep(gap)
+		}
+	}()
+
+	ticker := clock.NewTicker(clk, step)
+	defer ticker.Stop()
+
+	var (
+		tumbling windowAgg
+		ring     [buckets]windowAgg // ring[cur] is the bucket being filled
+		cur      int
+		ticks    int
+		start    = clk.Now()
+	)
+
+	fmt.Fprintf(stdout, "%-8s %-24s %-24s\n", "t", "tumbling (100ms)", "sliding (200ms/50ms)")
+	for {
+		select {
+		case v, ok := <-events:
+			if !ok {
+				fmt.Fprintln(stdout, "Event stream closed")
+				fmt.Fprintln(stdout)
+				return
+			}
+			tumbling.add(v)
+			ring[cur].add(v)
+
+		case <-ticker.C:
+			ticks++
+			elapsed := clk.Since(start).Round(step)
+
+			// Sliding window: aggregate all buckets, then advance and clear the oldest
+			var sliding windowAgg
+			for _, b := range ring {
+				sliding.count += b.count
+				sliding.sum += b.sum
+			}
+			cur = (cur + 1) % buckets
+			ring[cur] = windowAgg{}
+
+			tumblingCol := ""
+			if ticks%tumblingSize == 0 {
+				tumblingCol = fmt.Sprintf("count=%-3d sum=%-4d", tumbling.count, tumbling.sum)
+				tumbling = windowAgg{}
+			}
+			slidingCol := fmt.Sprintf("count=%-3d sum=%-4d", sliding.count, sliding.sum)
+
+			fmt.Fprintf(stdout, "%-8v %-24s %-24s\n", elapsed, tumblingCol, slidingCol)
+		}
+	}
+}
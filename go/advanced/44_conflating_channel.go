@@ -0,0 +1,114 @@
+/**
+ * This file demonstrates the Conflating Channel Pattern in Go.
+ *
+ * The dropping channel (12_dropping_channel.go) discards new messages when
+ * the buffer is full. A conflating channel does the opposite: it holds at
+ * most one value and every new send replaces the pending one, so a slow
+ * receiver always gets the most recent state and never processes stale data.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"time"
+
+	"threads/clock"
+)
+
+// conflatingChan holds only the latest value sent to it
+type conflatingChan[T any] struct {
+	ch chan T
+}
+
+func newConflatingChan[T any]() *conflatingChan[T] {
+	return &conflatingChan[T]{ch: make(chan T, 1)}
+}
+
+// Send stores v, replacing any value the receiver has not picked up yet.
+// It never blocks.
+func (c *conflatingChan[T]) Send(v T) (replaced bool) {
+	for {
+		select {
+		case c.ch <- v:
+			return replaced
+		default:
+			// Slot occupied: discard the stale value and retry
+			select {
+			case <-c.ch:
+				replaced = true
+			default:
+			}
+		}
+	}
+}
+
+// C returns the channel to receive the latest value from
+func (c *conflatingChan[T]) C() <-chan T {
+	return c.ch
+}
+
+/**
+ * Conflating "Latest Value" Channel
+ *
+ * A sensor publishes a position every millisecond while a renderer only
+ * redraws every 20ms. With conflation the renderer always draws the newest
+ * position and the intermediate updates are skipped instead of queueing up.
+ */
+func ConflatingChannelDemo() {
+	fmt.Fprintln(stdout, "Conflating \"Latest Value\" Channel")
+
+	type position struct {
+		seq  int
+		x, y int
+	}
+
+	latest := newConflatingChan[position]()
+	done := make(chan struct{})
+
+	// Fast producer: many state updates
+	go func() {
+		defer close(done)
+		for i := 1; i <= 100; i++ {
+			latest.Send(position{seq: i, x: i * 2, y: i * 3})
+			clk.Sleep(time.Millisecond)
+		}
+	}()
+
+	// Slow consumer: renders the latest state at its own pace
+	rendered := 0
+	lastSeq := 0
+	ticker := clock.NewTicker(clk, 20*time.Millisecond)
+	defer ticker.Stop()
+
+	render := func(p position) {
+		rendered++
+		fmt.Fprintf(stdout, "Render #%d: position seq=%3d (%d,%d), skipped %d stale updates\n",
+			rendered, p.seq, p.x, p.y, p.seq-lastSeq-1)
+		lastSeq = p.seq
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case p := <-latest.C():
+				render(p)
+			default:
+				// Nothing new since the last frame
+			}
+		case <-done:
+			// Pick up the final state, if it has not been rendered yet
+			select {
+			case p := <-latest.C():
+				render(p)
+			default:
+			}
+			fmt.Fprintf(stdout, "Producer sent 100 updates; renderer drew %d frames, ending at seq %d\n", rendered, lastSeq)
+			fmt.Fprintln(stdout, "Use conflation when only the current state matters (UI, gauges, config);")
+			fmt.Fprintln(stdout, "use dropping or buffering when every message matters.")
+			fmt.Fprintln(stdout)
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/**
+ * This file demonstrates the Sliding Buffer Pattern in Go.
+ *
+ * A sliding buffer is the counterpart of the dropping channel
+ * (12_dropping_channel.go): when it is full, it evicts the oldest buffered
+ * item to make room for the newest instead of rejecting the new one. It is a
+ * natural fit for "last N" data such as recent log lines or metric samples.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/chanx"
+)
+
+/**
+ * Sliding Buffer (Keep Newest N, Drop Oldest)
+ *
+ * This example compares dropping and sliding behaviour for the same burst of
+ * messages, then lets a fast producer feed a slow consumer through a
+ * chanx.SlidingBuffer and checks that the consumer only sees increasing,
+ * recent sequence numbers.
+ */
+func SlidingBufferDemo() {
+	fmt.Fprintln(stdout, "Sliding Buffer (Keep Newest N, Drop Oldest)")
+
+	// 1. Same burst into a dropping channel and a sliding buffer
+	fmt.Fprintln(stdout, "\n1. Sending messages 1..8 into buffers of size 3:")
+
+	dropping := make(chan int, 3)
+	sliding := chanx.NewSlidingBuffer[int](3)
+	for i := 1; i <= 8; i++ {
+		select {
+		case dropping <- i:
+		default: // Dropping pattern: reject the new message
+		}
+		sliding.Send(i)
+	}
+	close(dropping)
+	sliding.Close()
+
+	var kept []int
+	for v := range dropping {
+		kept = append(kept, v)
+	}
+	fmt.Fprintf(stdout, "Dropping channel kept %v (oldest)\n", kept)
+
+	kept = nil
+	for v := range sliding.C() {
+		kept = append(kept, v)
+	}
+	fmt.Fprintf(stdout, "Sliding buffer kept   %v (newest), evicted %d\n", kept, sliding.Evicted())
+
+	// 2. Fast producer, slow consumer
+	fmt.Fprintln(stdout, "\n2. Fast producer, slow consumer through a sliding buffer of size 4:")
+	buf := chanx.NewSlidingBuffer[int](4)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer buf.Close()
+		for i := 1; i <= 200; i++ {
+			buf.Send(i)
+			clk.Sleep(200 * time.Microsecond)
+		}
+	}()
+
+	received, last, ordered := 0, 0, true
+	for v := range buf.C() {
+		if v <= last {
+			ordered = false
+		}
+		last = v
+		received++
+		clk.Sleep(5 * time.Millisecond) // Slow consumer
+	}
+	wg.Wait()
+
+	fmt.Fprintf(stdout, "Received %d of 200 values, last=%d, evicted=%d\n", received, last, buf.Evicted())
+	fmt.Fprintf(stdout, "Values strictly increasing: %v; received+evicted=200: %v\n",
+		ordered, int64(received)+buf.Evicted() == 200)
+	fmt.Fprintln(stdout)
+}
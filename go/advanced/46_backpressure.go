@@ -0,0 +1,144 @@
+/**
+ * This file demonstrates Backpressure Propagation in a Pipeline in Go.
+ *
+ * When the last stage of a pipeline is slow, its input channel fills up and
+ * the stage before it blocks on send. That stage then stops receiving, so its
+ * own input fills up, and so on: backpressure travels upstream until it
+ * throttles the source. Buffers delay this effect but do not prevent it.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/clock"
+	"threads/topology"
+)
+
+/**
+ * Backpressure Propagation (Unbuffered vs Buffered Links)
+ *
+ * A four-stage pipeline (source -> parse -> enrich -> slow sink) is run twice,
+ * once with unbuffered links and once with buffered ones. Every stage records
+ * how long it was blocked sending downstream, and a monitor prints the
+ * occupancy of each link as the pipeline runs.
+ */
+func BackpressureDemo() {
+	fmt.Fprintln(stdout, "Backpressure Propagation (Unbuffered vs Buffered Links)")
+
+	for _, capacity := range []int{0, 4} {
+		fmt.Fprintf(stdout, "\nLinks with capacity %d:\n", capacity)
+		runBackpressurePipeline(capacity)
+	}
+
+	fmt.Fprintln(stdout, "\nObservations:")
+	fmt.Fprintln(stdout, "- Unbuffered: every upstream stage spends most of its time blocked, in lockstep with the sink")
+	fmt.Fprintln(stdout, "- Buffered: links fill from the sink backwards; the stage nearest the sink blocks first")
+	fmt.Fprintln(stdout, "  and longest, stages further upstream only once the buffers in between are full")
+	fmt.Fprintln(stdout, "- Throughput is set by the slowest stage; buffers only absorb bursts")
+	fmt.Fprintln(stdout)
+}
+
+// runBackpressurePipeline runs the pipeline with links of the given capacity
+func runBackpressurePipeline(capacity int) {
+	const items = 20
+
+	names := []string{"source", "parse", "enrich"}
+	links := []chan int{make(chan int, capacity), make(chan int, capacity), make(chan int, capacity)}
+	blocked := make([]atomic.Int64, len(names)) // Nanoseconds spent blocked on send
+
+	// send records how long a stage waited for downstream capacity
+	send := func(stage int, v int) {
+		start := clk.Now()
+		links[stage] <- v
+		blocked[stage].Add(int64(clk.Since(start)))
+	}
+
+	var wg sync.WaitGroup
+	start := clk.Now()
+
+	// Source
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(links[0])
+		for i := 1; i <= items; i++ {
+			send(0, i)
+		}
+	}()
+
+	// Intermediate stages: fast work, then forward downstream
+	for stage := 1; stage < len(names); stage++ {
+		wg.Add(1)
+		go func(stage int) {
+			defer wg.Done()
+			defer close(links[stage])
+			for v := range links[stage-1] {
+				clk.Sleep(time.Millisecond)
+				send(stage, v)
+			}
+		}(stage)
+	}
+
+	// Monitor printing link occupancy
+	stopMonitor := make(chan struct{})
+	monitorDone := make(chan struct{})
+	go func() {
+		defer close(monitorDone)
+		ticker := clock.NewTicker(clk, 40*time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopMonitor:
+				return
+			case <-ticker.C:
+				var cols []string
+				for i, l := range links {
+					cols = append(cols, fmt.Sprintf("%s->%d/%d", names[i], len(l), cap(l)))
+				}
+				fmt.Fprintf(stdout, "  t=%3dms %s\n", clk.Since(start).Milliseconds(), strings.Join(cols, "  "))
+			}
+		}
+	}()
+
+	// Slow sink
+	consumed := 0
+	for range links[len(links)-1] {
+		clk.Sleep(10 * time.Millisecond)
+		consumed++
+	}
+	wg.Wait()
+	close(stopMonitor)
+	<-monitorDone
+
+	fmt.Fprintf(stdout, "  Sink consumed %d items in %v\n", consumed, clk.Since(start).Round(time.Millisecond))
+	for i, name := range names {
+		fmt.Fprintf(stdout, "  %-7s blocked on send for %v\n", name, time.Duration(blocked[i].Load()).Round(time.Millisecond))
+	}
+}
+
+// BackpressureTopology describes the goroutines and channels of the demo
+func BackpressureTopology() *topology.Graph {
+	return topology.New("Backpressure Propagation").
+		Goroutine("source", "source").
+		Channel("l0", "link 0").
+		Goroutine("parse", "parse").
+		Channel("l1", "link 1").
+		Goroutine("enrich", "enrich").
+		Channel("l2", "link 2").
+		Goroutine("sink", "slow sink").
+		Flow("source", "l0", "").
+		Flow("l0", "parse", "").
+		Flow("parse", "l1", "").
+		Flow("l1", "enrich", "").
+		Flow("enrich", "l2", "").
+		Flow("l2", "sink", "").
+		Flow("sink", "enrich", "backpressure").
+		Flow("enrich", "parse", "backpressure").
+		Flow("parse", "source", "backpressure")
+}
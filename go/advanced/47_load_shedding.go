@@ -0,0 +1,121 @@
+/**
+ * This file demonstrates Load Shedding and Admission Control in Go.
+ *
+ * A server that queues every request it cannot handle immediately looks
+ * healthy until the queue grows so long that every response is too late to be
+ * useful. Load shedding rejects work early (an HTTP 503) once a concurrency
+ * or queue-length limit is reached, keeping latency bounded for the requests
+ * that are accepted.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// shedRequest is a request submitted to the simulated server
+type shedRequest struct {
+	id       int
+	received time.Time
+	reply    chan int // HTTP-like status code
+}
+
+/**
+ * Load Shedding with a Bounded Queue
+ *
+ * The same overload (requests arriving twice as fast as they can be served)
+ * is sent to a server with an unbounded backlog and to one that admits at
+ * most maxQueue waiting requests, answering 503 immediately when full.
+ */
+func LoadSheddingDemo() {
+	fmt.Fprintln(stdout, "Load Shedding and Admission Control")
+
+	const (
+		workers      = 3
+		serviceTime  = 20 * time.Millisecond
+		requests     = 60
+		arrivalEvery = serviceTime / workers / 2 // 2x overload
+	)
+
+	for _, maxQueue := range []int{-1, 5} {
+		if maxQueue < 0 {
+			fmt.Fprintln(stdout, "\nUnbounded queue (accept everything):")
+		} else {
+			fmt.Fprintf(stdout, "\nAdmission control (%d workers, queue limit %d):\n", workers, maxQueue)
+		}
+
+		queueCap := requests // Large enough to never reject
+		if maxQueue >= 0 {
+			queueCap = maxQueue
+		}
+		queue := make(chan shedRequest, queueCap)
+
+		// Workers serve requests from the queue
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for req := range queue {
+					clk.Sleep(serviceTime)
+					req.reply <- 200
+				}
+			}()
+		}
+
+		// submit is the admission check: never block the accept loop
+		submit := func(req shedRequest) {
+			select {
+			case queue <- req:
+			default:
+				req.reply <- 503 // Shed: fail fast instead of queueing
+			}
+		}
+
+		// Clients
+		var mu sync.Mutex
+		var latencies []time.Duration
+		accepted, shed := 0, 0
+
+		var clients sync.WaitGroup
+		for i := 1; i <= requests; i++ {
+			req := shedRequest{id: i, received: clk.Now(), reply: make(chan int, 1)}
+			submit(req)
+
+			clients.Add(1)
+			go func() {
+				defer clients.Done()
+				status := <-req.reply
+				mu.Lock()
+				defer mu.Unlock()
+				if status == 200 {
+					accepted++
+					latencies = append(latencies, clk.Since(req.received))
+				} else {
+					shed++
+				}
+			}()
+			clk.Sleep(arrivalEvery)
+		}
+
+		clients.Wait()
+		close(queue)
+		wg.Wait()
+
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		p50 := latencies[len(latencies)/2]
+		p99 := latencies[len(latencies)*99/100]
+		fmt.Fprintf(stdout, "accepted=%d shed(503)=%d  latency p50=%v p99=%v max=%v\n",
+			accepted, shed, p50.Round(time.Millisecond), p99.Round(time.Millisecond),
+			latencies[len(latencies)-1].Round(time.Millisecond))
+	}
+
+	fmt.Fprintln(stdout, "\nShedding trades a fast, explicit failure for some requests against")
+	fmt.Fprintln(stdout, "unbounded latency for all of them. Clients can retry with backoff or")
+	fmt.Fprintln(stdout, "be routed elsewhere, while accepted requests still meet their deadlines.")
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,173 @@
+/**
+ * This file demonstrates a Channel-based Load Balancer in Go.
+ *
+ * A balancer goroutine receives requests and forwards each one to a worker's
+ * private channel. Round-robin is simple but ignores how busy workers are;
+ * least-pending needs feedback from workers but adapts to uneven work. This
+ * is the structure from Rob Pike's "Concurrency is not Parallelism" talk.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"threads/topology"
+)
+
+// lbRequest is a unit of work routed by the balancer
+type lbRequest struct {
+	sent time.Time
+}
+
+/**
+ * Load Balancer: Round-robin vs Least-pending
+ *
+ * Four workers serve requests, but one of them is four times slower than the
+ * others. The same request stream is balanced round-robin and by fewest
+ * pending requests (workers report completions back to the balancer), and the
+ * latency distributions are compared.
+ */
+func LoadBalancerDemo() {
+	fmt.Fprintln(stdout, "Load Balancer: Round-robin vs Least-pending")
+
+	strategies := []struct {
+		name string
+		pick func(next int, pending []int) int
+	}{
+		{"round-robin", func(next int, pending []int) int {
+			return next % len(pending)
+		}},
+		{"least-pending", func(next int, pending []int) int {
+			best := 0
+			for i, p := range pending {
+				if p < pending[best] {
+					best = i
+				}
+			}
+			return best
+		}},
+	}
+
+	for _, s := range strategies {
+		latencies, perWorker := runLoadBalancer(s.pick)
+
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		pct := func(p int) time.Duration {
+			return latencies[len(latencies)*p/100].Round(time.Millisecond)
+		}
+		fmt.Fprintf(stdout, "\n%s:\n", s.name)
+		fmt.Fprintf(stdout, "  latency p50=%v p90=%v p99=%v max=%v\n",
+			pct(50), pct(90), pct(99), latencies[len(latencies)-1].Round(time.Millisecond))
+		fmt.Fprintf(stdout, "  requests per worker (worker 0 is slow): %v\n", perWorker)
+	}
+
+	fmt.Fprintln(stdout, "\nLeast-pending routes around the slow worker, cutting tail latency.")
+	fmt.Fprintln(stdout)
+}
+
+// runLoadBalancer sends a stream of requests through a balancer that uses
+// pick to choose a worker, and returns request latencies and per-worker counts.
+func runLoadBalancer(pick func(next int, pending []int) int) ([]time.Duration, []int) {
+	const (
+		numWorkers = 4
+		requests   = 80
+		baseWork   = 4 * time.Millisecond
+	)
+
+	incoming := make(chan lbRequest)
+	completed := make(chan int, numWorkers) // Workers report their index when done
+
+	var mu sync.Mutex
+	var latencies []time.Duration
+	perWorker := make([]int, numWorkers)
+
+	// Workers, each with its own queue
+	var workers sync.WaitGroup
+	queues := make([]chan lbRequest, numWorkers)
+	for w := range queues {
+		queues[w] = make(chan lbRequest, requests)
+		workers.Add(1)
+		go func(w int) {
+			defer workers.Done()
+			work := baseWork
+			if w == 0 {
+				work *= 4 // Skew: one slow worker
+			}
+			for req := range queues[w] {
+				clk.Sleep(work)
+				mu.Lock()
+				latencies = append(latencies, clk.Since(req.sent))
+				perWorker[w]++
+				mu.Unlock()
+				completed <- w
+			}
+		}(w)
+	}
+
+	// Balancer: the only goroutine that reads or writes pending
+	balancerDone := make(chan struct{})
+	go func() {
+		defer close(balancerDone)
+		pending := make([]int, numWorkers)
+		next := 0
+		in := incoming
+		outstanding := 0
+
+		for in != nil || outstanding > 0 {
+			select {
+			case req, ok := <-in:
+				if !ok {
+					in = nil // Disable this case, keep collecting completions
+					continue
+				}
+				w := pick(next, pending)
+				next++
+				pending[w]++
+				outstanding++
+				queues[w] <- req
+			case w := <-completed:
+				pending[w]--
+				outstanding--
+			}
+		}
+		for _, q := range queues {
+			close(q)
+		}
+	}()
+
+	// Request stream slightly below total capacity
+	for i := 0; i < requests; i++ {
+		incoming <- lbRequest{sent: clk.Now()}
+		clk.Sleep(baseWork / 3)
+	}
+	close(incoming)
+
+	<-balancerDone
+	workers.Wait()
+	return latencies, perWorker
+}
+
+// LoadBalancerTopology describes the goroutines and channels of the demo
+func LoadBalancerTopology() *topology.Graph {
+	g := topology.New("Load Balancer").
+		Goroutine("clients", "request stream").
+		Channel("incoming", "incoming").
+		Goroutine("balancer", "balancer (owns pending counts)").
+		Channel("completed", "completed").
+		Flow("clients", "incoming", "").
+		Flow("incoming", "balancer", "").
+		Flow("completed", "balancer", "worker index")
+	for w := 0; w < 4; w++ {
+		q, worker := fmt.Sprintf("q%d", w), fmt.Sprintf("worker%d", w)
+		g.Channel(q, fmt.Sprintf("queue %d", w)).
+			Goroutine(worker, fmt.Sprintf("worker %d", w)).
+			Flow("balancer", q, "").
+			Flow(q, worker, "").
+			Flow(worker, "completed", "")
+	}
+	return g
+}
@@ -0,0 +1,118 @@
+/**
+ * This file demonstrates the Supervisor Pattern in Go.
+ *
+ * Erlang/OTP popularised supervision trees: a supervisor starts workers,
+ * notices when they crash and restarts them according to a policy. In Go a
+ * panic in a goroutine crashes the whole program unless that goroutine
+ * recovers it, so a supervisor wraps each worker run in recover and decides
+ * whether to restart, back off or give up.
+ */
+
+package advanced
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// restartPolicy controls how a supervisor reacts to worker failures
+type restartPolicy struct {
+	maxRestarts    int           // Give up when a further failure would exceed this many restarts
+	initialBackoff time.Duration // Delay before the first restart
+	maxBackoff     time.Duration // Upper bound for the exponential backoff
+}
+
+// errPanicked wraps a value recovered from a worker panic
+var errPanicked = errors.New("worker panicked")
+
+/**
+ * Supervisor with Restart-on-Panic and Backoff
+ *
+ * The supervisor runs a worker function, converting panics into errors. Each
+ * failure triggers a restart after an exponentially growing backoff, and a
+ * clean return ends supervision. After too many failures the supervisor gives
+ * up and reports the last error.
+ */
+func SupervisorDemo() {
+	fmt.Fprintln(stdout, "Supervisor with Restart-on-Panic and Backoff")
+
+	policy := restartPolicy{maxRestarts: 3, initialBackoff: 10 * time.Millisecond, maxBackoff: 80 * time.Millisecond}
+
+	// 1. A flaky worker that recovers after a couple of crashes
+	fmt.Fprintln(stdout, "\n1. Flaky worker:")
+	attempts := 0
+	err := supervise("flaky", policy, func() error {
+		attempts++
+		if attempts <= 2 {
+			panic(fmt.Sprintf("simulated crash #%d", attempts))
+		}
+		fmt.Fprintf(stdout, "  [flaky] attempt %d processed its work successfully\n", attempts)
+		return nil
+	})
+	fmt.Fprintf(stdout, "Supervisor result: %v\n", err)
+
+	// 2. A worker that always fails: the supervisor gives up
+	fmt.Fprintln(stdout, "\n2. Broken worker:")
+	err = supervise("broken", policy, func() error {
+		var m map[string]int
+		m["boom"]++ // Nil map write: runtime panic
+		return nil
+	})
+	fmt.Fprintf(stdout, "Supervisor result: %v\n", err)
+	fmt.Fprintf(stdout, "Caused by a panic: %v\n", errors.Is(err, errPanicked))
+
+	// 3. Errors are failures too, not only panics
+	fmt.Fprintln(stdout, "\n3. Worker returning errors:")
+	calls := 0
+	err = supervise("erroring", policy, func() error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("connection refused (call %d)", calls)
+		}
+		fmt.Fprintln(stdout, "  [erroring] connected")
+		return nil
+	})
+	fmt.Fprintf(stdout, "Supervisor result: %v\n", err)
+	fmt.Fprintln(stdout)
+}
+
+// supervise runs work in its own goroutine, restarting it after failures
+// according to policy. It returns nil once work completes successfully, or
+// the last error once maxRestarts restarts have been used up.
+func supervise(name string, policy restartPolicy, work func() error) error {
+	backoff := policy.initialBackoff
+
+	for failures := 0; ; {
+		err := runSupervised(work)
+		if err == nil {
+			return nil
+		}
+
+		failures++
+		fmt.Fprintf(stdout, "  [%s] failed (%d/%d): %v\n", name, failures, policy.maxRestarts+1, err)
+		if failures > policy.maxRestarts {
+			return fmt.Errorf("%s: giving up after %d failures: %w", name, failures, err)
+		}
+
+		fmt.Fprintf(stdout, "  [%s] restarting in %v\n", name, backoff)
+		clk.Sleep(backoff)
+		backoff = min(backoff*2, policy.maxBackoff)
+	}
+}
+
+// runSupervised runs work in a new goroutine and turns a panic into an error
+func runSupervised(work func() error) error {
+	result := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("%w: %v", errPanicked, r)
+			}
+		}()
+		result <- work()
+	}()
+
+	return <-result
+}
@@ -0,0 +1,177 @@
+/**
+ * This file demonstrates Structured Concurrency in Go.
+ *
+ * A plain `go` statement starts a goroutine that is not tied to the function
+ * that started it: the function can return while the goroutine keeps running,
+ * its errors have nowhere to go and nothing waits for it. Structured
+ * concurrency (the "nursery" from Python's Trio) binds goroutines to a lexical
+ * scope: the scope does not exit until every child has finished, child errors
+ * are collected, and one failing child cancels its siblings.
+ */
+
+package advanced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errNurseryClosed is the panic value when Go is called on a finished scope
+var errNurseryClosed = errors.New("nursery: Go called after the scope exited")
+
+// nursery owns the goroutines started inside one withNursery call
+type nursery struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	closed atomic.Bool
+
+	mu   sync.Mutex
+	errs []error
+}
+
+/**
+ * Structured Concurrency with a Nursery Scope
+ *
+ * The example contrasts a free-floating goroutine that outlives its function
+ * with a nursery scope that waits for its children, aggregates their errors,
+ * cancels siblings on the first failure and refuses to start goroutines once
+ * it has exited.
+ */
+func StructuredConcurrencyDemo() {
+	fmt.Fprintln(stdout, "Structured Concurrency with a Nursery Scope")
+
+	// 1. Free-floating goroutines outlive the function that started them
+	fmt.Fprintln(stdout, "\n1. Plain go statement:")
+	baseline := runtime.NumGoroutine()
+	release, detachedDone := make(chan struct{}), make(chan struct{})
+	startDetached := func() {
+		go func() {
+			defer close(detachedDone)
+			<-release // Still running after startDetached returns
+		}()
+	}
+	startDetached()
+	fmt.Fprintf(stdout, "startDetached returned, goroutines above baseline: %d\n", runtime.NumGoroutine()-baseline)
+	close(release)
+	<-detachedDone // Nothing would wait for it unless we add this by hand
+
+	// 2. A nursery waits for all of its children
+	fmt.Fprintln(stdout, "\n2. Nursery waits for its children:")
+	baseline = runtime.NumGoroutine()
+	var finished atomic.Int32
+	err := withNursery(context.Background(), func(n *nursery) error {
+		for i := 1; i <= 3; i++ {
+			n.Go(func(ctx context.Context) error {
+				clk.Sleep(time.Duration(i) * 20 * time.Millisecond)
+				finished.Add(1)
+				return nil
+			})
+		}
+		return nil
+	})
+	fmt.Fprintf(stdout, "Scope exited with err=%v after %d/3 children finished, goroutines above baseline: %d\n",
+		err, finished.Load(), runtime.NumGoroutine()-baseline)
+
+	// 3. The first failure cancels the siblings; all errors are returned
+	fmt.Fprintln(stdout, "\n3. Failure cancels siblings and errors are aggregated:")
+	start := clk.Now()
+	err = withNursery(context.Background(), func(n *nursery) error {
+		n.Go(func(ctx context.Context) error {
+			clk.Sleep(30 * time.Millisecond)
+			return errors.New("fetch users: connection reset")
+		})
+		n.Go(func(ctx context.Context) error {
+			panic("parse orders: unexpected EOF")
+		})
+		n.Go(func(ctx context.Context) error {
+			timer := clk.NewTimer(time.Second)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return nil
+			case <-ctx.Done():
+				fmt.Fprintf(stdout, "  slow child cancelled: %v\n", context.Cause(ctx))
+				return nil
+			}
+		})
+		return nil
+	})
+	fmt.Fprintf(stdout, "Scope exited after %v with:\n%v\n", clk.Since(start).Round(10*time.Millisecond), err)
+
+	// 4. Nested scopes and escape attempts
+	fmt.Fprintln(stdout, "\n4. Goroutines cannot leak past the scope:")
+	var escaped *nursery
+	_ = withNursery(context.Background(), func(outer *nursery) error {
+		outer.Go(func(ctx context.Context) error {
+			// A child can open its own scope; it finishes before the child does
+			return withNursery(ctx, func(inner *nursery) error {
+				inner.Go(func(ctx context.Context) error { return nil })
+				return nil
+			})
+		})
+		escaped = outer
+		return nil
+	})
+	func() {
+		defer func() {
+			fmt.Fprintf(stdout, "Starting a goroutine on an exited scope: %v\n", recover())
+		}()
+		escaped.Go(func(ctx context.Context) error { return nil })
+	}()
+	fmt.Fprintln(stdout)
+}
+
+// withNursery runs body with a new nursery and returns only after every
+// goroutine started in it has returned. The first error (or panic) from body or
+// a child cancels the nursery's context; all errors are joined and returned.
+func withNursery(ctx context.Context, body func(n *nursery) error) error {
+	n := &nursery{}
+	n.ctx, n.cancel = context.WithCancelCause(ctx)
+	defer n.cancel(nil)
+
+	n.run(func() error { return body(n) })
+	n.wg.Wait()
+	n.closed.Store(true)
+
+	return errors.Join(n.errs...)
+}
+
+// Go starts fn in a goroutine owned by the nursery. It panics if the scope
+// has already exited, so children cannot be started after the wait.
+func (n *nursery) Go(fn func(ctx context.Context) error) {
+	if n.closed.Load() {
+		panic(errNurseryClosed)
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.run(func() error { return fn(n.ctx) })
+	}()
+}
+
+// run calls fn, recording its error or panic and cancelling the siblings
+func (n *nursery) run(fn func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			n.fail(fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	if err := fn(); err != nil {
+		n.fail(err)
+	}
+}
+
+func (n *nursery) fail(err error) {
+	n.mu.Lock()
+	n.errs = append(n.errs, err)
+	n.mu.Unlock()
+	n.cancel(err)
+}
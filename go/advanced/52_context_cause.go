@@ -0,0 +1,126 @@
+/**
+ * This file demonstrates Cancellation Causes in Go.
+ *
+ * A closed done channel (see 09_cancellation_pattern.go) tells a worker that
+ * it should stop, but not why. context.WithCancelCause and context.Cause
+ * (Go 1.20) attach an error to the cancellation, and WithTimeoutCause /
+ * WithDeadlineCause (Go 1.21) do the same for deadlines, so a worker can tell
+ * a shutdown from a timeout from a failed upstream dependency.
+ */
+
+package advanced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"threads/clock"
+)
+
+var (
+	errShutdown        = errors.New("server shutting down")
+	errRequestTimedOut = errors.New("request exceeded its 50ms budget")
+)
+
+/**
+ * Context Cancellation with Causes
+ *
+ * The same worker is cancelled in three different ways: a shutdown signal, a
+ * timeout and a failing sibling. ctx.Err() only reports Canceled or
+ * DeadlineExceeded, while context.Cause reports the reason, which the worker
+ * uses to decide how to react.
+ */
+func ContextCauseDemo() {
+	fmt.Fprintln(stdout, "Context Cancellation with Causes")
+
+	// 1. A done channel carries no reason
+	fmt.Fprintln(stdout, "\n1. Done channel:")
+	done := make(chan struct{})
+	result := make(chan string)
+	go func() {
+		<-done
+		result <- "stopped, reason unknown"
+	}()
+	close(done)
+	fmt.Fprintf(stdout, "Worker: %s\n", <-result)
+
+	// 2. Shutdown: the caller cancels with a sentinel cause
+	fmt.Fprintln(stdout, "\n2. Shutdown:")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	stopped := startCauseWorker(ctx, "worker-1")
+	clk.Sleep(20 * time.Millisecond)
+	cancel(errShutdown)
+	<-stopped
+
+	// 3. Timeout with a custom cause instead of a bare DeadlineExceeded
+	// clock.WithTimeoutCause is context.WithTimeoutCause on the examples' clock
+	fmt.Fprintln(stdout, "\n3. Timeout:")
+	ctx, cancelTimeout := clock.WithTimeoutCause(context.Background(), clk, 50*time.Millisecond, errRequestTimedOut)
+	<-startCauseWorker(ctx, "worker-2")
+	cancelTimeout()
+
+	// 4. An upstream failure cancels the siblings with the failure as cause
+	fmt.Fprintln(stdout, "\n4. Upstream error:")
+	ctx, cancel = context.WithCancelCause(context.Background())
+	stopped = startCauseWorker(ctx, "worker-3")
+	go func() {
+		clk.Sleep(30 * time.Millisecond)
+		err := fmt.Errorf("inventory service: %w", errors.New("503 Service Unavailable"))
+		cancel(err) // Only the first cause is kept; later calls are no-ops
+		cancel(errShutdown)
+	}()
+	<-stopped
+
+	// 5. Causes propagate to derived contexts
+	fmt.Fprintln(stdout, "\n5. Derived contexts:")
+	parent, cancelParent := context.WithCancelCause(context.Background())
+	child, cancelChild := context.WithTimeout(parent, time.Hour)
+	defer cancelChild()
+	cancelParent(errShutdown)
+	<-child.Done()
+	fmt.Fprintf(stdout, "child.Err() = %v, context.Cause(child) = %v\n", child.Err(), context.Cause(child))
+	fmt.Fprintf(stdout, "Cause of a context that is not cancelled: %v\n", context.Cause(context.Background()))
+	fmt.Fprintln(stdout)
+}
+
+// startCauseWorker processes ticks until ctx is cancelled and then reports
+// how it reacts to the cancellation cause. The returned channel is closed
+// once the worker has stopped.
+func startCauseWorker(ctx context.Context, name string) <-chan struct{} {
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := clock.NewTicker(clk, 10*time.Millisecond)
+		defer ticker.Stop()
+
+		processed := 0
+		for {
+			select {
+			case <-ticker.C:
+				processed++
+				continue
+			case <-ctx.Done():
+			}
+
+			cause := context.Cause(ctx)
+			fmt.Fprintf(stdout, "  [%s] processed %d items; ctx.Err() = %v\n", name, processed, ctx.Err())
+			fmt.Fprintf(stdout, "  [%s] cause: %v\n", name, cause)
+
+			switch {
+			case errors.Is(cause, errShutdown):
+				fmt.Fprintf(stdout, "  [%s] flushing state before exit\n", name)
+			case errors.Is(cause, errRequestTimedOut):
+				fmt.Fprintf(stdout, "  [%s] returning partial results to the client\n", name)
+			default:
+				fmt.Fprintf(stdout, "  [%s] aborting: dependency failed\n", name)
+			}
+			return
+		}
+	}()
+
+	return stopped
+}
@@ -0,0 +1,123 @@
+/**
+ * This file demonstrates context.AfterFunc and context.WithoutCancel in Go.
+ *
+ * Go 1.21 added two context helpers. AfterFunc registers a function that runs
+ * in its own goroutine once a context is done, which is handy for cleanup and
+ * for interrupting calls that do not accept a context. WithoutCancel derives a
+ * context that keeps the parent's values but ignores its cancellation, for
+ * work that must outlive the request that started it.
+ */
+
+package advanced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// requestIDKey is the context key for the demo's request IDs
+type requestIDKey struct{}
+
+/**
+ * context.AfterFunc and context.WithoutCancel
+ *
+ * The example uses AfterFunc to run cleanup on cancellation and to unblock a
+ * read on a connection that knows nothing about contexts, shows how the stop
+ * function deregisters a callback, and uses WithoutCancel to let an audit log
+ * write finish after its request has been cancelled.
+ */
+func ContextAfterFuncDemo() {
+	fmt.Fprintln(stdout, "context.AfterFunc and context.WithoutCancel")
+
+	// 1. Cleanup triggered by cancellation
+	fmt.Fprintln(stdout, "\n1. AfterFunc cleanup:")
+	ctx, cancel := context.WithCancel(context.Background())
+	cleaned := make(chan struct{})
+	context.AfterFunc(ctx, func() {
+		fmt.Fprintln(stdout, "  AfterFunc: releasing session resources")
+		close(cleaned)
+	})
+	fmt.Fprintln(stdout, "Cancelling the context...")
+	cancel()
+	<-cleaned // AfterFunc runs in its own goroutine, so wait for it
+
+	// 2. Interrupting a blocking call that does not take a context
+	fmt.Fprintln(stdout, "\n2. Unblocking a read with AfterFunc:")
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	ctx, cancelRead := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelRead()
+	stop := context.AfterFunc(ctx, func() {
+		// A deadline in the past makes the pending Read return immediately
+		client.SetReadDeadline(time.Now())
+	})
+
+	start := time.Now()
+	_, err := client.Read(make([]byte, 1)) // Nobody writes to server
+	fmt.Fprintf(stdout, "Read returned after %v: timeout=%v\n",
+		time.Since(start).Round(10*time.Millisecond), isTimeout(err))
+	fmt.Fprintf(stdout, "stop() after the callback ran: %v\n", stop())
+
+	// 3. Deregistering a callback before the context is done
+	fmt.Fprintln(stdout, "\n3. Stopping an AfterFunc:")
+	ctx, cancel = context.WithCancel(context.Background())
+	stop = context.AfterFunc(ctx, func() {
+		fmt.Fprintln(stdout, "  never printed")
+	})
+	fmt.Fprintf(stdout, "stop() before cancellation: %v\n", stop())
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	fmt.Fprintln(stdout, "Context cancelled, callback did not run")
+
+	// 4. Background work that outlives the request
+	fmt.Fprintln(stdout, "\n4. WithoutCancel for audit logging:")
+	reqCtx, cancelReq := context.WithCancel(context.WithValue(context.Background(), requestIDKey{}, "req-42"))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		writeAuditLog(reqCtx, "attached")
+	}()
+	go func() {
+		defer wg.Done()
+		// Detach from the request, but bound the work with its own timeout
+		detached, cancelDetached := context.WithTimeout(context.WithoutCancel(reqCtx), time.Second)
+		defer cancelDetached()
+		writeAuditLog(detached, "detached")
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	fmt.Fprintln(stdout, "Client disconnected, request context cancelled")
+	cancelReq()
+	wg.Wait()
+
+	detached := context.WithoutCancel(reqCtx)
+	fmt.Fprintf(stdout, "reqCtx.Err() = %v, detached.Err() = %v, detached.Done() == nil: %v\n",
+		reqCtx.Err(), detached.Err(), detached.Done() == nil)
+	fmt.Fprintln(stdout)
+}
+
+// writeAuditLog simulates a slow write that gives up when ctx is done
+func writeAuditLog(ctx context.Context, label string) {
+	id := ctx.Value(requestIDKey{})
+
+	select {
+	case <-time.After(50 * time.Millisecond):
+		fmt.Fprintf(stdout, "  [%s] audit entry for %v written\n", label, id)
+	case <-ctx.Done():
+		fmt.Fprintf(stdout, "  [%s] audit entry for %v lost: %v\n", label, id, ctx.Err())
+	}
+}
+
+// isTimeout reports whether err is a network timeout
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
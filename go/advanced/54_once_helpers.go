@@ -0,0 +1,111 @@
+/**
+ * This file demonstrates sync.OnceFunc, sync.OnceValue and sync.OnceValues.
+ *
+ * Go 1.21 wrapped the sync.Once pattern from 22_sync_once.go in helpers that
+ * return a function. OnceValue and OnceValues remember the result of the
+ * initialisation, so there is no separate variable to share, and all three
+ * helpers re-panic on every call if the wrapped function panicked, where a
+ * plain sync.Once silently treats the panicking call as done.
+ */
+
+package advanced
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+/**
+ * sync.OnceFunc, OnceValue and OnceValues
+ *
+ * The example lazily loads a value from many goroutines with OnceValue,
+ * caches a result together with its error with OnceValues, makes a cleanup
+ * function idempotent with OnceFunc, and compares what happens after a panic
+ * with the helpers and with sync.Once.
+ */
+func OnceHelpersDemo() {
+	fmt.Fprintln(stdout, "sync.OnceFunc, OnceValue and OnceValues")
+
+	// 1. Lazy initialisation returning a value
+	fmt.Fprintln(stdout, "\n1. OnceValue:")
+	var loads atomic.Int32
+	loadLimits := sync.OnceValue(func() map[string]int {
+		loads.Add(1)
+		fmt.Fprintln(stdout, "  loading limits...")
+		return map[string]int{"requests": 100, "burst": 20}
+	})
+
+	var wg sync.WaitGroup
+	var total atomic.Int64
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			total.Add(int64(loadLimits()["requests"]))
+		}()
+	}
+	wg.Wait()
+	fmt.Fprintf(stdout, "10 goroutines read the limits, loader ran %d time(s), sum of reads: %d\n", loads.Load(), total.Load())
+
+	// 2. Value and error are both cached
+	fmt.Fprintln(stdout, "\n2. OnceValues:")
+	attempts := 0
+	connect := sync.OnceValues(func() (string, error) {
+		attempts++
+		return "", fmt.Errorf("dial db (attempt %d): %w", attempts, errors.New("connection refused"))
+	})
+	for i := 0; i < 3; i++ {
+		_, err := connect()
+		fmt.Fprintf(stdout, "Call %d: %v\n", i+1, err)
+	}
+	fmt.Fprintln(stdout, "The error is cached too: OnceValues is not a retry mechanism")
+
+	// 3. Idempotent cleanup
+	fmt.Fprintln(stdout, "\n3. OnceFunc:")
+	closeConn := sync.OnceFunc(func() {
+		fmt.Fprintln(stdout, "  connection closed")
+	})
+	closeConn()
+	closeConn() // Deferred Close plus explicit Close on the error path
+	closeConn()
+	fmt.Fprintln(stdout, "closeConn called 3 times, cleanup ran once")
+
+	// 4. Panics in the wrapped function
+	fmt.Fprintln(stdout, "\n4. Panicking initialisation:")
+	calls := 0
+	mustLoad := sync.OnceValue(func() int {
+		calls++
+		panic("config file missing")
+	})
+	for i := 0; i < 2; i++ {
+		fmt.Fprintf(stdout, "OnceValue call %d: %s\n", i+1, callRecovering(func() { mustLoad() }))
+	}
+	fmt.Fprintf(stdout, "Wrapped function ran %d time(s); every call re-panics with the same value\n", calls)
+
+	var once sync.Once
+	var port int
+	for i := 0; i < 2; i++ {
+		result := callRecovering(func() {
+			once.Do(func() {
+				port = mustLoad()
+			})
+		})
+		fmt.Fprintf(stdout, "sync.Once call %d: %s (port = %d)\n", i+1, result, port)
+	}
+	fmt.Fprintln(stdout, "sync.Once counts the panicking call as done; later callers see the zero value")
+	fmt.Fprintln(stdout)
+}
+
+// callRecovering runs fn and describes whether it returned or panicked
+func callRecovering(fn func()) (result string) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = fmt.Sprintf("panicked with %q", r)
+		}
+	}()
+
+	fn()
+	return "returned normally"
+}
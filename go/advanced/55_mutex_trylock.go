@@ -0,0 +1,115 @@
+/**
+ * This file demonstrates sync.Mutex.TryLock and sync.RWMutex.TryRLock.
+ *
+ * Go 1.18 added TryLock to sync.Mutex and TryLock / TryRLock to sync.RWMutex,
+ * so the channel-based lock from 24_try_lock.go is no longer needed to attempt
+ * a lock without blocking. The sync documentation warns that correct uses are
+ * rare, and this example ends with the reasons why.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/**
+ * Standard Library TryLock
+ *
+ * The example tries a held mutex, runs an "optional refresh" from many
+ * goroutines where only one should do the work and the rest skip it, shows
+ * how readers and writers interact with TryLock and TryRLock on an RWMutex,
+ * and prints guidance on when TryLock is a design smell.
+ */
+func MutexTryLockDemo() {
+	fmt.Fprintln(stdout, "Standard Library TryLock")
+
+	// 1. TryLock on a free and on a held mutex
+	fmt.Fprintln(stdout, "\n1. Mutex.TryLock:")
+	var mu sync.Mutex
+	fmt.Fprintf(stdout, "TryLock on a free mutex: %v\n", mu.TryLock())
+	fmt.Fprintf(stdout, "TryLock while held: %v\n", mu.TryLock())
+	mu.Unlock()
+	fmt.Fprintf(stdout, "TryLock after Unlock: %v\n", mu.TryLock())
+	mu.Unlock()
+
+	// 2. Under contention: skip optional work instead of queueing for it
+	fmt.Fprintln(stdout, "\n2. Opportunistic refresh under contention:")
+	var (
+		refreshMu sync.Mutex
+		refreshed atomic.Int32
+		skipped   atomic.Int32
+		wg        sync.WaitGroup
+	)
+	start := clk.Now()
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !refreshMu.TryLock() {
+				skipped.Add(1) // Someone else is refreshing; serve the cached data
+				return
+			}
+			defer refreshMu.Unlock()
+			clk.Sleep(20 * time.Millisecond) // Refresh the cache
+			refreshed.Add(1)
+		}()
+	}
+	wg.Wait()
+	fmt.Fprintf(stdout, "TryLock: %d refresh(es), %d skipped, took %v\n",
+		refreshed.Load(), skipped.Load(), clk.Since(start).Round(10*time.Millisecond))
+
+	refreshed.Store(0)
+	start = clk.Now()
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			refreshMu.Lock()
+			defer refreshMu.Unlock()
+			clk.Sleep(20 * time.Millisecond)
+			refreshed.Add(1)
+		}()
+	}
+	wg.Wait()
+	fmt.Fprintf(stdout, "Lock:    %d refresh(es), took %v\n",
+		refreshed.Load(), clk.Since(start).Round(10*time.Millisecond))
+
+	// 3. RWMutex: readers block writers and vice versa
+	fmt.Fprintln(stdout, "\n3. RWMutex.TryLock and TryRLock:")
+	var rw sync.RWMutex
+	rw.RLock()
+	fmt.Fprintf(stdout, "With a reader: TryRLock=%v", rw.TryRLock())
+	rw.RUnlock()
+	fmt.Fprintf(stdout, ", TryLock=%v\n", rw.TryLock())
+	rw.RUnlock()
+
+	rw.Lock()
+	fmt.Fprintf(stdout, "With a writer: TryRLock=%v, TryLock=%v\n", rw.TryRLock(), rw.TryLock())
+	rw.Unlock()
+
+	// A blocked writer also makes TryRLock fail, so writers are not starved
+	rw.RLock()
+	writerDone := make(chan struct{})
+	go func() {
+		rw.Lock()
+		rw.Unlock()
+		close(writerDone)
+	}()
+	clk.Sleep(10 * time.Millisecond) // Let the writer start waiting
+	fmt.Fprintf(stdout, "With a reader and a waiting writer: TryRLock=%v\n", rw.TryRLock())
+	rw.RUnlock()
+	<-writerDone
+
+	// 4. Guidance
+	fmt.Fprintln(stdout, "\n4. Why TryLock is usually a design smell:")
+	fmt.Fprintln(stdout, "- A false result is stale immediately; the lock may be free by the time you act on it.")
+	fmt.Fprintln(stdout, "- Retrying TryLock in a loop is a spin lock that burns CPU and defeats the mutex's fairness.")
+	fmt.Fprintln(stdout, "- Needing it often means a goroutine holds the lock too long or the ownership is unclear.")
+	fmt.Fprintln(stdout, "- Prefer Lock, a channel with select/default, or a context-aware semaphore.")
+	fmt.Fprintln(stdout, "- Reasonable uses: optional work that can be skipped, and diagnostics such as deadlock checks.")
+	fmt.Fprintln(stdout)
+}
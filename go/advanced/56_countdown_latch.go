@@ -0,0 +1,96 @@
+/**
+ * This file demonstrates the CountDownLatch in Go.
+ *
+ * Java's CountDownLatch and C#'s CountdownEvent block waiters until a counter
+ * reaches zero. sync.WaitGroup is close, but it is meant for waiting on
+ * goroutines you started, cannot be waited on in a select and has no timeout.
+ * syncx.CountDownLatch is a small latch built on a closed channel, which
+ * broadcasts to every waiter at once.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/clock"
+	"threads/syncx"
+)
+
+/**
+ * CountDownLatch and Start Gate
+ *
+ * A "ready" latch of N lets the coordinator wait until every runner is in
+ * position, and a start gate (a latch of one) then releases all of them at
+ * the same moment. A final section waits on a latch that never opens with a
+ * context timeout.
+ */
+func CountDownLatchDemo() {
+	fmt.Fprintln(stdout, "CountDownLatch and Start Gate")
+
+	const runners = 8
+
+	// 1. Ready latch and start gate
+	fmt.Fprintln(stdout, "\n1. Releasing goroutines simultaneously:")
+	ready := syncx.NewCountDownLatch(runners)
+	startGate := syncx.NewCountDownLatch(1)
+	starts := make([]time.Time, runners)
+
+	var wg sync.WaitGroup
+	for i := 0; i < runners; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clk.Sleep(time.Duration(i) * 5 * time.Millisecond) // Setup takes a different time for each runner
+			ready.CountDown()
+			startGate.Wait()
+			starts[i] = clk.Now()
+		}()
+	}
+
+	ready.Wait()
+	fmt.Fprintf(stdout, "All %d runners ready, opening the start gate\n", runners)
+	opened := clk.Now()
+	startGate.CountDown()
+	wg.Wait()
+
+	var first, last time.Duration
+	for i, t := range starts {
+		d := t.Sub(opened)
+		if i == 0 || d < first {
+			first = d
+		}
+		last = max(last, d)
+	}
+	fmt.Fprintf(stdout, "Runners started between %v and %v after the gate opened\n", first, last)
+
+	// 2. The latch is one-shot
+	fmt.Fprintln(stdout, "\n2. One-shot behavior:")
+	startGate.CountDown() // No effect: the count stays at zero
+	fmt.Fprintf(stdout, "Count after an extra CountDown: %d\n", startGate.Count())
+	select {
+	case <-startGate.Done():
+		fmt.Fprintln(stdout, "Done() is still closed, late waiters pass straight through")
+	default:
+		fmt.Fprintln(stdout, "Latch closed again (unexpected)")
+	}
+
+	// 3. Waiting with a timeout
+	fmt.Fprintln(stdout, "\n3. Waiting with a context:")
+	services := syncx.NewCountDownLatch(3)
+	for _, name := range []string{"db", "cache"} { // "queue" never reports in
+		go func() {
+			clk.Sleep(10 * time.Millisecond)
+			logger.Info("service is up", "service", name)
+			services.CountDown()
+		}()
+	}
+	ctx, cancel := clock.WithTimeout(context.Background(), clk, 100*time.Millisecond)
+	defer cancel()
+	err := services.WaitContext(ctx)
+	fmt.Fprintf(stdout, "WaitContext: %v (still waiting for %d service)\n", err, services.Count())
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,99 @@
+/**
+ * This file demonstrates the Rendezvous and Exchanger Patterns in Go.
+ *
+ * An unbuffered channel is a rendezvous: the sender and the receiver both
+ * block until the other arrives, and the value moves in one direction. An
+ * exchanger extends this to a two-way swap, where each of a pair of goroutines
+ * hands over a value and receives the other's. syncx.Exchanger[T] packages
+ * this as a reusable primitive.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/clock"
+	"threads/syncx"
+)
+
+/**
+ * Rendezvous and Exchanger
+ *
+ * The example shows that both sides of an unbuffered channel wait for each
+ * other, swaps a full and an empty buffer between a producer and a consumer
+ * with an Exchanger so neither allocates, pairs up several goroutines, and
+ * gives up on an exchange that finds no partner.
+ */
+func ExchangerDemo() {
+	fmt.Fprintln(stdout, "Rendezvous and Exchanger")
+
+	// 1. Unbuffered channels are a one-way rendezvous
+	fmt.Fprintln(stdout, "\n1. Unbuffered channel rendezvous:")
+	ch := make(chan string)
+	start := clk.Now()
+	go func() {
+		clk.Sleep(50 * time.Millisecond) // The receiver arrives late
+		fmt.Fprintf(stdout, "Receiver got %q at %v\n", <-ch, clk.Since(start).Round(10*time.Millisecond))
+	}()
+	ch <- "ping"
+	fmt.Fprintf(stdout, "Sender unblocked at %v: it waited for the receiver\n", clk.Since(start).Round(10*time.Millisecond))
+
+	// 2. Producer and consumer swap buffers
+	fmt.Fprintln(stdout, "\n2. Buffer swapping:")
+	var swap syncx.Exchanger[[]int]
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() { // Producer fills a buffer, then trades it for an empty one
+		defer wg.Done()
+		buf := make([]int, 0, 4)
+		for round := 0; round < 3; round++ {
+			for i := 0; i < cap(buf); i++ {
+				buf = append(buf, round*10+i)
+			}
+			buf = swap.Exchange(buf)
+		}
+	}()
+
+	go func() { // Consumer trades an empty buffer for a full one, then drains it
+		defer wg.Done()
+		buf := make([]int, 0, 4)
+		arrays := map[*int]int{} // Backing array to the order it was first seen in
+		for round := 0; round < 3; round++ {
+			buf = swap.Exchange(buf)
+			if _, ok := arrays[&buf[0]]; !ok {
+				arrays[&buf[0]] = len(arrays) + 1
+			}
+			logger.Info("consumer received a buffer", "round", round, "values", buf, "array", arrays[&buf[0]])
+			buf = buf[:0]
+		}
+	}()
+	wg.Wait()
+	fmt.Fprintln(stdout, "Only two backing arrays are ever used")
+
+	// 3. Pairing several goroutines
+	fmt.Fprintln(stdout, "\n3. Pairing goroutines:")
+	var pairs syncx.Exchanger[string]
+	names := []string{"alice", "bob", "carol", "dave"}
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			partner := pairs.Exchange(name)
+			logger.Info("paired", "name", name, "partner", partner)
+		}()
+	}
+	wg.Wait()
+
+	// 4. No partner arrives
+	fmt.Fprintln(stdout, "\n4. Exchange with a timeout:")
+	ctx, cancel := clock.WithTimeout(context.Background(), clk, 50*time.Millisecond)
+	defer cancel()
+	got, err := pairs.ExchangeContext(ctx, "eve")
+	fmt.Fprintf(stdout, "eve got %q, err: %v\n", got, err)
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,149 @@
+/**
+ * This file demonstrates how CPU-bound and IO-bound work scale in Go.
+ *
+ * A CPU-bound job keeps a processor busy, so running more of them at once
+ * than there are Ps (GOMAXPROCS) only adds scheduling overhead. An IO-bound
+ * job spends most of its time blocked, and a blocked goroutine does not hold
+ * a P, so many more workers than CPUs can be in flight. This example runs the
+ * same worker pool against both kinds of jobs to show where each one stops
+ * getting faster.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"threads/stats"
+)
+
+// cpuSink keeps the compiler from optimising CPU jobs away
+var cpuSink uint64
+
+// workloadMix describes a batch of jobs for the scaling experiment
+type workloadMix struct {
+	name string
+	job  func(i int) uint64
+}
+
+/**
+ * CPU-bound vs IO-bound Scheduling
+ *
+ * For every combination of GOMAXPROCS and worker count the example runs a
+ * fixed batch of CPU-bound, IO-bound and mixed jobs, prints the time each
+ * took and reports the fastest worker count per workload.
+ */
+func CPUvsIOBoundDemo() {
+	fmt.Fprintln(stdout, "CPU-bound vs IO-bound Scheduling")
+
+	const jobs = 64
+	numCPU := runtime.NumCPU()
+	prevProcs := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(prevProcs)
+
+	mixes := []workloadMix{
+		{"CPU-bound", func(int) uint64 { return cpuJob() }},
+		{"IO-bound", func(int) uint64 { time.Sleep(5 * time.Millisecond); return 0 }},
+		{"Mixed", func(i int) uint64 {
+			if i%2 == 0 {
+				return cpuJob()
+			}
+			time.Sleep(5 * time.Millisecond)
+			return 0
+		}},
+	}
+	procsList := slices.Compact([]int{1, numCPU})
+	workersList := slices.Compact(slices.Sorted(slices.Values([]int{1, numCPU, 4 * numCPU, jobs})))
+
+	start := time.Now()
+	cpuSink += cpuJob()
+	fmt.Fprintf(stdout, "NumCPU: %d, %d jobs per run (CPU job %v of arithmetic, IO job 5ms sleep)\n",
+		numCPU, jobs, time.Since(start).Round(100*time.Microsecond))
+	if numCPU == 1 {
+		fmt.Fprintln(stdout, "Only one CPU is available, so CPU-bound work cannot speed up here")
+	}
+
+	for _, procs := range procsList {
+		runtime.GOMAXPROCS(procs)
+		fmt.Fprintf(stdout, "\nGOMAXPROCS=%d\n", procs)
+		fmt.Fprintf(stdout, "%-10s", "workers")
+		for _, mix := range mixes {
+			fmt.Fprintf(stdout, " %12s", mix.name)
+		}
+		fmt.Fprintln(stdout)
+
+		best := make([]time.Duration, len(mixes))
+		bestWorkers := make([]int, len(mixes))
+		for _, workers := range workersList {
+			fmt.Fprintf(stdout, "%-10d", workers)
+			for m, mix := range mixes {
+				elapsed := runJobPool(jobs, workers, mix.job)
+				fmt.Fprintf(stdout, " %12v", elapsed.Round(100*time.Microsecond))
+				if best[m] == 0 || elapsed < best[m] {
+					best[m], bestWorkers[m] = elapsed, workers
+				}
+			}
+			fmt.Fprintln(stdout)
+		}
+
+		for m, mix := range mixes {
+			stats.Record(fmt.Sprintf("%s GOMAXPROCS=%d best", mix.name, procs), best[m])
+			fmt.Fprintf(stdout, "Fastest for %s: %d workers (%v)\n", mix.name, bestWorkers[m], best[m].Round(100*time.Microsecond))
+		}
+	}
+
+	fmt.Fprintln(stdout, "\nObservations:")
+	fmt.Fprintln(stdout, "- CPU-bound jobs stop improving once workers reach GOMAXPROCS; size the pool to runtime.GOMAXPROCS(0).")
+	fmt.Fprintln(stdout, "- IO-bound jobs keep improving with more workers, independent of GOMAXPROCS, until the IO itself saturates.")
+	fmt.Fprintln(stdout, "- Mixed workloads are bounded by their CPU part once the IO overlaps; separate pools let each part be sized on its own.")
+	fmt.Fprintln(stdout)
+}
+
+// runJobPool runs jobs through a pool of workers and returns the wall time
+func runJobPool(jobs, workers int, job func(i int) uint64) time.Duration {
+	queue := make(chan int)
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sum uint64
+	)
+
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local uint64
+			for i := range queue {
+				local += job(i)
+			}
+			mu.Lock()
+			sum += local
+			mu.Unlock()
+		}()
+	}
+
+	for i := 0; i < jobs; i++ {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	cpuSink += sum
+	return time.Since(start)
+}
+
+// cpuJob burns a millisecond or two of CPU time with xorshift arithmetic
+func cpuJob() uint64 {
+	x := uint64(88172645463325252)
+	for i := 0; i < 1_000_000; i++ {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+	}
+	return x
+}
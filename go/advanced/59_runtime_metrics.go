@@ -0,0 +1,221 @@
+/**
+ * This file demonstrates observing the Go scheduler with runtime/metrics.
+ *
+ * runtime.NumGoroutine gives a single number. The runtime/metrics package
+ * (Go 1.16) exposes much more: how long runnable goroutines wait for a P
+ * (scheduler latency), how many GC cycles ran and how long they stopped the
+ * world. Sampling these while a program runs shows how load turns into
+ * queueing and GC pressure.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+const (
+	metricGoroutines = "/sched/goroutines:goroutines"
+	metricSchedLat   = "/sched/latencies:seconds"
+	metricGCCycles   = "/gc/cycles/total:gc-cycles"
+	metricGCPauses   = "/sched/pauses/total/gc:seconds"
+	metricHeapAllocs = "/gc/heap/allocs:bytes"
+)
+
+// metricsSink keeps the workload's allocations from being optimised away
+var metricsSink [][]byte
+
+/**
+ * Runtime Metrics Monitoring
+ *
+ * A sampler reads runtime.NumGoroutine and a handful of runtime/metrics every
+ * 50ms while a bursty workload alternates between spawning thousands of
+ * allocating goroutines and sitting idle. Each row of the time series shows
+ * the change since the previous sample.
+ */
+func RuntimeMetricsDemo() {
+	fmt.Fprintln(stdout, "Runtime Metrics Monitoring")
+
+	samples := []metrics.Sample{
+		{Name: metricGoroutines},
+		{Name: metricSchedLat},
+		{Name: metricGCCycles},
+		{Name: metricGCPauses},
+		{Name: metricHeapAllocs},
+	}
+	stop := make(chan struct{})
+	workloadDone := make(chan struct{})
+	go func() {
+		defer close(workloadDone)
+		runBurstyWorkload(stop)
+	}()
+
+	fmt.Fprintf(stdout, "%6s %10s %10s %14s %14s %9s %12s %10s\n",
+		"t", "NumG", "metricG", "sched p50", "sched p99", "GC runs", "GC pause", "alloc MB")
+
+	metrics.Read(samples)
+	prev := snapshotMetrics(samples)
+	start := time.Now()
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for range 24 {
+		<-ticker.C
+		metrics.Read(samples)
+		cur := snapshotMetrics(samples)
+		lat := histogramDelta(prev.schedLat, cur.schedLat)
+
+		fmt.Fprintf(stdout, "%6v %10d %10d %14s %14s %9d %12v %10.1f\n",
+			time.Since(start).Round(50*time.Millisecond),
+			runtime.NumGoroutine(),
+			cur.goroutines,
+			formatLatency(histogramQuantile(lat, 0.50)),
+			formatLatency(histogramQuantile(lat, 0.99)),
+			cur.gcCycles-prev.gcCycles,
+			histogramSum(histogramDelta(prev.gcPauses, cur.gcPauses)),
+			float64(cur.heapAllocs-prev.heapAllocs)/(1<<20))
+		prev = cur
+	}
+
+	close(stop)
+	<-workloadDone
+	metricsSink = nil
+
+	fmt.Fprintln(stdout, "\nReading the series:")
+	fmt.Fprintln(stdout, "- NumG and metricG move together; the metric also counts a few runtime-internal goroutines.")
+	fmt.Fprintln(stdout, "- Scheduler latency is the time a runnable goroutine waits for a P; it rises during bursts.")
+	fmt.Fprintln(stdout, "- \"-\" means no goroutine became runnable during that interval.")
+	fmt.Fprintln(stdout, "- Allocation-heavy bursts trigger GC cycles, and each adds a short stop-the-world pause.")
+	fmt.Fprintln(stdout, "- GC pause is an estimate built from histogram bucket midpoints.")
+	fmt.Fprintln(stdout)
+}
+
+// metricsSnapshot holds the values of one metrics.Read call
+type metricsSnapshot struct {
+	goroutines uint64
+	schedLat   *metrics.Float64Histogram
+	gcCycles   uint64
+	gcPauses   *metrics.Float64Histogram
+	heapAllocs uint64
+}
+
+func snapshotMetrics(samples []metrics.Sample) metricsSnapshot {
+	// Float64Histogram values are reused by the next Read, so copy them
+	return metricsSnapshot{
+		goroutines: samples[0].Value.Uint64(),
+		schedLat:   copyHistogram(samples[1].Value.Float64Histogram()),
+		gcCycles:   samples[2].Value.Uint64(),
+		gcPauses:   copyHistogram(samples[3].Value.Float64Histogram()),
+		heapAllocs: samples[4].Value.Uint64(),
+	}
+}
+
+// runBurstyWorkload alternates 100ms bursts of allocating goroutines with
+// 150ms of idleness until stop is closed
+func runBurstyWorkload(stop <-chan struct{}) {
+	for {
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		deadline := time.Now().Add(100 * time.Millisecond)
+
+		for time.Now().Before(deadline) {
+			for range 200 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					buf := make([]byte, 16<<10)
+					for i := range buf {
+						buf[i] = byte(i)
+					}
+					mu.Lock()
+					metricsSink = append(metricsSink[:min(len(metricsSink), 64)], buf)
+					mu.Unlock()
+					time.Sleep(30 * time.Millisecond) // Hold on like a request waiting for IO
+				}()
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		wg.Wait()
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(150 * time.Millisecond):
+		}
+	}
+}
+
+func copyHistogram(h *metrics.Float64Histogram) *metrics.Float64Histogram {
+	return &metrics.Float64Histogram{
+		Counts:  append([]uint64(nil), h.Counts...),
+		Buckets: h.Buckets, // Bucket boundaries never change
+	}
+}
+
+// histogramDelta returns the observations recorded between prev and cur
+func histogramDelta(prev, cur *metrics.Float64Histogram) *metrics.Float64Histogram {
+	d := copyHistogram(cur)
+	for i := range d.Counts {
+		d.Counts[i] -= prev.Counts[i]
+	}
+	return d
+}
+
+// histogramQuantile returns the upper bound of the bucket containing the
+// q-th quantile, or 0 if the histogram is empty
+func histogramQuantile(h *metrics.Float64Histogram, q float64) time.Duration {
+	var total uint64
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(q * float64(total)))
+	var seen uint64
+	for i, c := range h.Counts {
+		seen += c
+		if seen >= rank {
+			return bucketDuration(h.Buckets[i+1])
+		}
+	}
+	return 0
+}
+
+// histogramSum estimates the total of all observations using bucket midpoints
+func histogramSum(h *metrics.Float64Histogram) time.Duration {
+	var sum float64
+	for i, c := range h.Counts {
+		lo, hi := h.Buckets[i], h.Buckets[i+1]
+		if math.IsInf(lo, -1) {
+			lo = 0
+		}
+		if math.IsInf(hi, 1) {
+			hi = lo
+		}
+		sum += float64(c) * (lo + hi) / 2
+	}
+	return time.Duration(sum * float64(time.Second)).Round(time.Microsecond)
+}
+
+// formatLatency prints a quantile, using "-" for an empty interval
+func formatLatency(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.String()
+}
+
+// bucketDuration converts a bucket boundary in seconds to a Duration
+func bucketDuration(seconds float64) time.Duration {
+	if math.IsInf(seconds, 1) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(time.Microsecond)
+}
@@ -0,0 +1,254 @@
+/**
+ * This file provides a Contention Workload for profiling.
+ *
+ * Mutex and blocking profiles are empty unless something actually waits.
+ * This example runs for a couple of seconds with a hot mutex guarding a long
+ * critical section, an RWMutex with frequent writers and an unbuffered
+ * channel feeding a slow consumer, then a sharded version of the hot mutex for
+ * comparison. Run it with --blockprofile / --mutexprofile and open the
+ * results with `go tool pprof`, or read the summary the example prints itself.
+ */
+
+package advanced
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/stats"
+)
+
+/**
+ * Contention Workload for Block and Mutex Profiles
+ *
+ * The example enables block and mutex profile sampling, runs the contended
+ * phases, and ranks the call sites where goroutines waited the longest
+ * using runtime.BlockProfile and runtime.MutexProfile.
+ */
+func ContentionWorkloadDemo() {
+	fmt.Fprintln(stdout, "Contention Workload for Block and Mutex Profiles")
+
+	// Sample every blocking event and every contended mutex while we run.
+	// Profiles are cumulative, so --blockprofile and --mutexprofile still see
+	// these events after the rates are switched off again below.
+	runtime.SetBlockProfileRate(1)
+	prevFraction := runtime.SetMutexProfileFraction(1)
+	defer runtime.SetMutexProfileFraction(prevFraction)
+	defer runtime.SetBlockProfileRate(0)
+
+	blockBefore := blockRecords(runtime.BlockProfile)
+	mutexBefore := blockRecords(runtime.MutexProfile)
+
+	const workers, duration = 8, 600 * time.Millisecond
+
+	phases := []struct {
+		name string
+		run  func(workers int, d time.Duration) int
+	}{
+		{"hot mutex", contendHotMutex},
+		{"rwmutex with writers", contendRWMutex},
+		{"unbuffered channel", contendChannel},
+		{"sharded mutex", contendShardedMutex},
+	}
+	fmt.Fprintf(stdout, "\n%-22s %12s\n", "phase", "ops")
+	for _, phase := range phases {
+		start := time.Now()
+		ops := phase.run(workers, duration)
+		stats.Record(phase.name, time.Since(start))
+		fmt.Fprintf(stdout, "%-22s %12d\n", phase.name, ops)
+	}
+
+	fmt.Fprintln(stdout, "\nTop blocking sites (share of total delay):")
+	printContention(blockBefore, blockRecords(runtime.BlockProfile))
+	fmt.Fprintln(stdout, "\nTop mutex contention sites (share of total delay):")
+	printContention(mutexBefore, blockRecords(runtime.MutexProfile))
+
+	fmt.Fprintln(stdout, "\nrunFor's blocking is the WaitGroup.Wait for each phase, not contention.")
+	fmt.Fprintln(stdout, "The mutex profile attributes delay to the Unlock that made others wait,")
+	fmt.Fprintln(stdout, "so long critical sections show up at the function that holds the lock.")
+	fmt.Fprintln(stdout)
+}
+
+// contendHotMutex makes every worker update one map under one mutex, doing
+// some work inside the critical section
+func contendHotMutex(workers int, d time.Duration) int {
+	var mu sync.Mutex
+	counts := map[int]int{}
+	return runFor(workers, d, func(id, i int) {
+		mu.Lock()
+		counts[i%64]++
+		burnCPU(2000)
+		mu.Unlock()
+	})
+}
+
+// contendRWMutex mixes many readers with a writer every tenth operation
+func contendRWMutex(workers int, d time.Duration) int {
+	var mu sync.RWMutex
+	value := 0
+	return runFor(workers, d, func(id, i int) {
+		if i%10 == 0 {
+			mu.Lock()
+			value++
+			burnCPU(2000)
+			mu.Unlock()
+			return
+		}
+		mu.RLock()
+		_ = value
+		burnCPU(200)
+		mu.RUnlock()
+	})
+}
+
+// contendChannel feeds a slow consumer through an unbuffered channel
+func contendChannel(workers int, d time.Duration) int {
+	ch := make(chan int)
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+		for range ch {
+			burnCPU(5000)
+		}
+	}()
+
+	ops := runFor(workers, d, func(id, i int) {
+		ch <- i
+	})
+	close(ch)
+	<-consumerDone
+	return ops
+}
+
+// contendShardedMutex is the hot mutex workload with one lock per worker
+func contendShardedMutex(workers int, d time.Duration) int {
+	shards := make([]struct {
+		mu     sync.Mutex
+		counts map[int]int
+	}, workers)
+	for i := range shards {
+		shards[i].counts = map[int]int{}
+	}
+	return runFor(workers, d, func(id, i int) {
+		s := &shards[id]
+		s.mu.Lock()
+		s.counts[i%64]++
+		burnCPU(2000)
+		s.mu.Unlock()
+	})
+}
+
+// runFor calls op in a loop from each worker until d has elapsed and returns
+// the total number of calls
+func runFor(workers int, d time.Duration, op func(id, i int)) int {
+	deadline := time.Now().Add(d)
+	var wg sync.WaitGroup
+	totals := make([]int, workers)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; time.Now().Before(deadline); i++ {
+				op(w, i)
+				totals[w]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	sum := 0
+	for _, n := range totals {
+		sum += n
+	}
+	return sum
+}
+
+// burnSink keeps the compiler from optimising burnCPU away. The workloads
+// call burnCPU from many goroutines at once, so it is atomic.
+var burnSink atomic.Uint64
+
+// burnCPU spins for roughly n iterations of arithmetic
+func burnCPU(n int) {
+	burnSink.Add(spin(n))
+}
+
+// spin does n iterations of arithmetic and returns the result. Callers that
+// fold the result into their own output share no state at all.
+func spin(n int) uint64 {
+	var x uint64
+	for i := 0; i < n; i++ {
+		x = x*31 + uint64(i)
+	}
+	return x
+}
+
+// blockRecords reads a block or mutex profile, growing the slice until the
+// whole profile fits
+func blockRecords(read func([]runtime.BlockProfileRecord) (int, bool)) map[[32]uintptr]runtime.BlockProfileRecord {
+	n, _ := read(nil)
+	records := make([]runtime.BlockProfileRecord, n+50)
+	for {
+		var ok bool
+		if n, ok = read(records); ok {
+			break
+		}
+		records = make([]runtime.BlockProfileRecord, n+50)
+	}
+
+	byStack := make(map[[32]uintptr]runtime.BlockProfileRecord, n)
+	for _, r := range records[:n] {
+		byStack[r.Stack0] = r
+	}
+	return byStack
+}
+
+// printContention prints the five call sites with the most delay added
+// between the before and after snapshots
+func printContention(before, after map[[32]uintptr]runtime.BlockProfileRecord) {
+	delays := map[string]int64{}
+	var total int64
+	for stack, r := range after {
+		delta := r.Cycles - before[stack].Cycles
+		if delta <= 0 {
+			continue
+		}
+		delays[contentionSite(r.Stack())] += delta
+		total += delta
+	}
+	if total == 0 {
+		fmt.Fprintln(stdout, "  (no samples)")
+		return
+	}
+
+	sites := make([]string, 0, len(delays))
+	for site := range delays {
+		sites = append(sites, site)
+	}
+	slices.SortFunc(sites, func(a, b string) int { return cmp.Compare(delays[b], delays[a]) })
+
+	for _, site := range sites[:min(5, len(sites))] {
+		fmt.Fprintf(stdout, "  %5.1f%%  %s\n", 100*float64(delays[site])/float64(total), site)
+	}
+}
+
+// contentionSite names the first frame outside the runtime and sync packages
+func contentionSite(stack []uintptr) string {
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		name := frame.Function
+		if !strings.HasPrefix(name, "runtime.") && !strings.HasPrefix(name, "sync.") && name != "" {
+			return strings.TrimPrefix(name, "threads/")
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
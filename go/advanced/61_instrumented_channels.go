@@ -0,0 +1,155 @@
+/**
+ * This file demonstrates Channel Instrumentation in Go.
+ *
+ * When a pipeline is slower than expected, the question is which stage is
+ * to blame. chanx.Instrumented wraps a channel and records how many values
+ * went through it, how many were dropped and how long senders and receivers
+ * were blocked. Senders blocking on a link means the stage after it is slow;
+ * receivers blocking means the stage before it is.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/chanx"
+	"threads/topology"
+)
+
+/**
+ * Instrumented Channels for Pipeline Debugging
+ *
+ * A four-stage pipeline with one unexpectedly slow stage is run with every
+ * link wrapped in an instrumented channel. The per-link statistics point at
+ * the bottleneck, and a lossy metrics side channel shows up as drops.
+ */
+func InstrumentedChannelsDemo() {
+	fmt.Fprintln(stdout, "Instrumented Channels for Pipeline Debugging")
+
+	const items = 50
+
+	stages := []string{"decode", "resize", "store"} // stages[i] reads from links[i]
+	names := []string{"source->decode", "decode->resize", "resize->store"}
+	links := make([]*chanx.Instrumented[int], len(names))
+	for i := range links {
+		links[i] = chanx.NewInstrumented[int](2)
+	}
+	metrics := chanx.NewInstrumented[int](4) // Best-effort side channel
+
+	// stageWork is the per-item cost of the stage reading from each link;
+	// resize is the hidden bottleneck
+	stageWork := []time.Duration{500 * time.Microsecond, 4 * time.Millisecond, time.Millisecond}
+
+	var wg sync.WaitGroup
+	start := clk.Now()
+
+	wg.Add(1)
+	go func() { // Source
+		defer wg.Done()
+		defer links[0].Close()
+		for i := 0; i < items; i++ {
+			links[0].Send(i)
+		}
+	}()
+
+	for stage := range links {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if stage+1 < len(links) {
+				defer links[stage+1].Close()
+			}
+			for {
+				v, ok := links[stage].Recv()
+				if !ok {
+					return
+				}
+				clk.Sleep(stageWork[stage])
+				if stage+1 < len(links) {
+					links[stage+1].Send(v)
+				} else {
+					metrics.TrySend(v) // Never slow down the store for metrics
+				}
+			}
+		}()
+	}
+
+	collectorDone := make(chan struct{})
+	go func() { // Metrics collector that falls behind
+		defer close(collectorDone)
+		for {
+			if _, ok := metrics.Recv(); !ok {
+				return
+			}
+			clk.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	wg.Wait()
+	elapsed := clk.Since(start)
+	metrics.Close()
+	<-collectorDone
+
+	fmt.Fprintf(stdout, "\nProcessed %d items in %v\n\n", items, elapsed.Round(time.Millisecond))
+	fmt.Fprintf(stdout, "%-16s %6s %6s %6s %14s %14s\n", "link", "sends", "recvs", "drops", "send-blocked", "recv-blocked")
+	linkStats := make([]chanx.ChanStats, len(links))
+	for i, link := range links {
+		linkStats[i] = link.Stats()
+		printChanStats(names[i], linkStats[i])
+	}
+	printChanStats("metrics", metrics.Stats())
+
+	// A slow stage backs up its input (senders block) and starves its output
+	// (receivers block). Backpressure also blocks every sender further
+	// upstream, so neither number alone identifies the stage.
+	worst, worstScore := 0, time.Duration(0)
+	for i := range stages {
+		score := linkStats[i].SendBlocked
+		if i+1 < len(linkStats) {
+			score += linkStats[i+1].RecvBlocked
+		}
+		if score > worstScore {
+			worst, worstScore = i, score
+		}
+	}
+
+	fmt.Fprintf(stdout, "\nDiagnosis: %q is the bottleneck: senders waited %v on its input\n",
+		stages[worst], linkStats[worst].SendBlocked.Round(time.Millisecond))
+	if worst+1 < len(linkStats) {
+		fmt.Fprintf(stdout, "and the next stage waited %v on its output.\n", linkStats[worst+1].RecvBlocked.Round(time.Millisecond))
+	}
+	fmt.Fprintln(stdout, "Send-blocked time on links further upstream is backpressure from the same stage.")
+	fmt.Fprintln(stdout, "Drops on the metrics channel mean the collector cannot keep up, which is")
+	fmt.Fprintln(stdout, "acceptable for best-effort data.")
+	fmt.Fprintln(stdout)
+}
+
+func printChanStats(name string, s chanx.ChanStats) {
+	fmt.Fprintf(stdout, "%-16s %6d %6d %6d %14v %14v\n", name, s.Sends, s.Receives, s.Drops,
+		s.SendBlocked.Round(time.Millisecond), s.RecvBlocked.Round(time.Millisecond))
+}
+
+// InstrumentedChannelsTopology describes the goroutines and channels of the demo
+func InstrumentedChannelsTopology() *topology.Graph {
+	return topology.New("Instrumented Channels").
+		Goroutine("source", "source").
+		Channel("l0", "source->decode").
+		Goroutine("decode", "decode").
+		Channel("l1", "decode->resize").
+		Goroutine("resize", "resize (bottleneck)").
+		Channel("l2", "resize->store").
+		Goroutine("store", "store").
+		Channel("metrics", "metrics (cap 4)").
+		Goroutine("collector", "metrics collector").
+		Flow("source", "l0", "Send").
+		Flow("l0", "decode", "Recv").
+		Flow("decode", "l1", "Send").
+		Flow("l1", "resize", "Recv").
+		Flow("resize", "l2", "Send").
+		Flow("l2", "store", "Recv").
+		Flow("store", "metrics", "TrySend").
+		Flow("metrics", "collector", "Recv")
+}
@@ -0,0 +1,357 @@
+/**
+ * This file demonstrates a Concurrent TCP Chat Server in Go.
+ *
+ * A network server is where the channel patterns from the other examples
+ * meet: a goroutine per connection reads from the client, a hub goroutine
+ * owns the set of clients and fans every message out to them, and a write
+ * pump per connection drains a bounded send buffer. A client that cannot
+ * keep up has messages dropped instead of stalling everyone else, and
+ * shutdown closes the listener, drains the pumps and waits for every
+ * goroutine to exit.
+ */
+
+package advanced
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"threads/topology"
+)
+
+const (
+	chatSendBuffer   = 16              // Messages queued per client before the hub drops
+	chatSocketBuffer = 4096            // Small socket buffers make a slow reader back up quickly
+	chatWriteTimeout = 2 * time.Second // A stuck client cannot hold up shutdown for longer
+)
+
+// chatClient is the server's side of a connection
+type chatClient struct {
+	name string
+	send chan string // Closed by the hub when the client leaves or the server stops
+
+	// Owned by the hub goroutine
+	delivered, dropped int
+}
+
+type chatMessage struct {
+	from *chatClient
+	text string
+}
+
+// chatHub owns the set of connected clients. Only its goroutine touches the
+// map, so it needs no lock.
+type chatHub struct {
+	register   chan *chatClient
+	unregister chan *chatClient
+	broadcast  chan chatMessage
+	done       chan struct{} // Closed when the hub stops
+	clients    map[*chatClient]bool
+	left       []*chatClient // Clients that were connected when the hub stopped or that left
+}
+
+func newChatHub() *chatHub {
+	return &chatHub{
+		register:   make(chan *chatClient),
+		unregister: make(chan *chatClient),
+		broadcast:  make(chan chatMessage),
+		done:       make(chan struct{}),
+		clients:    map[*chatClient]bool{},
+	}
+}
+
+// run serves the hub until ctx is cancelled, then closes every client's send
+// channel so that the write pumps drain what is queued and exit
+func (h *chatHub) run(ctx context.Context) {
+	defer close(h.done)
+	for {
+		select {
+		case c := <-h.register:
+			h.clients[c] = true
+			h.deliver(c, fmt.Sprintf("welcome %s (%d online)", c.name, len(h.clients)))
+
+		case c := <-h.unregister:
+			if h.clients[c] {
+				delete(h.clients, c)
+				close(c.send)
+				h.left = append(h.left, c)
+			}
+
+		case msg := <-h.broadcast:
+			for c := range h.clients {
+				if c != msg.from {
+					h.deliver(c, msg.from.name+": "+msg.text)
+				}
+			}
+
+		case <-ctx.Done():
+			for c := range h.clients {
+				close(c.send)
+				h.left = append(h.left, c)
+			}
+			return
+		}
+	}
+}
+
+// deliver queues text for c without blocking; if c's buffer is full the
+// message is dropped
+func (h *chatHub) deliver(c *chatClient, text string) {
+	select {
+	case c.send <- text:
+		c.delivered++
+	default:
+		c.dropped++
+	}
+}
+
+// acceptChat starts a goroutine per connection until the listener is closed
+func acceptChat(ln net.Listener, hub *chatHub, wg *sync.WaitGroup) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return // The listener was closed
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serveChat(conn, hub, wg)
+		}()
+	}
+}
+
+// serveChat reads the client's name and then its messages, passing them to
+// the hub. Writing is left to the client's write pump.
+func serveChat(conn net.Conn, hub *chatHub, wg *sync.WaitGroup) {
+	if tc, ok := conn.(*net.TCPConn); ok {
+		tc.SetWriteBuffer(chatSocketBuffer)
+	}
+
+	lines := bufio.NewScanner(conn)
+	if !lines.Scan() {
+		conn.Close()
+		return
+	}
+	c := &chatClient{name: lines.Text(), send: make(chan string, chatSendBuffer)}
+
+	select {
+	case hub.register <- c:
+	case <-hub.done:
+		conn.Close()
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		writePump(conn, c)
+	}()
+
+	for lines.Scan() {
+		select {
+		case hub.broadcast <- chatMessage{c, lines.Text()}:
+		case <-hub.done:
+			return
+		}
+	}
+
+	select {
+	case hub.unregister <- c:
+	case <-hub.done:
+	}
+}
+
+// writePump writes the client's queued messages until the hub closes its
+// send channel, then closes the connection, which also ends serveChat's reads
+func writePump(conn net.Conn, c *chatClient) {
+	defer conn.Close()
+	for text := range c.send {
+		conn.SetWriteDeadline(time.Now().Add(chatWriteTimeout))
+		if _, err := fmt.Fprintln(conn, text); err != nil {
+			return
+		}
+	}
+}
+
+// chatConn is the client side of a connection used by the demo
+type chatConn struct {
+	conn  net.Conn
+	lines *bufio.Scanner
+}
+
+// dialChat connects, sends the client's name and waits for the welcome line,
+// so that the client is known to be registered when it returns
+func dialChat(addr, name string, readBuffer int) (*chatConn, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if tc, ok := conn.(*net.TCPConn); ok && readBuffer > 0 {
+		tc.SetReadBuffer(readBuffer)
+	}
+	c := &chatConn{conn: conn, lines: bufio.NewScanner(conn)}
+	fmt.Fprintln(conn, name)
+	if _, ok := c.read(); !ok {
+		conn.Close()
+		return nil, fmt.Errorf("%s was not welcomed", name)
+	}
+	return c, nil
+}
+
+func (c *chatConn) say(text string) {
+	fmt.Fprintln(c.conn, text)
+}
+
+func (c *chatConn) read() (string, bool) {
+	if !c.lines.Scan() {
+		return "", false
+	}
+	return c.lines.Text(), true
+}
+
+// count reads until the server closes the connection and returns how many
+// lines it read
+func (c *chatConn) count() int {
+	n := 0
+	for c.lines.Scan() {
+		n++
+	}
+	return n
+}
+
+/**
+ * Concurrent TCP Chat Server
+ *
+ * Three clients connect over loopback TCP. A chat message is broadcast by the
+ * hub to the other clients; then one client stops reading while another
+ * floods the room, and the hub drops the messages the slow client cannot
+ * take. Finally the server shuts down gracefully: queued messages are still
+ * delivered and every server goroutine exits.
+ */
+func TCPChatDemo() {
+	fmt.Fprintln(stdout, "Concurrent TCP Chat Server")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Fprintf(stdout, "Cannot listen: %v\n\n", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	context.AfterFunc(ctx, func() { ln.Close() }) // Shutdown stops accepting first
+
+	hub := newChatHub()
+	var server sync.WaitGroup
+	server.Add(2)
+	go func() {
+		defer server.Done()
+		hub.run(ctx)
+	}()
+	go func() {
+		defer server.Done()
+		acceptChat(ln, hub, &server)
+	}()
+	fmt.Fprintln(stdout, "Server listening on a loopback port")
+
+	var clients []*chatConn
+	for _, name := range []string{"alice", "bob", "carol"} {
+		readBuffer := 0
+		if name == "carol" {
+			readBuffer = chatSocketBuffer
+		}
+		c, err := dialChat(ln.Addr().String(), name, readBuffer)
+		if err != nil {
+			fmt.Fprintf(stdout, "Cannot connect: %v\n\n", err)
+			return
+		}
+		defer c.conn.Close()
+		clients = append(clients, c)
+	}
+	alice, bob, carol := clients[0], clients[1], clients[2]
+	fmt.Fprintln(stdout, "alice, bob and carol joined")
+
+	steps.Checkpoint("Each connection has a reader goroutine and a write pump. The hub goroutine owns the " +
+		"client set, so joining and leaving are just messages on its channels.")
+
+	// 1. A message goes through the hub to everyone else
+	fmt.Fprintln(stdout, "\n1. Broadcast through the hub:")
+	alice.say("hi everyone")
+	fmt.Fprintln(stdout, `alice says "hi everyone"`)
+	for _, c := range []struct {
+		name string
+		conn *chatConn
+	}{{"bob", bob}, {"carol", carol}} {
+		line, _ := c.conn.read()
+		fmt.Fprintf(stdout, "  %s received %q\n", c.name, line)
+	}
+
+	// 2. carol stops reading while bob floods the room
+	const flood = 500
+	fmt.Fprintf(stdout, "\n2. carol stops reading while bob sends %d messages:\n", flood)
+	aliceCount := make(chan int, 1)
+	go func() { aliceCount <- alice.count() }()
+
+	padding := strings.Repeat(".", 80)
+	for i := 1; i <= flood; i++ {
+		bob.say(fmt.Sprintf("message %03d %s", i, padding))
+		time.Sleep(100 * time.Microsecond)
+	}
+	time.Sleep(100 * time.Millisecond) // Let the hub and pumps catch up
+	fmt.Fprintf(stdout, "bob sent %d messages; alice kept reading, carol did not\n", flood)
+
+	steps.Checkpoint("carol's socket buffers are full, so her write pump is blocked and her send buffer " +
+		"filled up. The hub drops her messages rather than waiting, so alice is not slowed down.")
+
+	// 3. Graceful shutdown
+	fmt.Fprintln(stdout, "\n3. Graceful shutdown:")
+	carolCount := make(chan int, 1)
+	go func() { carolCount <- carol.count() }() // carol catches up on what is still queued
+	start := time.Now()
+	cancel()
+	server.Wait()
+	fmt.Fprintf(stdout, "Listener closed, send buffers drained, all server goroutines exited in %v\n",
+		time.Since(start).Round(time.Microsecond))
+
+	received := map[string]int{"alice": <-aliceCount, "carol": <-carolCount}
+	for _, c := range hub.left {
+		if c.name == "bob" {
+			continue
+		}
+		fmt.Fprintf(stdout, "%-5s received %3d of %d flood messages; the hub dropped %3d\n",
+			c.name, received[c.name], flood, c.dropped)
+	}
+
+	fmt.Fprintln(stdout)
+}
+
+// TCPChatTopology describes the goroutines and channels of the demo
+func TCPChatTopology() *topology.Graph {
+	g := topology.New("TCP Chat Server").
+		Goroutine("accept", "accept loop").
+		Goroutine("hub", "hub (owns the clients)").
+		Channel("register", "register").
+		Channel("broadcast", "broadcast").
+		Channel("unregister", "unregister").
+		Flow("register", "hub", "").
+		Flow("broadcast", "hub", "").
+		Flow("unregister", "hub", "")
+	for _, name := range []string{"alice", "bob", "carol"} {
+		reader, send, pump := name+"_reader", name+"_send", name+"_pump"
+		g.Goroutine(reader, name+" reader").
+			Channel(send, name+" send (cap 16, drops when full)").
+			Goroutine(pump, name+" write pump").
+			Flow("accept", reader, "go").
+			Flow(reader, "register", "").
+			Flow(reader, "broadcast", "").
+			Flow(reader, "unregister", "").
+			Flow("hub", send, "non-blocking").
+			Flow(send, pump, "")
+	}
+	return g
+}
@@ -0,0 +1,193 @@
+/**
+ * This file demonstrates HTTP Server Concurrency Patterns in Go.
+ *
+ * net/http runs every request on its own goroutine, so a handler is
+ * concurrent code whether it looks like it or not. The request's context is
+ * cancelled when the client goes away, which lets a handler stop wasted
+ * work; a semaphore in a middleware caps how many requests run at once; and
+ * Server.Shutdown stops accepting connections and waits for the requests in
+ * flight to finish.
+ */
+
+package advanced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/console"
+	"threads/syncx"
+)
+
+// limitInFlight is a middleware that lets at most n requests into next at a
+// time. Waiting requests give up if their client does.
+func limitInFlight(n int, next http.Handler) http.Handler {
+	sem := syncx.NewSemaphore(n)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := sem.Acquire(r.Context()); err != nil {
+			http.Error(w, "client gave up while queued", http.StatusServiceUnavailable)
+			return
+		}
+		defer sem.Release()
+		next.ServeHTTP(w, r)
+	})
+}
+
+/**
+ * HTTP Server Concurrency Patterns
+ *
+ * A server on a loopback port handles concurrent requests on separate
+ * goroutines, notices a client that disconnects through the request context,
+ * limits the requests in flight with a semaphore middleware, and drains
+ * outstanding requests on Shutdown.
+ */
+func HTTPServerDemo() {
+	fmt.Fprintln(stdout, "HTTP Server Concurrency Patterns")
+
+	const work = 50 * time.Millisecond
+
+	var (
+		mu         sync.Mutex
+		goroutines = map[uint64]bool{}
+		inFlight   atomic.Int32
+		maxFlight  atomic.Int32
+		cancelled  = make(chan time.Duration, 1)
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/work", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		goroutines[console.GoroutineID()] = true
+		mu.Unlock()
+		time.Sleep(work)
+		fmt.Fprintln(w, "done")
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		select {
+		case <-time.After(time.Second):
+			fmt.Fprintln(w, "done")
+		case <-r.Context().Done():
+			cancelled <- time.Since(start) // Nobody is listening; stop working
+		}
+	})
+	mux.Handle("/limited", limitInFlight(2, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			prev := maxFlight.Load()
+			if n <= prev || maxFlight.CompareAndSwap(prev, n) {
+				break
+			}
+		}
+		time.Sleep(work)
+		fmt.Fprintln(w, "done")
+	})))
+
+	// A plain listener rather than net/http/httptest, which would link the
+	// testing package into the binary
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Fprintf(stdout, "Cannot listen: %v\n\n", err)
+		return
+	}
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+	baseURL := "http://" + ln.Addr().String()
+	client := &http.Client{}
+
+	get := func(ctx context.Context, path string) (int, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+		if err != nil {
+			return 0, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+
+	// getAll sends n requests at once and returns how long they took
+	getAll := func(n int, path string) time.Duration {
+		start := time.Now()
+		var wg sync.WaitGroup
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				get(context.Background(), path)
+			}()
+		}
+		wg.Wait()
+		return time.Since(start)
+	}
+
+	// 1. Every request runs on its own goroutine
+	fmt.Fprintln(stdout, "\n1. A goroutine per request:")
+	elapsed := getAll(5, "/work")
+	fmt.Fprintf(stdout, "5 requests of %v each took %v in total, served by %d different goroutines\n",
+		work, elapsed.Round(10*time.Millisecond), len(goroutines))
+
+	steps.Checkpoint("The server started a goroutine for every connection, so the handler ran five times " +
+		"at once. Anything a handler shares, like the goroutines map here, needs a lock.")
+
+	// 2. The request context is cancelled when the client goes away
+	fmt.Fprintln(stdout, "\n2. Client disconnects:")
+	ctx, cancel := context.WithTimeout(context.Background(), work)
+	_, err = get(ctx, "/slow")
+	cancel()
+	fmt.Fprintf(stdout, "Client gave up after %v: %v\n", work, errors.Is(err, context.DeadlineExceeded))
+	select {
+	case d := <-cancelled:
+		fmt.Fprintf(stdout, "Handler saw r.Context() cancelled after %v and stopped its 1s job\n", d.Round(10*time.Millisecond))
+	case <-time.After(time.Second):
+		fmt.Fprintln(stdout, "Handler never noticed the client leaving")
+	}
+
+	// 3. A semaphore middleware caps the requests in flight
+	fmt.Fprintln(stdout, "\n3. At most 2 requests in flight:")
+	elapsed = getAll(6, "/limited")
+	fmt.Fprintf(stdout, "6 requests took %v; at most %d ran at once\n",
+		elapsed.Round(10*time.Millisecond), maxFlight.Load())
+
+	steps.Checkpoint("The middleware's buffered channel is a semaphore: four requests waited for a slot, " +
+		"so the six ran in three rounds instead of all at once.")
+
+	// 4. Shutdown drains the requests in flight
+	fmt.Fprintln(stdout, "\n4. Graceful shutdown:")
+	var wg sync.WaitGroup
+	statuses := make([]int, 3)
+	for i := range statuses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i], _ = get(context.Background(), "/work")
+		}()
+	}
+	time.Sleep(work / 5) // Let the requests reach the handler
+
+	start := time.Now()
+	if err := srv.Shutdown(context.Background()); err != nil {
+		fmt.Fprintf(stdout, "Shutdown: %v\n", err)
+	}
+	fmt.Fprintf(stdout, "Shutdown returned after %v, once the requests in flight were done\n",
+		time.Since(start).Round(10*time.Millisecond))
+	wg.Wait()
+	fmt.Fprintf(stdout, "Their status codes: %v\n", statuses)
+
+	_, err = get(context.Background(), "/work")
+	fmt.Fprintf(stdout, "A request after Shutdown is refused: %v\n", err != nil)
+
+	fmt.Fprintln(stdout)
+}
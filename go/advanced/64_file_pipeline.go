@@ -0,0 +1,254 @@
+/**
+ * This file demonstrates a Concurrent File Processing Pipeline in Go.
+ *
+ * Walking a directory tree and reading every file is I/O-bound: most of the
+ * time goes into waiting for the disk (or the network, for a remote file
+ * system), not into computing. A walker goroutine feeds paths to a pool of
+ * workers that hash and count lines, and an aggregator collects the
+ * results. Small channel buffers and a fixed read buffer per worker keep the
+ * memory use independent of the size of the tree, and cancelling the
+ * context stops the walk part way through.
+ */
+
+package advanced
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"threads/rng"
+	"threads/topology"
+)
+
+const (
+	fileReadBuffer = 32 << 10             // Per worker, however large the files are
+	fileOpenDelay  = 2 * time.Millisecond // Simulated latency of a slow file system
+)
+
+// fileResult is what a worker reports for one file
+type fileResult struct {
+	path  string
+	size  int64
+	lines int
+	sum   [sha256.Size]byte
+	err   error
+}
+
+// hashFile streams a file through SHA-256 and a line counter using buf, so
+// memory use does not depend on the size of the file
+func hashFile(path string, buf []byte) fileResult {
+	r := fileResult{path: path}
+	clk.Sleep(fileOpenDelay)
+	f, err := os.Open(path)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	for {
+		n, err := f.Read(buf)
+		h.Write(buf[:n])
+		r.size += int64(n)
+		r.lines += bytes.Count(buf[:n], []byte{'\n'})
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			r.err = err
+			return r
+		}
+	}
+	h.Sum(r.sum[:0])
+	return r
+}
+
+// processTree walks root and hashes its files on the given number of
+// workers, calling collect for every result from the calling goroutine. At
+// most workers paths and workers results are in flight at any time. If ctx
+// is cancelled the walk stops, the workers skip what is queued, and
+// processTree returns the number of files the walker handed out with ctx's
+// error.
+func processTree(ctx context.Context, root string, workers int, collect func(fileResult)) (int, error) {
+	paths := make(chan string, workers)
+	results := make(chan fileResult, workers)
+
+	// Walker: the only sender on paths, so it closes it
+	walked := 0
+	var walkErr error
+	walkDone := make(chan struct{})
+	go func() {
+		defer close(walkDone)
+		defer close(paths)
+		walkErr = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			select {
+			case paths <- path:
+				walked++
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	// Workers: each owns its read buffer
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, fileReadBuffer)
+			for path := range paths {
+				if ctx.Err() != nil {
+					continue // Drain without working so the walker is not stuck
+				}
+				results <- hashFile(path, buf)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Aggregator
+	for r := range results {
+		collect(r)
+	}
+	<-walkDone
+	return walked, walkErr
+}
+
+// makeFileTree writes dirs directories of files text files of random length
+// under root
+func makeFileTree(root string, dirs, files int) error {
+	line := strings.Repeat("concurrency ", 6) + "\n"
+	for d := range dirs {
+		dir := filepath.Join(root, fmt.Sprintf("dir%02d", d))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		for f := range files {
+			content := strings.Repeat(line, 1+rng.IntN(2000))
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%02d.txt", f)), []byte(content), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/**
+ * Concurrent File Processing Pipeline
+ *
+ * A generated tree of 100 files is hashed with 1 and with 8 workers to show
+ * the speed-up for I/O-bound work, the results are aggregated into totals
+ * and a digest of the whole tree, and a third run is cancelled part way
+ * through the walk.
+ */
+func FilePipelineDemo() {
+	fmt.Fprintln(stdout, "Concurrent File Processing Pipeline")
+
+	root, err := os.MkdirTemp("", "file-pipeline-")
+	if err != nil {
+		fmt.Fprintf(stdout, "Cannot create a temporary directory: %v\n\n", err)
+		return
+	}
+	defer os.RemoveAll(root)
+
+	const dirs, files = 4, 25
+	if err := makeFileTree(root, dirs, files); err != nil {
+		fmt.Fprintf(stdout, "Cannot create the file tree: %v\n\n", err)
+		return
+	}
+	fmt.Fprintf(stdout, "Generated %d files in %d directories (each open takes an extra %v)\n",
+		dirs*files, dirs, fileOpenDelay)
+
+	// 1. The same tree with 1 and 8 workers
+	fmt.Fprintln(stdout, "\n1. Hashing the tree:")
+	var results []fileResult
+	for _, workers := range []int{1, 8} {
+		results = results[:0]
+		start := clk.Now()
+		processTree(context.Background(), root, workers, func(r fileResult) {
+			results = append(results, r)
+		})
+		fmt.Fprintf(stdout, "%d worker(s): %d files in %v\n", workers, len(results), clk.Since(start).Round(time.Millisecond))
+	}
+
+	steps.Checkpoint("With one worker every file waits for the previous one. With eight, while one worker " +
+		"waits on the file system the others make progress, even on a single CPU.")
+
+	// 2. Aggregate the results of the last run
+	fmt.Fprintln(stdout, "\n2. Aggregated results:")
+	slices.SortFunc(results, func(a, b fileResult) int { return strings.Compare(a.path, b.path) })
+	var size int64
+	var lines int
+	tree := sha256.New() // Over the sorted results, so it does not depend on arrival order
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(stdout, "%s: %v\n", r.path, r.err)
+			continue
+		}
+		size += r.size
+		lines += r.lines
+		tree.Write(r.sum[:])
+	}
+	slices.SortStableFunc(results, func(a, b fileResult) int { return int(b.size - a.size) })
+	fmt.Fprintf(stdout, "%d bytes, %d lines, tree digest %x\n", size, lines, tree.Sum(nil)[:8])
+	fmt.Fprintln(stdout, "Largest files:")
+	for _, r := range results[:3] {
+		rel, _ := filepath.Rel(root, r.path)
+		fmt.Fprintf(stdout, "  %-18s %7d bytes %5d lines %x\n", filepath.ToSlash(rel), r.size, r.lines, r.sum[:4])
+	}
+	fmt.Fprintf(stdout, "Memory in flight: at most 8 paths, 8 results and 8 x %d KiB read buffers\n", fileReadBuffer>>10)
+
+	// 3. Cancel part way through the walk
+	fmt.Fprintln(stdout, "\n3. Cancelling mid-walk after 20 results:")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	processed := 0
+	walked, err := processTree(ctx, root, 8, func(fileResult) {
+		processed++
+		if processed == 20 {
+			cancel()
+		}
+	})
+	fmt.Fprintf(stdout, "Walker handed out %d of %d files and stopped with %v\n", walked, dirs*files, err)
+	fmt.Fprintf(stdout, "Workers finished %d files, counting those already in progress; the rest were skipped\n", processed)
+
+	fmt.Fprintln(stdout)
+}
+
+// FilePipelineTopology describes the goroutines and channels of the demo
+func FilePipelineTopology() *topology.Graph {
+	g := topology.New("File Processing Pipeline").
+		Goroutine("walker", "walker (filepath.WalkDir)").
+		Channel("paths", "paths (cap = workers)").
+		Channel("results", "results (cap = workers)").
+		Goroutine("closer", "closer (wg.Wait, then close)").
+		Goroutine("aggregator", "aggregator").
+		Flow("walker", "paths", "").
+		Flow("closer", "results", "close").
+		Flow("results", "aggregator", "")
+	for i := 1; i <= 3; i++ {
+		w := fmt.Sprintf("worker%d", i)
+		g.Goroutine(w, fmt.Sprintf("worker %d (hash, count lines)", i)).
+			Flow("paths", w, "").
+			Flow(w, "results", "")
+	}
+	return g
+}
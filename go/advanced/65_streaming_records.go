@@ -0,0 +1,263 @@
+/**
+ * This file demonstrates a Streaming JSON/CSV Processing Pipeline in Go.
+ *
+ * An ETL job reads records from a stream it cannot hold in memory, decodes
+ * and validates them, and writes them out in their original order. Reading
+ * is sequential, but decoding can be spread over workers; a reorder stage
+ * then puts the results back in sequence. A window of sequence numbers
+ * bounds how far the workers can run ahead, and malformed records go to an
+ * error channel instead of stopping the job.
+ */
+
+package advanced
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"threads/rng"
+)
+
+// recordError reports a record that could not be decoded
+type recordError struct {
+	Record int // 1-based position in the stream
+	Err    error
+}
+
+func (e *recordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Record, e.Err)
+}
+
+func (e *recordError) Unwrap() error { return e.Err }
+
+// sequenced is a raw or decoded record with its position in the stream
+type sequenced[T any] struct {
+	seq   int
+	value T
+	err   error
+}
+
+// orderedDecode reads raw records with read until io.EOF and decodes them on
+// workers goroutines. Decoded values are sent on the first channel and
+// records that fail to read or decode on the second, both in stream order;
+// both channels are closed at the end. At most window records are between
+// the reader and the output at any time.
+func orderedDecode[R, T any](workers, window int, read func() (R, error), decode func(R) (T, error)) (<-chan T, <-chan error) {
+	raw := make(chan sequenced[R])
+	done := make(chan sequenced[T])
+	tokens := make(chan struct{}, window) // Taken by the reader, returned by the reorder stage
+
+	// Reader: sequential, as the stream demands
+	go func() {
+		defer close(raw)
+		for seq := 0; ; seq++ {
+			r, err := read()
+			if err == io.EOF {
+				return
+			}
+			tokens <- struct{}{}
+			raw <- sequenced[R]{seq: seq, value: r, err: err}
+		}
+	}()
+
+	// Decoders: finish in any order
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range raw {
+				d := sequenced[T]{seq: r.seq, err: r.err}
+				if d.err == nil {
+					d.value, d.err = decode(r.value)
+				}
+				done <- d
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Reorder stage: holds early results until their turn
+	values := make(chan T)
+	errs := make(chan error)
+	go func() {
+		defer close(values)
+		defer close(errs)
+		pending := map[int]sequenced[T]{}
+		next := 0
+		for d := range done {
+			pending[d.seq] = d
+			for {
+				d, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+				if d.err != nil {
+					errs <- &recordError{Record: d.seq + 1, Err: d.err}
+				} else {
+					values <- d.value
+				}
+				<-tokens
+			}
+		}
+	}()
+
+	return values, errs
+}
+
+// order is the record type of the demo
+type order struct {
+	ID       int     `json:"id"`
+	Customer string  `json:"customer"`
+	Item     string  `json:"item"`
+	Qty      int     `json:"qty"`
+	Price    float64 `json:"price"`
+}
+
+func (o order) validate() error {
+	switch {
+	case o.Customer == "":
+		return errors.New("missing customer")
+	case o.Qty <= 0:
+		return fmt.Errorf("quantity %d is not positive", o.Qty)
+	}
+	return nil
+}
+
+// decodeWork simulates a decode that sometimes takes longer, such as one
+// that calls out to validate an address
+func decodeWork() {
+	clk.Sleep(time.Duration(rng.IntN(4)) * time.Millisecond)
+}
+
+const ordersJSON = `{"id":1,"customer":"alice","item":"widget","qty":3,"price":9.99}
+{"id":2,"customer":"bob","item":"gadget","qty":1,"price":24.50}
+{"id":3,"customer":"carol","item":"widget","qty":2,"price":9.99}
+{"id":4,"customer":"dave","item":"gizmo","qty":"two","price":4.25}
+{"id":5,"customer":"erin","item":"gadget","qty":4,"price":24.50}
+{"id":6,"customer":"frank","item":"widget","qty":1,"price":9.99
+{"id":7,"customer":"grace","item":"gizmo","qty":6,"price":4.25}
+{"id":8,"customer":"heidi","item":"gadget","qty":2,"price":24.50}
+{"id":9,"customer":"","item":"widget","qty":1,"price":9.99}
+{"id":10,"customer":"ivan","item":"gizmo","qty":5,"price":4.25}
+`
+
+const ordersCSV = `id,customer,item,qty,price
+11,judy,widget,2,9.99
+12,mallory,gadget,0,24.50
+13,niaj,gizmo,3,4.25
+14,olivia,widget
+15,peggy,gadget,1,24.50
+`
+
+/**
+ * Streaming JSON/CSV Processing Pipeline
+ *
+ * Orders are streamed from an NDJSON reader and from a CSV reader, decoded
+ * by four workers that finish in random order, and printed in their original
+ * order. Malformed and invalid records are reported on the error channel,
+ * with their position, while the rest of the stream carries on.
+ */
+func StreamingRecordsDemo() {
+	fmt.Fprintln(stdout, "Streaming JSON/CSV Processing Pipeline")
+
+	const workers, window = 4, 8
+	var total float64
+	var good, bad int
+
+	// consume prints the values and errors as they arrive, in stream order.
+	// Each channel is set to nil once closed, which disables its case.
+	consume := func(values <-chan order, errs <-chan error) {
+		for values != nil || errs != nil {
+			select {
+			case o, ok := <-values:
+				if !ok {
+					values = nil
+					continue
+				}
+				good++
+				total += float64(o.Qty) * o.Price
+				fmt.Fprintf(stdout, "  order %2d: %-8s %d x %-7s %7.2f\n", o.ID, o.Customer, o.Qty, o.Item, float64(o.Qty)*o.Price)
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				bad++
+				fmt.Fprintf(stdout, "  error:    %v\n", err)
+			}
+		}
+	}
+
+	// 1. Newline-delimited JSON
+	fmt.Fprintln(stdout, "\n1. NDJSON stream:")
+	lines := bufio.NewScanner(strings.NewReader(ordersJSON))
+	readLine := func() ([]byte, error) {
+		if !lines.Scan() {
+			if err := lines.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		return append([]byte(nil), lines.Bytes()...), nil // The scanner reuses its buffer
+	}
+	decodeJSON := func(line []byte) (order, error) {
+		decodeWork()
+		var o order
+		if err := json.Unmarshal(line, &o); err != nil {
+			return o, err
+		}
+		return o, o.validate()
+	}
+	consume(orderedDecode(workers, window, readLine, decodeJSON))
+
+	steps.Checkpoint("Four workers decoded the lines in whatever order they finished, but the reorder stage " +
+		"released them by sequence number, so the output matches the input.")
+
+	// 2. CSV with a header
+	fmt.Fprintln(stdout, "\n2. CSV stream:")
+	records := csv.NewReader(strings.NewReader(ordersCSV))
+	records.FieldsPerRecord = -1 // Check the field count while decoding, so a short row is one bad record
+	header, _ := records.Read()
+	readRow := func() ([]string, error) {
+		return records.Read()
+	}
+	decodeCSV := func(row []string) (order, error) {
+		decodeWork()
+		if len(row) != len(header) {
+			return order{}, fmt.Errorf("%d fields, want %d", len(row), len(header))
+		}
+		var o order
+		var err error
+		if o.ID, err = strconv.Atoi(row[0]); err != nil {
+			return o, err
+		}
+		o.Customer, o.Item = row[1], row[2]
+		if o.Qty, err = strconv.Atoi(row[3]); err != nil {
+			return o, err
+		}
+		if o.Price, err = strconv.ParseFloat(row[4], 64); err != nil {
+			return o, err
+		}
+		return o, o.validate()
+	}
+	consume(orderedDecode(workers, window, readRow, decodeCSV))
+
+	fmt.Fprintf(stdout, "\n%d orders totalling %.2f, %d records rejected\n", good, total, bad)
+	fmt.Fprintf(stdout, "At most %d records were in flight between the reader and the output\n", window)
+
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,109 @@
+/**
+ * This file demonstrates a TTL Cache with a Janitor Goroutine in Go.
+ *
+ * A cache whose entries expire needs something to remove them, and the
+ * usual answer is a background goroutine that sweeps on a ticker. That
+ * goroutine lives as long as the cache, so the cache has to own its
+ * lifecycle: a context or a Close method stops it, Close waits until it has
+ * exited, and if the owner forgets both, a cleanup attached to the cache
+ * stops it once the cache is garbage collected. The trick for the last part
+ * is that the janitor only refers to an inner struct, never to the value the
+ * caller holds.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"threads/cache"
+)
+
+const (
+	ttlCacheTTL   = 100 * time.Millisecond
+	ttlCacheSweep = 10 * time.Millisecond
+)
+
+// abandonTTLCache creates a cache and drops it without closing it, returning
+// only the channel that tells when its janitor has exited
+func abandonTTLCache() <-chan struct{} {
+	c := cache.NewTTL[string, string](context.Background(), ttlCacheTTL, ttlCacheSweep)
+	c.Put("orphan", "never closed")
+	return c.Done()
+}
+
+/**
+ * TTL Cache with a Janitor Goroutine
+ *
+ * Session tokens expire while the janitor sweeps them away. Then the
+ * janitor is stopped three ways, by cancelling the context, by Close and by
+ * dropping the cache altogether, and each time the goroutine count returns
+ * to where it was.
+ */
+func TTLCacheDemo() {
+	fmt.Fprintln(stdout, "TTL Cache with a Janitor Goroutine")
+	baseline := runtime.NumGoroutine()
+
+	// 1. Entries expire and the janitor removes them
+	fmt.Fprintf(stdout, "\n1. Sessions with a TTL of %v, swept every %v:\n", ttlCacheTTL, ttlCacheSweep)
+	sessions := cache.NewTTL[string, string](context.Background(), ttlCacheTTL, ttlCacheSweep)
+	for _, user := range []string{"alice", "bob", "carol"} {
+		sessions.Put(user, "token-"+user)
+	}
+	fmt.Fprintf(stdout, "Stored 3 sessions; janitor running: %d goroutine(s) above baseline\n", runtime.NumGoroutine()-baseline)
+
+	time.Sleep(ttlCacheTTL / 2)
+	sessions.Put("bob", "token-bob-2") // Refreshing restarts bob's TTL
+	token, ok := sessions.Get("alice")
+	fmt.Fprintf(stdout, "After %v: alice -> %q %v, bob refreshed\n", ttlCacheTTL/2, token, ok)
+
+	time.Sleep(ttlCacheTTL * 3 / 4) // alice and carol expired a quarter TTL ago
+	for _, user := range []string{"alice", "bob", "carol"} {
+		_, ok := sessions.Get(user)
+		fmt.Fprintf(stdout, "  %-5s cached: %v\n", user, ok)
+	}
+	stats := sessions.Stats()
+	fmt.Fprintf(stdout, "The janitor evicted %d expired entries; %d left\n", stats.Evictions, sessions.Len())
+
+	steps.Checkpoint("Get never returns an expired entry, but without the janitor alice and carol would " +
+		"stay in the map forever. The sweep is what bounds the memory.")
+
+	// 2. Close, the context and the garbage collector each stop the janitor
+	fmt.Fprintln(stdout, "\n2. Three ways to stop the janitor:")
+	sessions.Close()
+	fmt.Fprintf(stdout, "Close:          goroutines above baseline: %d\n", runtime.NumGoroutine()-baseline)
+	sessions.Close() // Safe to call again
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scoped := cache.NewTTL[string, string](ctx, ttlCacheTTL, ttlCacheSweep)
+	fmt.Fprintf(stdout, "NewTTL(ctx):    goroutines above baseline: %d\n", runtime.NumGoroutine()-baseline)
+	cancel()
+	<-scoped.Done()
+	fmt.Fprintf(stdout, "cancel():       goroutines above baseline: %d\n", runtime.NumGoroutine()-baseline)
+
+	// A cache that is dropped without Close
+	done := abandonTTLCache()
+	fmt.Fprintf(stdout, "Dropped cache:  goroutines above baseline: %d\n", runtime.NumGoroutine()-baseline)
+	collected := false
+	for range 10 {
+		runtime.GC() // Cleanups run after the cache is found unreachable
+		select {
+		case <-done:
+			collected = true
+		case <-time.After(10 * time.Millisecond):
+		}
+		if collected {
+			break
+		}
+	}
+	fmt.Fprintf(stdout, "After a GC:     goroutines above baseline: %d (janitor stopped: %v)\n",
+		runtime.NumGoroutine()-baseline, collected)
+
+	steps.Checkpoint("The janitor only holds the inner state, so the cache the caller dropped became " +
+		"unreachable and the cleanup registered with runtime.AddCleanup cancelled the janitor's context.")
+
+	fmt.Fprintln(stdout)
+}
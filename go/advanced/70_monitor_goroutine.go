@@ -0,0 +1,259 @@
+/**
+ * This file demonstrates a Monitor Goroutine (Serial Confinement) in Go.
+ *
+ * "Do not communicate by sharing memory; share memory by communicating."
+ * Instead of guarding state with a mutex, a single goroutine owns it and
+ * nothing else can reach it. Other goroutines send requests on a channel,
+ * each with a reply channel, and the owner serves them one at a time, so
+ * the state needs no lock at all. The same confinement makes it easy to
+ * hold back a request until it can be served, which with a mutex needs a
+ * condition variable. Each request costs two channel operations and a
+ * goroutine switch, so a mutex is faster for small critical sections.
+ */
+
+package advanced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/rng"
+)
+
+var errOutOfStock = errors.New("out of stock")
+
+// inventory is implemented by a mutex-guarded and a monitor-owned store
+type inventory interface {
+	Add(item string, n int)
+	Take(item string, n int) error
+	Count(item string) int
+}
+
+// mutexInventory guards its map with a mutex
+type mutexInventory struct {
+	mu    sync.Mutex
+	stock map[string]int
+}
+
+func (inv *mutexInventory) Add(item string, n int) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.stock[item] += n
+}
+
+func (inv *mutexInventory) Take(item string, n int) error {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	if inv.stock[item] < n {
+		return errOutOfStock
+	}
+	inv.stock[item] -= n
+	return nil
+}
+
+func (inv *mutexInventory) Count(item string) int {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.stock[item]
+}
+
+// inventoryRequest is one operation for the monitor. reply receives the
+// item's count after the operation, or an error.
+type inventoryRequest struct {
+	item  string
+	delta int  // Added to the count; negative to take
+	wait  bool // Hold a take back until there is enough stock instead of failing
+	reply chan inventoryReply
+}
+
+type inventoryReply struct {
+	count int
+	err   error
+}
+
+// monitorInventory sends every operation to the goroutine that owns the map
+type monitorInventory struct {
+	requests chan inventoryRequest
+}
+
+// newMonitorInventory starts the owner goroutine, which runs until ctx is
+// cancelled. The stock map is created inside it, so no other goroutine can
+// ever reach it.
+func newMonitorInventory(ctx context.Context) *monitorInventory {
+	inv := &monitorInventory{requests: make(chan inventoryRequest)}
+	go func() {
+		stock := map[string]int{}
+		var waiting []inventoryRequest // Takes held back until stock arrives
+		for {
+			select {
+			case req := <-inv.requests:
+				if stock[req.item]+req.delta < 0 {
+					if req.wait {
+						waiting = append(waiting, req)
+					} else {
+						req.reply <- inventoryReply{stock[req.item], errOutOfStock}
+					}
+					continue
+				}
+				stock[req.item] += req.delta
+				req.reply <- inventoryReply{count: stock[req.item]}
+
+				// Stock may have arrived for a waiting take; serve them in order
+				pending := waiting[:0]
+				for _, w := range waiting {
+					if stock[w.item]+w.delta >= 0 {
+						stock[w.item] += w.delta
+						w.reply <- inventoryReply{count: stock[w.item]}
+					} else {
+						pending = append(pending, w)
+					}
+				}
+				waiting = pending
+			case <-ctx.Done():
+				for _, w := range waiting {
+					w.reply <- inventoryReply{err: ctx.Err()}
+				}
+				return
+			}
+		}
+	}()
+	return inv
+}
+
+// do sends a request and waits for the reply. reply is buffered, so the
+// owner never blocks on a caller.
+func (inv *monitorInventory) do(item string, delta int, wait bool) inventoryReply {
+	req := inventoryRequest{item: item, delta: delta, wait: wait, reply: make(chan inventoryReply, 1)}
+	inv.requests <- req
+	return <-req.reply
+}
+
+func (inv *monitorInventory) Add(item string, n int) { inv.do(item, n, false) }
+
+func (inv *monitorInventory) Take(item string, n int) error { return inv.do(item, -n, false).err }
+
+func (inv *monitorInventory) Count(item string) int { return inv.do(item, 0, false).count }
+
+// TakeWait takes n of item, waiting until there is enough stock
+func (inv *monitorInventory) TakeWait(item string, n int) error { return inv.do(item, -n, true).err }
+
+/**
+ * Monitor Goroutine (Serial Confinement)
+ *
+ * Eight goroutines add and take stock through the same interface backed by
+ * a mutex and by a monitor goroutine; both keep the books balanced and the
+ * timings show what the channel round trips cost. Then takes that wait for
+ * stock are queued inside the monitor and served as deliveries arrive.
+ */
+func MonitorGoroutineDemo() {
+	fmt.Fprintln(stdout, "Monitor Goroutine (Serial Confinement)")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 1. The same workload against both implementations
+	const goroutines, opsEach = 8, 5000
+	items := []string{"apples", "pears", "plums"}
+	fmt.Fprintf(stdout, "\n1. %d goroutines x %d random adds and takes:\n", goroutines, opsEach)
+	fmt.Fprintf(stdout, "%-8s %10s %8s %8s %9s\n", "store", "time", "added", "taken", "balanced")
+
+	stores := []struct {
+		name string
+		inv  inventory
+	}{
+		{"mutex", &mutexInventory{stock: map[string]int{}}},
+		{"monitor", newMonitorInventory(ctx)},
+	}
+	for _, s := range stores {
+		var mu sync.Mutex
+		added, taken := map[string]int{}, map[string]int{}
+		var wg sync.WaitGroup
+		start := time.Now()
+		for range goroutines {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				myAdded, myTaken := map[string]int{}, map[string]int{}
+				for range opsEach {
+					item, n := items[rng.IntN(len(items))], 1+rng.IntN(3)
+					if rng.IntN(2) == 0 {
+						s.inv.Add(item, n)
+						myAdded[item] += n
+					} else if s.inv.Take(item, n) == nil {
+						myTaken[item] += n
+					}
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, item := range items {
+					added[item] += myAdded[item]
+					taken[item] += myTaken[item]
+				}
+			}()
+		}
+		wg.Wait()
+		elapsed := time.Since(start)
+
+		balanced := true
+		totalAdded, totalTaken := 0, 0
+		for _, item := range items {
+			balanced = balanced && s.inv.Count(item) == added[item]-taken[item]
+			totalAdded += added[item]
+			totalTaken += taken[item]
+		}
+		fmt.Fprintf(stdout, "%-8s %10v %8d %8d %9v\n", s.name, elapsed.Round(time.Millisecond), totalAdded, totalTaken, balanced)
+	}
+
+	steps.Checkpoint("The monitor's map lives inside its goroutine, so there is nothing to lock and nothing " +
+		"to forget to lock. The cost is a channel round trip per operation instead of an uncontended lock.")
+
+	// 2. Requests that wait inside the monitor
+	fmt.Fprintln(stdout, "\n2. Takes that wait for stock:")
+	store := newMonitorInventory(ctx)
+	var wg sync.WaitGroup
+	served := make(chan string, 3)
+	for _, customer := range []string{"ann", "ben", "cy"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.TakeWait("cherries", 2); err != nil {
+				served <- fmt.Sprintf("%s gave up: %v", customer, err)
+				return
+			}
+			served <- customer + " got 2 cherries"
+		}()
+	}
+	clk.Sleep(10 * time.Millisecond) // Let the takes reach the monitor
+	fmt.Fprintf(stdout, "3 customers want 2 cherries each; in stock: %d\n", store.Count("cherries"))
+
+	for _, delivery := range []int{3, 2} {
+		store.Add("cherries", delivery)
+		fmt.Fprintf(stdout, "Delivered %d:\n", delivery)
+		clk.Sleep(10 * time.Millisecond)
+	drain:
+		for {
+			select {
+			case msg := <-served:
+				fmt.Fprintf(stdout, "  %s\n", msg)
+			default:
+				break drain
+			}
+		}
+	}
+	fmt.Fprintf(stdout, "Left in stock: %d\n", store.Count("cherries"))
+
+	cancel() // Stops both monitors; a take still waiting gets the context's error
+	wg.Wait()
+	close(served)
+	for msg := range served {
+		fmt.Fprintf(stdout, "On shutdown: %s\n", msg)
+	}
+
+	steps.Checkpoint("The waiting takes are just a slice in the owner goroutine, re-checked after every " +
+		"change. A mutex version would need a sync.Cond and a loop around Wait to do the same.")
+
+	fmt.Fprintln(stdout)
+}
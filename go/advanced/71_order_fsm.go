@@ -0,0 +1,289 @@
+/**
+ * This file demonstrates a Channel-based Finite State Machine in Go.
+ *
+ * An order moves through a fixed lifecycle: created, paid, shipped,
+ * delivered, or cancelled before it ships. Modelling it as a goroutine that
+ * owns the state and receives commands on typed channels means every
+ * transition is checked against the table and applied one at a time, even
+ * when many goroutines act on the order at once. Callers see a plain API;
+ * the channels and the goroutine are an implementation detail.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/rng"
+)
+
+type orderState int
+
+const (
+	orderCreated orderState = iota
+	orderPaid
+	orderShipped
+	orderDelivered
+	orderCancelled
+)
+
+func (s orderState) String() string {
+	return [...]string{"created", "paid", "shipped", "delivered", "cancelled"}[s]
+}
+
+type orderEvent string
+
+const (
+	eventPay     orderEvent = "pay"
+	eventShip    orderEvent = "ship"
+	eventDeliver orderEvent = "deliver"
+	eventCancel  orderEvent = "cancel"
+)
+
+// orderTransitions lists the valid transitions; anything else is rejected
+var orderTransitions = map[orderState]map[orderEvent]orderState{
+	orderCreated: {eventPay: orderPaid, eventCancel: orderCancelled},
+	orderPaid:    {eventShip: orderShipped, eventCancel: orderCancelled},
+	orderShipped: {eventDeliver: orderDelivered},
+}
+
+// transitionError reports an event that is not valid in the current state
+type transitionError struct {
+	State orderState
+	Event orderEvent
+}
+
+func (e *transitionError) Error() string {
+	return fmt.Sprintf("cannot %s an order that is %s", e.Event, e.State)
+}
+
+// Commands, one type per event, each with a buffered reply channel
+type (
+	payCmd struct {
+		amount float64
+		reply  chan error
+	}
+	shipCmd struct {
+		carrier string
+		reply   chan error
+	}
+	deliverCmd struct {
+		reply chan error
+	}
+	cancelCmd struct {
+		reason string
+		reply  chan error
+	}
+)
+
+// orderSnapshot is a copy of the order's state, safe to keep
+type orderSnapshot struct {
+	State   orderState
+	Paid    float64
+	Carrier string
+	History []string
+}
+
+// shopOrder is the client API for one order. Its methods may be called
+// from any goroutine.
+type shopOrder struct {
+	id      string
+	pay     chan payCmd
+	ship    chan shipCmd
+	deliver chan deliverCmd
+	cancel  chan cancelCmd
+	query   chan chan orderSnapshot
+	done    chan struct{} // Closed when the machine stops
+}
+
+// newShopOrder starts the order's machine, which runs until ctx is cancelled
+func newShopOrder(ctx context.Context, id string) *shopOrder {
+	o := &shopOrder{
+		id:      id,
+		pay:     make(chan payCmd),
+		ship:    make(chan shipCmd),
+		deliver: make(chan deliverCmd),
+		cancel:  make(chan cancelCmd),
+		query:   make(chan chan orderSnapshot),
+		done:    make(chan struct{}),
+	}
+	go o.run(ctx)
+	return o
+}
+
+// run owns the order's state. Every command is checked against
+// orderTransitions before it changes anything.
+func (o *shopOrder) run(ctx context.Context) {
+	defer close(o.done)
+	s := orderSnapshot{State: orderCreated, History: []string{"created"}}
+
+	// apply moves to the next state if ev is valid, then runs update
+	apply := func(ev orderEvent, update func()) error {
+		next, ok := orderTransitions[s.State][ev]
+		if !ok {
+			return &transitionError{s.State, ev}
+		}
+		s.State = next
+		update()
+		return nil
+	}
+
+	for {
+		select {
+		case c := <-o.pay:
+			c.reply <- apply(eventPay, func() {
+				s.Paid = c.amount
+				s.History = append(s.History, fmt.Sprintf("paid %.2f", c.amount))
+			})
+		case c := <-o.ship:
+			c.reply <- apply(eventShip, func() {
+				s.Carrier = c.carrier
+				s.History = append(s.History, "shipped via "+c.carrier)
+			})
+		case c := <-o.deliver:
+			c.reply <- apply(eventDeliver, func() {
+				s.History = append(s.History, "delivered")
+			})
+		case c := <-o.cancel:
+			c.reply <- apply(eventCancel, func() {
+				s.History = append(s.History, "cancelled: "+c.reason)
+			})
+		case reply := <-o.query:
+			s.History = s.History[:len(s.History):len(s.History)] // Later appends must not write into the copy
+			reply <- s
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// send delivers a command to the machine and waits for its verdict
+func send[C any](o *shopOrder, ch chan C, cmd C, reply chan error) error {
+	select {
+	case ch <- cmd:
+		return <-reply
+	case <-o.done:
+		return fmt.Errorf("order %s: machine stopped", o.id)
+	}
+}
+
+func (o *shopOrder) Pay(amount float64) error {
+	reply := make(chan error, 1)
+	return send(o, o.pay, payCmd{amount, reply}, reply)
+}
+
+func (o *shopOrder) Ship(carrier string) error {
+	reply := make(chan error, 1)
+	return send(o, o.ship, shipCmd{carrier, reply}, reply)
+}
+
+func (o *shopOrder) Deliver() error {
+	reply := make(chan error, 1)
+	return send(o, o.deliver, deliverCmd{reply}, reply)
+}
+
+func (o *shopOrder) Cancel(reason string) error {
+	reply := make(chan error, 1)
+	return send(o, o.cancel, cancelCmd{reason, reply}, reply)
+}
+
+// Snapshot returns a copy of the order's current state
+func (o *shopOrder) Snapshot() orderSnapshot {
+	reply := make(chan orderSnapshot, 1)
+	select {
+	case o.query <- reply:
+		return <-reply
+	case <-o.done:
+		return orderSnapshot{}
+	}
+}
+
+/**
+ * Channel-based Finite State Machine
+ *
+ * One order goes through its whole lifecycle, a second one is sent commands
+ * that are not valid in its state and rejects them, and finally a customer
+ * and a warehouse race to cancel and ship the same paid order: exactly one
+ * of them wins.
+ */
+func OrderFSMDemo() {
+	fmt.Fprintln(stdout, "Channel-based Finite State Machine")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	report := func(what string, err error) {
+		if err != nil {
+			fmt.Fprintf(stdout, "  %-22s rejected: %v\n", what, err)
+		} else {
+			fmt.Fprintf(stdout, "  %-22s ok\n", what)
+		}
+	}
+
+	// 1. The happy path
+	fmt.Fprintln(stdout, "\n1. Order A from creation to delivery:")
+	a := newShopOrder(ctx, "A")
+	report("pay 42.50", a.Pay(42.50))
+	report("ship via parcel post", a.Ship("parcel post"))
+	report("deliver", a.Deliver())
+	snap := a.Snapshot()
+	fmt.Fprintf(stdout, "State %s, history %q\n", snap.State, snap.History)
+
+	// 2. Invalid transitions are rejected and change nothing
+	fmt.Fprintln(stdout, "\n2. Order B receives commands out of order:")
+	b := newShopOrder(ctx, "B")
+	report("ship", b.Ship("courier"))
+	report("pay 10.00", b.Pay(10))
+	report("pay 10.00 again", b.Pay(10))
+	report("ship via courier", b.Ship("courier"))
+	report("cancel", b.Cancel("changed my mind"))
+	snap = b.Snapshot()
+	fmt.Fprintf(stdout, "State %s, history %q\n", snap.State, snap.History)
+
+	steps.Checkpoint("The transition table is the single source of truth: a command that is not listed for " +
+		"the current state gets a transitionError and the order is left as it was.")
+
+	// 3. Concurrent commands are applied one at a time
+	const orders = 20
+	fmt.Fprintf(stdout, "\n3. %d paid orders, each with a cancel and a ship racing from two goroutines:\n", orders)
+	var wg sync.WaitGroup
+	outcomes := map[orderState]int{}
+	bothWon := 0
+	for i := range orders {
+		o := newShopOrder(ctx, fmt.Sprintf("C%d", i))
+		o.Pay(5)
+		var cancelErr, shipErr error
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			clk.Sleep(time.Duration(rng.IntN(100)) * time.Microsecond)
+			cancelErr = o.Cancel("customer request")
+		}()
+		go func() {
+			defer wg.Done()
+			clk.Sleep(time.Duration(rng.IntN(100)) * time.Microsecond)
+			shipErr = o.Ship("courier")
+		}()
+		wg.Wait()
+
+		outcomes[o.Snapshot().State]++
+		if cancelErr == nil && shipErr == nil {
+			bothWon++
+		}
+	}
+	fmt.Fprintf(stdout, "shipped: %d, cancelled: %d, both succeeded: %d\n",
+		outcomes[orderShipped], outcomes[orderCancelled], bothWon)
+
+	steps.Checkpoint("Whichever command reached the machine first moved the order on; the other then found " +
+		"the order in a state where it is invalid. No order was both shipped and cancelled.")
+
+	cancel()
+	<-a.done
+	err := a.Pay(1)
+	fmt.Fprintf(stdout, "\nAfter shutdown: %v\n", err)
+
+	fmt.Fprintln(stdout)
+}
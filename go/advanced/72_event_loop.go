@@ -0,0 +1,181 @@
+/**
+ * This file demonstrates the Event Loop Pattern in Go.
+ *
+ * An event loop is a single goroutine that owns all of its state and
+ * reacts to whatever happens next: a task posted by another goroutine, a
+ * timer coming due, a consumer ready for the next event, or shutdown. One
+ * select statement multiplexes them all, which is how Node.js works inside.
+ * The cases that have nothing to do are disabled by setting their channel to
+ * nil, as in the nil channel example: no timer channel while no timers are
+ * pending, and no send case while the outbox is empty. That keeps the loop
+ * from ever blocking on one source while the others wait.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// loopTimer is a callback due at a point in time
+type loopTimer struct {
+	due  time.Time
+	name string
+	fn   func()
+}
+
+// eventLoop runs tasks and timer callbacks on a single goroutine. Callbacks
+// run on that goroutine and may call after and emit directly; other
+// goroutines must go through Post.
+type eventLoop struct {
+	tasks  chan func()
+	events chan string // Emitted events, for the consumer
+	done   chan struct{}
+
+	// Owned by the loop goroutine
+	timers  []loopTimer // Sorted by due time
+	outbox  []string    // Emitted events the consumer has not taken yet
+	dropped int         // Timers still pending at shutdown
+}
+
+func newEventLoop(ctx context.Context) *eventLoop {
+	l := &eventLoop{tasks: make(chan func()), events: make(chan string), done: make(chan struct{})}
+	go l.run(ctx)
+	return l
+}
+
+func (l *eventLoop) run(ctx context.Context) {
+	defer close(l.done)
+	defer close(l.events)
+
+	for {
+		// Enable only the cases that have something to do
+		var timerC <-chan time.Time
+		if len(l.timers) > 0 {
+			timerC = clk.After(max(l.timers[0].due.Sub(clk.Now()), 0))
+		}
+		var out chan<- string
+		var next string
+		if len(l.outbox) > 0 {
+			out, next = l.events, l.outbox[0]
+		}
+
+		select {
+		case task := <-l.tasks:
+			task()
+		case now := <-timerC:
+			for len(l.timers) > 0 && !l.timers[0].due.After(now) {
+				t := l.timers[0]
+				l.timers = l.timers[1:]
+				t.fn()
+			}
+		case out <- next:
+			l.outbox = l.outbox[1:]
+		case <-ctx.Done():
+			l.dropped = len(l.timers)
+			return
+		}
+	}
+}
+
+// Post runs fn on the loop goroutine. It reports false if the loop has
+// stopped.
+func (l *eventLoop) Post(fn func()) bool {
+	select {
+	case l.tasks <- fn:
+		return true
+	case <-l.done:
+		return false
+	}
+}
+
+// after schedules fn to run on the loop in d. Loop goroutine only.
+func (l *eventLoop) after(d time.Duration, name string, fn func()) {
+	t := loopTimer{due: clk.Now().Add(d), name: name, fn: fn}
+	i, _ := slices.BinarySearchFunc(l.timers, t, func(a, b loopTimer) int { return a.due.Compare(b.due) })
+	l.timers = slices.Insert(l.timers, i, t)
+}
+
+// emit queues an event for the consumer without blocking. Loop goroutine
+// only.
+func (l *eventLoop) emit(format string, args ...any) {
+	l.outbox = append(l.outbox, fmt.Sprintf(format, args...))
+}
+
+/**
+ * Event Loop Pattern
+ *
+ * Timers are set out of order and fire in due order, one of them
+ * rescheduling itself like setInterval. Then the consumer stops reading
+ * while events pile up, and the loop still answers a ping at once. Finally
+ * cancelling the context stops the loop and drops the pending timers.
+ */
+func EventLoopDemo() {
+	fmt.Fprintln(stdout, "Event Loop Pattern")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	loop := newEventLoop(ctx)
+
+	// 1. Timers
+	fmt.Fprintln(stdout, "\n1. setTimeout and setInterval on one goroutine:")
+	loop.Post(func() {
+		for _, t := range []struct {
+			name  string
+			delay time.Duration
+		}{{"C", 30 * time.Millisecond}, {"A", 10 * time.Millisecond}, {"B", 20 * time.Millisecond}} {
+			loop.after(t.delay, t.name, func() { loop.emit("timeout %s (%v)", t.name, t.delay) })
+		}
+		ticks := 0
+		var tick func()
+		tick = func() {
+			ticks++
+			loop.emit("interval tick %d", ticks)
+			if ticks < 3 {
+				loop.after(12*time.Millisecond, "tick", tick) // Reschedule from inside the loop
+			}
+		}
+		loop.after(12*time.Millisecond, "tick", tick)
+	})
+	for range 6 {
+		fmt.Fprintf(stdout, "  %s\n", <-loop.events)
+	}
+
+	steps.Checkpoint("The loop waited on a single timer channel for the earliest deadline. A callback that " +
+		"reschedules itself calls after directly; posting to its own loop would deadlock.")
+
+	// 2. A slow consumer does not block the loop
+	fmt.Fprintln(stdout, "\n2. The consumer stops reading while 5 events are emitted:")
+	loop.Post(func() {
+		for i := 1; i <= 5; i++ {
+			loop.emit("event %d", i)
+		}
+	})
+	start := clk.Now()
+	queued := make(chan int, 1)
+	loop.Post(func() { queued <- len(loop.outbox) })
+	fmt.Fprintf(stdout, "Ping answered in %v with %d events waiting in the outbox\n",
+		clk.Since(start).Round(time.Microsecond), <-queued)
+	for range 5 {
+		fmt.Fprintf(stdout, "  %s\n", <-loop.events)
+	}
+
+	steps.Checkpoint("The send case was enabled only while the outbox had events. Sending directly from emit " +
+		"would have blocked the whole loop, timers and all, until the consumer came back.")
+
+	// 3. Shutdown with timers pending
+	fmt.Fprintln(stdout, "\n3. Shutdown:")
+	loop.Post(func() {
+		loop.after(time.Hour, "reminder", func() { loop.emit("reminder") })
+		loop.after(2*time.Hour, "report", func() { loop.emit("report") })
+	})
+	cancel()
+	<-loop.done
+	fmt.Fprintf(stdout, "Loop stopped with %d timers still pending; Post now reports %v\n",
+		loop.dropped, loop.Post(func() {}))
+
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,162 @@
+/**
+ * This file demonstrates Result Values in Pipelines in Go.
+ *
+ * A pipeline stage that can fail has two poor options if its channels
+ * carry plain values: stop the whole stream at the first error, or log the
+ * error and silently drop the item. Sending a Result[T], a value or an
+ * error, keeps each failure attached to its item. Later stages pass errors
+ * through untouched, and at the end a split stage separates the values from
+ * the errors so each can go its own way.
+ */
+
+package advanced
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"threads/chanx"
+)
+
+var errUnknownSKU = errors.New("unknown SKU")
+
+// skuPrices is the catalogue the pricing stage looks items up in
+var skuPrices = map[int]float64{101: 4.50, 102: 12.00, 103: 0.99, 105: 7.25}
+
+// orderLines is the raw input: SKU and quantity, some of it malformed
+var orderLines = []string{"101 x2", "102 x1", "10x x3", "104 x1", "103 x10", "105", "105 x4"}
+
+// parseLine reads "SKU xQTY"
+func parseLine(line string) (sku, qty int, err error) {
+	skuText, qtyText, ok := strings.Cut(line, " x")
+	if !ok {
+		return 0, 0, fmt.Errorf("line %q: missing quantity", line)
+	}
+	if sku, err = strconv.Atoi(skuText); err != nil {
+		return 0, 0, fmt.Errorf("line %q: bad SKU: %w", line, err)
+	}
+	if qty, err = strconv.Atoi(qtyText); err != nil {
+		return 0, 0, fmt.Errorf("line %q: bad quantity: %w", line, err)
+	}
+	return sku, qty, nil
+}
+
+type lineItem struct {
+	sku, qty int
+}
+
+type pricedItem struct {
+	lineItem
+	total float64
+}
+
+// priceItem looks the item up in the catalogue
+func priceItem(it lineItem) (pricedItem, error) {
+	price, ok := skuPrices[it.sku]
+	if !ok {
+		return pricedItem{}, fmt.Errorf("SKU %d: %w", it.sku, errUnknownSKU)
+	}
+	return pricedItem{it, price * float64(it.qty)}, nil
+}
+
+/**
+ * Result Values in Pipelines
+ *
+ * The same order lines go through a parse stage and a pricing stage twice:
+ * first with plain channels, where the first error stops the pipeline, then
+ * with chanx.Result, where every line comes out as a priced item or an
+ * error. A split stage finally routes values and errors to separate
+ * channels.
+ */
+func ResultPipelineDemo() {
+	fmt.Fprintln(stdout, "Result Values in Pipelines")
+	fmt.Fprintf(stdout, "Input: %q\n", orderLines)
+
+	// 1. Plain values: the first error ends the stream
+	fmt.Fprintln(stdout, "\n1. Plain channels, stop on the first error:")
+	items := make(chan lineItem)
+	var parseErr error
+	go func() {
+		defer close(items)
+		for _, line := range orderLines {
+			sku, qty, err := parseLine(line)
+			if err != nil {
+				parseErr = err // The only way to report it: give up
+				return
+			}
+			items <- lineItem{sku, qty}
+		}
+	}()
+	priced := 0
+	for it := range items {
+		if p, err := priceItem(it); err == nil {
+			priced++
+			fmt.Fprintf(stdout, "  SKU %d x%d = %.2f\n", p.sku, p.qty, p.total)
+		}
+	}
+	fmt.Fprintf(stdout, "Stopped after %d of %d lines: %v\n", priced, len(orderLines), parseErr)
+
+	steps.Checkpoint("One bad line cost every line after it. And the pricing stage had no channel to report " +
+		"its own errors on, so it could only drop the items it could not price.")
+
+	// 2. Results: every line comes out, as a value or an error
+	fmt.Fprintln(stdout, "\n2. Result channels, one outcome per line:")
+	source := func() <-chan chanx.Result[string] {
+		out := make(chan chanx.Result[string])
+		go func() {
+			defer close(out)
+			for _, line := range orderLines {
+				out <- chanx.Ok(line)
+			}
+		}()
+		return out
+	}
+	parse := func(line string) (lineItem, error) {
+		sku, qty, err := parseLine(line)
+		return lineItem{sku, qty}, err
+	}
+
+	results := chanx.MapResults(chanx.MapResults(source(), parse), priceItem)
+	for r := range results {
+		if p, err := r.Get(); err != nil {
+			fmt.Fprintf(stdout, "  error: %v\n", err)
+		} else {
+			fmt.Fprintf(stdout, "  SKU %d x%d = %.2f\n", p.sku, p.qty, p.total)
+		}
+	}
+
+	steps.Checkpoint("The pricing stage passed parse errors through untouched and added its own, so each " +
+		"failure arrived in order, next to the items around it, and no line was lost.")
+
+	// 3. Split values and errors
+	fmt.Fprintln(stdout, "\n3. Split into a value channel and an error channel:")
+	values, errs := chanx.SplitResults(chanx.MapResults(chanx.MapResults(source(), parse), priceItem))
+	var total float64
+	var unknown, malformed int
+	for values != nil || errs != nil {
+		select {
+		case p, ok := <-values:
+			if !ok {
+				values = nil
+				continue
+			}
+			total += p.total
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			if errors.Is(err, errUnknownSKU) {
+				unknown++
+			} else {
+				malformed++
+			}
+		}
+	}
+	fmt.Fprintf(stdout, "Order total %.2f; %d malformed lines, %d unknown SKUs\n", total, malformed, unknown)
+	fmt.Fprintln(stdout, "Both channels were received from in one select loop, so the split never blocked")
+
+	fmt.Fprintln(stdout)
+}
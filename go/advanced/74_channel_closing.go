@@ -0,0 +1,186 @@
+/**
+ * This file demonstrates Who Closes the Channel in Go.
+ *
+ * Closing a channel tells its receivers that no more values will come, so
+ * only a party that knows that can close it: the sender. With one sender
+ * the rule is simple, the sender closes when it is done. With several, none
+ * of them knows when the others are done, so a coordinator waits for all of
+ * them and closes once. A channel that several parties may close, such as a
+ * quit signal, is guarded by sync.Once. And a receiver never closes a
+ * channel it receives from: a sender's next send would panic.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/rng"
+)
+
+// sendOrPanic sends v on ch and returns the panic message, if any
+func sendOrPanic(ch chan<- int, v int) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprint(r)
+		}
+	}()
+	ch <- v
+	return ""
+}
+
+// closeOrPanic closes ch and returns the panic message, if any
+func closeOrPanic(ch chan struct{}) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprint(r)
+		}
+	}()
+	close(ch)
+	return ""
+}
+
+// quitSignal is a channel that any number of goroutines may close
+type quitSignal struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+func newQuitSignal() *quitSignal {
+	return &quitSignal{ch: make(chan struct{})}
+}
+
+// Quit closes the channel the first time it is called and does nothing after
+func (q *quitSignal) Quit() {
+	q.once.Do(func() { close(q.ch) })
+}
+
+func (q *quitSignal) Done() <-chan struct{} {
+	return q.ch
+}
+
+/**
+ * Who Closes the Channel
+ *
+ * A single producer closes its own channel; three producers share one
+ * channel that a coordinator closes after a WaitGroup; several goroutines
+ * race to close a quit channel guarded by sync.Once; and a receiver that
+ * closes its input makes the producer panic, shown with the panic
+ * recovered, followed by the right way for a receiver to say stop.
+ */
+func ChannelClosingDemo() {
+	fmt.Fprintln(stdout, "Who Closes the Channel")
+
+	// 1. One producer: it closes when it is done
+	fmt.Fprintln(stdout, "\n1. A single producer closes its channel:")
+	squares := make(chan int)
+	go func() {
+		defer close(squares) // The only sender, so the only one who knows it is done
+		for i := 1; i <= 5; i++ {
+			squares <- i * i
+		}
+	}()
+	var got []int
+	for v := range squares {
+		got = append(got, v)
+	}
+	fmt.Fprintf(stdout, "Receiver ranged over %v and stopped when the channel closed\n", got)
+
+	// 2. Several producers: a coordinator closes after all are done
+	fmt.Fprintln(stdout, "\n2. Three producers, one coordinator:")
+	merged := make(chan int)
+	var producers sync.WaitGroup
+	for p := 1; p <= 3; p++ {
+		producers.Add(1)
+		go func() {
+			defer producers.Done() // A producer must not close merged: the others may still send
+			for i := range 4 {
+				clk.Sleep(time.Duration(rng.IntN(5)) * time.Millisecond)
+				merged <- p*100 + i
+			}
+		}()
+	}
+	go func() {
+		producers.Wait()
+		close(merged) // Exactly once, after the last send
+	}()
+	count := 0
+	for range merged {
+		count++
+	}
+	fmt.Fprintf(stdout, "Received %d values from 3 producers; the coordinator closed after the last one\n", count)
+
+	steps.Checkpoint("None of the producers could close merged: it would not know whether the others had " +
+		"finished, and their next send would panic. The coordinator's WaitGroup does know.")
+
+	// 3. A channel several goroutines may close
+	fmt.Fprintln(stdout, "\n3. A quit signal closed through sync.Once:")
+	quit := newQuitSignal()
+	var closers sync.WaitGroup
+	for range 5 {
+		closers.Add(1)
+		go func() {
+			defer closers.Done()
+			quit.Quit()
+		}()
+	}
+	closers.Wait()
+	<-quit.Done()
+	fmt.Fprintln(stdout, "5 goroutines called Quit; the channel was closed once and no one panicked")
+
+	raw := make(chan struct{})
+	close(raw)
+	fmt.Fprintf(stdout, "Without the guard, a second close panics: %q\n", closeOrPanic(raw))
+
+	// 4. A receiver must never close
+	fmt.Fprintln(stdout, "\n4. A receiver closes its input to say stop:")
+	jobs := make(chan int)
+	panicked := make(chan string, 1)
+	next := make(chan struct{}) // Paces the producer, so that the close and the send do not also race
+	go func() {
+		for i := 1; ; i++ {
+			<-next
+			if msg := sendOrPanic(jobs, i); msg != "" {
+				panicked <- msg
+				return
+			}
+		}
+	}()
+	for range 2 {
+		next <- struct{}{}
+		<-jobs
+	}
+	close(jobs)        // Wrong: the producer still has work
+	next <- struct{}{} // and does not know
+	fmt.Fprintf(stdout, "The producer's next send panicked (recovered here): %q\n", <-panicked)
+
+	fmt.Fprintln(stdout, "The right way: the receiver closes a separate done channel, the producer stops and closes jobs:")
+	jobs = make(chan int)
+	done := make(chan struct{})
+	go func() {
+		defer close(jobs)
+		for i := 1; ; i++ {
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+	<-jobs
+	<-jobs
+	close(done)
+	drained := 0
+	for range jobs {
+		drained++ // At most the one send that raced with done
+	}
+	// Whether the raced send happened is up to select, so only the bound is printed
+	fmt.Fprintf(stdout, "Producer stopped cleanly; at most one value drained after done was closed: %v\n", drained <= 1)
+
+	steps.Checkpoint("Closing is a message from senders to receivers. Receivers that want the senders to " +
+		"stop send a message the other way, on a channel the receivers own.")
+
+	fmt.Fprintln(stdout)
+}
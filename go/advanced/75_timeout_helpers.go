@@ -0,0 +1,110 @@
+/**
+ * This file demonstrates Cancellable Sleep and Timeout Helpers in Go.
+ *
+ * Many examples wait with a hand-written select on time.After: sleep unless
+ * cancelled, receive unless too slow, send unless nobody is listening. Each
+ * copy is a chance to forget the cancellation case or to leave a timer
+ * running until it fires. ctxx.Sleep, chanx.RecvTimeout and
+ * chanx.SendTimeout wrap the three selects once, stop their timer as soon
+ * as they return, and report the outcome as an error.
+ */
+
+package advanced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"threads/chanx"
+	"threads/ctxx"
+)
+
+/**
+ * Cancellable Sleep and Timeout Helpers
+ *
+ * A polling loop backs off with ctxx.Sleep and stops in the middle of a
+ * sleep when its context is cancelled. A consumer reads a slow producer
+ * with RecvTimeout, telling timeouts from the end of the stream, and a
+ * producer sheds load with SendTimeout when its consumer stalls.
+ */
+func TimeoutHelpersDemo() {
+	fmt.Fprintln(stdout, "Cancellable Sleep and Timeout Helpers")
+
+	// 1. Sleep that returns early when cancelled
+	fmt.Fprintln(stdout, "\n1. A polling loop with ctxx.Sleep, cancelled after 100ms:")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	backoff := 20 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		fmt.Fprintf(stdout, "  poll %d, then sleep %v\n", attempt, backoff)
+		if err := ctxx.Sleep(ctx, backoff); err != nil {
+			fmt.Fprintf(stdout, "Sleep returned %v after %v in total, without finishing the %v sleep\n",
+				err, time.Since(start).Round(10*time.Millisecond), backoff)
+			break
+		}
+		backoff *= 2
+	}
+
+	steps.Checkpoint("time.Sleep cannot be interrupted, so a loop built on it notices cancellation only " +
+		"after the sleep. ctxx.Sleep selects on the context too and stops its timer on the way out.")
+
+	// 2. Receive with a timeout
+	fmt.Fprintln(stdout, "\n2. Reading a slow producer with chanx.RecvTimeout(ch, 30ms):")
+	readings := make(chan int)
+	go func() {
+		defer close(readings)
+		for i, delay := range []time.Duration{10, 50, 10} {
+			time.Sleep(delay * time.Millisecond)
+			readings <- i + 1
+		}
+	}()
+	for {
+		v, err := chanx.RecvTimeout(readings, 30*time.Millisecond)
+		if errors.Is(err, chanx.ErrClosed) {
+			fmt.Fprintln(stdout, "  channel closed: done")
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(stdout, "  %v, still waiting\n", err)
+			continue
+		}
+		fmt.Fprintf(stdout, "  reading %d\n", v)
+	}
+
+	// 3. Send with a timeout
+	fmt.Fprintln(stdout, "\n3. Shedding load with chanx.SendTimeout(ch, v, 10ms):")
+	work := make(chan int, 2)
+	stalled := make(chan struct{})
+	consumed := make(chan int)
+	go func() {
+		n := 0
+		for range 2 {
+			<-work
+			n++
+		}
+		<-stalled // The consumer stalls
+		for range work {
+			n++
+		}
+		consumed <- n
+	}()
+	sent, shed := 0, 0
+	for i := 1; i <= 8; i++ {
+		if err := chanx.SendTimeout(work, i, 10*time.Millisecond); err != nil {
+			shed++
+			continue
+		}
+		sent++
+	}
+	close(stalled)
+	close(work)
+	fmt.Fprintf(stdout, "Sent %d jobs, shed %d after a 10ms wait each; the consumer handled %d\n", sent, shed, <-consumed)
+
+	steps.Checkpoint("Each helper returns an error that says what happened: ErrTimeout, ErrClosed or the " +
+		"context's error, so callers decide whether to retry, give up or drop.")
+
+	fmt.Fprintln(stdout)
+}
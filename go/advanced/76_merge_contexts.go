@@ -0,0 +1,98 @@
+/**
+ * This file demonstrates Merging Contexts in Go.
+ *
+ * A context has one parent, but work often has two reasons to stop: the
+ * request it serves was abandoned, or the whole server is shutting down.
+ * ctxx.MergeContexts derives a context from the request and registers a
+ * context.AfterFunc on the shutdown context, so the merged context is done
+ * when either is, with the cause of whichever finished first. AfterFunc runs
+ * no goroutine while it waits, unlike the obvious hand-rolled version with a
+ * goroutine selecting on both Done channels, which also leaks when neither
+ * ever finishes.
+ */
+
+package advanced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"threads/ctxx"
+)
+
+var errClientGone = errors.New("client disconnected")
+
+// handleJob works in n steps of 10ms until it is finished or ctx is done,
+// and describes how it ended
+func handleJob(ctx context.Context, n int) string {
+	for i := range n {
+		if err := ctxx.Sleep(ctx, 10*time.Millisecond); err != nil {
+			return fmt.Sprintf("stopped after %d of %d steps: err=%v, cause=%v", i, n, err, context.Cause(ctx))
+		}
+	}
+	return fmt.Sprintf("finished all %d steps", n)
+}
+
+/**
+ * Merging Contexts
+ *
+ * A job runs under a request context merged with a server context. It is
+ * stopped by the client disconnecting, by the server shutting down and by
+ * the request's deadline, and each time context.Cause says which. Finally
+ * a thousand merged contexts are created and cancelled without leaving a
+ * goroutine behind.
+ */
+func MergeContextsDemo() {
+	fmt.Fprintln(stdout, "Merging Contexts")
+
+	run := func(name string, request, server context.Context, stop func()) {
+		ctx, cancel := ctxx.MergeContexts(request, server)
+		defer cancel()
+		if stop != nil {
+			time.AfterFunc(35*time.Millisecond, stop)
+		}
+		fmt.Fprintf(stdout, "  %-22s %s\n", name+":", handleJob(ctx, 6))
+	}
+
+	fmt.Fprintln(stdout, "\n1. A 6-step job under a merged request and server context:")
+
+	request, cancelRequest := context.WithCancelCause(context.Background())
+	server, cancelServer := context.WithCancelCause(context.Background())
+	run("nothing happens", request, server, nil)
+
+	run("client disconnects", request, server, func() { cancelRequest(errClientGone) })
+
+	request, cancelRequest = context.WithCancelCause(context.Background())
+	run("server shuts down", request, server, func() { cancelServer(errShutdown) })
+	cancelRequest(nil)
+
+	server, cancelServer = context.WithCancelCause(context.Background())
+	timed, cancelTimed := context.WithTimeout(context.Background(), 25*time.Millisecond)
+	run("request times out", timed, server, nil)
+	cancelTimed()
+
+	steps.Checkpoint("The job only sees one context. context.Cause tells it which parent stopped it, so it " +
+		"can, for example, log a shutdown differently from a client that went away.")
+
+	// 2. No goroutine per merged context
+	fmt.Fprintln(stdout, "\n2. 1000 merged contexts waiting on a server context that never finishes:")
+	before := runtime.NumGoroutine()
+	cancels := make([]context.CancelFunc, 1000)
+	for i := range cancels {
+		_, cancels[i] = ctxx.MergeContexts(context.Background(), server)
+	}
+	fmt.Fprintf(stdout, "Extra goroutines while they wait: %d\n", runtime.NumGoroutine()-before)
+	for _, cancel := range cancels {
+		cancel() // Unregisters the AfterFunc from the server context
+	}
+	fmt.Fprintf(stdout, "Extra goroutines after cancel:    %d\n", runtime.NumGoroutine()-before)
+	cancelServer(nil)
+
+	steps.Checkpoint("context.AfterFunc registers a callback instead of parking a goroutine, and the " +
+		"CancelFunc unregisters it, so merged contexts cost nothing while they wait and leave nothing behind.")
+
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,241 @@
+/**
+ * This file demonstrates a Delay Queue in Go.
+ *
+ * time.After or time.AfterFunc per item works for a handful of delayed
+ * jobs, but every pending job then owns a timer and cancelling one means
+ * finding and stopping it. A delay queue keeps the scheduled items in a
+ * min-heap ordered by due time and runs a single goroutine with a single
+ * timer, always set for the earliest item. Scheduling an earlier item or
+ * cancelling one nudges the goroutine to re-arm the timer, and items are
+ * released on a channel once their time has come.
+ */
+
+package advanced
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/topology"
+)
+
+// delayItem is a value scheduled for release at due
+type delayItem struct {
+	id    int
+	due   time.Time
+	value string
+	index int // Position in the heap, kept up to date by delayHeap
+}
+
+// delayHeap is a min-heap of items by due time, for container/heap
+type delayHeap []*delayItem
+
+func (h delayHeap) Len() int           { return len(h) }
+func (h delayHeap) Less(i, j int) bool { return h[i].due.Before(h[j].due) }
+func (h delayHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *delayHeap) Push(x any) {
+	item := x.(*delayItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+func (h *delayHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// delayQueue releases scheduled values on its output channel once they are
+// due. Schedule and Cancel may be called from any goroutine.
+type delayQueue struct {
+	mu     sync.Mutex
+	items  delayHeap
+	byID   map[int]*delayItem
+	nextID int
+
+	wake chan struct{} // Buffered; tells the timer goroutine the earliest item changed
+	out  chan string
+}
+
+// newDelayQueue starts the timer goroutine, which runs until ctx is
+// cancelled and then closes the output channel
+func newDelayQueue(ctx context.Context) *delayQueue {
+	q := &delayQueue{byID: map[int]*delayItem{}, wake: make(chan struct{}, 1), out: make(chan string)}
+	go q.run(ctx)
+	return q
+}
+
+// Schedule queues value for release after delay and returns its id
+func (q *delayQueue) Schedule(value string, delay time.Duration) int {
+	q.mu.Lock()
+	q.nextID++
+	item := &delayItem{id: q.nextID, due: clk.Now().Add(delay), value: value}
+	heap.Push(&q.items, item)
+	q.byID[item.id] = item
+	earliest := item.index == 0
+	q.mu.Unlock()
+
+	if earliest {
+		q.nudge()
+	}
+	return item.id
+}
+
+// Cancel removes a scheduled item and reports whether it was still pending
+func (q *delayQueue) Cancel(id int) bool {
+	q.mu.Lock()
+	item, ok := q.byID[id]
+	if ok {
+		heap.Remove(&q.items, item.index)
+		delete(q.byID, id)
+	}
+	q.mu.Unlock()
+
+	if ok {
+		q.nudge()
+	}
+	return ok
+}
+
+// nudge wakes the timer goroutine without blocking; one pending wake-up is
+// as good as many
+func (q *delayQueue) nudge() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// C returns the channel that due values are released on
+func (q *delayQueue) C() <-chan string {
+	return q.out
+}
+
+// Pending returns the number of scheduled items not yet released
+func (q *delayQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// run owns the one timer. It releases every due item, then sleeps until the
+// next one is due or a nudge says the schedule changed.
+func (q *delayQueue) run(ctx context.Context) {
+	defer close(q.out)
+	timer := clk.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		// Take the due items out of the heap, and find when the next one is due
+		var ready []string
+		wait := time.Duration(-1) // Nothing scheduled
+		q.mu.Lock()
+		now := clk.Now()
+		for len(q.items) > 0 {
+			next := q.items[0]
+			if next.due.After(now) {
+				wait = next.due.Sub(now)
+				break
+			}
+			heap.Pop(&q.items)
+			delete(q.byID, next.id)
+			ready = append(ready, next.value)
+		}
+		q.mu.Unlock()
+
+		for _, v := range ready {
+			select {
+			case q.out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if len(ready) > 0 {
+			continue // Time passed while sending; look again
+		}
+
+		var timerC <-chan time.Time
+		if wait >= 0 {
+			timer.Reset(wait) // Since Go 1.23, Reset also discards a stale expiry
+			timerC = timer.C
+		}
+		select {
+		case <-timerC:
+		case <-q.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+/**
+ * Delay Queue
+ *
+ * Five jobs are scheduled out of order and one of them is cancelled. A job
+ * due sooner than everything else is added while the queue is waiting, and
+ * the timer goroutine re-arms for it. Jobs come out in due order, and one
+ * goroutine and one timer served them all.
+ */
+func DelayQueueDemo() {
+	fmt.Fprintln(stdout, "Delay Queue")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q := newDelayQueue(ctx)
+	start := clk.Now()
+
+	// 1. Schedule out of order, cancel one
+	fmt.Fprintln(stdout, "\n1. Scheduling:")
+	ids := map[string]int{}
+	for _, job := range []struct {
+		name  string
+		delay time.Duration
+	}{{"send-report", 80}, {"refresh-cache", 20}, {"retry-payment", 60}, {"expire-session", 40}, {"ping-peer", 100}} {
+		ids[job.name] = q.Schedule(job.name, job.delay*time.Millisecond)
+		fmt.Fprintf(stdout, "  %-14s in %3dms\n", job.name, job.delay)
+	}
+	fmt.Fprintf(stdout, "Cancel retry-payment: %v\n", q.Cancel(ids["retry-payment"]))
+
+	// 2. An earlier job arrives while the queue waits for refresh-cache
+	go func() {
+		clk.Sleep(5 * time.Millisecond)
+		q.Schedule("urgent-alert", 5*time.Millisecond)
+	}()
+
+	fmt.Fprintln(stdout, "\n2. Released in due order:")
+	for range 5 {
+		job := <-q.C()
+		fmt.Fprintf(stdout, "  %-14s at ~%3dms\n", job, clk.Since(start).Round(10*time.Millisecond).Milliseconds())
+	}
+	fmt.Fprintf(stdout, "Pending: %d; cancelling a job that already ran: %v\n", q.Pending(), q.Cancel(ids["refresh-cache"]))
+
+	steps.Checkpoint("urgent-alert was due before the timer's next expiry, so Schedule nudged the goroutine, " +
+		"which re-armed the timer for it. retry-payment was removed from the heap and never fired.")
+
+	cancel()
+	_, open := <-q.C()
+	fmt.Fprintf(stdout, "After cancel the output channel is closed: %v\n", !open)
+
+	fmt.Fprintln(stdout)
+}
+
+// DelayQueueTopology describes the goroutines and channels of the demo. The
+// heap itself is shared under a mutex, not passed over a channel.
+func DelayQueueTopology() *topology.Graph {
+	return topology.New("Delay Queue").
+		Goroutine("schedulers", "Schedule / Cancel callers (push / remove in the heap)").
+		Channel("wake", "wake (cap 1, non-blocking)").
+		Goroutine("timer", "timer goroutine (one time.Timer, pops due items)").
+		Channel("out", "out").
+		Goroutine("consumer", "consumer").
+		Flow("schedulers", "wake", "nudge").
+		Flow("wake", "timer", "").
+		Flow("timer", "out", "due items").
+		Flow("out", "consumer", "")
+}
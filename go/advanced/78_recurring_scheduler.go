@@ -0,0 +1,196 @@
+/**
+ * This file demonstrates a Recurring Job Scheduler in Go.
+ *
+ * A cron-like scheduler runs jobs on intervals, and sooner or later a run
+ * takes longer than the interval. Starting another copy anyway can pile up
+ * work and make runs race each other, so each job has an overlap policy
+ * enforced by a one-slot semaphore: skip the run while the previous one is
+ * still going, or coalesce the missed runs into a single run that starts as
+ * soon as the previous one ends. One scheduler goroutine owns the ticker and
+ * the due times, and shutdown stops the ticker, cancels the running jobs and
+ * waits for them.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/clock"
+)
+
+// overlapPolicy says what happens when a job is due while it is still
+// running
+type overlapPolicy int
+
+const (
+	overlapSkip     overlapPolicy = iota // Drop the run
+	overlapCoalesce                      // Run once more when the current run ends, however many were missed
+)
+
+func (p overlapPolicy) String() string {
+	return [...]string{"skip", "coalesce"}[p]
+}
+
+// recurringJob is a registered job and its counters
+type recurringJob struct {
+	name   string
+	every  time.Duration
+	policy overlapPolicy
+	run    func(ctx context.Context)
+
+	sem     chan struct{} // One slot: held while the job runs
+	pending atomic.Bool   // A coalesced run is owed
+
+	due, runs, skipped, coalesced atomic.Int64
+	running, maxRunning           atomic.Int32
+}
+
+// recurringScheduler runs its jobs from a single goroutine and ticker
+type recurringScheduler struct {
+	tick time.Duration // Resolution of the schedule
+	jobs []*recurringJob
+	runs sync.WaitGroup // Job runs in progress
+	done chan struct{}  // Closed when the scheduler goroutine has stopped
+}
+
+func (s *recurringScheduler) Register(name string, every time.Duration, policy overlapPolicy, run func(ctx context.Context)) {
+	s.jobs = append(s.jobs, &recurringJob{name: name, every: every, policy: policy, run: run, sem: make(chan struct{}, 1)})
+}
+
+// Start runs the scheduler goroutine until ctx is cancelled. Jobs receive
+// ctx, so they are asked to stop at the same time.
+func (s *recurringScheduler) Start(ctx context.Context) {
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := clock.NewTicker(clk, s.tick)
+		defer ticker.Stop() // An unstopped ticker keeps its goroutine running
+
+		next := make([]time.Time, len(s.jobs))
+		start := clk.Now()
+		for i, job := range s.jobs {
+			next[i] = start.Add(job.every)
+		}
+		for {
+			select {
+			case now := <-ticker.C:
+				for i, job := range s.jobs {
+					if now.Before(next[i]) {
+						continue
+					}
+					for !now.Before(next[i]) {
+						next[i] = next[i].Add(job.every) // Missed ticks collapse into one due run
+					}
+					s.fire(ctx, job)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// fire starts job if its slot is free, and otherwise applies its policy.
+// It never blocks the scheduler goroutine.
+func (s *recurringScheduler) fire(ctx context.Context, job *recurringJob) {
+	job.due.Add(1)
+	select {
+	case job.sem <- struct{}{}:
+	default:
+		if job.policy == overlapCoalesce && job.pending.Swap(true) {
+			job.coalesced.Add(1) // Already owed a run; this one merges into it
+		} else if job.policy == overlapSkip {
+			job.skipped.Add(1)
+		}
+		return
+	}
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		for {
+			n := job.running.Add(1)
+			for {
+				m := job.maxRunning.Load()
+				if n <= m || job.maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			job.runs.Add(1)
+			job.run(ctx)
+			job.running.Add(-1)
+
+			// Keep the slot for an owed run, unless we are shutting down
+			if job.policy == overlapCoalesce && job.pending.Swap(false) && ctx.Err() == nil {
+				continue
+			}
+			<-job.sem
+			return
+		}
+	}()
+}
+
+// Stop waits for the scheduler goroutine and every running job to finish.
+// Cancel the context passed to Start first.
+func (s *recurringScheduler) Stop() {
+	<-s.done
+	s.runs.Wait()
+}
+
+/**
+ * Recurring Job Scheduler
+ *
+ * Three jobs run every 20ms for a quarter of a second: a quick one, and two
+ * that take 50ms, one skipping overlapping runs and one coalescing them.
+ * No job ever runs twice at once. Shutdown cancels the context, which
+ * stops the ticker and cuts the slow runs short, and waits for them.
+ */
+func RecurringSchedulerDemo() {
+	fmt.Fprintln(stdout, "Recurring Job Scheduler")
+
+	work := func(d time.Duration) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			select {
+			case <-clk.After(d):
+			case <-ctx.Done(): // Shutdown interrupts a run
+			}
+		}
+	}
+
+	s := &recurringScheduler{tick: 5 * time.Millisecond}
+	s.Register("metrics", 20*time.Millisecond, overlapSkip, work(2*time.Millisecond))
+	s.Register("backup", 20*time.Millisecond, overlapSkip, work(50*time.Millisecond))
+	s.Register("sync", 20*time.Millisecond, overlapCoalesce, work(50*time.Millisecond))
+
+	const runFor = 250 * time.Millisecond
+	fmt.Fprintf(stdout, "\n1. Jobs every 20ms for %v:\n", runFor)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	clk.Sleep(runFor)
+
+	steps.Checkpoint("backup and sync take 50ms but are due every 20ms. Their one-slot semaphores are full " +
+		"most of the time, so the scheduler applies their overlap policy instead of starting another copy.")
+
+	// 2. Shutdown
+	start := clk.Now()
+	cancel()
+	s.Stop()
+	fmt.Fprintf(stdout, "Shutdown: ticker stopped, running jobs cancelled and waited for in %v\n",
+		clk.Since(start).Round(time.Microsecond))
+
+	fmt.Fprintf(stdout, "\n2. Results:\n%-8s %-9s %4s %5s %8s %10s %12s\n", "job", "policy", "due", "runs", "skipped", "coalesced", "max running")
+	for _, job := range s.jobs {
+		fmt.Fprintf(stdout, "%-8s %-9s %4d %5d %8d %10d %12d\n", job.name, job.policy,
+			job.due.Load(), job.runs.Load(), job.skipped.Load(), job.coalesced.Load(), job.maxRunning.Load())
+	}
+	fmt.Fprintln(stdout, "\nskip drops the runs that fall inside a long run; coalesce owes one run and merges the rest,")
+	fmt.Fprintln(stdout, "so it starts again right after each run and keeps the job busy without ever doubling up.")
+
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,231 @@
+/**
+ * This file demonstrates an At-least-once Job Queue in Go.
+ *
+ * A queue that must not lose jobs keeps each one in its store until a
+ * consumer acknowledges it. A delivered job is only leased: if the consumer
+ * crashes before acking, the lease runs out and the job is delivered again.
+ * That guarantees every job is processed at least once, and it also means
+ * some are processed twice: a consumer that crashed after doing the work
+ * but before the ack. Consumers therefore have to be idempotent, for
+ * example by recording the ids of the jobs whose effect they have applied
+ * in the same step as the effect.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/clock"
+	"threads/rng"
+)
+
+// queuedJob is a job in the store
+type queuedJob struct {
+	id       int
+	amount   int       // Credit to apply
+	attempts int       // Deliveries so far
+	leased   time.Time // Zero while ready; lease expiry while in flight
+	acked    bool
+}
+
+// jobStore is the queue's durable state. It outlives the consumers, which
+// is what lets jobs survive their crashes.
+type jobStore struct {
+	mu      sync.Mutex
+	jobs    []*queuedJob
+	lease   time.Duration
+	pending int           // Jobs not yet acked
+	drained chan struct{} // Closed when every job has been acked
+	ready   chan struct{} // One buffered wake-up per job made ready again, so idle consumers can wait
+
+	deliveries, redeliveries int
+}
+
+func newJobStore(lease time.Duration, amounts []int) *jobStore {
+	s := &jobStore{lease: lease, pending: len(amounts), drained: make(chan struct{}), ready: make(chan struct{}, len(amounts))}
+	for i, amount := range amounts {
+		s.jobs = append(s.jobs, &queuedJob{id: i + 1, amount: amount})
+	}
+	return s
+}
+
+// dequeue leases the first ready job, if any
+func (s *jobStore) dequeue() (queuedJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if !j.acked && j.leased.IsZero() {
+			j.leased = clk.Now().Add(s.lease)
+			j.attempts++
+			s.deliveries++
+			if j.attempts > 1 {
+				s.redeliveries++
+			}
+			return *j, true
+		}
+	}
+	return queuedJob{}, false
+}
+
+// ack marks a job done. Acking a job twice, or a job whose lease has been
+// given to another consumer, is harmless.
+func (s *jobStore) ack(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j := s.jobs[id-1]
+	if j.acked {
+		return
+	}
+	j.acked = true
+	s.pending--
+	if s.pending == 0 {
+		close(s.drained)
+	}
+}
+
+// reap makes the jobs whose lease has expired ready again
+func (s *jobStore) reap(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if !j.acked && !j.leased.IsZero() && now.After(j.leased) {
+			j.leased = time.Time{}
+			select {
+			case s.ready <- struct{}{}:
+			default: // Already full of wake-ups
+			}
+		}
+	}
+}
+
+// ledger is the consumers' side effect: credits applied to an account
+type ledger struct {
+	mu         sync.Mutex
+	balance    int
+	applied    map[int]bool // Job ids already applied; nil for a non-idempotent ledger
+	duplicates int
+}
+
+// credit applies a job's amount. An idempotent ledger checks and records
+// the job id under the same lock as the balance change.
+func (l *ledger) credit(id, amount int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.applied != nil {
+		if l.applied[id] {
+			l.duplicates++
+			return
+		}
+		l.applied[id] = true
+	}
+	l.balance += amount
+}
+
+/**
+ * At-least-once Job Queue
+ *
+ * Three consumers work through 30 credit jobs and crash now and then,
+ * either before doing the work or after doing it but before the ack. A
+ * reaper returns the jobs with expired leases to the queue. Every job is
+ * processed, and the duplicates show up in a naive ledger but not in an
+ * idempotent one.
+ */
+func JobQueueDemo() {
+	fmt.Fprintln(stdout, "At-least-once Job Queue")
+
+	const jobs, consumers, lease = 30, 3, 20 * time.Millisecond
+	amounts := make([]int, jobs)
+	want := 0
+	for i := range amounts {
+		amounts[i] = 10 * (1 + rng.IntN(10))
+		want += amounts[i]
+	}
+	store := newJobStore(lease, amounts)
+	naive := &ledger{}
+	idempotent := &ledger{applied: map[int]bool{}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+
+	// Reaper: returns expired leases to the queue
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := clock.NewTicker(clk, lease/4)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				store.reap(now)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Consumers: a crash loses the consumer's progress on its current job,
+	// but not the job itself, which is still in the store
+	var mu sync.Mutex
+	crashedBefore, crashedAfter := 0, 0
+	for range consumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, ok := store.dequeue()
+				if !ok {
+					select {
+					case <-store.ready:
+						continue
+					case <-ctx.Done():
+						return
+					}
+				}
+				clk.Sleep(time.Millisecond) // Process
+
+				switch rng.IntN(10) {
+				case 0: // Crash before applying the effect
+					mu.Lock()
+					crashedBefore++
+					mu.Unlock()
+					continue
+				case 1: // Crash after applying the effect, before the ack
+					naive.credit(job.id, job.amount)
+					idempotent.credit(job.id, job.amount)
+					mu.Lock()
+					crashedAfter++
+					mu.Unlock()
+					continue
+				}
+				naive.credit(job.id, job.amount)
+				idempotent.credit(job.id, job.amount)
+				store.ack(job.id)
+			}
+		}()
+	}
+
+	fmt.Fprintf(stdout, "\n1. %d consumers, %d jobs, %v leases, a 1-in-5 chance of crashing per delivery:\n", consumers, jobs, lease)
+	<-store.drained
+	cancel()
+	wg.Wait()
+
+	fmt.Fprintf(stdout, "Every job acked after %d deliveries (%d redeliveries)\n", store.deliveries, store.redeliveries)
+	fmt.Fprintf(stdout, "Crashes before the work: %d; after the work, before the ack: %d\n", crashedBefore, crashedAfter)
+
+	steps.Checkpoint("A crash never lost a job: it was still in the store, leased, and the reaper made it " +
+		"ready again when the lease ran out. Crashes after the work made the redelivery a duplicate.")
+
+	// 2. The effect of duplicates
+	fmt.Fprintln(stdout, "\n2. Ledgers, expected balance", want)
+	fmt.Fprintf(stdout, "  naive:      %5d (off by %d)\n", naive.balance, naive.balance-want)
+	fmt.Fprintf(stdout, "  idempotent: %5d (%d duplicate deliveries ignored)\n", idempotent.balance, idempotent.duplicates)
+
+	fmt.Fprintln(stdout, "\nAt-least-once delivery plus an idempotent consumer gives an exactly-once effect.")
+
+	fmt.Fprintln(stdout)
+}
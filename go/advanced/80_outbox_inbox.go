@@ -0,0 +1,223 @@
+/**
+ * This file demonstrates the Transactional Outbox and Inbox in Go.
+ *
+ * A service that saves an order and then publishes an "order placed" event
+ * makes two writes to two systems, and a crash between them loses the
+ * event. The outbox pattern writes the event to an outbox table in the same
+ * transaction as the order; a relay goroutine later publishes unpublished
+ * outbox rows to the broker and marks them published. If the relay crashes
+ * between publishing and marking, it publishes the row again, so delivery
+ * is at least once. The consumer's inbox records the ids of the events it
+ * has handled, in the same transaction as their effect, and drops
+ * duplicates.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"threads/clock"
+	"threads/rng"
+	"threads/topology"
+)
+
+// orderEventMsg is what the broker carries
+type orderEventMsg struct {
+	id    int // Outbox row id, used by the inbox to deduplicate
+	order string
+}
+
+// outboxRow is an event waiting to be published
+type outboxRow struct {
+	event     orderEventMsg
+	published bool
+}
+
+// orderDB is the service's database: the mutex plays the part of a
+// transaction around the orders and the outbox
+type orderDB struct {
+	mu     sync.Mutex
+	orders []string
+	outbox []outboxRow
+}
+
+// placeOrder saves the order and its event atomically
+func (db *orderDB) placeOrder(order string) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.orders = append(db.orders, order)
+	db.outbox = append(db.outbox, outboxRow{event: orderEventMsg{id: len(db.outbox) + 1, order: order}})
+}
+
+// unpublished returns a copy of the rows not yet marked published
+func (db *orderDB) unpublished() []orderEventMsg {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var events []orderEventMsg
+	for _, row := range db.outbox {
+		if !row.published {
+			events = append(events, row.event)
+		}
+	}
+	return events
+}
+
+func (db *orderDB) markPublished(id int) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.outbox[id-1].published = true
+}
+
+// relayOutbox polls the outbox and publishes new rows until ctx is done.
+// With crashRate > 0 it sometimes "crashes" after publishing a row and
+// before marking it, so that row is published again on the next poll.
+func relayOutbox(ctx context.Context, db *orderDB, broker chan<- orderEventMsg, crashRate int, crashes *int) {
+	ticker := clock.NewTicker(clk, 2*time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	poll:
+		for _, ev := range db.unpublished() {
+			select {
+			case broker <- ev:
+			case <-ctx.Done():
+				return
+			}
+			if rng.IntN(100) < crashRate {
+				*crashes++ // Only the relay goroutine touches it
+				break poll // The relay restarts and polls again
+			}
+			db.markPublished(ev.id)
+		}
+	}
+}
+
+// inboxConsumer handles events from the broker, skipping the ids already in
+// its inbox
+type inboxConsumer struct {
+	mu         sync.Mutex
+	inbox      map[int]bool
+	shipped    []string
+	duplicates int
+}
+
+// handle records the event id and its effect in one step, so an event is
+// either fully handled or not at all
+func (c *inboxConsumer) handle(ev orderEventMsg) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inbox[ev.id] {
+		c.duplicates++
+		return
+	}
+	c.inbox[ev.id] = true
+	c.shipped = append(c.shipped, ev.order)
+}
+
+/**
+ * Transactional Outbox and Inbox
+ *
+ * First the naive dual write loses events when the service crashes between
+ * saving and publishing. Then orders are placed through the outbox while a
+ * relay that sometimes crashes publishes them, and an inbox consumer ends
+ * up handling every order exactly once despite the duplicates.
+ */
+func OutboxInboxDemo() {
+	fmt.Fprintln(stdout, "Transactional Outbox and Inbox")
+
+	const orders = 40
+
+	// 1. Dual write
+	fmt.Fprintln(stdout, "\n1. Save, then publish (dual write), crashing 1 time in 10 in between:")
+	saved, published := 0, 0
+	for range orders {
+		saved++ // Committed to the database
+		if rng.IntN(10) == 0 {
+			continue // Crash: the event is gone, the order is not
+		}
+		published++
+	}
+	fmt.Fprintf(stdout, "%d orders saved, %d events published: %d orders will never ship\n", saved, published, saved-published)
+
+	// 2. Outbox, relay and inbox
+	fmt.Fprintln(stdout, "\n2. Outbox + relay (crashing 1 time in 10 after publishing) + inbox:")
+	db := &orderDB{}
+	broker := make(chan orderEventMsg, 8)
+	consumer := &inboxConsumer{inbox: map[int]bool{}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var relayCrashes int
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relayOutbox(ctx, db, broker, 10, &relayCrashes)
+	}()
+
+	var consumers sync.WaitGroup
+	delivered := 0
+	consumers.Add(1)
+	go func() {
+		defer consumers.Done()
+		for ev := range broker {
+			delivered++
+			consumer.handle(ev)
+		}
+	}()
+
+	// Writers place orders concurrently
+	var writers sync.WaitGroup
+	for w := range 4 {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			for i := range orders / 4 {
+				db.placeOrder(fmt.Sprintf("order-%d-%02d", w, i))
+				clk.Sleep(time.Duration(rng.IntN(500)) * time.Microsecond)
+			}
+		}()
+	}
+	writers.Wait()
+
+	// Wait for the relay to publish everything, then shut down in order
+	for len(db.unpublished()) > 0 {
+		clk.Sleep(2 * time.Millisecond)
+	}
+	cancel()
+	<-relayDone
+	close(broker) // The relay was the only sender
+	consumers.Wait()
+
+	fmt.Fprintf(stdout, "%d orders placed by 4 writers, %d events delivered (relay crashed %d times)\n",
+		len(db.orders), delivered, relayCrashes)
+	fmt.Fprintf(stdout, "Consumer shipped %d orders and dropped %d duplicates\n", len(consumer.shipped), consumer.duplicates)
+
+	steps.Checkpoint("Writing the order and its event in one transaction means no event can be lost. The " +
+		"relay's crashes only cause duplicates, and the inbox turns those into no-ops.")
+
+	fmt.Fprintln(stdout)
+}
+
+// OutboxInboxTopology describes the goroutines and channels of the demo
+func OutboxInboxTopology() *topology.Graph {
+	g := topology.New("Transactional Outbox and Inbox").
+		Goroutine("relay", "relay (polls the outbox, marks rows published)").
+		Channel("broker", "broker (cap 8)").
+		Goroutine("consumer", "consumer (inbox dedup)").
+		Flow("relay", "broker", "at least once").
+		Flow("broker", "consumer", "")
+	for i := range 4 {
+		w := fmt.Sprintf("writer%d", i)
+		g.Goroutine(w, fmt.Sprintf("writer %d (order + outbox row in one transaction)", i)).
+			Flow(w, "relay", "outbox table")
+	}
+	return g
+}
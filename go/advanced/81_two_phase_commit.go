@@ -0,0 +1,251 @@
+/**
+ * This file demonstrates a Two-Phase Commit Coordinator in Go.
+ *
+ * Two-phase commit makes several participants agree to commit or abort a
+ * transaction together. In the prepare phase the coordinator asks each
+ * participant to vote; a yes vote promises to commit if told to, so the
+ * participant keeps its locks. If every vote is yes the coordinator records
+ * "commit" and tells everyone, otherwise it aborts. A missing vote is a no,
+ * so a crashed participant only costs a timeout. The weak spot is the
+ * coordinator: if it fails after the votes, the prepared participants may
+ * neither commit nor abort on their own and hold their locks until it comes
+ * back. Here the nodes are goroutines and the network is channels.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type tpcPhase int
+
+const (
+	tpcPrepare tpcPhase = iota
+	tpcCommit
+	tpcAbort
+)
+
+// tpcRequest is a message from the coordinator. reply is buffered, so a
+// participant never blocks on a coordinator that has given up.
+type tpcRequest struct {
+	phase tpcPhase
+	tx    string
+	reply chan bool // The vote for prepare, an ack otherwise
+}
+
+// tpcParticipant is a resource manager with a single lockable row
+type tpcParticipant struct {
+	name    string
+	inbox   chan tpcRequest
+	voteNo  bool // Votes no on prepare, e.g. a failed constraint
+	crashes bool // Stops responding when asked to prepare
+
+	mu     sync.Mutex
+	state  string
+	lockTx string // Transaction holding the row lock; "" if free
+}
+
+func newTPCParticipant(name string) *tpcParticipant {
+	return &tpcParticipant{name: name, inbox: make(chan tpcRequest), state: "idle"}
+}
+
+func (p *tpcParticipant) run(ctx context.Context) {
+	for {
+		var req tpcRequest
+		select {
+		case req = <-p.inbox:
+		case <-ctx.Done():
+			return
+		}
+
+		p.mu.Lock()
+		switch req.phase {
+		case tpcPrepare:
+			if p.crashes {
+				p.state = "crashed"
+				p.mu.Unlock()
+				return // Never replies
+			}
+			switch {
+			case p.lockTx != "" && p.lockTx != req.tx:
+				req.reply <- false // Row locked by a transaction in doubt
+			case p.voteNo:
+				p.state = "aborted " + req.tx
+				req.reply <- false
+			default:
+				p.lockTx = req.tx
+				p.state = "prepared " + req.tx
+				req.reply <- true
+			}
+		case tpcCommit, tpcAbort:
+			if p.lockTx == req.tx {
+				p.lockTx = ""
+			}
+			p.state = map[tpcPhase]string{tpcCommit: "committed ", tpcAbort: "aborted "}[req.phase] + req.tx
+			req.reply <- true
+		}
+		p.mu.Unlock()
+	}
+}
+
+func (p *tpcParticipant) State() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.state
+}
+
+// tpcCoordinator runs transactions. Its log survives a crash.
+type tpcCoordinator struct {
+	timeout time.Duration // For each vote and ack
+	log     map[string]tpcPhase
+}
+
+// send delivers req to p and waits for the reply, up to the timeout. It
+// reports the reply and whether there was one.
+func (c *tpcCoordinator) send(p *tpcParticipant, phase tpcPhase, tx string) (reply, ok bool) {
+	req := tpcRequest{phase: phase, tx: tx, reply: make(chan bool, 1)}
+	deadline := clk.After(c.timeout)
+	select {
+	case p.inbox <- req:
+	case <-deadline:
+		return false, false
+	}
+	select {
+	case v := <-req.reply:
+		return v, true
+	case <-deadline:
+		return false, false
+	}
+}
+
+// prepare collects every participant's vote concurrently and returns the
+// decision: commit only if all voted yes in time
+func (c *tpcCoordinator) prepare(tx string, parts []*tpcParticipant) (tpcPhase, []string) {
+	votes := make([]string, len(parts))
+	decision := tpcCommit
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, p := range parts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			yes, ok := c.send(p, tpcPrepare, tx)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case !ok:
+				votes[i] = p.name + ": timeout"
+				decision = tpcAbort
+			case !yes:
+				votes[i] = p.name + ": no"
+				decision = tpcAbort
+			default:
+				votes[i] = p.name + ": yes"
+			}
+		}()
+	}
+	wg.Wait()
+	c.log[tx] = decision // Logged before anyone is told
+	return decision, votes
+}
+
+// finish sends the logged decision for tx to every participant
+func (c *tpcCoordinator) finish(tx string, parts []*tpcParticipant) {
+	var wg sync.WaitGroup
+	for _, p := range parts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.send(p, c.log[tx], tx) // A crashed participant learns the outcome when it recovers
+		}()
+	}
+	wg.Wait()
+}
+
+/**
+ * Two-Phase Commit Coordinator
+ *
+ * Three participants commit a transaction; then one votes no, and one
+ * crashes before voting, and both times everyone aborts. Finally the
+ * coordinator crashes after collecting yes votes: the participants stay
+ * prepared and block a second transaction until the coordinator recovers,
+ * reads its log and finishes the first.
+ */
+func TwoPhaseCommitDemo() {
+	fmt.Fprintln(stdout, "Two-Phase Commit Coordinator")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	coord := &tpcCoordinator{timeout: 50 * time.Millisecond, log: map[string]tpcPhase{}}
+	decisionName := map[tpcPhase]string{tpcCommit: "COMMIT", tpcAbort: "ABORT"}
+
+	cluster := func(configure func(name string, p *tpcParticipant)) []*tpcParticipant {
+		var parts []*tpcParticipant
+		for _, name := range []string{"orders", "payments", "stock"} {
+			p := newTPCParticipant(name)
+			if configure != nil {
+				configure(name, p)
+			}
+			go p.run(ctx)
+			parts = append(parts, p)
+		}
+		return parts
+	}
+	states := func(parts []*tpcParticipant) string {
+		var s []string
+		for _, p := range parts {
+			s = append(s, p.name+"="+p.State())
+		}
+		return strings.Join(s, ", ")
+	}
+	transact := func(tx string, parts []*tpcParticipant) {
+		start := clk.Now()
+		decision, votes := coord.prepare(tx, parts)
+		coord.finish(tx, parts)
+		fmt.Fprintf(stdout, "  votes [%s] -> %s in %v\n", strings.Join(votes, ", "), decisionName[decision],
+			clk.Since(start).Round(10*time.Millisecond))
+		fmt.Fprintf(stdout, "  %s\n", states(parts))
+	}
+
+	// 1. Everyone votes yes
+	fmt.Fprintln(stdout, "\n1. All participants vote yes:")
+	transact("tx1", cluster(nil))
+
+	// 2. A no vote or a crashed participant aborts everyone
+	fmt.Fprintln(stdout, "\n2. payments votes no:")
+	transact("tx2", cluster(func(name string, p *tpcParticipant) { p.voteNo = name == "payments" }))
+
+	fmt.Fprintf(stdout, "\n3. stock crashes before voting (vote timeout %v):\n", coord.timeout)
+	transact("tx3", cluster(func(name string, p *tpcParticipant) { p.crashes = name == "stock" }))
+
+	steps.Checkpoint("A participant that votes no, or does not vote in time, vetoes the transaction. " +
+		"Presumed abort is safe because nobody has committed anything yet.")
+
+	// 4. The coordinator fails between the phases
+	fmt.Fprintln(stdout, "\n4. The coordinator crashes after the votes, before sending the decision:")
+	parts := cluster(nil)
+	decision, votes := coord.prepare("tx4", parts)
+	fmt.Fprintf(stdout, "  votes [%s], %s logged, then the coordinator crashes\n", strings.Join(votes, ", "), decisionName[decision])
+	clk.Sleep(100 * time.Millisecond)
+	fmt.Fprintf(stdout, "  100ms later: %s\n", states(parts))
+
+	decision, votes = coord.prepare("tx5", parts)
+	fmt.Fprintf(stdout, "  tx5 touching the same rows: votes [%s] -> %s\n", strings.Join(votes, ", "), decisionName[decision])
+	coord.finish("tx5", parts)
+
+	steps.Checkpoint("Having voted yes, the participants may not abort on their own: the coordinator may " +
+		"have logged commit. They hold their locks, in doubt, and every transaction that needs them fails.")
+
+	fmt.Fprintln(stdout, "  The coordinator recovers and replays its log:")
+	coord.finish("tx4", parts)
+	fmt.Fprintf(stdout, "  %s\n", states(parts))
+	fmt.Fprintln(stdout, "  tx5 retried:")
+	transact("tx5", parts)
+
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,256 @@
+/**
+ * This file demonstrates Leader Election with the Bully Algorithm in Go.
+ *
+ * A group of nodes needs exactly one leader, and a new one when the leader
+ * fails. In the bully algorithm every node has a fixed id and the highest
+ * live id wins. The leader sends heartbeats; a node that hears none for a
+ * while starts an election by messaging every higher node. A higher node
+ * answers "ok" and starts its own election; a node that gets no answer
+ * declares itself leader and tells everyone. A recovered node with a higher
+ * id bullies its way back in the same way. Each node is a goroutine that
+ * owns its state, and the network is a set of buffered inbox channels that
+ * drop messages when full.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/clock"
+	"threads/rng"
+)
+
+const (
+	bullyHeartbeat     = 50 * time.Millisecond
+	bullyLeaderTimeout = 3 * bullyHeartbeat // Plus up to one heartbeat of jitter per node
+	bullyOKTimeout     = bullyHeartbeat     // Wait for an ok from a higher node
+	bullyVictoryWait   = 2 * bullyHeartbeat // After an ok, wait for the victory message
+	bullyTick          = 10 * time.Millisecond
+)
+
+type bullyKind int
+
+const (
+	bullyElection bullyKind = iota
+	bullyOK
+	bullyVictory
+	bullyBeat
+)
+
+type bullyMessage struct {
+	kind bullyKind
+	from int
+}
+
+// bullyNode is one member of the group. Its election state is local to run.
+type bullyNode struct {
+	id     int
+	inbox  chan bullyMessage
+	power  chan bool // false crashes the node, true restarts it
+	group  []*bullyNode
+	sent   *atomic.Int64
+	events chan<- string
+	start  time.Time
+}
+
+// send delivers m to node id, dropping it if the inbox is full
+func (n *bullyNode) send(id int, kind bullyKind) {
+	n.sent.Add(1)
+	select {
+	case n.group[id-1].inbox <- bullyMessage{kind: kind, from: n.id}:
+	default:
+	}
+}
+
+func (n *bullyNode) logf(format string, args ...any) {
+	n.events <- fmt.Sprintf("  [%4dms] node %d: ", clk.Since(n.start).Milliseconds(), n.id) + fmt.Sprintf(format, args...)
+}
+
+func (n *bullyNode) run(ctx context.Context) {
+	ticker := clock.NewTicker(clk, bullyTick)
+	defer ticker.Stop()
+
+	var (
+		down       bool
+		leader     int
+		lastHeard  = clk.Now()
+		lastBeat   time.Time
+		beats      int
+		electing   bool
+		gotOK      bool
+		electStart time.Time
+		timeout    = bullyLeaderTimeout + time.Duration(rng.IntN(int(bullyHeartbeat)))
+	)
+
+	victory := func() {
+		leader, electing, beats = n.id, false, 0
+		n.logf("elected leader")
+		for _, peer := range n.group {
+			if peer.id != n.id {
+				n.send(peer.id, bullyVictory)
+			}
+		}
+	}
+	elect := func() {
+		electing, gotOK, electStart = true, false, clk.Now()
+		if n.id == len(n.group) {
+			victory() // Nobody to ask
+			return
+		}
+		for id := n.id + 1; id <= len(n.group); id++ {
+			n.send(id, bullyElection)
+		}
+	}
+
+	elect()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case up := <-n.power:
+			down = !up
+			if up {
+				n.logf("recovered, starting an election")
+				leader = 0
+				elect()
+			} else {
+				n.logf("crashed")
+			}
+
+		case m := <-n.inbox:
+			if down {
+				continue // A crashed node hears nothing
+			}
+			switch m.kind {
+			case bullyElection:
+				n.send(m.from, bullyOK)
+				if leader == n.id {
+					n.send(m.from, bullyVictory) // Remind it who leads
+				} else if !electing {
+					elect()
+				}
+			case bullyOK:
+				gotOK = true
+			case bullyVictory:
+				if m.from < n.id {
+					elect() // Bully the lower node
+					continue
+				}
+				leader, electing, lastHeard = m.from, false, clk.Now()
+			case bullyBeat:
+				if m.from == leader {
+					lastHeard = clk.Now()
+				}
+			}
+
+		case <-ticker.C:
+			if down {
+				continue
+			}
+			switch {
+			case leader == n.id:
+				if clk.Since(lastBeat) >= bullyHeartbeat {
+					lastBeat = clk.Now()
+					beats++
+					n.logf("heartbeat #%d", beats)
+					for _, peer := range n.group {
+						if peer.id != n.id {
+							n.send(peer.id, bullyBeat)
+						}
+					}
+				}
+			case electing && !gotOK && clk.Since(electStart) >= bullyOKTimeout:
+				victory() // No higher node answered
+			case electing && gotOK && clk.Since(electStart) >= bullyOKTimeout+bullyVictoryWait:
+				elect() // The higher node went quiet before announcing itself
+			case !electing && clk.Since(lastHeard) >= timeout:
+				n.logf("no heartbeat from node %d for %v, starting an election", leader, timeout.Round(time.Millisecond))
+				leader = 0
+				elect()
+			}
+		}
+	}
+}
+
+/**
+ * Leader Election with the Bully Algorithm
+ *
+ * Five nodes start together and elect node 5, which prints its heartbeats.
+ * Node 5 then crashes; the others notice the missing heartbeats and elect
+ * node 4. When node 5 recovers it takes the leadership back.
+ */
+func LeaderElectionDemo() {
+	fmt.Fprintln(stdout, "Leader Election with the Bully Algorithm")
+
+	const nodes = 5
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan string, 256)
+	var sent atomic.Int64
+	start := clk.Now()
+
+	group := make([]*bullyNode, nodes)
+	for i := range group {
+		group[i] = &bullyNode{
+			id: i + 1, inbox: make(chan bullyMessage, 4*nodes), power: make(chan bool),
+			group: group, sent: &sent, events: events, start: start,
+		}
+	}
+
+	printed := make(chan struct{})
+	go func() {
+		defer close(printed)
+		for e := range events {
+			fmt.Fprintln(stdout, e)
+		}
+	}()
+
+	phase := func(d time.Duration) int64 {
+		before := sent.Load()
+		clk.Sleep(d)
+		return sent.Load() - before
+	}
+
+	// 1. Every node starts an election; the highest id wins. The headings go
+	// through events too, to stay in order with the nodes' lines.
+	events <- "\n1. Five nodes start and elect a leader:"
+	var wg sync.WaitGroup
+	for _, n := range group {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n.run(ctx)
+		}()
+	}
+	msgs := phase(4 * bullyHeartbeat)
+
+	// 2. The leader crashes
+	events <- "\n2. The leader crashes:"
+	group[nodes-1].power <- false
+	msgs2 := phase(bullyLeaderTimeout + 4*bullyHeartbeat)
+
+	// 3. The old leader comes back
+	events <- "\n3. Node 5 recovers:"
+	group[nodes-1].power <- true
+	msgs3 := phase(4 * bullyHeartbeat)
+
+	cancel()
+	wg.Wait()
+	close(events)
+	<-printed
+
+	fmt.Fprintf(stdout, "\nMessages sent, elections and heartbeats: %d, %d and %d in the three phases\n",
+		msgs, msgs2, msgs3)
+
+	steps.Checkpoint("Failure is detected only by silence: the followers waited out their leader timeout, with " +
+		"jitter so they did not all start at once. Then the highest live id won, and won again when node 5 returned.")
+
+	fmt.Fprintln(stdout, "\nEach node's state is owned by its goroutine; the only sharing is through messages.")
+
+	fmt.Fprintln(stdout)
+}
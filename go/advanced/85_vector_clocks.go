@@ -0,0 +1,182 @@
+/**
+ * This file demonstrates Vector Clocks in Go.
+ *
+ * The memory model orders events by happens-before: within a goroutine in
+ * program order, and across goroutines through synchronization such as a
+ * send and its receive. Vector clocks make that relation visible. Each
+ * process keeps one counter per process, increments its own for every
+ * event, and sends a copy with every message; a receiver takes the maximum
+ * of both clocks. Event x happened before y exactly when x's clock is less
+ * than or equal to y's in every entry and smaller in at least one. If
+ * neither is before the other the events are concurrent: no chain of
+ * messages connects them, whatever order they ran in. A Lamport clock, a
+ * single counter, is consistent with happens-before but cannot tell
+ * concurrent events from ordered ones.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"threads/rng"
+)
+
+// vectorClock has one counter per process
+type vectorClock []int
+
+func (v vectorClock) merge(o vectorClock) {
+	for i := range v {
+		v[i] = max(v[i], o[i])
+	}
+}
+
+// happensBefore reports whether v <= o in every entry and v != o
+func (v vectorClock) happensBefore(o vectorClock) bool {
+	for i := range v {
+		if v[i] > o[i] {
+			return false
+		}
+	}
+	return !slices.Equal(v, o)
+}
+
+// vcEvent is an event as recorded by the process that performed it
+type vcEvent struct {
+	name    string
+	what    string
+	clock   vectorClock
+	lamport int
+}
+
+// vcMessage carries the sender's clocks
+type vcMessage struct {
+	clock   vectorClock
+	lamport int
+}
+
+// vcScripts lists each process's steps: "local", "recv" or "send" plus the
+// receiver
+var vcScripts = [][]string{
+	{"local", "send B", "local", "recv"},
+	{"local", "recv", "send C", "local"},
+	{"local", "local", "recv", "send A"},
+}
+
+/**
+ * Vector Clocks
+ *
+ * Three processes, A, B and C, run a fixed script of local events, sends and
+ * receives at random speeds. The events are printed in the order they
+ * actually happened, which changes from run to run, and then as a matrix
+ * of the happens-before relation computed from their vector clocks, which
+ * does not.
+ */
+func VectorClocksDemo() {
+	fmt.Fprintln(stdout, "Vector Clocks")
+
+	n := len(vcScripts)
+	inboxes := make([]chan vcMessage, n)
+	for i := range inboxes {
+		inboxes[i] = make(chan vcMessage, 1)
+	}
+
+	var mu sync.Mutex
+	var occurred []vcEvent // In real-time order
+	var wg sync.WaitGroup
+	for i, script := range vcScripts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clock := make(vectorClock, n)
+			lamport := 0
+			for k, step := range script {
+				clk.Sleep(time.Duration(rng.IntN(5)) * time.Millisecond)
+				clock[i]++
+				lamport++
+				var to int
+				switch {
+				case step == "recv":
+					m := <-inboxes[i]
+					clock.merge(m.clock)
+					lamport = max(lamport, m.lamport+1)
+				case strings.HasPrefix(step, "send "):
+					to = int(step[5] - 'A')
+				}
+
+				mu.Lock()
+				occurred = append(occurred, vcEvent{
+					name: fmt.Sprintf("%c%d", 'A'+i, k+1), what: step, clock: slices.Clone(clock), lamport: lamport,
+				})
+				mu.Unlock()
+				if strings.HasPrefix(step, "send ") {
+					inboxes[to] <- vcMessage{clock: slices.Clone(clock), lamport: lamport}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 1. What happened, in real time
+	fmt.Fprintln(stdout, "\n1. Events in the order they occurred (varies between runs):")
+	fmt.Fprintf(stdout, "  %-5s %-7s %-9s %s\n", "event", "step", "vector", "Lamport")
+	for _, e := range occurred {
+		fmt.Fprintf(stdout, "  %-5s %-7s %-9v %d\n", e.name, e.what, e.clock, e.lamport)
+	}
+
+	// 2. The happens-before relation
+	events := slices.Clone(occurred)
+	slices.SortFunc(events, func(a, b vcEvent) int { return strings.Compare(a.name, b.name) })
+	fmt.Fprintln(stdout, "\n2. Happens-before (row -> column, row <- column, || concurrent):")
+	fmt.Fprint(stdout, "     ")
+	for _, e := range events {
+		fmt.Fprintf(stdout, "%4s", e.name)
+	}
+	fmt.Fprintln(stdout)
+	concurrent := 0
+	for _, x := range events {
+		fmt.Fprintf(stdout, "  %-3s", x.name)
+		for _, y := range events {
+			rel := "||"
+			switch {
+			case x.name == y.name:
+				rel = "."
+			case x.clock.happensBefore(y.clock):
+				rel = "->"
+			case y.clock.happensBefore(x.clock):
+				rel = "<-"
+			default:
+				concurrent++
+			}
+			fmt.Fprintf(stdout, "%4s", rel)
+		}
+		fmt.Fprintln(stdout)
+	}
+	fmt.Fprintf(stdout, "%d of %d pairs are concurrent\n", concurrent/2, len(events)*(len(events)-1)/2)
+
+	steps.Checkpoint("The real-time order changes from run to run; the matrix does not. Within a row of " +
+		"the script the order is program order, and across processes only messages create \"->\".")
+
+	// 3. What a Lamport clock cannot tell
+	fmt.Fprintln(stdout, "\n3. Lamport clocks:")
+	misleading := func() (vcEvent, vcEvent) {
+		for _, x := range events {
+			for _, y := range events {
+				if x.lamport < y.lamport && !x.clock.happensBefore(y.clock) {
+					return x, y
+				}
+			}
+		}
+		panic("no concurrent events with ordered Lamport times")
+	}
+	x, y := misleading()
+	fmt.Fprintf(stdout, "%s has Lamport time %d and %s has %d, yet they are concurrent: %v vs %v\n",
+		x.name, x.lamport, y.name, y.lamport, x.clock, y.clock)
+	fmt.Fprintln(stdout, "If x -> y then L(x) < L(y), but L(x) < L(y) does not mean x -> y.")
+
+	fmt.Fprintln(stdout)
+}
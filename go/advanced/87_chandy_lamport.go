@@ -0,0 +1,237 @@
+/**
+ * This file demonstrates the Chandy-Lamport Snapshot Algorithm in Go.
+ *
+ * Reading every process's state one after another while they keep
+ * exchanging messages gives an inconsistent picture: money can be counted
+ * twice or not at all. Chandy and Lamport's algorithm records a consistent
+ * global state without stopping anyone, over FIFO channels. The initiator
+ * records its state and sends a marker on each outgoing channel. A process
+ * receiving its first marker records its state, marks that channel empty,
+ * forwards markers, and starts recording every other incoming channel; a
+ * later marker on a channel ends that channel's recording. The recorded
+ * states plus the recorded messages in transit form a state the system
+ * could have been in. Go channels are FIFO, so they fit the algorithm
+ * directly: each process has one inbox, and the messages from one sender
+ * form that sender's channel.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/rng"
+	"threads/topology"
+)
+
+// clMessage is a transfer of money or a marker
+type clMessage struct {
+	from   int
+	amount int
+	marker bool
+}
+
+// clSnapshot is what one process records
+type clSnapshot struct {
+	process   int
+	balance   int
+	inTransit map[int][]int // Transfers recorded on each incoming channel
+}
+
+// clProcess is a bank branch that moves money to the others
+type clProcess struct {
+	id       int
+	inboxes  []chan clMessage
+	balance  int
+	visible  *atomic.Int64 // The balance, for the naive reader
+	start    chan struct{} // Begins a snapshot at this process
+	recorded chan<- clSnapshot
+	rand     *rand.Rand // Its own generator, so its draws do not depend on scheduling
+
+	snap      *clSnapshot
+	recording map[int]bool // Incoming channels still being recorded
+}
+
+func (p *clProcess) sendAll(m clMessage) {
+	for to := range p.inboxes {
+		if to != p.id {
+			p.inboxes[to] <- m
+		}
+	}
+}
+
+// record saves the local state, forwards markers and starts recording every
+// incoming channel except skip
+func (p *clProcess) record(skip int) {
+	p.snap = &clSnapshot{process: p.id, balance: p.balance, inTransit: map[int][]int{}}
+	p.recording = map[int]bool{}
+	for from := range p.inboxes {
+		if from != p.id && from != skip {
+			p.recording[from] = true
+		}
+	}
+	p.sendAll(clMessage{from: p.id, marker: true})
+	p.finishIfDone()
+}
+
+func (p *clProcess) finishIfDone() {
+	if p.snap != nil && len(p.recording) == 0 {
+		p.recorded <- *p.snap
+		p.snap = nil
+	}
+}
+
+func (p *clProcess) handle(m clMessage) {
+	switch {
+	case m.marker && p.snap == nil:
+		p.record(m.from) // The channel from m.from is recorded as empty
+	case m.marker:
+		delete(p.recording, m.from)
+		p.finishIfDone()
+	default:
+		p.balance += m.amount
+		p.visible.Store(int64(p.balance))
+		if p.snap != nil && p.recording[m.from] {
+			p.snap.inTransit[m.from] = append(p.snap.inTransit[m.from], m.amount)
+		}
+	}
+}
+
+func (p *clProcess) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.start:
+			p.record(-1)
+		default:
+		}
+
+		clk.Sleep(time.Duration(p.rand.IntN(1500)) * time.Microsecond)
+		if amount := 1 + p.rand.IntN(10); p.rand.IntN(2) == 0 && p.balance >= amount {
+			p.balance -= amount
+			p.visible.Store(int64(p.balance))
+			to := (p.id + 1 + p.rand.IntN(len(p.inboxes)-1)) % len(p.inboxes)
+			p.inboxes[to] <- clMessage{from: p.id, amount: amount}
+		}
+		select {
+		case m := <-p.inboxes[p.id]: // One message per step, so transfers queue up in the channels
+			p.handle(m)
+		default:
+		}
+	}
+}
+
+/**
+ * Chandy-Lamport Snapshot Algorithm
+ *
+ * Three branches start with 100 each and keep transferring money. Reading
+ * their balances one by one gives totals other than 300. Three snapshots
+ * taken with markers, started at different branches, each account for
+ * exactly 300, split between balances and transfers in flight.
+ */
+func ChandyLamportDemo() {
+	fmt.Fprintln(stdout, "Chandy-Lamport Snapshot Algorithm")
+
+	const branches, initial = 3, 100
+	ctx, cancel := context.WithCancel(context.Background())
+	inboxes := make([]chan clMessage, branches)
+	for i := range inboxes {
+		inboxes[i] = make(chan clMessage, 1024) // Large enough that a send never waits
+	}
+	recorded := make(chan clSnapshot)
+	procs := make([]*clProcess, branches)
+	var wg sync.WaitGroup
+	for i := range procs {
+		procs[i] = &clProcess{
+			id: i, inboxes: inboxes, balance: initial, visible: new(atomic.Int64),
+			start: make(chan struct{}, 1), recorded: recorded, rand: rng.New(),
+		}
+		procs[i].visible.Store(initial)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			procs[i].run(ctx)
+		}()
+	}
+	clk.Sleep(20 * time.Millisecond) // Let transfers get going
+
+	// 1. Reading the balances one at a time
+	fmt.Fprintf(stdout, "\n1. Reading the balances one after another (should total %d):\n", branches*initial)
+	for range 3 {
+		total := 0
+		var seen []int64
+		for _, p := range procs {
+			b := p.visible.Load()
+			seen = append(seen, b)
+			total += int(b)
+			clk.Sleep(time.Millisecond)
+		}
+		fmt.Fprintf(stdout, "  balances %v total %d\n", seen, total)
+	}
+
+	// 2. Snapshots with markers
+	fmt.Fprintln(stdout, "\n2. Chandy-Lamport snapshots:")
+	for s := range 3 {
+		initiator := s % branches
+		procs[initiator].start <- struct{}{}
+		snaps := make([]clSnapshot, branches)
+		for range branches {
+			snap := <-recorded
+			snaps[snap.process] = snap
+		}
+
+		fmt.Fprintf(stdout, "  snapshot %d, started by P%d:\n", s+1, initiator)
+		balances, transit := 0, 0
+		for _, snap := range snaps {
+			balances += snap.balance
+			fmt.Fprintf(stdout, "    P%d balance %3d", snap.process, snap.balance)
+			for from := range branches {
+				if amounts := snap.inTransit[from]; len(amounts) > 0 {
+					fmt.Fprintf(stdout, ", in transit from P%d %v", from, amounts)
+					for _, a := range amounts {
+						transit += a
+					}
+				}
+			}
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintf(stdout, "    balances %d + in transit %d = %d\n", balances, transit, balances+transit)
+		clk.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	wg.Wait()
+
+	steps.Checkpoint("Every snapshot adds up, though no branch stopped trading. The money a naive read " +
+		"misses is in the channels; the markers mark where each channel's recording starts and ends.")
+
+	fmt.Fprintln(stdout)
+}
+
+// ChandyLamportTopology describes the goroutines and channels of the demo
+func ChandyLamportTopology() *topology.Graph {
+	g := topology.New("Chandy-Lamport Snapshot").
+		Goroutine("main", "main (starts snapshots, collects the records)").
+		Channel("recorded", "recorded")
+	for i := range 3 {
+		p, in := fmt.Sprintf("p%d", i), fmt.Sprintf("inbox%d", i)
+		g.Goroutine(p, fmt.Sprintf("P%d (balance, records its state)", i)).
+			Channel(in, fmt.Sprintf("inbox %d (FIFO, cap 1024)", i)).
+			Flow(in, p, "").
+			Flow(p, "recorded", "")
+	}
+	for i := range 3 {
+		for j := range 3 {
+			if i != j {
+				g.Flow(fmt.Sprintf("p%d", i), fmt.Sprintf("inbox%d", j), "transfers, markers")
+			}
+		}
+	}
+	return g.Flow("main", "p0", "start").Flow("recorded", "main", "")
+}
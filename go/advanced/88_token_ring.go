@@ -0,0 +1,249 @@
+/**
+ * This file demonstrates Token Ring Mutual Exclusion in Go.
+ *
+ * Instead of a lock, the right to enter the critical section is a token
+ * passed around a ring of goroutines: a node may enter only while it holds
+ * the token, then hands it to its neighbour. There is no contention and
+ * every node gets its turn, but the token travels even when nobody needs
+ * it, and a node waits up to a full rotation. If the token is lost, the
+ * ring stops. Here node 0 watches for it: when the token has not come round
+ * within a timeout it announces a new generation and creates a new token.
+ * Nodes discard tokens of older generations, so a token that was only late
+ * cannot come back as a second one. An announcement can arrive after the
+ * late token it makes stale, so a holder also checks the ring's current
+ * generation as it enters, and node 0 waits for the critical section to be
+ * empty before it starts a new generation. The timeout must be longer than
+ * any node holds the token, or a slow holder would be mistaken for a loss.
+ */
+
+package advanced
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/rng"
+)
+
+const (
+	ringTimeout = 25 * time.Millisecond // Node 0 regenerates a token not seen for this long
+	ringStall   = 35 * time.Millisecond
+)
+
+// ringMsg is the token, or an announcement of a new generation
+type ringMsg struct {
+	gen   int
+	token bool
+}
+
+type ringFault int32
+
+const (
+	ringNoFault    ringFault = iota
+	ringDrop                 // Lose the token instead of passing it on
+	ringStallFault           // Hold the token past the timeout, then pass it on
+)
+
+// tokenRing is shared by the node goroutines. shared is the protected
+// resource: only the token holder touches it, and passing the token over a
+// channel orders the accesses. fence guards the current generation: holders
+// take its read side to check their token and enter, node 0 its write side
+// to move to a new generation.
+type tokenRing struct {
+	fence       sync.RWMutex
+	gen         int
+	inboxes     []chan ringMsg
+	faults      []atomic.Int32
+	entries     []int // Per node, written only by that node
+	shared      int
+	work        time.Duration // Time spent in each critical section
+	inCS        atomic.Int32
+	maxInCS     atomic.Int32
+	rotations   atomic.Int32
+	regenerated atomic.Int32
+	discarded   atomic.Int32
+	logf        func(format string, args ...any)
+}
+
+// newTokenRing returns a ring of n nodes in generation 1, with no token yet
+func newTokenRing(n int, logf func(format string, args ...any)) *tokenRing {
+	r := &tokenRing{
+		gen:     1,
+		work:    100 * time.Microsecond,
+		inboxes: make([]chan ringMsg, n),
+		faults:  make([]atomic.Int32, n),
+		entries: make([]int, n),
+		logf:    logf,
+	}
+	for i := range r.inboxes {
+		r.inboxes[i] = make(chan ringMsg, n) // Room for announcements and the token
+	}
+	return r
+}
+
+// hold checks that a token of generation gen is current and, if work is
+// set, runs the critical section before a new generation can start. It
+// reports false for a stale token.
+func (r *tokenRing) hold(id, gen int, work bool) bool {
+	r.fence.RLock()
+	defer r.fence.RUnlock()
+	if gen != r.gen {
+		return false
+	}
+	if work {
+		if n := r.inCS.Add(1); n > r.maxInCS.Load() {
+			r.maxInCS.Store(n)
+		}
+		r.shared++
+		r.entries[id]++
+		clk.Sleep(r.work)
+		r.inCS.Add(-1)
+	}
+	return true
+}
+
+// regenerate starts a new generation once no node is in the critical
+// section, and returns it
+func (r *tokenRing) regenerate() int {
+	r.fence.Lock()
+	defer r.fence.Unlock()
+	r.gen++
+	return r.gen
+}
+
+func (r *tokenRing) node(ctx context.Context, id int) {
+	n := len(r.inboxes)
+	highest := 1
+	lastSeen := clk.Now()
+	var check <-chan time.Time // Only node 0 watches for a lost token
+	if id == 0 {
+		check = clk.After(ringTimeout)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-check:
+			// One timer at a time, re-armed for whatever is left of the timeout
+			if wait := ringTimeout - clk.Since(lastSeen); wait > 0 {
+				check = clk.After(wait)
+				continue
+			}
+			highest = r.regenerate()
+			lastSeen = clk.Now()
+			check = clk.After(ringTimeout)
+			r.regenerated.Add(1)
+			r.logf("node 0: no token for %v, creating generation %d", ringTimeout, highest)
+			for to := 1; to < n; to++ {
+				r.inboxes[to] <- ringMsg{gen: highest}
+			}
+			r.inboxes[0] <- ringMsg{gen: highest, token: true}
+
+		case m := <-r.inboxes[id]:
+			if m.gen < highest {
+				if m.token {
+					r.discarded.Add(1)
+					r.logf("node %d: discarding a stale token of generation %d", id, m.gen)
+				}
+				continue
+			}
+			highest = m.gen
+			if !m.token {
+				continue
+			}
+			if !r.hold(id, m.gen, rng.IntN(2) == 0) {
+				// The announcement has not arrived yet, but the generation moved on
+				r.discarded.Add(1)
+				r.logf("node %d: discarding a stale token of generation %d", id, m.gen)
+				continue
+			}
+			lastSeen = clk.Now()
+			if id == 0 {
+				r.rotations.Add(1)
+			}
+
+			switch ringFault(r.faults[id].Swap(int32(ringNoFault))) {
+			case ringDrop:
+				r.logf("node %d: loses the token of generation %d", id, m.gen)
+				continue
+			case ringStallFault:
+				r.logf("node %d: stalls for %v holding the token of generation %d", id, ringStall, m.gen)
+				clk.Sleep(ringStall)
+			}
+			r.inboxes[(id+1)%n] <- m
+		}
+	}
+}
+
+/**
+ * Token Ring Mutual Exclusion
+ *
+ * Five nodes pass a token and enter a critical section about every other
+ * time they hold it. Then one node loses the token and node 0 regenerates
+ * it, and another stalls while holding it: the token is regenerated and
+ * the late one discarded when it turns up. Throughout, at most one node is
+ * ever inside the critical section.
+ */
+func TokenRingDemo() {
+	fmt.Fprintln(stdout, "Token Ring Mutual Exclusion")
+
+	const nodes = 5
+	var logMu sync.Mutex
+	start := clk.Now()
+	ring := newTokenRing(nodes, func(format string, args ...any) {
+		logMu.Lock()
+		defer logMu.Unlock()
+		fmt.Fprintf(stdout, "  [%3dms] "+format+"\n", append([]any{clk.Since(start).Milliseconds()}, args...)...)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for id := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ring.node(ctx, id)
+		}()
+	}
+
+	// 1. Normal rotation
+	fmt.Fprintf(stdout, "\n1. %d nodes pass the token for 50ms:\n", nodes)
+	ring.inboxes[0] <- ringMsg{gen: 1, token: true}
+	clk.Sleep(50 * time.Millisecond)
+	fmt.Fprintf(stdout, "  The token went round %d times\n", ring.rotations.Load())
+
+	// 2. A lost token
+	logMu.Lock()
+	fmt.Fprintln(stdout, "\n2. Node 3 loses the token:")
+	logMu.Unlock()
+	ring.faults[3].Store(int32(ringDrop))
+	clk.Sleep(2 * ringTimeout)
+
+	// 3. A late token
+	logMu.Lock()
+	fmt.Fprintln(stdout, "\n3. Node 2 holds the token longer than the timeout:")
+	logMu.Unlock()
+	ring.faults[2].Store(int32(ringStallFault))
+	clk.Sleep(2 * ringStall)
+
+	cancel()
+	wg.Wait()
+
+	total := 0
+	for _, e := range ring.entries {
+		total += e
+	}
+	fmt.Fprintf(stdout, "\nCritical section entries per node: %v (total %d)\n", ring.entries, total)
+	fmt.Fprintf(stdout, "Shared counter: %d, most nodes inside at once: %d\n", ring.shared, ring.maxInCS.Load())
+	fmt.Fprintf(stdout, "Tokens regenerated: %d, stale tokens discarded: %d\n", ring.regenerated.Load(), ring.discarded.Load())
+
+	steps.Checkpoint("The shared counter was updated without a mutex: holding the token is the lock, and " +
+		"the channel send that passes it on orders each holder's writes before the next holder's.")
+
+	fmt.Fprintln(stdout)
+}
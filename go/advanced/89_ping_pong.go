@@ -0,0 +1,141 @@
+/**
+ * This file demonstrates Ping-Pong Latency between goroutines in Go.
+ *
+ * How long does it take to hand a value to another goroutine and get an
+ * answer back? Each round trip here is two handoffs, and each handoff
+ * usually means parking one goroutine and readying the other. An
+ * unbuffered channel hands the value over directly when the receiver is
+ * already waiting. A buffered channel does not save anything in a strict
+ * ping-pong, since each side still waits for the other. A mutex with a
+ * condition variable does the same work by hand. The numbers are a few
+ * hundred nanoseconds: cheap, but a thousand times a function call, which
+ * is why per-item channel traffic in hot loops is worth batching.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// pingPongStrategy bounces a value between two goroutines rounds times
+type pingPongStrategy struct {
+	name string
+	run  func(rounds int)
+}
+
+func pingPongChannels(buffer int) func(rounds int) {
+	return func(rounds int) {
+		ping, pong := make(chan int, buffer), make(chan int, buffer)
+		go func() {
+			for v := range ping {
+				pong <- v + 1
+			}
+		}()
+		v := 0
+		for range rounds {
+			ping <- v
+			v = <-pong
+		}
+		close(ping)
+	}
+}
+
+// pingPongCond passes the value through a shared variable, with a flag
+// saying whose turn it is
+func pingPongCond(rounds int) {
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+	value, pongTurn, done := 0, false, false
+
+	go func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for {
+			for !pongTurn && !done {
+				cond.Wait()
+			}
+			if done {
+				return
+			}
+			value++
+			pongTurn = false
+			cond.Signal()
+		}
+	}()
+
+	mu.Lock()
+	for range rounds {
+		pongTurn = true
+		cond.Signal()
+		for pongTurn {
+			cond.Wait()
+		}
+	}
+	done = true
+	cond.Signal()
+	mu.Unlock()
+}
+
+var pingPongStrategies = []pingPongStrategy{
+	{"unbuffered channels", pingPongChannels(0)},
+	{"buffered channels", pingPongChannels(1)},
+	{"mutex + cond", pingPongCond},
+}
+
+// timePingPong returns the fastest of three runs, per round trip
+func timePingPong(s pingPongStrategy, rounds int) time.Duration {
+	best := time.Duration(1<<63 - 1)
+	for range 3 {
+		start := time.Now()
+		s.run(rounds)
+		best = min(best, time.Since(start))
+	}
+	return best / time.Duration(rounds)
+}
+
+/**
+ * Ping-Pong Latency
+ *
+ * A value bounces between two goroutines 100,000 times over unbuffered
+ * channels, buffered channels and a mutex with a condition variable, first
+ * with the current GOMAXPROCS and then with GOMAXPROCS=1, and the time per
+ * round trip is printed next to a plain function call.
+ */
+func PingPongDemo() {
+	fmt.Fprintln(stdout, "Ping-Pong Latency")
+
+	const rounds = 100_000
+	call := func(v int) int { return v + 1 }
+	start := time.Now()
+	v := 0
+	for range rounds {
+		v = call(v)
+	}
+	callNs := float64(time.Since(start).Nanoseconds()) / rounds
+
+	procs := runtime.GOMAXPROCS(0)
+	for i, p := range []int{procs, 1} {
+		if i == 1 && procs == 1 {
+			break
+		}
+		fmt.Fprintf(stdout, "\n%d. Round trips with GOMAXPROCS=%d:\n", i+1, p)
+		prev := runtime.GOMAXPROCS(p)
+		for _, s := range pingPongStrategies {
+			rt := timePingPong(s, rounds)
+			fmt.Fprintf(stdout, "  %-20s %8d ns/op  (%d ns per handoff)\n", s.name, rt.Nanoseconds(), rt.Nanoseconds()/2)
+		}
+		runtime.GOMAXPROCS(prev)
+	}
+	fmt.Fprintf(stdout, "  %-20s %8.1f ns/op\n", "function call", callNs)
+
+	steps.Checkpoint("Every round trip parks and wakes a goroutine twice. With one P the partner runs " +
+		"on the same thread right away; with several, the wake-up may involve another thread, which can cost more.")
+
+	fmt.Fprintln(stdout, "\nFor stable numbers, run the benchmark: make bench BENCH=PingPong")
+
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,211 @@
+/**
+ * This file demonstrates Calling a Thread-bound API in Go.
+ *
+ * Some C libraries keep state per OS thread: OpenGL's current context,
+ * errno-style "last error" values, COM apartments, GUI toolkits that must
+ * run on the thread that initialised them. Go's scheduler moves goroutines
+ * between threads whenever they block, so a goroutine that initialises
+ * such a library and calls it later may be on another thread by then.
+ * runtime.LockOSThread pins a goroutine to its thread. The usual design is
+ * one locked goroutine that owns the library and serves every other
+ * goroutine's calls over a channel, the way cgo programs drive a UI loop.
+ */
+
+package advanced
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var errWrongThread = errors.New("called from a thread other than the one that initialised the library")
+
+// threadBoundLib simulates a C library that must be used from the thread
+// that initialised it and reports errors through thread-local storage
+type threadBoundLib struct {
+	mu        sync.Mutex // Guards the simulation's bookkeeping, not the API
+	owner     uint64
+	lastError map[uint64]error // The "thread-local" last error, by thread
+}
+
+// initLib binds the library to the calling thread
+func initLib() *threadBoundLib {
+	id, _ := osThreadID()
+	return &threadBoundLib{owner: id, lastError: map[uint64]error{}}
+}
+
+// draw fails unless called on the owner thread; like a C call it returns
+// only a status, and the error is left in thread-local storage
+func (l *threadBoundLib) draw() bool {
+	id, _ := osThreadID()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if id != l.owner {
+		l.lastError[id] = errWrongThread
+		return false
+	}
+	l.lastError[id] = nil
+	return true
+}
+
+// lastErr reads the calling thread's last error, like errno
+func (l *threadBoundLib) lastErr() error {
+	id, _ := osThreadID()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastError[id]
+}
+
+// threadChurn raises GOMAXPROCS and starts goroutines that keep waking up,
+// so a sleeping goroutine is likely to resume on another thread. The
+// returned function stops them and restores GOMAXPROCS.
+func threadChurn() (stop func()) {
+	prev := runtime.GOMAXPROCS(max(4, runtime.GOMAXPROCS(0)))
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					time.Sleep(50 * time.Microsecond)
+				}
+			}
+		}()
+	}
+	return func() {
+		close(done)
+		wg.Wait()
+		runtime.GOMAXPROCS(prev)
+	}
+}
+
+// libThread is the goroutine that owns the library. Other goroutines send
+// it calls as closures; it runs them on its locked thread.
+type libThread struct {
+	calls chan func(*threadBoundLib)
+	done  chan struct{}
+}
+
+func startLibThread() *libThread {
+	t := &libThread{calls: make(chan func(*threadBoundLib)), done: make(chan struct{})}
+	go func() {
+		defer close(t.done)
+		runtime.LockOSThread()
+		// No UnlockOSThread: a goroutine that exits while locked takes its
+		// thread with it, so no other goroutine inherits the library's
+		// thread-local state
+		lib := initLib()
+		for call := range t.calls {
+			call(lib)
+		}
+	}()
+	return t
+}
+
+// do runs fn on the library's thread and waits for it
+func (t *libThread) do(fn func(*threadBoundLib)) {
+	finished := make(chan struct{})
+	t.calls <- func(lib *threadBoundLib) {
+		fn(lib)
+		close(finished)
+	}
+	<-finished
+}
+
+func (t *libThread) stop() {
+	close(t.calls)
+	<-t.done
+}
+
+/**
+ * Calling a Thread-bound API
+ *
+ * A goroutine initialises a simulated thread-bound library and calls it
+ * 100 times, sleeping in between: without LockOSThread some calls land on
+ * other threads and fail, and their error is not even where the caller
+ * looks for it. Then eight goroutines make the same calls through one
+ * locked goroutine that owns the library, and all of them succeed.
+ */
+func ThreadBoundAPIDemo() {
+	fmt.Fprintln(stdout, "Calling a Thread-bound API")
+
+	if _, ok := osThreadID(); !ok {
+		fmt.Fprintln(stdout, "This build cannot read OS thread IDs (it needs Linux, Windows or cgo), so the")
+		fmt.Fprintln(stdout, "simulated library cannot tell threads apart.")
+		fmt.Fprintln(stdout)
+		return
+	}
+	stopChurn := threadChurn()
+	defer stopChurn()
+
+	// 1. No locking
+	fmt.Fprintln(stdout, "\n1. Initialise and call from an ordinary goroutine:")
+	var failed, lostErrors int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lib := initLib()
+		for range 100 {
+			if !lib.draw() {
+				failed++
+				// Any sleep or blocking call here could move us again, and
+				// then the error would be on another thread's record
+				time.Sleep(10 * time.Microsecond)
+				if lib.lastErr() == nil {
+					lostErrors++
+				}
+			}
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+	<-done
+	fmt.Fprintf(stdout, "%d of 100 calls ran on the wrong thread; for %d of them the caller then read\n", failed, lostErrors)
+	fmt.Fprintln(stdout, "the last error on yet another thread and saw no error at all")
+
+	steps.Checkpoint("The goroutine never moved itself: every sleep let the scheduler resume it on " +
+		"whichever thread was free. Nothing in the Go code shows the thread changed.")
+
+	// 2. One locked goroutine owns the library
+	fmt.Fprintln(stdout, "\n2. Eight goroutines call through a locked owner goroutine:")
+	owner := startLibThread()
+	var mu sync.Mutex
+	calls, ok := 0, 0
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 25 {
+				var drawn bool
+				var err error
+				owner.do(func(lib *threadBoundLib) {
+					drawn = lib.draw()
+					err = lib.lastErr() // Read on the same thread, in the same call
+				})
+				mu.Lock()
+				calls++
+				if drawn && err == nil {
+					ok++
+				}
+				mu.Unlock()
+				time.Sleep(100 * time.Microsecond)
+			}
+		}()
+	}
+	wg.Wait()
+	owner.stop()
+	fmt.Fprintf(stdout, "%d of %d calls succeeded\n", ok, calls)
+
+	fmt.Fprintln(stdout, "\nThe callers still move between threads; only the owner goroutine is pinned, and")
+	fmt.Fprintln(stdout, "each call and its error check run together on the owner's thread.")
+
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,154 @@
+/**
+ * This file demonstrates Pausing and Resuming Goroutines in Go.
+ *
+ * Go has no API to suspend a goroutine from outside, unlike the thread
+ * suspend and resume calls of some platforms. That is deliberate: a thread
+ * suspended at an arbitrary point may be holding a lock, and then everyone
+ * else waiting for it is suspended too. Instead the worker pauses itself.
+ * Between units of work it checks a pause channel, and when told to pause
+ * it blocks until it is told to resume (or to quit). Because the pause
+ * channel is unbuffered, the send returns only once the worker has taken
+ * the request, so the controller knows the worker is paused, not merely
+ * asked to pause. The cost is latency: a worker finishes its current unit
+ * before it notices.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// pausableWorker repeats a unit of work until stopped, pausing between
+// units when asked. Pause and Resume must alternate.
+type pausableWorker struct {
+	pause, resume chan struct{}
+	quit, done    chan struct{}
+	units         atomic.Int64
+}
+
+func startPausable(work func()) *pausableWorker {
+	w := &pausableWorker{
+		pause:  make(chan struct{}),
+		resume: make(chan struct{}),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		for {
+			select {
+			case <-w.pause:
+				select {
+				case <-w.resume:
+				case <-w.quit: // Stopping a paused worker must not need a resume first
+					return
+				}
+			case <-w.quit:
+				return
+			default:
+				work()
+				w.units.Add(1)
+			}
+		}
+	}()
+	return w
+}
+
+// Pause returns once the worker has finished its current unit and paused
+func (w *pausableWorker) Pause() { w.pause <- struct{}{} }
+
+func (w *pausableWorker) Resume() { w.resume <- struct{}{} }
+
+func (w *pausableWorker) Stop() {
+	close(w.quit)
+	<-w.done
+}
+
+/**
+ * Pausing and Resuming Goroutines
+ *
+ * One worker doing 2ms units of work is paused, left paused, and resumed,
+ * with its progress shown for each phase. Then a pool of four workers is
+ * paused as a group, and a paused pool is stopped without resuming it.
+ */
+func PauseResumeDemo() {
+	fmt.Fprintln(stdout, "Pausing and Resuming Goroutines")
+
+	const unit, phase = 2 * time.Millisecond, 40 * time.Millisecond
+	work := func() { clk.Sleep(unit) }
+
+	// 1. One worker
+	fmt.Fprintln(stdout, "\n1. One worker, 2ms per unit of work:")
+	w := startPausable(work)
+	clk.Sleep(phase)
+	fmt.Fprintf(stdout, "  running for %v:  %2d units\n", phase, w.units.Load())
+
+	start := clk.Now()
+	w.Pause()
+	fmt.Fprintf(stdout, "  Pause returned after %v (the worker finished its current unit first)\n",
+		clk.Since(start).Round(100*time.Microsecond))
+	before := w.units.Load()
+	clk.Sleep(phase)
+	fmt.Fprintf(stdout, "  paused for %v:   %2d units\n", phase, w.units.Load()-before)
+
+	w.Resume()
+	before = w.units.Load()
+	clk.Sleep(phase)
+	fmt.Fprintf(stdout, "  resumed for %v:  %2d units\n", phase, w.units.Load()-before)
+	w.Stop()
+
+	steps.Checkpoint("The worker only checks the pause channel between units, so a pause takes effect " +
+		"at a point the worker chose, where it holds no locks and has no half-done work.")
+
+	// 2. A pool
+	fmt.Fprintln(stdout, "\n2. Pausing a pool of four workers:")
+	pool := make([]*pausableWorker, 4)
+	for i := range pool {
+		pool[i] = startPausable(work)
+	}
+	total := func() int64 {
+		var n int64
+		for _, w := range pool {
+			n += w.units.Load()
+		}
+		return n
+	}
+	// each applies fn to every worker at once and waits for all of them
+	each := func(fn func(*pausableWorker)) {
+		var wg sync.WaitGroup
+		for _, w := range pool {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fn(w)
+			}()
+		}
+		wg.Wait()
+	}
+
+	clk.Sleep(phase)
+	fmt.Fprintf(stdout, "  running:  %3d units\n", total())
+	start = clk.Now()
+	each((*pausableWorker).Pause)
+	fmt.Fprintf(stdout, "  all four paused after %v\n", clk.Since(start).Round(100*time.Microsecond))
+	before = total()
+	clk.Sleep(phase)
+	fmt.Fprintf(stdout, "  paused:   %3d units\n", total()-before)
+	each((*pausableWorker).Resume)
+	before = total()
+	clk.Sleep(phase)
+	fmt.Fprintf(stdout, "  resumed:  %3d units\n", total()-before)
+
+	each((*pausableWorker).Pause)
+	each((*pausableWorker).Stop)
+	fmt.Fprintln(stdout, "  paused again, then stopped without a resume: all four exited")
+
+	fmt.Fprintln(stdout, "\nPausing the workers one after another would let the others keep going meanwhile;")
+	fmt.Fprintln(stdout, "sending the requests concurrently pauses the pool within one unit of work.")
+
+	fmt.Fprintln(stdout)
+}
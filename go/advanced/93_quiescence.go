@@ -0,0 +1,174 @@
+/**
+ * This file demonstrates Quiescence Detection in Go.
+ *
+ * When tasks spawn more tasks, as in the dynamic WaitGroup example, nobody
+ * knows the total in advance; the work is finished when no task is running
+ * and none is about to start. An in-flight counter detects that moment:
+ * it counts tasks that exist but have not finished, and closes a channel
+ * when the count drops to zero. The rule that makes it correct is that a
+ * parent counts its children before it spawns them and before it counts
+ * itself done, so the count cannot touch zero while work remains. A child
+ * that counts itself once it starts running is too late: its parent may
+ * already be done, the count reaches zero and the detector fires with work
+ * still to come. The same mistake with a WaitGroup lets Wait return early,
+ * or panics when Add races with Wait. The channel also lets the waiter
+ * select on it alongside a ticker or a context, which Wait cannot do.
+ * syncx.TaskTracker packages the correct version behind a Go method.
+ */
+
+package advanced
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/clock"
+	"threads/rng"
+)
+
+// inFlight counts tasks that have been created but not finished and closes
+// a channel the first time the count returns to zero
+type inFlight struct {
+	mu    sync.Mutex
+	n     int
+	quiet chan struct{}
+	fired bool
+	late  int // Adds after the detector fired: work it missed
+}
+
+func newInFlight() *inFlight {
+	return &inFlight{quiet: make(chan struct{})}
+}
+
+// Add counts k new tasks; call it before starting them
+func (f *inFlight) Add(k int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fired {
+		f.late += k
+	}
+	f.n += k
+}
+
+// Done marks one task finished
+func (f *inFlight) Done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.n--
+	if f.n < 0 {
+		panic("inFlight: Done called more often than Add")
+	}
+	if f.n == 0 && !f.fired {
+		f.fired = true
+		close(f.quiet)
+	}
+}
+
+// Quiet is closed when no task is left
+func (f *inFlight) Quiet() <-chan struct{} { return f.quiet }
+
+func (f *inFlight) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.n
+}
+
+// quiescenceRun runs a random tree of tasks, each spawning up to three
+// children, counted by f. If countEarly is false the children count
+// themselves once they start, which is the bug. truth is a correctly used
+// WaitGroup, so the demo can tell how much work really remained.
+type quiescenceRun struct {
+	f        *inFlight
+	truth    sync.WaitGroup
+	started  atomic.Int32
+	finished atomic.Int32
+}
+
+func (r *quiescenceRun) task(depth int, countEarly bool) {
+	defer r.truth.Done()
+	defer r.f.Done()
+	r.started.Add(1)
+
+	clk.Sleep(3 * time.Millisecond) // The task's own work
+	if depth > 0 {
+		children := rng.IntN(3) + 1
+		r.truth.Add(children)
+		if countEarly {
+			r.f.Add(children)
+		}
+		for range children {
+			go func() {
+				if !countEarly {
+					r.f.Add(1) // Too late: the parent may already have called Done
+				}
+				r.task(depth-1, countEarly)
+			}()
+		}
+	}
+	r.finished.Add(1)
+}
+
+func runQuiescence(countEarly bool) *quiescenceRun {
+	r := &quiescenceRun{f: newInFlight()}
+	r.truth.Add(1)
+	r.f.Add(1) // The root is counted by its creator in both runs
+	go r.task(5, countEarly)
+	return r
+}
+
+/**
+ * Quiescence Detection
+ *
+ * A random tree of tasks six levels deep, each spawning one to three
+ * children, is run twice. When the children count themselves after they
+ * start, the detector fires while most of the tree is still to come. When
+ * parents count their children before spawning them, it fires exactly
+ * when the last task finishes, and the waiter watches the in-flight count
+ * on a ticker while it waits.
+ */
+func QuiescenceDemo() {
+	fmt.Fprintln(stdout, "Quiescence Detection")
+
+	// 1. Children count themselves
+	fmt.Fprintln(stdout, "\n1. Each child adds itself to the count when it starts:")
+	r := runQuiescence(false)
+	<-r.f.Quiet()
+	fmt.Fprintf(stdout, "Detector fired after %d task(s) had finished\n", r.finished.Load())
+	r.truth.Wait()
+	r.f.mu.Lock()
+	late := r.f.late
+	r.f.mu.Unlock()
+	fmt.Fprintf(stdout, "The tree had %d tasks; %d were counted after the detector had already fired\n",
+		r.finished.Load(), late)
+
+	steps.Checkpoint("The root finished and called Done before its children ran their Add, so the count " +
+		"touched zero. Nothing in the code waits for a goroutine to start.")
+
+	// 2. Parents count their children
+	fmt.Fprintln(stdout, "\n2. Each parent adds its children before spawning them:")
+	r = runQuiescence(true)
+	ticker := clock.NewTicker(clk, 2*time.Millisecond)
+	defer ticker.Stop()
+	var samples []string
+wait:
+	for {
+		select {
+		case <-ticker.C:
+			samples = append(samples, fmt.Sprint(r.f.Count()))
+		case <-r.f.Quiet():
+			break wait
+		}
+	}
+	fmt.Fprintf(stdout, "In flight every 2ms: %s\n", strings.Join(samples, " "))
+	fmt.Fprintf(stdout, "Detector fired after %d of %d tasks had finished\n", r.finished.Load(), r.started.Load())
+	r.truth.Wait()
+	fmt.Fprintf(stdout, "The tree had %d tasks; none were counted late\n", r.finished.Load())
+
+	fmt.Fprintln(stdout, "\nA task is counted by whoever creates it, while that creator is itself still")
+	fmt.Fprintln(stdout, "counted, so the count stays above zero until the whole tree has finished.")
+
+	fmt.Fprintln(stdout)
+}
@@ -0,0 +1,63 @@
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// fanOutFanIn squares items with width workers reading from one input
+// channel and merges their results, returning the sum. Each item also costs
+// work iterations of spin, whose results the workers keep to themselves.
+func fanOutFanIn(items, width, work int) int {
+	input := make(chan int)
+	go func() {
+		defer close(input)
+		for i := 0; i < items; i++ {
+			input <- i
+		}
+	}()
+
+	merged := make(chan int)
+	spun := make([]uint64, width)
+	var wg sync.WaitGroup
+	for w := 0; w < width; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local uint64
+			for n := range input {
+				local += spin(work)
+				merged <- n * n
+			}
+			spun[w] = local
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	sum := 0
+	for n := range merged {
+		sum += n
+	}
+	return sum
+}
+
+// BenchmarkFanOutFanIn measures the time per item for several fan-out
+// widths, with cheap and expensive per-item work.
+func BenchmarkFanOutFanIn(b *testing.B) {
+	const items = 1000
+
+	for _, work := range []int{10, 10000} {
+		for _, width := range []int{1, 2, 4, 8, 32} {
+			b.Run(fmt.Sprintf("work=%d/width=%d", work, width), func(b *testing.B) {
+				for b.Loop() {
+					fanOutFanIn(items, width, work)
+				}
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*items), "ns/item")
+			})
+		}
+	}
+}
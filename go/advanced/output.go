@@ -0,0 +1,22 @@
+package advanced
+
+import (
+	"threads/clock"
+	"threads/console"
+)
+
+// stdout is where the examples in this package print; see console.SetOutput.
+var stdout = console.Out
+
+// steps pauses the examples at annotated checkpoints; see console.SetStepper.
+var steps = console.Steps
+
+// clk is the clock the examples sleep on; see clock.SetCurrent.
+var clk = clock.Current
+
+// logger is the structured logger the examples report events to; see
+// console.SetHandler. What a worker, producer or consumer goroutine does is
+// logged, with its ID as an attribute; headings, explanations and results
+// are printed to stdout. Simulations that keep their own timestamped event
+// log (leader-election, token-ring) print it to stdout.
+var logger = console.Log
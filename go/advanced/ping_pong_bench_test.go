@@ -0,0 +1,13 @@
+package advanced
+
+import "testing"
+
+// BenchmarkPingPong measures one round trip between two goroutines for each
+// ping-pong strategy of the demo.
+func BenchmarkPingPong(b *testing.B) {
+	for _, s := range pingPongStrategies {
+		b.Run(s.name, func(b *testing.B) {
+			s.run(b.N)
+		})
+	}
+}
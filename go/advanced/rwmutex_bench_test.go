@@ -0,0 +1,61 @@
+package advanced
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// rwLocker adapts sync.Mutex and sync.RWMutex to one read/write interface
+type rwLocker interface {
+	RLock()
+	RUnlock()
+	Lock()
+	Unlock()
+}
+
+// exclusiveMutex is a sync.Mutex that also takes the exclusive lock for reads
+type exclusiveMutex struct{ sync.Mutex }
+
+func (m *exclusiveMutex) RLock()   { m.Lock() }
+func (m *exclusiveMutex) RUnlock() { m.Unlock() }
+
+// BenchmarkMutexVsRWMutex compares a Mutex and an RWMutex guarding a map
+// under parallel load, for several percentages of reads.
+func BenchmarkMutexVsRWMutex(b *testing.B) {
+	locks := []struct {
+		name string
+		new  func() rwLocker
+	}{
+		{"mutex", func() rwLocker { return &exclusiveMutex{} }},
+		{"rwmutex", func() rwLocker { return &sync.RWMutex{} }},
+	}
+
+	for _, readPct := range []int{50, 90, 99} {
+		for _, lock := range locks {
+			b.Run(fmt.Sprintf("reads=%d%%/%s", readPct, lock.name), func(b *testing.B) {
+				mu := lock.new()
+				data := make(map[int]int, 1024)
+				for i := range 1024 {
+					data[i] = i
+				}
+
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 0; pb.Next(); i++ {
+						key := i % 1024
+						if i%100 < readPct {
+							mu.RLock()
+							_ = data[key]
+							burnCPU(50) // Keep the critical section non-trivial
+							mu.RUnlock()
+						} else {
+							mu.Lock()
+							data[key]++
+							mu.Unlock()
+						}
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package advanced
+
+import "embed"
+
+// Sources holds the source of every demo in this package, so that it can be
+// searched and printed by the examples package.
+//
+//go:embed [0-9][0-9]_*.go
+var Sources embed.FS
@@ -0,0 +1,19 @@
+//go:build cgo && !linux && !windows
+
+package advanced
+
+/*
+#include <pthread.h>
+#include <stdint.h>
+
+static unsigned long long thread_id(void) {
+	return (unsigned long long)(uintptr_t)pthread_self();
+}
+*/
+import "C"
+
+// osThreadID returns the ID of the OS thread running the caller: the value
+// of pthread_self, on systems such as macOS and the BSDs
+func osThreadID() (uint64, bool) {
+	return uint64(C.thread_id()), true
+}
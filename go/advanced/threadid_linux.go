@@ -0,0 +1,11 @@
+//go:build linux
+
+package advanced
+
+import "syscall"
+
+// osThreadID returns the ID of the OS thread running the caller: the
+// kernel's thread ID on Linux
+func osThreadID() (uint64, bool) {
+	return uint64(syscall.Gettid()), true
+}
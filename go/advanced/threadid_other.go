@@ -0,0 +1,9 @@
+//go:build !cgo && !linux && !windows
+
+package advanced
+
+// osThreadID reports false: without cgo there is no portable way to ask
+// for the current thread, and js/wasm has a single thread anyway
+func osThreadID() (uint64, bool) {
+	return 0, false
+}
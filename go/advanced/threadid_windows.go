@@ -0,0 +1,14 @@
+//go:build windows
+
+package advanced
+
+import "syscall"
+
+var getCurrentThreadID = syscall.NewLazyDLL("kernel32.dll").NewProc("GetCurrentThreadId")
+
+// osThreadID returns the ID of the OS thread running the caller, from
+// GetCurrentThreadId on Windows
+func osThreadID() (uint64, bool) {
+	id, _, _ := getCurrentThreadID.Call()
+	return uint64(id), true
+}
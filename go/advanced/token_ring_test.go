@@ -0,0 +1,71 @@
+package advanced
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runTokenRing runs a ring of five nodes while inject drives it, and
+// checks that no two nodes were ever in the critical section together
+func runTokenRing(t *testing.T, work time.Duration, inject func(ring *tokenRing)) *tokenRing {
+	t.Helper()
+	const nodes = 5
+	ring := newTokenRing(nodes, func(string, ...any) {})
+	ring.work = work
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for id := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ring.node(ctx, id)
+		}()
+	}
+
+	ring.inboxes[0] <- ringMsg{gen: 1, token: true}
+	inject(ring)
+	cancel()
+	wg.Wait()
+
+	if n := ring.maxInCS.Load(); n != 1 {
+		t.Errorf("at most %d nodes were in the critical section at once, want 1", n)
+	}
+	total := 0
+	for _, e := range ring.entries {
+		total += e
+	}
+	if ring.shared != total {
+		t.Errorf("shared counter = %d, want %d, the number of entries", ring.shared, total)
+	}
+	return ring
+}
+
+func TestTokenRingFaults(t *testing.T) {
+	// Every fault the demo injects, several times over
+	ring := runTokenRing(t, 100*time.Microsecond, func(ring *tokenRing) {
+		time.Sleep(20 * time.Millisecond)
+		for i := range 3 {
+			ring.faults[(i+3)%len(ring.inboxes)].Store(int32(ringDrop))
+			time.Sleep(2 * ringTimeout)
+			ring.faults[(i+2)%len(ring.inboxes)].Store(int32(ringStallFault))
+			time.Sleep(2 * ringStall)
+		}
+	})
+	if ring.regenerated.Load() == 0 || ring.discarded.Load() == 0 {
+		t.Errorf("%d tokens regenerated and %d discarded, want the faults to exercise both",
+			ring.regenerated.Load(), ring.discarded.Load())
+	}
+}
+
+func TestTokenRingRegenerateDuringCriticalSection(t *testing.T) {
+	// Every holder stays in the critical section past the timeout, so node 0
+	// starts new generations while a node is inside
+	ring := runTokenRing(t, 2*ringTimeout, func(*tokenRing) {
+		time.Sleep(10 * ringTimeout)
+	})
+	if ring.regenerated.Load() == 0 {
+		t.Error("no token was regenerated, want node 0 to time out")
+	}
+}
@@ -0,0 +1,31 @@
+package advanced
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+// BenchmarkWorkerPool measures the cost of a batch of jobs through
+// runJobPool for several pool sizes, with CPU-bound and IO-bound jobs.
+func BenchmarkWorkerPool(b *testing.B) {
+	const jobs = 64
+	procs := runtime.GOMAXPROCS(0)
+
+	mixes := []workloadMix{
+		{"cpu", func(int) uint64 { return spin(20000) }},
+		{"io", func(int) uint64 { time.Sleep(100 * time.Microsecond); return 0 }},
+	}
+	for _, mix := range mixes {
+		for _, workers := range slices.Compact([]int{1, procs, 4 * procs, jobs}) {
+			b.Run(fmt.Sprintf("job=%s/workers=%d", mix.name, workers), func(b *testing.B) {
+				for b.Loop() {
+					runJobPool(jobs, workers, mix.job)
+				}
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*jobs), "ns/job")
+			})
+		}
+	}
+}
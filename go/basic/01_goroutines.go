@@ -3,6 +3,7 @@
  *
  * In Go, concurrency is handled using goroutines, which are lightweight threads managed by the Go runtime.
  * Unlike OS threads, goroutines are multiplexed onto a smaller number of system threads, allowing you to run millions of them efficiently.
+ * The goroutine-scaling example measures what a goroutine costs to create and to keep alive.
  */
 
 package basic
@@ -20,7 +21,7 @@ import (
  * Notice that the goroutines are not guaranteed to run in any specific order.
  */
 func GoroutineDemo() {
-	fmt.Println("Basic Goroutine Example")
+	fmt.Fprintln(stdout, "Basic Goroutine Example")
 
 	// Start goroutines by adding the go keyword before the function call
 	go sayHello("Alice")
@@ -29,17 +30,21 @@ func GoroutineDemo() {
 
 	// Anonymous function as a goroutine
 	go func() {
-		fmt.Println("Hello from an anonymous function!")
+		fmt.Fprintln(stdout, "Hello from an anonymous function!")
 	}()
+	steps.Checkpoint("Four goroutines were started with the go keyword. The go statement returns immediately, " +
+		"so they run independently of this function and their greetings may appear in any order.")
 
 	// Sleep to allow goroutines to execute
 	// In real code, you would use proper synchronization
 	// as the functions may take more time than the Sleep time
-	time.Sleep(100 * time.Millisecond)
-	fmt.Println()
+	clk.Sleep(100 * time.Millisecond)
+	steps.Checkpoint("The Sleep gave the goroutines time to finish. Sleeping is only a guess; " +
+		"a WaitGroup or a channel waits for exactly as long as needed.")
+	fmt.Fprintln(stdout)
 }
 
 // A helper function
 func sayHello(name string) {
-	fmt.Printf("Hello, %s!\n", name)
+	fmt.Fprintf(stdout, "Hello, %s!\n", name)
 }
@@ -19,34 +19,38 @@ import (
 // You can send values into channels from one goroutine and
 // receive those values in another goroutine.
 func ChannelDemo() {
-	fmt.Println("Channel Basics Example")
+	fmt.Fprintln(stdout, "Channel Basics Example")
 
 	// Create an unbuffered channel
 	messages := make(chan string)
 
 	// Send a message in a goroutine
 	go func() {
-		fmt.Println("Sending message to channel")
+		fmt.Fprintln(stdout, "Sending message to channel")
 		messages <- "Hello, Channel!"
-		fmt.Println("Message sent")
+		fmt.Fprintln(stdout, "Message sent")
 	}()
 
 	// Receive the message
 	msg := <-messages
-	fmt.Println("Received message:", msg)
+	fmt.Fprintln(stdout, "Received message:", msg)
+	steps.Checkpoint("The send on the unbuffered channel could only complete once this goroutine received, " +
+		"so the value was handed over directly and both sides synchronised.")
 
 	// Channel as a synchronization mechanism
 	done := make(chan bool)
 
 	go func() {
-		fmt.Println("Working...")
-		time.Sleep(time.Second)
-		fmt.Println("Done working")
+		fmt.Fprintln(stdout, "Working...")
+		clk.Sleep(time.Second)
+		fmt.Fprintln(stdout, "Done working")
 		done <- true
 	}()
 
 	// Wait until work is done
 	// note the channel here is blocking till something is sent
 	<-done
-	fmt.Println()
+	steps.Checkpoint("Receiving from done blocked until the worker sent on it: " +
+		"a channel can signal completion without carrying useful data.")
+	fmt.Fprintln(stdout)
 }
@@ -18,7 +18,7 @@ import (
  * This can be useful when you know how many values will be sent in advance.
  */
 func BufferedChannelDemo() {
-	fmt.Println("Channel Buffering Example")
+	fmt.Fprintln(stdout, "Channel Buffering Example")
 
 	// Create a buffered channel with capacity 3
 	ch := make(chan string, 3)
@@ -28,11 +28,13 @@ func BufferedChannelDemo() {
 	ch <- "buffered"
 	ch <- "channel"
 	ch <- "example"
+	steps.Checkpoint("Three sends completed with no receiver ready, because the buffer has room for three values. " +
+		"A fourth send would block until someone received.")
 
 	// Receive values
-	fmt.Println(<-ch)
-	fmt.Println(<-ch)
-	fmt.Println(<-ch)
+	fmt.Fprintln(stdout, <-ch)
+	fmt.Fprintln(stdout, <-ch)
+	fmt.Fprintln(stdout, <-ch)
 
 	// Demonstrate channel closing
 	jobs := make(chan int, 5)
@@ -41,11 +43,11 @@ func BufferedChannelDemo() {
 	// Producer
 	go func() {
 		for i := 1; i <= 5; i++ {
-			fmt.Println("Sending job", i)
+			logger.Info("sending job", "job", i)
 			jobs <- i
 		}
 		close(jobs) // Close the channel when done sending
-		fmt.Println("All jobs sent")
+		logger.Info("all jobs sent")
 	}()
 
 	// Consumer
@@ -53,9 +55,9 @@ func BufferedChannelDemo() {
 		for {
 			j, more := <-jobs
 			if more {
-				fmt.Println("Received job", j)
+				logger.Info("received job", "job", j)
 			} else {
-				fmt.Println("All jobs received")
+				logger.Info("all jobs received")
 				done <- true
 				return
 			}
@@ -64,5 +66,7 @@ func BufferedChannelDemo() {
 
 	// Wait until all jobs are processed
 	<-done
-	fmt.Println()
+	steps.Checkpoint("The producer closed jobs after its last send. The consumer drained the buffered values " +
+		"first and only then saw more == false.")
+	fmt.Fprintln(stdout)
 }
@@ -21,7 +21,7 @@ import (
  * If multiple cases are ready, it chooses one at random.
  */
 func SelectDemo() {
-	fmt.Println("Select Example")
+	fmt.Fprintln(stdout, "Select Example")
 
 	// Create channels
 	c1 := make(chan string)
@@ -29,12 +29,12 @@ func SelectDemo() {
 
 	// Send values on each channel
 	go func() {
-		time.Sleep(1 * time.Second)
+		clk.Sleep(1 * time.Second)
 		c1 <- "one"
 	}()
 
 	go func() {
-		time.Sleep(2 * time.Second)
+		clk.Sleep(2 * time.Second)
 		c2 <- "two"
 	}()
 
@@ -42,38 +42,44 @@ func SelectDemo() {
 	for i := 0; i < 2; i++ {
 		select {
 		case msg1 := <-c1:
-			fmt.Println("Received", msg1)
+			fmt.Fprintln(stdout, "Received", msg1)
 		case msg2 := <-c2:
-			fmt.Println("Received", msg2)
+			fmt.Fprintln(stdout, "Received", msg2)
 		}
 	}
 
+	steps.Checkpoint("select waited on both channels at once and ran whichever case was ready first. " +
+		"Both values arrived after about 2 seconds in total, not 3.")
+
 	// Select with timeout
-	fmt.Println("\nSelect with timeout:")
+	fmt.Fprintln(stdout, "\nSelect with timeout:")
 	ch := make(chan string)
 
 	go func() {
-		time.Sleep(2 * time.Second)
+		clk.Sleep(2 * time.Second)
 		ch <- "result"
 	}()
 
 	select {
 	case res := <-ch:
-		fmt.Println("Received:", res)
-	case <-time.After(1 * time.Second):
-		fmt.Println("Timeout: operation took too long")
+		fmt.Fprintln(stdout, "Received:", res)
+	case <-clk.After(1 * time.Second):
+		fmt.Fprintln(stdout, "Timeout: operation took too long")
 	}
 
+	steps.Checkpoint("time.After raced against the slow result and won after 1 second. " +
+		"This is the usual way to put a timeout on a channel operation.")
+
 	// Non-blocking select
-	fmt.Println("Non-blocking select:")
+	fmt.Fprintln(stdout, "Non-blocking select:")
 	select {
 	case msg := <-ch:
-		fmt.Println("Received message:", msg)
+		fmt.Fprintln(stdout, "Received message:", msg)
 	default:
-		fmt.Println("No message available")
+		fmt.Fprintln(stdout, "No message available")
 	}
 
 	// Drain the channel
 	<-ch
-	fmt.Println()
+	fmt.Fprintln(stdout)
 }
@@ -9,9 +9,10 @@ package basic
 
 import (
 	"fmt"
-	"math/rand"
 	"sync"
 	"time"
+
+	"threads/rng"
 )
 
 /**
@@ -21,7 +22,7 @@ import (
  * It provides a way to synchronize goroutines.
  */
 func WaitGroupDemo() {
-	fmt.Println("WaitGroup Example")
+	fmt.Fprintln(stdout, "WaitGroup Example")
 
 	// Create a WaitGroup
 	var wg sync.WaitGroup
@@ -30,9 +31,14 @@ func WaitGroupDemo() {
 	worker := func(id int) {
 		defer wg.Done() // Decrement the counter when the goroutine completes
 
-		fmt.Printf("Worker %d starting\n", id)
-		time.Sleep(time.Duration(rand.Intn(1000)) * time.Millisecond)
-		fmt.Printf("Worker %d done\n", id)
+		// Every record this worker logs carries its ID as an attribute
+		log := logger.With("worker", id)
+
+		log.Info("worker starting")
+		work := time.Duration(rng.IntN(1000)) * time.Millisecond
+		log.Debug("simulating work", "duration", work)
+		clk.Sleep(work)
+		log.Info("worker done")
 	}
 
 	// Launch several workers
@@ -41,7 +47,11 @@ func WaitGroupDemo() {
 		go worker(i)
 	}
 
+	steps.Checkpoint("wg.Add(1) ran before each go statement, so the counter is already 5 " +
+		"even though some workers may not have started yet.")
+
 	// Wait for all workers to finish
 	wg.Wait()
-	fmt.Println()
+	steps.Checkpoint("Every worker called wg.Done when it finished, and Wait returned once the counter reached zero.")
+	fmt.Fprintln(stdout)
 }
@@ -0,0 +1,229 @@
+/**
+ * The bench subcommand.
+ *
+ * "bench" (or "bench counters") compares three ways of incrementing a shared
+ * counter from many goroutines: a mutex, an atomic, and a dedicated owner
+ * goroutine fed through a channel.
+ *
+ * "bench buffers" turns the dynamic buffer sizing demo into a suite measuring
+ * send latency and throughput across buffer sizes and producer/consumer
+ * speed ratios. It times plain loops so the binary does not link the testing
+ * package; BenchmarkChannelBuffer runs the same measurements under go test.
+ *
+ * "bench report" summarises `go test -bench` output; see benchreport.go.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// benchIncrements is the total number of increments per measurement
+const benchIncrements = 1000000
+
+// counterStrategy increments a shared counter goroutines*perGoroutine times
+// and returns the final count.
+type counterStrategy struct {
+	name string
+	run  func(goroutines, perGoroutine int) int64
+}
+
+var counterStrategies = []counterStrategy{
+	{"mutex", func(goroutines, perGoroutine int) int64 {
+		var mu sync.Mutex
+		var count int64
+		parallelIncrements(goroutines, perGoroutine, func() {
+			mu.Lock()
+			count++
+			mu.Unlock()
+		})
+		return count
+	}},
+	{"atomic", func(goroutines, perGoroutine int) int64 {
+		var count atomic.Int64
+		parallelIncrements(goroutines, perGoroutine, func() {
+			count.Add(1)
+		})
+		return count.Load()
+	}},
+	{"channel owner", func(goroutines, perGoroutine int) int64 {
+		// Only the owner goroutine ever touches count
+		incs := make(chan struct{}, 128)
+		result := make(chan int64)
+		go func() {
+			var count int64
+			for range incs {
+				count++
+			}
+			result <- count
+		}()
+
+		parallelIncrements(goroutines, perGoroutine, func() {
+			incs <- struct{}{}
+		})
+		close(incs)
+		return <-result
+	}},
+}
+
+// parallelIncrements calls inc perGoroutine times from each of n goroutines
+func parallelIncrements(n, perGoroutine int, inc func()) {
+	var wg sync.WaitGroup
+	for g := 0; g < n; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				inc()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runBench runs the named benchmark suite
+func runBench(suite string) {
+	switch suite {
+	case "", "counters":
+		runCounterBench()
+	case "buffers":
+		runBufferBench()
+	case "report":
+		runBenchReport(os.Stdin)
+	default:
+		fmt.Printf("Unknown benchmark suite %q (available: counters, buffers, report)\n", suite)
+	}
+}
+
+// runCounterBench prints a table of ns per increment for each strategy and
+// goroutine count.
+func runCounterBench() {
+	fmt.Println("\nShared counter benchmark: mutex vs atomic vs channel owner")
+	fmt.Printf("GOMAXPROCS=%d, %d increments per cell, ns/op\n\n", runtime.GOMAXPROCS(0), benchIncrements)
+
+	fmt.Printf("%-12s", "goroutines")
+	for _, s := range counterStrategies {
+		fmt.Printf(" %14s", s.name)
+	}
+	fmt.Println()
+
+	for _, goroutines := range []int{1, 2, 4, 8, 16, 64} {
+		perGoroutine := benchIncrements / goroutines
+		total := goroutines * perGoroutine
+
+		fmt.Printf("%-12d", goroutines)
+		for _, s := range counterStrategies {
+			start := time.Now()
+			count := s.run(goroutines, perGoroutine)
+			nsPerOp := float64(time.Since(start).Nanoseconds()) / float64(total)
+
+			cell := fmt.Sprintf("%.1f", nsPerOp)
+			if count != int64(total) {
+				cell += "!" // Lost increments
+			}
+			fmt.Printf(" %14s", cell)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// bufferBenchTime is roughly how long each buffer measurement runs
+const bufferBenchTime = 200 * time.Millisecond
+
+// speedRatio describes the per-item work done by producer and consumer
+type speedRatio struct {
+	name     string
+	producer int
+	consumer int
+}
+
+var speedRatios = []speedRatio{
+	{"fast-producer", 1, 4},
+	{"balanced", 2, 2},
+	{"fast-consumer", 4, 1},
+}
+
+// runBufferBench runs the channel buffer benchmarks and prints results in the
+// standard Go benchmark format, which tools such as benchstat can parse. The
+// same measurements run under go test as BenchmarkChannelBuffer.
+//
+// Besides ns/op (the inverse of throughput) every result reports send-ns/op,
+// the average time the producer spent blocked in a send, and items/s.
+func runBufferBench() {
+	fmt.Printf("\ngoos: %s\ngoarch: %s\n", runtime.GOOS, runtime.GOARCH)
+	for _, ratio := range speedRatios {
+		for _, size := range []int{0, 1, 16, 256, 4096} {
+			n, elapsed, blocked := timeBuffer(size, ratio.producer, ratio.consumer)
+			fmt.Printf("BenchmarkChannelBuffer/ratio=%s/size=%d-%d\t%8d\t%10.1f ns/op\t%10.1f send-ns/op\t%10.0f items/s\n",
+				ratio.name, size, runtime.GOMAXPROCS(0), n,
+				float64(elapsed.Nanoseconds())/float64(n), float64(blocked.Nanoseconds())/float64(n),
+				float64(n)/elapsed.Seconds())
+		}
+	}
+	fmt.Println()
+}
+
+// timeBuffer sends growing batches through sendThrough until one takes at
+// least bufferBenchTime, like testing.B does, and returns that batch's item
+// count, duration and time spent blocked in sends
+func timeBuffer(size, producerWork, consumerWork int) (n int, elapsed, blocked time.Duration) {
+	for n = 1; ; n *= 2 {
+		start := time.Now()
+		blocked = sendThrough(size, producerWork, consumerWork, n)
+		elapsed = time.Since(start)
+		if elapsed >= bufferBenchTime || n >= 1<<30 {
+			return n, elapsed, blocked
+		}
+	}
+}
+
+// sendThrough sends n items through a channel with the given buffer size,
+// with producer and consumer doing the given units of work per item. It
+// returns once the consumer has received every item, reporting how long the
+// producer spent blocked in sends.
+func sendThrough(size, producerWork, consumerWork, n int) (blocked time.Duration) {
+	ch := make(chan int, size)
+	done := make(chan struct{})
+
+	// Each side keeps its own sink and they are folded in after the join
+	var consumed int
+	go func() {
+		defer close(done)
+		for range ch {
+			consumed += spinWork(consumerWork)
+		}
+	}()
+
+	var produced int
+	for i := 0; i < n; i++ {
+		produced += spinWork(producerWork)
+
+		start := time.Now()
+		ch <- i
+		blocked += time.Since(start)
+	}
+	close(ch)
+	<-done
+	spinSink += produced + consumed
+	return blocked
+}
+
+// spinSink keeps the compiler from optimizing spinWork away
+var spinSink int
+
+// spinWork burns roughly units * 100ns of CPU without blocking and returns
+// the result for the caller to fold into its own sink
+func spinWork(units int) int {
+	x := units
+	for i := 0; i < units*100; i++ {
+		x = x*31 + i
+	}
+	return x
+}
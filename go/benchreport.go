@@ -0,0 +1,180 @@
+/**
+ * The "bench report" subcommand.
+ *
+ * It reads the output of `go test -bench` (usually with -count > 1) from
+ * standard input and prints one table per benchmark family: the mean and
+ * spread of every metric across runs, and how each case compares with the
+ * fastest case of its family. `make bench` pipes the whole suite into it.
+ */
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"threads/stats"
+)
+
+// benchCase collects the results of one benchmark across -count runs
+type benchCase struct {
+	name    string
+	nsPerOp []time.Duration
+	metrics map[string][]float64 // Other metrics by unit, e.g. "B/op"
+	units   []string             // Units of metrics in first-seen order
+}
+
+// benchFamily groups the cases of one top-level benchmark
+type benchFamily struct {
+	pkg, name string
+	cases     []*benchCase
+}
+
+// runBenchReport parses benchmark output from r and prints the summary
+func runBenchReport(r io.Reader) {
+	families, err := parseBenchOutput(r)
+	if err != nil {
+		fmt.Println("Error reading benchmark output:", err)
+		return
+	}
+	if len(families) == 0 {
+		fmt.Println("\nNo benchmark results found on standard input.")
+		fmt.Println("Usage: go test -run '^$' -bench . -count 5 ./... | go run . bench report")
+		return
+	}
+
+	for _, f := range families {
+		printBenchFamily(f)
+	}
+}
+
+func parseBenchOutput(r io.Reader) ([]*benchFamily, error) {
+	var (
+		families []*benchFamily
+		byName   = map[string]*benchFamily{}
+		cases    = map[string]*benchCase{}
+		pkg      string
+	)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if p, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = p
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue // Not a result line
+		}
+
+		name := trimProcs(fields[0])
+		family, _, _ := strings.Cut(name, "/")
+		key := pkg + "." + name
+
+		f := byName[pkg+"."+family]
+		if f == nil {
+			f = &benchFamily{pkg: pkg, name: family}
+			byName[pkg+"."+family] = f
+			families = append(families, f)
+		}
+		c := cases[key]
+		if c == nil {
+			c = &benchCase{name: name, metrics: map[string][]float64{}}
+			cases[key] = c
+			f.cases = append(f.cases, c)
+		}
+
+		// The remaining fields are value/unit pairs
+		for i := 2; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			unit := fields[i+1]
+			if unit == "ns/op" {
+				c.nsPerOp = append(c.nsPerOp, time.Duration(v))
+				continue
+			}
+			if _, ok := c.metrics[unit]; !ok {
+				c.units = append(c.units, unit)
+			}
+			c.metrics[unit] = append(c.metrics[unit], v)
+		}
+	}
+	return families, sc.Err()
+}
+
+// trimProcs removes the -GOMAXPROCS suffix from a benchmark name
+func trimProcs(name string) string {
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
+
+func printBenchFamily(f *benchFamily) {
+	var fastest time.Duration
+	for _, c := range f.cases {
+		if len(c.nsPerOp) == 0 {
+			continue
+		}
+		if mean := stats.Summarize(c.nsPerOp).Mean; fastest == 0 || mean < fastest {
+			fastest = mean
+		}
+	}
+
+	fmt.Printf("\n%s (%s)\n", f.name, f.pkg)
+	fmt.Printf("%-44s %5s %14s %8s %9s  %s\n", "case", "runs", "ns/op", "±", "vs best", "other metrics (mean)")
+	for _, c := range f.cases {
+		if len(c.nsPerOp) == 0 {
+			continue
+		}
+		s := stats.Summarize(c.nsPerOp)
+		spread := 0.0
+		if s.Mean > 0 {
+			spread = 100 * float64(s.StdDev) / float64(s.Mean)
+		}
+
+		var others []string
+		for _, unit := range c.units {
+			others = append(others, fmt.Sprintf("%s %s", formatMetric(mean(c.metrics[unit])), unit))
+		}
+
+		label := strings.TrimPrefix(strings.TrimPrefix(c.name, f.name), "/")
+		if label == "" {
+			label = f.name
+		}
+		fmt.Printf("%-44s %5d %14.1f %7.1f%% %8.2fx  %s\n", label, s.N, float64(s.Mean),
+			spread, float64(s.Mean)/float64(fastest), strings.Join(others, ", "))
+	}
+}
+
+func mean(vs []float64) float64 {
+	sum := 0.0
+	for _, v := range vs {
+		sum += v
+	}
+	return sum / float64(len(vs))
+}
+
+// formatMetric prints large values without decimals and small ones with two
+func formatMetric(v float64) string {
+	if math.Abs(v) >= 100 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// BenchmarkChannelBuffer is the "bench buffers" suite as a regular
+// benchmark, so it can be run with go test and compared with benchstat.
+func BenchmarkChannelBuffer(b *testing.B) {
+	for _, ratio := range speedRatios {
+		for _, size := range []int{0, 1, 16, 256, 4096} {
+			b.Run(fmt.Sprintf("ratio=%s/size=%d", ratio.name, size), benchmarkBuffer(size, ratio.producer, ratio.consumer))
+		}
+	}
+}
+
+// benchmarkBuffer sends b.N items through sendThrough and reports the same
+// metrics as "bench buffers"
+func benchmarkBuffer(size, producerWork, consumerWork int) func(b *testing.B) {
+	return func(b *testing.B) {
+		blocked := sendThrough(size, producerWork, consumerWork, b.N)
+		b.ReportMetric(float64(blocked.Nanoseconds())/float64(b.N), "send-ns/op")
+		b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "items/s")
+	}
+}
@@ -0,0 +1,116 @@
+// Package cache provides concurrency-safe in-memory caches.
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache is the interface shared by the caches in this package.
+type Cache[K comparable, V any] interface {
+	// Get returns the value cached for key, marking it as recently used.
+	Get(key K) (V, bool)
+	// Put caches value under key and reports whether another entry was
+	// evicted to make room for it.
+	Put(key K, value V) bool
+	// Len returns the number of cached entries.
+	Len() int
+	// Stats returns the hit, miss and eviction counts so far.
+	Stats() Stats
+}
+
+// Stats counts the outcomes of cache operations.
+type Stats struct {
+	Hits, Misses, Evictions uint64
+}
+
+// HitRate returns the fraction of lookups that were hits.
+func (s Stats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+func (s Stats) add(t Stats) Stats {
+	return Stats{s.Hits + t.Hits, s.Misses + t.Misses, s.Evictions + t.Evictions}
+}
+
+// LRU is a fixed-capacity cache that evicts the least recently used entry
+// when it is full. A single mutex guards it; note that even Get needs the
+// exclusive lock, because a lookup moves the entry to the front of the
+// recency list, so an RWMutex would not help.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*list.Element // Values are *lruEntry[K, V]
+	order    *list.List          // Most recently used first
+	stats    Stats
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLRU returns an empty LRU cache holding up to capacity entries. It
+// panics if capacity is less than one.
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	if capacity < 1 {
+		panic("cache: LRU capacity must be at least 1")
+	}
+	return &LRU[K, V]{capacity: capacity, items: make(map[K]*list.Element, capacity), order: list.New()}
+}
+
+// Get returns the value cached for key, marking it as most recently used.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.order.MoveToFront(e)
+	return e.Value.(*lruEntry[K, V]).value, true
+}
+
+// Put caches value under key as the most recently used entry. If the cache
+// is full, the least recently used entry is evicted and Put reports true.
+func (c *LRU[K, V]) Put(key K, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(e)
+		return false
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key, value})
+	if c.order.Len() <= c.capacity {
+		return false
+	}
+	oldest := c.order.Back()
+	c.order.Remove(oldest)
+	delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+	c.stats.Evictions++
+	return true
+}
+
+// Len returns the number of cached entries.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns the hit, miss and eviction counts so far.
+func (c *LRU[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
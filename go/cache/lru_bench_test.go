@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// BenchmarkLRU compares the single-mutex and sharded LRU caches under
+// parallel load, for several percentages of reads. Keys are skewed towards
+// small numbers so that the cache gets hits as well as misses.
+func BenchmarkLRU(b *testing.B) {
+	const keys, capacity = 4096, 1024
+
+	caches := []struct {
+		name string
+		new  func() Cache[int, int]
+	}{
+		{"mutex", func() Cache[int, int] { return NewLRU[int, int](capacity) }},
+		{"sharded-16", func() Cache[int, int] { return NewShardedLRU[int, int](capacity, 16) }},
+	}
+
+	for _, readPct := range []int{50, 90, 99} {
+		for _, c := range caches {
+			b.Run(fmt.Sprintf("reads=%d%%/%s", readPct, c.name), func(b *testing.B) {
+				cache := c.new()
+				for i := range capacity {
+					cache.Put(i, i)
+				}
+
+				b.RunParallel(func(pb *testing.PB) {
+					r := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+					for pb.Next() {
+						key := r.IntN(r.IntN(keys) + 1)
+						if r.IntN(100) < readPct {
+							cache.Get(key)
+						} else {
+							cache.Put(key, key)
+						}
+					}
+				})
+				b.ReportMetric(cache.Stats().HitRate()*100, "hit%")
+			})
+		}
+	}
+}
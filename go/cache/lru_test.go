@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// present returns the keys in keys that c still holds, in order. Looking a
+// key up refreshes its recency, so this is only used at the end of a test.
+func present(c Cache[int, int], keys []int) []int {
+	var got []int
+	for _, k := range keys {
+		if _, ok := c.Get(k); ok {
+			got = append(got, k)
+		}
+	}
+	return got
+}
+
+func TestLRUEvictionOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  func(c *LRU[int, int])
+		want []int // Keys 1 to 5 still cached afterwards
+	}{
+		{"oldest is evicted", func(c *LRU[int, int]) {
+			for k := 1; k <= 4; k++ {
+				c.Put(k, k)
+			}
+		}, []int{2, 3, 4}},
+		{"Get refreshes recency", func(c *LRU[int, int]) {
+			c.Put(1, 1)
+			c.Put(2, 2)
+			c.Put(3, 3)
+			c.Get(1)
+			c.Put(4, 4)
+		}, []int{1, 3, 4}},
+		{"Put of an existing key refreshes recency", func(c *LRU[int, int]) {
+			c.Put(1, 1)
+			c.Put(2, 2)
+			c.Put(3, 3)
+			c.Put(1, 10)
+			c.Put(4, 4)
+			c.Put(5, 5)
+		}, []int{1, 4, 5}},
+		{"a miss does not change recency", func(c *LRU[int, int]) {
+			c.Put(1, 1)
+			c.Put(2, 2)
+			c.Put(3, 3)
+			c.Get(9)
+			c.Put(4, 4)
+		}, []int{2, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewLRU[int, int](3)
+			tt.ops(c)
+			if got := present(c, []int{1, 2, 3, 4, 5}); !slices.Equal(got, tt.want) {
+				t.Errorf("cached keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLRUPutReportsEviction(t *testing.T) {
+	c := NewLRU[string, int](2)
+	steps := []struct {
+		key     string
+		evicted bool
+	}{
+		{"a", false},
+		{"b", false},
+		{"a", false}, // Replacing an entry never evicts
+		{"c", true},
+		{"d", true},
+	}
+	for _, s := range steps {
+		if got := c.Put(s.key, 0); got != s.evicted {
+			t.Errorf("Put(%q) = %v, want %v", s.key, got, s.evicted)
+		}
+	}
+	if v, ok := c.Get("d"); !ok || v != 0 {
+		t.Errorf("Get(d) = %d, %v; want 0, true", v, ok)
+	}
+	if s := c.Stats(); s.Evictions != 2 || s.Hits != 1 {
+		t.Errorf("Stats = %+v, want 2 evictions and 1 hit", s)
+	}
+}
+
+func TestLRUCapacity(t *testing.T) {
+	for _, capacity := range []int{1, 2, 10} {
+		c := NewLRU[int, int](capacity)
+		for k := range 3 * capacity {
+			c.Put(k, k)
+			if c.Len() > capacity {
+				t.Fatalf("capacity %d: Len = %d after %d puts", capacity, c.Len(), k+1)
+			}
+		}
+		if c.Len() != capacity {
+			t.Errorf("capacity %d: Len = %d when full", capacity, c.Len())
+		}
+	}
+}
+
+func TestNewLRUPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewLRU(0) did not panic")
+		}
+	}()
+	NewLRU[int, int](0)
+}
+
+func TestShardedLRUCapacity(t *testing.T) {
+	tests := []struct {
+		capacity, shards int
+		max              int // Capacity rounded up to a multiple of shards
+	}{
+		{16, 4, 16},
+		{10, 4, 12},
+		{3, 8, 8},
+		{5, 1, 5},
+	}
+	for _, tt := range tests {
+		c := NewShardedLRU[int, int](tt.capacity, tt.shards)
+		for k := range 10 * tt.max {
+			c.Put(k, k)
+		}
+		if n := c.Len(); n > tt.max || n == 0 {
+			t.Errorf("NewShardedLRU(%d, %d): Len = %d, want 1 to %d", tt.capacity, tt.shards, n, tt.max)
+		}
+	}
+}
+
+func TestShardedLRUOneShardIsLRU(t *testing.T) {
+	c := NewShardedLRU[int, int](3, 1)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	c.Get(1)
+	if !c.Put(4, 4) {
+		t.Error("Put into a full shard did not report an eviction")
+	}
+	if got := present(c, []int{1, 2, 3, 4}); !slices.Equal(got, []int{1, 3, 4}) {
+		t.Errorf("cached keys = %v, want [1 3 4]", got)
+	}
+}
+
+func TestNewShardedLRUPanics(t *testing.T) {
+	for _, args := range [][2]int{{0, 4}, {4, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewShardedLRU(%d, %d) did not panic", args[0], args[1])
+				}
+			}()
+			NewShardedLRU[int, int](args[0], args[1])
+		}()
+	}
+}
+
+// TestCachesConcurrent hammers each cache from several goroutines; run it
+// with -race. Every hit must return the value stored for that key.
+func TestCachesConcurrent(t *testing.T) {
+	caches := []struct {
+		name  string
+		cache Cache[int, int]
+	}{
+		{"lru", NewLRU[int, int](64)},
+		{"sharded", NewShardedLRU[int, int](64, 8)},
+	}
+	for _, tc := range caches {
+		t.Run(tc.name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for g := range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range 2000 {
+						key := (g*7 + i) % 128
+						if i%3 == 0 {
+							tc.cache.Put(key, key*10)
+						} else if v, ok := tc.cache.Get(key); ok && v != key*10 {
+							t.Errorf("Get(%d) = %d, want %d", key, v, key*10)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			if n := tc.cache.Len(); n > 64 {
+				t.Errorf("Len = %d, want at most 64", n)
+			}
+			// Every third operation is a Put; the rest are counted as hits or misses
+			s := tc.cache.Stats()
+			if lookups, want := s.Hits+s.Misses, uint64(8*(2000-667)); lookups != want {
+				t.Errorf("Stats counted %d lookups, want %d", lookups, want)
+			}
+		})
+	}
+}
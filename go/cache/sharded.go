@@ -0,0 +1,66 @@
+package cache
+
+import "hash/maphash"
+
+// ShardedLRU spreads its entries over several independent LRU caches, each
+// with its own lock, chosen by a hash of the key. Goroutines working on keys
+// in different shards do not contend. The price is that recency is tracked
+// per shard: a full shard evicts its own least recently used entry, which
+// may be more recent than entries in other shards.
+type ShardedLRU[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*LRU[K, V]
+}
+
+// NewShardedLRU returns an empty sharded cache holding about capacity
+// entries, split evenly over the given number of shards. It panics if
+// capacity or shards is less than one.
+func NewShardedLRU[K comparable, V any](capacity, shards int) *ShardedLRU[K, V] {
+	if shards < 1 {
+		panic("cache: ShardedLRU needs at least one shard")
+	}
+	if capacity < 1 {
+		panic("cache: ShardedLRU capacity must be at least 1")
+	}
+	c := &ShardedLRU[K, V]{seed: maphash.MakeSeed(), shards: make([]*LRU[K, V], shards)}
+	perShard := (capacity + shards - 1) / shards
+	for i := range c.shards {
+		c.shards[i] = NewLRU[K, V](perShard)
+	}
+	return c
+}
+
+func (c *ShardedLRU[K, V]) shard(key K) *LRU[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
+
+// Get returns the value cached for key, marking it as most recently used in
+// its shard.
+func (c *ShardedLRU[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+// Put caches value under key. If the key's shard is full, its least
+// recently used entry is evicted and Put reports true.
+func (c *ShardedLRU[K, V]) Put(key K, value V) bool {
+	return c.shard(key).Put(key, value)
+}
+
+// Len returns the number of cached entries. Shards are counted one at a
+// time, so the result is approximate while other goroutines use the cache.
+func (c *ShardedLRU[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// Stats returns the hit, miss and eviction counts summed over the shards.
+func (c *ShardedLRU[K, V]) Stats() Stats {
+	var total Stats
+	for _, s := range c.shards {
+		total = total.add(s.Stats())
+	}
+	return total
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// TTL is a cache whose entries expire a fixed time after they were stored.
+// A janitor goroutine owned by the cache removes expired entries on a
+// ticker; lookups never return an expired entry, even before it is swept.
+//
+// The janitor stops when the context passed to NewTTL is cancelled or Close
+// is called. If the cache is simply dropped, the janitor is stopped once the
+// cache has been garbage collected, so it never outlives its owner.
+type TTL[K comparable, V any] struct {
+	*ttlCache[K, V]
+}
+
+// ttlCache holds the state of a TTL cache. The janitor only refers to this
+// inner struct, so the outer TTL can become unreachable while it runs.
+type ttlCache[K comparable, V any] struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	items map[K]ttlEntry[V]
+	stats Stats
+
+	stop context.CancelFunc
+	done chan struct{} // Closed when the janitor has exited
+}
+
+type ttlEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// NewTTL returns an empty cache whose entries expire ttl after they are
+// stored, and starts its janitor, which sweeps expired entries every sweep
+// interval until ctx is cancelled or Close is called. It panics if sweep is
+// not positive.
+func NewTTL[K comparable, V any](ctx context.Context, ttl, sweep time.Duration) *TTL[K, V] {
+	if sweep <= 0 {
+		panic("cache: TTL sweep interval must be positive")
+	}
+	ctx, stop := context.WithCancel(ctx)
+	c := &ttlCache[K, V]{ttl: ttl, items: map[K]ttlEntry[V]{}, stop: stop, done: make(chan struct{})}
+	go c.janitor(ctx, sweep)
+
+	outer := &TTL[K, V]{c}
+	runtime.AddCleanup(outer, func(stop context.CancelFunc) { stop() }, stop)
+	return outer
+}
+
+// janitor sweeps expired entries on every tick until ctx is done
+func (c *ttlCache[K, V]) janitor(ctx context.Context, interval time.Duration) {
+	defer close(c.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			c.sweep(now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep removes the entries that expired at or before now
+func (c *ttlCache[K, V]) sweep(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, e := range c.items {
+		if !now.Before(e.expires) {
+			delete(c.items, key)
+			c.stats.Evictions++
+		}
+	}
+}
+
+// Get returns the value cached for key if it has not expired.
+func (c *ttlCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok || !time.Now().Before(e.expires) {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	return e.value, true
+}
+
+// Put caches value under key until the TTL elapses, replacing any previous
+// entry and restarting its TTL. The cache has no capacity limit, so Put never
+// evicts and always reports false.
+func (c *ttlCache[K, V]) Put(key K, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = ttlEntry[V]{value, time.Now().Add(c.ttl)}
+	return false
+}
+
+// Len returns the number of entries the janitor has not removed yet, which
+// may include expired ones.
+func (c *ttlCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Stats returns the hit and miss counts so far. Evictions counts the
+// expired entries removed by the janitor.
+func (c *ttlCache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Close stops the janitor and waits for it to exit. The cache can still be
+// used afterwards, but expired entries are no longer removed. Close may be
+// called more than once.
+func (c *ttlCache[K, V]) Close() {
+	c.stop()
+	<-c.done
+}
+
+// Done returns a channel that is closed once the janitor has exited.
+func (c *ttlCache[K, V]) Done() <-chan struct{} {
+	return c.done
+}
@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// waitDone fails the test if the janitor has not exited within a second
+func waitDone(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the janitor did not exit")
+	}
+}
+
+func TestTTLExpiry(t *testing.T) {
+	c := NewTTL[string, int](context.Background(), 20*time.Millisecond, time.Hour)
+	defer c.Close()
+
+	c.Put("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get = %d, %v before the TTL; want 1, true", v, ok)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	// The janitor has not swept yet, but the expired entry is not returned
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get returned an expired entry")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len = %d before the sweep, want 1", c.Len())
+	}
+	if s := c.Stats(); s.Hits != 1 || s.Misses != 1 {
+		t.Errorf("Stats = %+v, want 1 hit and 1 miss", s)
+	}
+}
+
+func TestTTLPutRestartsTTL(t *testing.T) {
+	c := NewTTL[string, int](context.Background(), 40*time.Millisecond, time.Hour)
+	defer c.Close()
+
+	c.Put("a", 1)
+	time.Sleep(25 * time.Millisecond)
+	c.Put("a", 2)
+	time.Sleep(25 * time.Millisecond)
+	if v, ok := c.Get("a"); !ok || v != 2 {
+		t.Errorf("Get = %d, %v after a second Put; want 2, true", v, ok)
+	}
+}
+
+func TestTTLSweep(t *testing.T) {
+	c := NewTTL[int, int](context.Background(), 5*time.Millisecond, 5*time.Millisecond)
+	defer c.Close()
+
+	for i := range 10 {
+		c.Put(i, i)
+	}
+	deadline := time.Now().Add(time.Second)
+	for c.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len = %d after the TTL, want the janitor to have removed every entry", c.Len())
+	}
+	if s := c.Stats(); s.Evictions != 10 {
+		t.Errorf("Evictions = %d, want 10", s.Evictions)
+	}
+}
+
+func TestTTLJanitorStops(t *testing.T) {
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		c := NewTTL[string, int](ctx, time.Minute, time.Millisecond)
+		cancel()
+		waitDone(t, c.Done())
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		c := NewTTL[string, int](context.Background(), time.Minute, time.Millisecond)
+		c.Close()
+		waitDone(t, c.Done())
+		c.Close() // A second Close returns straight away
+	})
+
+	t.Run("garbage collected", func(t *testing.T) {
+		done := NewTTL[string, int](context.Background(), time.Minute, time.Millisecond).Done()
+		deadline := time.Now().Add(time.Second)
+		for {
+			runtime.GC()
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("the janitor kept running after the cache was garbage collected")
+			}
+		}
+	})
+}
+
+func TestNewTTLPanics(t *testing.T) {
+	for _, sweep := range []time.Duration{0, -time.Second} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewTTL with sweep %v did not panic", sweep)
+				}
+			}()
+			NewTTL[string, int](context.Background(), time.Minute, sweep)
+		}()
+	}
+}
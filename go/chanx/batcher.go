@@ -0,0 +1,83 @@
+// Package chanx provides reusable building blocks on top of Go channels.
+package chanx
+
+import (
+	"time"
+
+	"threads/clock"
+)
+
+// Batcher groups values received from a channel into slices.
+//
+// A batch is emitted as soon as it holds Size items, or when MaxWait has
+// passed since its first item arrived, whichever comes first. The timeout
+// bounds the latency of every item even when traffic is too light to fill a
+// batch.
+type Batcher[T any] struct {
+	// Size is the maximum number of items in a batch. Values below 1 are
+	// treated as 1.
+	Size int
+	// MaxWait is the longest an item waits in a partial batch. Zero disables
+	// the timeout, so batches are flushed on size (and on close) only.
+	MaxWait time.Duration
+	// Clock measures MaxWait. Nil means the real clock.
+	Clock clock.Clock
+}
+
+// Batch reads from in until it is closed and sends batches on the returned
+// channel. Any partial batch is flushed when in is closed, after which the
+// returned channel is closed too.
+func (b Batcher[T]) Batch(in <-chan T) <-chan []T {
+	size := max(b.Size, 1)
+	c := b.Clock
+	if c == nil {
+		c = clock.Real()
+	}
+	out := make(chan []T)
+
+	go func() {
+		defer close(out)
+
+		// One timer is reused for every batch instead of calling time.After
+		timer := c.NewTimer(time.Hour)
+		timer.Stop()
+		defer timer.Stop()
+
+		var batch []T
+		var timeout <-chan time.Time // nil while no batch is pending
+
+		flush := func() {
+			if len(batch) > 0 {
+				out <- batch
+				batch = nil
+			}
+			timer.Stop()
+			timeout = nil
+		}
+
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+
+				batch = append(batch, v)
+				if len(batch) == 1 && b.MaxWait > 0 {
+					// First item of a new batch starts the clock
+					timer.Reset(b.MaxWait)
+					timeout = timer.C
+				}
+				if len(batch) >= size {
+					flush()
+				}
+
+			case <-timeout:
+				flush()
+			}
+		}
+	}()
+
+	return out
+}
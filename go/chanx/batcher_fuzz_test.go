@@ -0,0 +1,42 @@
+package chanx
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// FuzzBatcher feeds random values through a Batcher and checks that no batch
+// is empty or larger than Size, and that the concatenated batches equal the
+// input in order.
+func FuzzBatcher(f *testing.F) {
+	f.Add(uint8(3), false, []byte{1, 2, 3, 4, 5, 6, 7})
+	f.Add(uint8(1), true, []byte{9, 8})
+	f.Add(uint8(0), false, []byte{})
+
+	f.Fuzz(func(t *testing.T, size uint8, timeout bool, values []byte) {
+		b := Batcher[byte]{Size: int(size % 10)}
+		if timeout {
+			b.MaxWait = time.Microsecond
+		}
+
+		in := make(chan byte)
+		go func() {
+			defer close(in)
+			for _, v := range values {
+				in <- v
+			}
+		}()
+
+		var got []byte
+		for batch := range b.Batch(in) {
+			if len(batch) == 0 || len(batch) > max(b.Size, 1) {
+				t.Fatalf("batch of %d items with Size %d", len(batch), b.Size)
+			}
+			got = append(got, batch...)
+		}
+		if !slices.Equal(got, values) {
+			t.Fatalf("batches concatenate to %v, want %v", got, values)
+		}
+	})
+}
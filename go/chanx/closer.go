@@ -0,0 +1,36 @@
+package chanx
+
+import "sync"
+
+// CloseCoordinator closes a channel shared by a fixed number of producers
+// once every one of them has finished. Producers call Done instead of
+// closing the channel themselves, which would panic the others' next send;
+// the coordinator closes it exactly once, after the last Done.
+type CloseCoordinator[T any] struct {
+	wg     sync.WaitGroup
+	closed chan struct{}
+}
+
+// NewCloseCoordinator returns a coordinator that closes out after producers
+// calls to Done. With producers <= 0, out is closed straight away.
+func NewCloseCoordinator[T any](out chan<- T, producers int) *CloseCoordinator[T] {
+	c := &CloseCoordinator[T]{closed: make(chan struct{})}
+	c.wg.Add(max(producers, 0))
+	go func() {
+		c.wg.Wait()
+		close(out)
+		close(c.closed)
+	}()
+	return c
+}
+
+// Done signals that one producer has sent its last value. Calling Done
+// more times than there are producers panics.
+func (c *CloseCoordinator[T]) Done() {
+	c.wg.Done()
+}
+
+// Closed returns a channel that is closed once the output channel has been.
+func (c *CloseCoordinator[T]) Closed() <-chan struct{} {
+	return c.closed
+}
@@ -0,0 +1,98 @@
+package chanx
+
+import (
+	"testing"
+	"time"
+)
+
+// closedWithin reports whether ch is closed within d
+func closedWithin[T any](ch <-chan T, d time.Duration) bool {
+	select {
+	case _, ok := <-ch:
+		return !ok
+	case <-time.After(d):
+		return false
+	}
+}
+
+func TestCloseCoordinator(t *testing.T) {
+	tests := []struct {
+		name       string
+		producers  int
+		dones      int
+		wantClosed bool
+	}{
+		{"zero producers closes at once", 0, 0, true},
+		{"negative producers closes at once", -2, 0, true},
+		{"waits for the last producer", 3, 2, false},
+		{"closes after every producer", 3, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := make(chan int)
+			c := NewCloseCoordinator(out, tt.producers)
+			for range tt.dones {
+				c.Done()
+			}
+
+			wait := time.Second
+			if !tt.wantClosed {
+				wait = 20 * time.Millisecond
+			}
+			if got := closedWithin(out, wait); got != tt.wantClosed {
+				t.Errorf("out closed = %v, want %v", got, tt.wantClosed)
+			}
+			if got := closedWithin(c.Closed(), wait); got != tt.wantClosed {
+				t.Errorf("Closed() closed = %v, want %v", got, tt.wantClosed)
+			}
+			for range tt.producers - tt.dones {
+				c.Done() // Let the coordinator's goroutine finish
+			}
+		})
+	}
+}
+
+// TestCloseCoordinatorManyProducers has many producers send and call Done
+// concurrently. The channel must close exactly once, after the last value;
+// a send on a closed channel or a second close would panic. Run with -race.
+func TestCloseCoordinatorManyProducers(t *testing.T) {
+	const producers, each = 50, 100
+	out := make(chan int, 16)
+	c := NewCloseCoordinator(out, producers)
+
+	for p := range producers {
+		go func() {
+			defer c.Done()
+			for i := range each {
+				out <- p*each + i
+			}
+		}()
+	}
+
+	seen := make(map[int]bool, producers*each)
+	for v := range out {
+		if seen[v] {
+			t.Fatalf("received %d twice", v)
+		}
+		seen[v] = true
+	}
+	if len(seen) != producers*each {
+		t.Errorf("received %d values before the close, want %d", len(seen), producers*each)
+	}
+	if !closedWithin(c.Closed(), time.Second) {
+		t.Error("Closed() was not closed after out was")
+	}
+}
+
+func TestCloseCoordinatorExtraDonePanics(t *testing.T) {
+	c := NewCloseCoordinator(make(chan int), 1)
+	c.Done()
+	<-c.Closed()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("a Done call beyond the producer count did not panic")
+		}
+	}()
+	c.Done()
+}
@@ -0,0 +1,111 @@
+package chanx
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"threads/chaos"
+)
+
+// Instrumented is a channel that counts sends, receives and drops and
+// measures how long senders and receivers spend blocked. Wrapping the links
+// of a pipeline in Instrumented channels shows where time is lost: a link
+// whose senders block feeds a slow stage, and a link whose receivers block
+// follows one.
+//
+// Only time spent waiting is measured; operations that complete immediately
+// add nothing to the blocked totals. Every operation is also a chaos.Point,
+// so stress runs perturb the scheduling around it.
+type Instrumented[T any] struct {
+	ch          chan T
+	sends       atomic.Int64
+	receives    atomic.Int64
+	drops       atomic.Int64
+	sendBlocked atomic.Int64 // Nanoseconds
+	recvBlocked atomic.Int64 // Nanoseconds
+}
+
+// ChanStats is a snapshot of an Instrumented channel's counters.
+type ChanStats struct {
+	Sends, Receives, Drops   int64
+	SendBlocked, RecvBlocked time.Duration
+	Len, Cap                 int
+}
+
+func (s ChanStats) String() string {
+	return fmt.Sprintf("sends=%d receives=%d drops=%d send-blocked=%v recv-blocked=%v len=%d/%d",
+		s.Sends, s.Receives, s.Drops, s.SendBlocked, s.RecvBlocked, s.Len, s.Cap)
+}
+
+// NewInstrumented returns an instrumented channel with the given buffer size.
+func NewInstrumented[T any](size int) *Instrumented[T] {
+	return &Instrumented[T]{ch: make(chan T, max(size, 0))}
+}
+
+// Send sends v, blocking until there is room. Send must not be called after
+// Close.
+func (c *Instrumented[T]) Send(v T) {
+	chaos.Point()
+	select {
+	case c.ch <- v:
+	default:
+		start := time.Now()
+		c.ch <- v
+		c.sendBlocked.Add(int64(time.Since(start)))
+	}
+	c.sends.Add(1)
+}
+
+// TrySend sends v only if it can do so without blocking and reports whether
+// it did. A value that could not be sent is counted as a drop.
+func (c *Instrumented[T]) TrySend(v T) bool {
+	chaos.Point()
+	select {
+	case c.ch <- v:
+		c.sends.Add(1)
+		return true
+	default:
+		c.drops.Add(1)
+		return false
+	}
+}
+
+// Recv receives a value, blocking until one is available. The boolean is
+// false once the channel is closed and drained.
+func (c *Instrumented[T]) Recv() (T, bool) {
+	chaos.Point()
+	var (
+		v  T
+		ok bool
+	)
+	select {
+	case v, ok = <-c.ch:
+	default:
+		start := time.Now()
+		v, ok = <-c.ch
+		c.recvBlocked.Add(int64(time.Since(start)))
+	}
+	if ok {
+		c.receives.Add(1)
+	}
+	return v, ok
+}
+
+// Close closes the channel. Receivers can still drain the remaining values.
+func (c *Instrumented[T]) Close() {
+	close(c.ch)
+}
+
+// Stats returns a snapshot of the channel's counters.
+func (c *Instrumented[T]) Stats() ChanStats {
+	return ChanStats{
+		Sends:       c.sends.Load(),
+		Receives:    c.receives.Load(),
+		Drops:       c.drops.Load(),
+		SendBlocked: time.Duration(c.sendBlocked.Load()),
+		RecvBlocked: time.Duration(c.recvBlocked.Load()),
+		Len:         len(c.ch),
+		Cap:         cap(c.ch),
+	}
+}
@@ -0,0 +1,117 @@
+package chanx
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInstrumentedCounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		sends    int // TrySend calls, before any receive
+		receives int
+		want     ChanStats
+	}{
+		{"unbuffered drops without a receiver", 0, 3, 0, ChanStats{Drops: 3, Cap: 0}},
+		{"buffer fills, then drops", 2, 5, 0, ChanStats{Sends: 2, Drops: 3, Len: 2, Cap: 2}},
+		{"receives drain the buffer", 4, 3, 2, ChanStats{Sends: 3, Receives: 2, Len: 1, Cap: 4}},
+		{"negative size is unbuffered", -1, 1, 0, ChanStats{Drops: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewInstrumented[int](tt.size)
+			for i := range tt.sends {
+				c.TrySend(i)
+			}
+			for range tt.receives {
+				c.Recv()
+			}
+			got := c.Stats()
+			got.SendBlocked, got.RecvBlocked = 0, 0 // Nothing here blocks
+			if got != tt.want {
+				t.Errorf("Stats = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstrumentedBlockedTime(t *testing.T) {
+	const wait = 20 * time.Millisecond
+
+	// A receiver waiting on an empty channel
+	c := NewInstrumented[int](0)
+	go func() {
+		time.Sleep(wait)
+		c.Send(1)
+	}()
+	if v, ok := c.Recv(); !ok || v != 1 {
+		t.Fatalf("Recv = %d, %v; want 1, true", v, ok)
+	}
+	if s := c.Stats(); s.RecvBlocked < wait/2 {
+		t.Errorf("RecvBlocked = %v, want about %v", s.RecvBlocked, wait)
+	}
+
+	// A sender waiting on a full channel
+	c = NewInstrumented[int](1)
+	c.Send(1)
+	go func() {
+		time.Sleep(wait)
+		c.Recv()
+	}()
+	c.Send(2)
+	if s := c.Stats(); s.SendBlocked < wait/2 || s.Sends != 2 {
+		t.Errorf("Stats = %v, want 2 sends and SendBlocked about %v", s, wait)
+	}
+}
+
+func TestInstrumentedClose(t *testing.T) {
+	c := NewInstrumented[string](2)
+	c.Send("a")
+	c.Close()
+
+	if v, ok := c.Recv(); !ok || v != "a" {
+		t.Errorf("Recv after Close = %q, %v; want the buffered value", v, ok)
+	}
+	if _, ok := c.Recv(); ok {
+		t.Error("Recv on a closed, drained channel reported a value")
+	}
+	// The failed receive is not counted
+	if s := c.Stats(); s.Receives != 1 {
+		t.Errorf("Receives = %d, want 1", s.Receives)
+	}
+}
+
+// TestInstrumentedConcurrent sends from several goroutines; run it with -race
+func TestInstrumentedConcurrent(t *testing.T) {
+	const producers, each = 4, 500
+	c := NewInstrumented[int](8)
+
+	var wg sync.WaitGroup
+	for range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range each {
+				c.Send(i)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		c.Close()
+	}()
+
+	received := 0
+	for {
+		if _, ok := c.Recv(); !ok {
+			break
+		}
+		received++
+	}
+	s := c.Stats()
+	if received != producers*each || s.Sends != producers*each || s.Receives != producers*each {
+		t.Errorf("received %d, Stats = %v; want %d of each", received, s, producers*each)
+	}
+}
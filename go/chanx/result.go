@@ -0,0 +1,66 @@
+package chanx
+
+// Result carries either a value or the error that prevented producing it.
+// Sending Results through a pipeline lets a stage report a failure for one
+// item and carry on with the next, instead of stopping the whole stream.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// Ok returns a successful Result holding v.
+func Ok[T any](v T) Result[T] {
+	return Result[T]{Value: v}
+}
+
+// Fail returns a failed Result holding err.
+func Fail[T any](err error) Result[T] {
+	return Result[T]{Err: err}
+}
+
+// Get returns the value and the error, in the usual Go order.
+func (r Result[T]) Get() (T, error) {
+	return r.Value, r.Err
+}
+
+// MapResults applies fn to every successful value received from in and
+// sends the outcome on the returned channel. Failed results are passed on
+// unchanged, so an error from an early stage reaches the end of the
+// pipeline. The returned channel is closed after in is closed.
+func MapResults[T, U any](in <-chan Result[T], fn func(T) (U, error)) <-chan Result[U] {
+	out := make(chan Result[U])
+	go func() {
+		defer close(out)
+		for r := range in {
+			if r.Err != nil {
+				out <- Fail[U](r.Err)
+				continue
+			}
+			v, err := fn(r.Value)
+			out <- Result[U]{v, err}
+		}
+	}()
+	return out
+}
+
+// SplitResults sends the values of successful results on the first channel
+// and the errors of failed ones on the second, preserving their order, and
+// closes both once in is closed. Both channels are unbuffered, so the
+// caller must receive from them concurrently, for example in one select
+// loop; draining one before the other can block the split.
+func SplitResults[T any](in <-chan Result[T]) (<-chan T, <-chan error) {
+	values := make(chan T)
+	errs := make(chan error)
+	go func() {
+		defer close(values)
+		defer close(errs)
+		for r := range in {
+			if r.Err != nil {
+				errs <- r.Err
+			} else {
+				values <- r.Value
+			}
+		}
+	}()
+	return values, errs
+}
@@ -0,0 +1,108 @@
+package chanx
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// send returns a closed channel holding rs
+func send[T any](rs ...Result[T]) <-chan Result[T] {
+	ch := make(chan Result[T], len(rs))
+	for _, r := range rs {
+		ch <- r
+	}
+	close(ch)
+	return ch
+}
+
+func TestResult(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name    string
+		r       Result[int]
+		want    int
+		wantErr error
+	}{
+		{"Ok", Ok(7), 7, nil},
+		{"Fail", Fail[int](boom), 0, boom},
+		{"zero value is a success", Result[int]{}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.r.Get()
+			if v != tt.want || err != tt.wantErr {
+				t.Errorf("Get = %d, %v; want %d, %v", v, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMapResults(t *testing.T) {
+	early := errors.New("early")
+	in := send(Ok("1"), Fail[string](early), Ok("x"), Ok("3"))
+
+	var got []Result[int]
+	for r := range MapResults(in, strconv.Atoi) {
+		got = append(got, r)
+	}
+
+	if len(got) != 4 {
+		t.Fatalf("MapResults sent %d results, want 4", len(got))
+	}
+	if got[0] != Ok(1) || got[3] != Ok(3) {
+		t.Errorf("successful results = %v, %v; want 1 and 3", got[0], got[3])
+	}
+	// An earlier failure is passed on as is, and fn's own error is reported
+	if got[1].Err != early {
+		t.Errorf("result 1 = %v, want the earlier error passed through", got[1])
+	}
+	var numErr *strconv.NumError
+	if !errors.As(got[2].Err, &numErr) {
+		t.Errorf("result 2 = %v, want fn's error", got[2])
+	}
+}
+
+func TestSplitResults(t *testing.T) {
+	e1, e2 := errors.New("e1"), errors.New("e2")
+	tests := []struct {
+		name       string
+		in         []Result[int]
+		wantValues []int
+		wantErrs   []error
+	}{
+		{"empty", nil, nil, nil},
+		{"values only", []Result[int]{Ok(1), Ok(2)}, []int{1, 2}, nil},
+		{"errors only", []Result[int]{Fail[int](e1), Fail[int](e2)}, nil, []error{e1, e2}},
+		{"mixed keeps order", []Result[int]{Ok(1), Fail[int](e1), Ok(2), Fail[int](e2), Ok(3)}, []int{1, 2, 3}, []error{e1, e2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, errs := SplitResults(send(tt.in...))
+
+			// Receive from both at once, as SplitResults requires
+			var gotValues []int
+			var gotErrs []error
+			for values != nil || errs != nil {
+				select {
+				case v, ok := <-values:
+					if !ok {
+						values = nil
+						continue
+					}
+					gotValues = append(gotValues, v)
+				case err, ok := <-errs:
+					if !ok {
+						errs = nil
+						continue
+					}
+					gotErrs = append(gotErrs, err)
+				}
+			}
+			if !slices.Equal(gotValues, tt.wantValues) || !slices.Equal(gotErrs, tt.wantErrs) {
+				t.Errorf("split into %v and %v, want %v and %v", gotValues, gotErrs, tt.wantValues, tt.wantErrs)
+			}
+		})
+	}
+}
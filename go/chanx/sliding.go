@@ -0,0 +1,60 @@
+package chanx
+
+import "sync/atomic"
+
+// SlidingBuffer is a bounded channel that never blocks senders: when the
+// buffer is full, the oldest buffered value is evicted to make room for the
+// new one. Receivers therefore always see the newest Size values.
+//
+// This complements the dropping pattern, which keeps the oldest values and
+// discards new ones when full.
+type SlidingBuffer[T any] struct {
+	ch      chan T
+	evicted atomic.Int64
+}
+
+// NewSlidingBuffer returns a sliding buffer holding up to size values.
+// Sizes below 1 are treated as 1.
+func NewSlidingBuffer[T any](size int) *SlidingBuffer[T] {
+	return &SlidingBuffer[T]{ch: make(chan T, max(size, 1))}
+}
+
+// Send adds v to the buffer, evicting the oldest value if the buffer is full,
+// and reports whether a value was evicted. Send must not be called after Close.
+func (b *SlidingBuffer[T]) Send(v T) (evicted bool) {
+	for {
+		select {
+		case b.ch <- v:
+			return evicted
+		default:
+			// Full: drop the oldest value and try again. A concurrent receiver
+			// may empty the slot first, in which case nothing is evicted.
+			select {
+			case <-b.ch:
+				evicted = true
+				b.evicted.Add(1)
+			default:
+			}
+		}
+	}
+}
+
+// C returns the channel to receive buffered values from, oldest first.
+func (b *SlidingBuffer[T]) C() <-chan T {
+	return b.ch
+}
+
+// Close closes the buffer. Receivers can still drain the remaining values.
+func (b *SlidingBuffer[T]) Close() {
+	close(b.ch)
+}
+
+// Len returns the number of values currently buffered.
+func (b *SlidingBuffer[T]) Len() int {
+	return len(b.ch)
+}
+
+// Evicted returns the total number of values evicted so far.
+func (b *SlidingBuffer[T]) Evicted() int64 {
+	return b.evicted.Load()
+}
@@ -0,0 +1,52 @@
+package chanx
+
+import "testing"
+
+// FuzzSlidingBuffer applies random sends and receives to a SlidingBuffer and
+// checks it against a model that keeps the newest size values. Even bytes
+// send, odd bytes receive.
+func FuzzSlidingBuffer(f *testing.F) {
+	f.Add(uint8(3), []byte{0, 2, 4, 6, 8, 1, 1, 10})
+	f.Add(uint8(1), []byte{0, 0, 1, 1, 2})
+
+	f.Fuzz(func(t *testing.T, size uint8, ops []byte) {
+		n := int(size%16) + 1
+		b := NewSlidingBuffer[byte](n)
+		var model []byte
+		var evictions int64
+
+		for i, op := range ops {
+			if op%2 == 0 {
+				evicted := b.Send(op)
+				model = append(model, op)
+				full := len(model) > n
+				if full {
+					model = model[1:]
+					evictions++
+				}
+				if evicted != full {
+					t.Fatalf("op %d: Send reported evicted = %v, want %v", i, evicted, full)
+				}
+			} else if len(model) > 0 {
+				if got := <-b.C(); got != model[0] {
+					t.Fatalf("op %d: received %d, want %d (oldest kept value)", i, got, model[0])
+				}
+				model = model[1:]
+			}
+
+			if b.Len() != len(model) || b.Len() > n {
+				t.Fatalf("op %d: Len = %d, want %d (cap %d)", i, b.Len(), len(model), n)
+			}
+			if b.Evicted() != evictions {
+				t.Fatalf("op %d: Evicted = %d, want %d", i, b.Evicted(), evictions)
+			}
+		}
+
+		b.Close()
+		for _, want := range model {
+			if got := <-b.C(); got != want {
+				t.Fatalf("drain: received %d, want %d", got, want)
+			}
+		}
+	})
+}
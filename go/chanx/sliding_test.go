@@ -0,0 +1,123 @@
+package chanx
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// drain receives from b until it is closed
+func drain[T any](b *SlidingBuffer[T]) []T {
+	var got []T
+	for v := range b.C() {
+		got = append(got, v)
+	}
+	return got
+}
+
+func TestSlidingBufferEvictionOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		send    []int
+		want    []int
+		evicted int64
+	}{
+		{"under capacity", 3, []int{1, 2}, []int{1, 2}, 0},
+		{"at capacity", 3, []int{1, 2, 3}, []int{1, 2, 3}, 0},
+		{"oldest evicted first", 3, []int{1, 2, 3, 4, 5}, []int{3, 4, 5}, 2},
+		{"size 1 keeps the newest", 1, []int{1, 2, 3}, []int{3}, 2},
+		{"size below 1 is 1", 0, []int{1, 2}, []int{2}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewSlidingBuffer[int](tt.size)
+			for i, v := range tt.send {
+				full := i >= max(tt.size, 1)
+				if evicted := b.Send(v); evicted != full {
+					t.Errorf("Send(%d) evicted = %v, want %v", v, evicted, full)
+				}
+			}
+			if b.Len() != len(tt.want) {
+				t.Errorf("Len = %d, want %d", b.Len(), len(tt.want))
+			}
+			if b.Evicted() != tt.evicted {
+				t.Errorf("Evicted = %d, want %d", b.Evicted(), tt.evicted)
+			}
+			b.Close()
+			if got := drain(b); !slices.Equal(got, tt.want) {
+				t.Errorf("received %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlidingBufferCloseDrain(t *testing.T) {
+	b := NewSlidingBuffer[string](2)
+	b.Send("a")
+	b.Send("b")
+	b.Close()
+
+	// Values buffered before Close are still delivered, then the channel
+	// reports closed
+	if got := drain(b); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("drained %v after Close, want [a b]", got)
+	}
+	if v, ok := <-b.C(); ok {
+		t.Errorf("received %q from a drained, closed buffer", v)
+	}
+	if b.Len() != 0 {
+		t.Errorf("Len after draining = %d, want 0", b.Len())
+	}
+}
+
+func TestSlidingBufferBlockingReceive(t *testing.T) {
+	b := NewSlidingBuffer[int](2)
+	got := make(chan int)
+	go func() {
+		got <- <-b.C()
+	}()
+
+	// The receiver waits on the empty buffer
+	select {
+	case v := <-got:
+		t.Fatalf("received %d from an empty buffer", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	b.Send(42)
+	select {
+	case v := <-got:
+		if v != 42 {
+			t.Errorf("blocked receiver got %d, want 42", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a blocked receiver was not woken by Send")
+	}
+}
+
+func TestSlidingBufferConcurrent(t *testing.T) {
+	const sends = 10000
+	b := NewSlidingBuffer[int](4)
+	done := make(chan []int)
+	go func() {
+		done <- drain(b)
+	}()
+	for i := range sends {
+		b.Send(i)
+	}
+	b.Close()
+
+	// The receiver sees an increasing subsequence ending with the last value,
+	// and every value is either received or evicted
+	got := <-done
+	if !slices.IsSorted(got) {
+		t.Errorf("received values out of order: %v", got)
+	}
+	if len(got) == 0 || got[len(got)-1] != sends-1 {
+		t.Errorf("the last value received was not the newest, %d", sends-1)
+	}
+	if total := int64(len(got)) + b.Evicted(); total != sends {
+		t.Errorf("received %d and evicted %d, want them to add up to %d", len(got), b.Evicted(), sends)
+	}
+}
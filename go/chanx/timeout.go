@@ -0,0 +1,49 @@
+package chanx
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTimeout is returned when a channel operation did not complete in
+	// time.
+	ErrTimeout = errors.New("chanx: timed out")
+	// ErrClosed is returned when receiving from a closed channel.
+	ErrClosed = errors.New("chanx: channel closed")
+)
+
+// RecvTimeout receives a value from ch, waiting at most d. It returns
+// ErrClosed if ch is closed and ErrTimeout if nothing arrived in time. The
+// timer is stopped before RecvTimeout returns, so calling it in a loop does
+// not leave timers behind.
+func RecvTimeout[T any](ch <-chan T, d time.Duration) (T, error) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case v, ok := <-ch:
+		if !ok {
+			return v, ErrClosed
+		}
+		return v, nil
+	case <-t.C:
+		var zero T
+		return zero, ErrTimeout
+	}
+}
+
+// SendTimeout sends v on ch, waiting at most d for a receiver or buffer
+// space, and returns ErrTimeout if the send did not happen. Like a plain
+// send, it panics if ch is closed.
+func SendTimeout[T any](ch chan<- T, v T, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case ch <- v:
+		return nil
+	case <-t.C:
+		return ErrTimeout
+	}
+}
@@ -0,0 +1,55 @@
+package chanx
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecvTimeout(t *testing.T) {
+	ch := make(chan int, 1)
+	ch <- 7
+	if v, err := RecvTimeout(ch, time.Second); v != 7 || err != nil {
+		t.Errorf("RecvTimeout with a value ready = %d, %v; want 7, nil", v, err)
+	}
+
+	if v, err := RecvTimeout(ch, 10*time.Millisecond); v != 0 || !errors.Is(err, ErrTimeout) {
+		t.Errorf("RecvTimeout on an empty channel = %d, %v; want 0, %v", v, err, ErrTimeout)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ch <- 8
+	}()
+	if v, err := RecvTimeout(ch, time.Second); v != 8 || err != nil {
+		t.Errorf("RecvTimeout with a late value = %d, %v; want 8, nil", v, err)
+	}
+
+	close(ch)
+	if _, err := RecvTimeout(ch, time.Second); !errors.Is(err, ErrClosed) {
+		t.Errorf("RecvTimeout on a closed channel = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestSendTimeout(t *testing.T) {
+	ch := make(chan int, 1)
+	if err := SendTimeout(ch, 1, time.Second); err != nil {
+		t.Errorf("SendTimeout with buffer space = %v, want nil", err)
+	}
+
+	if err := SendTimeout(ch, 2, 10*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Errorf("SendTimeout on a full channel = %v, want %v", err, ErrTimeout)
+	}
+	if v := <-ch; v != 1 {
+		t.Errorf("received %d, want 1: the timed-out send must not have happened", v)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-ch
+	}()
+	ch <- 3 // Fill the buffer again; the receiver above frees it
+	if err := SendTimeout(ch, 4, time.Second); err != nil {
+		t.Errorf("SendTimeout with a late receiver = %v, want nil", err)
+	}
+}
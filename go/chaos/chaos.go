@@ -0,0 +1,55 @@
+// Package chaos perturbs goroutine scheduling to shake out ordering bugs.
+//
+// Code calls Point at places where an unlucky interleaving could matter,
+// such as just before a channel operation. Normally Point does nothing. Once
+// chaos is enabled with Set, each Point randomly yields the processor or
+// sleeps for a short random delay, so repeated runs explore many more
+// orderings than the scheduler would naturally produce.
+package chaos
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// Config describes how aggressively Point perturbs scheduling.
+type Config struct {
+	// Probability is the chance, between 0 and 1, that a Point does anything.
+	Probability float64
+	// MaxDelay bounds the random sleep. Half of the perturbations sleep and
+	// the other half only call runtime.Gosched. Zero means Gosched only.
+	MaxDelay time.Duration
+}
+
+// Default is a configuration that perturbs often but keeps runs fast.
+var Default = Config{Probability: 0.3, MaxDelay: time.Millisecond}
+
+var current atomic.Pointer[Config]
+
+// Set enables chaos with the given configuration and returns the previous
+// one. A nil configuration disables chaos.
+func Set(c *Config) (prev *Config) {
+	return current.Swap(c)
+}
+
+// Enabled reports whether chaos is currently enabled.
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// Point is a place where scheduling may be perturbed. It costs a single
+// atomic load while chaos is disabled.
+func Point() {
+	c := current.Load()
+	if c == nil || rand.Float64() >= c.Probability {
+		return
+	}
+
+	if c.MaxDelay > 0 && rand.IntN(2) == 0 {
+		time.Sleep(rand.N(c.MaxDelay))
+		return
+	}
+	runtime.Gosched()
+}
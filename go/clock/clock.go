@@ -0,0 +1,101 @@
+// Package clock lets the examples run against real or virtual time.
+//
+// Examples call Current instead of the time package for sleeps, timeouts and
+// timestamps. By default Current is the real clock. SetCurrent installs a
+// Virtual clock instead, under which sleeps complete without real waiting,
+// timestamps start at a fixed instant and printed durations are exact, so
+// the output of a run can be compared against a golden file.
+//
+// Only code that goes through Current is affected: timers, tickers and
+// context deadlines created with the time and context packages keep using
+// real time. WithTimeout creates a context deadline on a Clock and NewTicker
+// a ticker.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the subset of the time package the examples use.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) *Timer
+}
+
+// Timer is a one-shot timer on a Clock. Unlike a channel from After, it can
+// be stopped, so a timer that is no longer needed does not linger, and
+// reused.
+type Timer struct {
+	// C receives the time once the timer fires.
+	C     <-chan time.Time
+	stop  func() bool
+	reset func(d time.Duration) bool
+}
+
+// Stop prevents the timer from firing. It reports false if the timer had
+// already fired or been stopped.
+func (t *Timer) Stop() bool {
+	return t.stop()
+}
+
+// Reset changes the timer to fire after d, discarding an expiry nobody has
+// received yet. It reports whether the timer was active.
+func (t *Timer) Reset(d time.Duration) bool {
+	return t.reset(d)
+}
+
+// Current is the shared clock used by the examples. It forwards to the clock
+// most recently passed to SetCurrent (Real by default).
+var Current Clock = &redirect
+
+var redirect = switchClock{c: Real()}
+
+// SetCurrent changes the destination of Current and returns the previous one.
+func SetCurrent(c Clock) Clock {
+	redirect.mu.Lock()
+	defer redirect.mu.Unlock()
+
+	prev := redirect.c
+	redirect.c = c
+	return prev
+}
+
+// switchClock is a Clock whose destination can be replaced concurrently with
+// calls. The lock is not held while sleeping.
+type switchClock struct {
+	mu sync.Mutex
+	c  Clock
+}
+
+func (s *switchClock) get() Clock {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.c
+}
+
+func (s *switchClock) Now() time.Time                         { return s.get().Now() }
+func (s *switchClock) Since(t time.Time) time.Duration        { return s.get().Since(t) }
+func (s *switchClock) Sleep(d time.Duration)                  { s.get().Sleep(d) }
+func (s *switchClock) After(d time.Duration) <-chan time.Time { return s.get().After(d) }
+func (s *switchClock) NewTimer(d time.Duration) *Timer        { return s.get().NewTimer(d) }
+
+// Real returns the clock backed by the time package.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) NewTimer(d time.Duration) *Timer {
+	t := time.NewTimer(d)
+	return &Timer{C: t.C, stop: t.Stop, reset: t.Reset}
+}
@@ -0,0 +1,57 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout is like context.WithTimeout, but the timeout is measured on c,
+// so under a Virtual clock it expires in virtual time. The context's Err is
+// context.DeadlineExceeded once it has expired. Contexts derived from it
+// with the context package report context.Canceled as their Err, with
+// context.DeadlineExceeded as their cause.
+func WithTimeout(parent context.Context, c Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	return WithTimeoutCause(parent, c, d, nil)
+}
+
+// WithTimeoutCause is like WithTimeout but also sets the cause of the
+// context when the timeout expires. A nil cause means
+// context.DeadlineExceeded.
+func WithTimeoutCause(parent context.Context, c Clock, d time.Duration, cause error) (context.Context, context.CancelFunc) {
+	if cause == nil {
+		cause = context.DeadlineExceeded
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+	t := &timeoutCtx{Context: ctx, deadline: c.Now().Add(d), cause: cause}
+	timer := c.NewTimer(d)
+	go func() {
+		select {
+		case <-timer.C:
+			cancel(cause)
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}()
+	return t, func() { cancel(context.Canceled) }
+}
+
+// timeoutCtx reports its deadline in the time of its clock and
+// DeadlineExceeded once it has expired
+type timeoutCtx struct {
+	context.Context
+	deadline time.Time
+	cause    error
+}
+
+func (t *timeoutCtx) Deadline() (time.Time, bool) {
+	return t.deadline, true
+}
+
+func (t *timeoutCtx) Err() error {
+	err := t.Context.Err()
+	if err != nil && context.Cause(t.Context) == t.cause {
+		return context.DeadlineExceeded
+	}
+	return err
+}
@@ -0,0 +1,48 @@
+package clock
+
+import "time"
+
+// Ticker delivers ticks at intervals measured on a Clock. Like a
+// time.Ticker, it drops ticks for a slow receiver rather than queueing them.
+type Ticker struct {
+	// C receives the time of each tick.
+	C    <-chan time.Time
+	done chan struct{}
+}
+
+// NewTicker returns a ticker that ticks every d on c. Call Stop to release
+// it. It panics if d is not positive.
+func NewTicker(c Clock, d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	ch := make(chan time.Time, 1)
+	t := &Ticker{C: ch, done: make(chan struct{})}
+	go func() {
+		timer := c.NewTimer(d)
+		for {
+			select {
+			case now := <-timer.C:
+				// Arm the next tick before delivering this one, so under a
+				// Virtual clock it is queued ahead of whatever the receiver
+				// does in response
+				timer = c.NewTimer(d)
+				select {
+				case ch <- now:
+				default:
+				}
+			case <-t.done:
+				timer.Stop()
+				return
+			}
+		}
+	}()
+	return t
+}
+
+// Stop turns off the ticker. A tick that was already due may still arrive
+// on C.
+func (t *Ticker) Stop() {
+	close(t.done)
+}
@@ -0,0 +1,179 @@
+package clock
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// quietPeriod is how long a Virtual clock waits, in real time, without any
+// new sleeper before it advances to the next deadline. It gives goroutines
+// woken by the previous deadline time to run and go back to sleep.
+const quietPeriod = 2 * time.Millisecond
+
+// yields is how many times a Virtual clock yields the processor after a
+// quiet period before it advances
+const yields = 3
+
+// Virtual is a clock whose time only moves when goroutines sleep on it.
+//
+// Once no new sleeps have been registered for a short quiet period, the
+// clock jumps to the earliest pending deadline and wakes that one sleeper.
+// Sleepers are woken one at a time in deadline order, ties in the order they
+// started sleeping, which makes programs that coordinate through sleeps run
+// in the same order every time. Goroutines that are busy for longer than the
+// quiet period without sleeping can still race with the sleepers.
+type Virtual struct {
+	mu           sync.Mutex
+	now          time.Time
+	sleepers     []sleeper
+	seq          int
+	lastActivity time.Time // Real time of the last registration or wakeup
+	activity     int       // Number of registrations and wakeups so far
+	advancing    bool      // Whether the advance goroutine is running
+}
+
+type sleeper struct {
+	deadline time.Time
+	seq      int
+	wake     chan time.Time
+}
+
+// NewVirtual returns a virtual clock that starts at the given time.
+func NewVirtual(start time.Time) *Virtual {
+	return &Virtual{now: start}
+}
+
+// Now returns the current virtual time.
+func (v *Virtual) Now() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now
+}
+
+// Since returns the virtual time elapsed since t.
+func (v *Virtual) Since(t time.Time) time.Duration {
+	return v.Now().Sub(t)
+}
+
+// Sleep blocks until the virtual time has advanced by d.
+func (v *Virtual) Sleep(d time.Duration) {
+	<-v.After(d)
+}
+
+// After returns a channel that receives the virtual time once it has
+// advanced by d.
+func (v *Virtual) After(d time.Duration) <-chan time.Time {
+	wake := make(chan time.Time, 1)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.add(wake, d)
+	return wake
+}
+
+// NewTimer returns a timer that fires once the virtual time has advanced by
+// d. A stopped timer no longer counts as a sleeper.
+func (v *Virtual) NewTimer(d time.Duration) *Timer {
+	wake := make(chan time.Time, 1)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	seq := v.add(wake, d)
+
+	return &Timer{
+		C: wake,
+		stop: func() bool {
+			v.mu.Lock()
+			defer v.mu.Unlock()
+			return v.remove(seq)
+		},
+		reset: func(d time.Duration) bool {
+			v.mu.Lock()
+			defer v.mu.Unlock()
+			active := v.remove(seq)
+			select {
+			case <-wake: // Discard a stale expiry, as time.Timer does
+			default:
+			}
+			seq = v.add(wake, d)
+			return active
+		},
+	}
+}
+
+// remove drops the sleeper with the given sequence number, reporting
+// whether it was still waiting. The caller holds v.mu.
+func (v *Virtual) remove(seq int) bool {
+	i := slices.IndexFunc(v.sleepers, func(s sleeper) bool { return s.seq == seq })
+	if i < 0 {
+		return false
+	}
+	v.sleepers = slices.Delete(v.sleepers, i, i+1)
+	return true
+}
+
+// add registers wake to receive the time after d and returns its sequence
+// number, or 0 if d has already passed. The caller holds v.mu.
+func (v *Virtual) add(wake chan time.Time, d time.Duration) int {
+	if d <= 0 {
+		wake <- v.now
+		return 0
+	}
+
+	v.seq++
+	s := sleeper{deadline: v.now.Add(d), seq: v.seq, wake: wake}
+	i, _ := slices.BinarySearchFunc(v.sleepers, s, func(a, b sleeper) int {
+		if c := a.deadline.Compare(b.deadline); c != 0 {
+			return c
+		}
+		return a.seq - b.seq
+	})
+	v.sleepers = slices.Insert(v.sleepers, i, s)
+	v.lastActivity = time.Now()
+	v.activity++
+
+	if !v.advancing {
+		v.advancing = true
+		go v.advance()
+	}
+	return s.seq
+}
+
+// advance wakes sleepers one at a time until none are left. It exits as
+// soon as the last one is woken, so an idle clock has no goroutine.
+//
+// After a quiet period it also yields to every runnable goroutine before
+// advancing, so a goroutine that was woken but not yet scheduled, for
+// instance because the whole process was descheduled, gets to go back to
+// sleep first.
+func (v *Virtual) advance() {
+	for {
+		time.Sleep(quietPeriod)
+
+		v.mu.Lock()
+		quiet := time.Since(v.lastActivity) >= quietPeriod
+		seen := v.activity
+		v.mu.Unlock()
+		if quiet {
+			for range yields {
+				runtime.Gosched()
+			}
+		}
+
+		v.mu.Lock()
+		if len(v.sleepers) > 0 && quiet && v.activity == seen {
+			s := v.sleepers[0]
+			v.sleepers = v.sleepers[1:]
+			v.now = s.deadline
+			v.lastActivity = time.Now()
+			v.activity++
+			s.wake <- v.now
+		}
+		if len(v.sleepers) == 0 {
+			v.advancing = false
+			v.mu.Unlock()
+			return
+		}
+		v.mu.Unlock()
+	}
+}
@@ -0,0 +1,193 @@
+package clock
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestVirtualSleep(t *testing.T) {
+	v := NewVirtual(epoch)
+	start := time.Now()
+	v.Sleep(time.Hour)
+
+	if got := v.Since(epoch); got != time.Hour {
+		t.Errorf("Since after Sleep(1h) = %v, want exactly 1h", got)
+	}
+	if real := time.Since(start); real > time.Second {
+		t.Errorf("Sleep(1h) took %v of real time, want it to complete at once", real)
+	}
+}
+
+func TestVirtualWakeOrder(t *testing.T) {
+	v := NewVirtual(epoch)
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	sleep := func(name string, d time.Duration) {
+		defer wg.Done()
+		v.Sleep(d)
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+
+	// Registered in this order; woken by deadline, ties in registration order
+	for _, s := range []struct {
+		name string
+		d    time.Duration
+	}{{"c", 30 * time.Millisecond}, {"a", 10 * time.Millisecond}, {"b1", 20 * time.Millisecond}, {"b2", 20 * time.Millisecond}} {
+		wg.Add(1)
+		go sleep(s.name, s.d)
+		time.Sleep(time.Millisecond / 4) // Let each register before the next
+	}
+	wg.Wait()
+
+	if want := []string{"a", "b1", "b2", "c"}; !slices.Equal(order, want) {
+		t.Errorf("woken in order %v, want %v", order, want)
+	}
+	if got := v.Since(epoch); got != 30*time.Millisecond {
+		t.Errorf("virtual time after the last wake-up = %v, want 30ms", got)
+	}
+}
+
+func TestVirtualAfter(t *testing.T) {
+	v := NewVirtual(epoch)
+	select {
+	case now := <-v.After(0):
+		if !now.Equal(epoch) {
+			t.Errorf("After(0) delivered %v, want the current time %v", now, epoch)
+		}
+	default:
+		t.Fatal("After(0) was not ready at once")
+	}
+
+	if now := <-v.After(5 * time.Second); !now.Equal(epoch.Add(5 * time.Second)) {
+		t.Errorf("After(5s) delivered %v, want %v", now, epoch.Add(5*time.Second))
+	}
+	if now := v.Now(); !now.Equal(epoch.Add(5 * time.Second)) {
+		t.Errorf("Now = %v, want %v", now, epoch.Add(5*time.Second))
+	}
+}
+
+func TestSetCurrent(t *testing.T) {
+	v := NewVirtual(epoch)
+	prev := SetCurrent(v)
+	defer SetCurrent(prev)
+
+	if now := Current.Now(); !now.Equal(epoch) {
+		t.Errorf("Current.Now = %v, want the virtual clock's %v", now, epoch)
+	}
+	Current.Sleep(time.Minute)
+	if got := v.Since(epoch); got != time.Minute {
+		t.Errorf("virtual time after Current.Sleep(1m) = %v, want 1m", got)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	v := NewVirtual(epoch)
+	ctx, cancel := WithTimeout(context.Background(), v, time.Hour)
+	defer cancel()
+
+	if d, ok := ctx.Deadline(); !ok || !d.Equal(epoch.Add(time.Hour)) {
+		t.Errorf("Deadline = %v, %v; want %v, true", d, ok, epoch.Add(time.Hour))
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("a 1h virtual timeout did not expire")
+	}
+	if err := ctx.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel = WithTimeout(context.Background(), v, time.Hour)
+	cancel()
+	if err := ctx.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Err after cancel = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestVirtualTimerStop(t *testing.T) {
+	v := NewVirtual(epoch)
+	stopped := v.NewTimer(time.Hour)
+	if !stopped.Stop() {
+		t.Error("Stop on a pending timer = false, want true")
+	}
+	if stopped.Stop() {
+		t.Error("a second Stop = true, want false")
+	}
+
+	// The stopped timer must not hold back or move the clock
+	fired := v.NewTimer(time.Minute)
+	<-fired.C
+	if got := v.Since(epoch); got != time.Minute {
+		t.Errorf("virtual time after a 1m timer = %v, want 1m", got)
+	}
+	if fired.Stop() {
+		t.Error("Stop on a fired timer = true, want false")
+	}
+	select {
+	case <-stopped.C:
+		t.Error("a stopped timer fired")
+	default:
+	}
+}
+
+func TestTicker(t *testing.T) {
+	v := NewVirtual(epoch)
+	ticker := NewTicker(v, 10*time.Millisecond)
+	for i := 1; i <= 3; i++ {
+		if now := <-ticker.C; !now.Equal(epoch.Add(time.Duration(i) * 10 * time.Millisecond)) {
+			t.Errorf("tick %d at %v, want %v", i, now.Sub(epoch), time.Duration(i)*10*time.Millisecond)
+		}
+	}
+	ticker.Stop()
+
+	// A stopped ticker leaves no sleeper behind, so the clock stays put
+	time.Sleep(10 * quietPeriod)
+	if got := v.Since(epoch); got > 40*time.Millisecond {
+		t.Errorf("virtual time after Stop = %v, want the ticker to have stopped re-arming", got)
+	}
+}
+
+func TestVirtualTimerReset(t *testing.T) {
+	v := NewVirtual(epoch)
+	timer := v.NewTimer(time.Hour)
+	if !timer.Reset(time.Minute) {
+		t.Error("Reset on a pending timer = false, want true")
+	}
+	if now := <-timer.C; !now.Equal(epoch.Add(time.Minute)) {
+		t.Errorf("reset timer fired at %v, want %v", now.Sub(epoch), time.Minute)
+	}
+
+	// An expiry nobody received is discarded by Reset
+	timer.Reset(time.Second)
+	v.Sleep(2 * time.Second)
+	if timer.Reset(time.Second) {
+		t.Error("Reset on a fired timer = true, want false")
+	}
+	if now := <-timer.C; !now.Equal(epoch.Add(time.Minute + 3*time.Second)) {
+		t.Errorf("timer fired at %v, want the reset expiry %v", now.Sub(epoch), time.Minute+3*time.Second)
+	}
+}
+
+func TestWithTimeoutCause(t *testing.T) {
+	errSlow := errors.New("too slow")
+	v := NewVirtual(epoch)
+	ctx, cancel := WithTimeoutCause(context.Background(), v, time.Minute, errSlow)
+	defer cancel()
+
+	<-ctx.Done()
+	if err := ctx.Err(); err != context.DeadlineExceeded {
+		t.Errorf("Err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if cause := context.Cause(ctx); cause != errSlow {
+		t.Errorf("Cause = %v, want %v", cause, errSlow)
+	}
+}
@@ -0,0 +1,260 @@
+/**
+ * The compare command.
+ *
+ * `compare [NAME]` runs alternative solutions to the same task back to back
+ * and prints their timings side by side, so that the trade-offs between
+ * approaches show up next to each other instead of in separate demos. Without
+ * a name every comparison is run.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"threads/stats"
+)
+
+// compareRuns is how many times each solution is timed
+const compareRuns = 5
+
+// comparison is a task with several solutions
+type comparison struct {
+	name, title, task string
+	solutions         []solution
+}
+
+// solution solves a comparison's task and returns a result that must be the
+// same for every solution, so that a faster but wrong one stands out
+type solution struct {
+	name, tradeOff string
+	run            func() int64
+}
+
+var comparisons = []comparison{
+	{
+		name:  "counter",
+		title: "Shared counter: mutex vs atomic vs channel owner",
+		task:  fmt.Sprintf("8 goroutines increment one counter %d times in total", benchIncrements),
+		solutions: []solution{
+			counterSolution("mutex", "simple and general; every increment takes the lock"),
+			counterSolution("atomic", "cheapest for a single word, but only for a single word"),
+			counterSolution("channel owner", "one goroutine owns the state; a channel send per increment costs the most"),
+		},
+	},
+	{
+		name:  "pipeline",
+		title: "Cancelling a pipeline: done channel vs context",
+		task:  "take 100000 squares from a generator -> square pipeline, then stop both stages",
+		solutions: []solution{
+			{"done channel", "no allocation or locking beyond the channel; carries no reason or deadline",
+				func() int64 {
+					done, cancel := doneChannel()
+					return cancelPipeline(100000, done, cancel)
+				}},
+			{"context", "the standard way, composable with deadlines, causes and values, for a small setup cost",
+				func() int64 {
+					ctx, cancel := context.WithCancel(context.Background())
+					return cancelPipeline(100000, ctx.Done, cancel)
+				}},
+		},
+	},
+	{
+		name:  "fan-in",
+		title: "Merging channels: goroutine per input vs reflect.Select",
+		task:  "merge 8 channels of 20000 values each into one",
+		solutions: []solution{
+			{"goroutine per input", "the idiomatic merge; scales to any number of inputs with one goroutine each",
+				func() int64 { return sumMerged(8, 20000, mergeWithGoroutines) }},
+			{"reflect.Select", "a single goroutine and a dynamic set of cases, at the cost of reflection on every receive",
+				func() int64 { return sumMerged(8, 20000, mergeWithReflect) }},
+		},
+	},
+}
+
+// runCompare runs the named comparison, or all of them if name is empty,
+// and returns the exit code
+func runCompare(name string) int {
+	ran := false
+	for _, c := range comparisons {
+		if name == "" || name == c.name {
+			runComparison(c)
+			ran = true
+		}
+	}
+	if !ran {
+		var names []string
+		for _, c := range comparisons {
+			names = append(names, c.name)
+		}
+		fmt.Fprintf(os.Stderr, "unknown comparison %q (available: %s)\n", name, strings.Join(names, ", "))
+		return 2
+	}
+	return 0
+}
+
+// runComparison times every solution of c and prints a table of the results
+func runComparison(c comparison) {
+	fmt.Printf("\n%s\n", c.title)
+	fmt.Printf("Task: %s\n", c.task)
+	fmt.Printf("GOMAXPROCS=%d, %d runs each\n\n", runtime.GOMAXPROCS(0), compareRuns)
+	fmt.Printf("%-22s %12s %12s %12s %12s\n", "solution", "result", "mean", "min", "max")
+
+	var fastest time.Duration
+	means := make([]time.Duration, len(c.solutions))
+	for i, s := range c.solutions {
+		var timings []time.Duration
+		var result int64
+		for range compareRuns {
+			start := time.Now()
+			result = s.run()
+			timings = append(timings, time.Since(start))
+		}
+
+		sum := stats.Summarize(timings)
+		means[i] = sum.Mean
+		if fastest == 0 || sum.Mean < fastest {
+			fastest = sum.Mean
+		}
+		fmt.Printf("%-22s %12d %12v %12v %12v\n", s.name, result,
+			sum.Mean.Round(time.Microsecond), sum.Min.Round(time.Microsecond), sum.Max.Round(time.Microsecond))
+	}
+
+	fmt.Println("\nTrade-offs:")
+	for i, s := range c.solutions {
+		fmt.Printf("  %-22s %4.1fx  %s\n", s.name, float64(means[i])/float64(fastest), s.tradeOff)
+	}
+}
+
+// counterSolution wraps the bench command's counter strategy of that name
+func counterSolution(name, tradeOff string) solution {
+	for _, s := range counterStrategies {
+		if s.name == name {
+			return solution{name, tradeOff, func() int64 {
+				return s.run(8, benchIncrements/8)
+			}}
+		}
+	}
+	panic("unknown counter strategy " + name)
+}
+
+// doneChannel returns a done function and the cancel function closing it,
+// shaped like ctx.Done and a context.CancelFunc
+func doneChannel() (func() <-chan struct{}, func()) {
+	done := make(chan struct{})
+	return func() <-chan struct{} { return done }, func() { close(done) }
+}
+
+// cancelPipeline takes n values from a generator -> square pipeline whose
+// stages stop when done is closed, then cancels it and waits for both stages
+// to exit. It returns the sum of the values taken.
+func cancelPipeline(n int, done func() <-chan struct{}, cancel func()) int64 {
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	nums := make(chan int64)
+	go func() {
+		defer wg.Done()
+		defer close(nums)
+		for i := int64(1); ; i++ {
+			select {
+			case nums <- i:
+			case <-done():
+				return
+			}
+		}
+	}()
+
+	squares := make(chan int64)
+	go func() {
+		defer wg.Done()
+		defer close(squares)
+		for v := range nums {
+			select {
+			case squares <- v * v:
+			case <-done():
+				return
+			}
+		}
+	}()
+
+	var sum int64
+	for range n {
+		sum += <-squares
+	}
+	cancel()
+	wg.Wait()
+	return sum
+}
+
+// sumMerged sends count values on each of n channels, merges them with merge
+// and returns the sum of everything received
+func sumMerged(n, count int, merge func(...<-chan int64) <-chan int64) int64 {
+	inputs := make([]<-chan int64, n)
+	for i := range inputs {
+		ch := make(chan int64)
+		inputs[i] = ch
+		go func() {
+			defer close(ch)
+			for v := range int64(count) {
+				ch <- v
+			}
+		}()
+	}
+
+	var sum int64
+	for v := range merge(inputs...) {
+		sum += v
+	}
+	return sum
+}
+
+// mergeWithGoroutines forwards every input from its own goroutine and closes
+// the output once all of them are done
+func mergeWithGoroutines(inputs ...<-chan int64) <-chan int64 {
+	out := make(chan int64)
+	var wg sync.WaitGroup
+	wg.Add(len(inputs))
+	for _, in := range inputs {
+		go func() {
+			defer wg.Done()
+			for v := range in {
+				out <- v
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// mergeWithReflect receives from all inputs in one goroutine with
+// reflect.Select, dropping each input's case once it is closed
+func mergeWithReflect(inputs ...<-chan int64) <-chan int64 {
+	out := make(chan int64)
+	go func() {
+		defer close(out)
+		cases := make([]reflect.SelectCase, len(inputs))
+		for i, in := range inputs {
+			cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(in)}
+		}
+		for len(cases) > 0 {
+			i, v, ok := reflect.Select(cases)
+			if !ok {
+				cases = append(cases[:i], cases[i+1:]...)
+				continue
+			}
+			out <- v.Int()
+		}
+	}()
+	return out
+}
@@ -0,0 +1,52 @@
+// Package console holds the writer that every example prints to and the
+// stepper that pauses examples at annotated checkpoints.
+//
+// Examples write to Out instead of os.Stdout so that the same demo code can
+// be driven from the CLI, embedded in other programs or captured in tests.
+// The destination can be swapped at any time with SetOutput; writes are
+// serialized, so goroutines inside a demo may print concurrently. Likewise,
+// examples call Steps.Checkpoint, which does nothing unless a Stepper has
+// been installed with SetStepper.
+package console
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"threads/chaos"
+)
+
+// Out is the shared writer used by the examples. It forwards to the
+// destination most recently passed to SetOutput (os.Stdout by default).
+var Out io.Writer = &redirect
+
+var redirect = switchWriter{w: os.Stdout}
+
+// SetOutput changes the destination of Out and returns the previous one.
+func SetOutput(w io.Writer) io.Writer {
+	redirect.mu.Lock()
+	defer redirect.mu.Unlock()
+
+	prev := redirect.w
+	redirect.w = w
+	return prev
+}
+
+// switchWriter is an io.Writer whose destination can be replaced concurrently
+// with writes.
+type switchWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *switchWriter) Write(p []byte) (int, error) {
+	// Every print in an example is a point where stress runs may perturb
+	// scheduling; outside the lock so other goroutines are not held up.
+	chaos.Point()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.w.Write(p)
+}
@@ -0,0 +1,195 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// Log is the shared structured logger used by the examples. Its records go
+// to the handler most recently passed to SetHandler; by default each record
+// is printed to Out as its message followed by its attributes.
+var Log = slog.New(&redirectHandler{sw: &handlerSwitch})
+
+var handlerSwitch = switchHandler{h: NewPlainHandler(Out, nil)}
+
+// SetHandler changes the handler behind Log and returns the previous one. A
+// nil handler restores the default plain handler.
+func SetHandler(h slog.Handler) slog.Handler {
+	if h == nil {
+		h = NewPlainHandler(Out, nil)
+	}
+
+	handlerSwitch.mu.Lock()
+	defer handlerSwitch.mu.Unlock()
+
+	prev := handlerSwitch.h
+	handlerSwitch.h = h
+	return prev
+}
+
+type switchHandler struct {
+	mu sync.Mutex
+	h  slog.Handler
+}
+
+func (s *switchHandler) current() slog.Handler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.h
+}
+
+// redirectHandler forwards to the current handler of sw. Loggers derived
+// with With or WithGroup keep forwarding after SetHandler: their attributes
+// and groups are replayed onto whichever handler is current.
+type redirectHandler struct {
+	sw     *switchHandler
+	derive func(slog.Handler) slog.Handler // nil for Log itself
+}
+
+func (r *redirectHandler) handler() slog.Handler {
+	h := r.sw.current()
+	if r.derive != nil {
+		h = r.derive(h)
+	}
+	return h
+}
+
+func (r *redirectHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return r.handler().Enabled(ctx, level)
+}
+
+func (r *redirectHandler) Handle(ctx context.Context, rec slog.Record) error {
+	return r.handler().Handle(ctx, rec)
+}
+
+func (r *redirectHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return r.with(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+func (r *redirectHandler) WithGroup(name string) slog.Handler {
+	return r.with(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+func (r *redirectHandler) with(f func(slog.Handler) slog.Handler) slog.Handler {
+	parent := r.derive
+	return &redirectHandler{sw: r.sw, derive: func(h slog.Handler) slog.Handler {
+		if parent != nil {
+			h = parent(h)
+		}
+		return f(h)
+	}}
+}
+
+// NewPlainHandler returns a handler that writes each record to w as a single
+// line holding its message and then its attributes as key=value pairs,
+// without a timestamp or level. It is meant for reading demo output in a
+// terminal; use slog.NewTextHandler or slog.NewJSONHandler for tooling. A
+// nil opts logs at slog.LevelInfo and above.
+func NewPlainHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	h := &plainHandler{w: w, level: slog.LevelInfo}
+	if opts != nil && opts.Level != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+type plainHandler struct {
+	w      io.Writer
+	level  slog.Leveler
+	prefix string // Group prefix for attribute keys, e.g. "req."
+	attrs  []byte // Preformatted attributes from WithAttrs
+}
+
+func (h *plainHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *plainHandler) Handle(_ context.Context, rec slog.Record) error {
+	var buf bytes.Buffer
+	buf.WriteString(rec.Message)
+	buf.Write(h.attrs)
+	rec.Attrs(func(a slog.Attr) bool {
+		appendAttr(&buf, h.prefix, a)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	// One Write per record, so concurrent records never interleave
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+func (h *plainHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	var buf bytes.Buffer
+	buf.Write(h.attrs)
+	for _, a := range attrs {
+		appendAttr(&buf, h.prefix, a)
+	}
+	h2.attrs = buf.Bytes()
+	return &h2
+}
+
+func (h *plainHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+func appendAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendAttr(buf, prefix, ga)
+		}
+		return
+	}
+	fmt.Fprintf(buf, " %s%s=%v", prefix, a.Key, a.Value)
+}
+
+// WithGoroutine wraps h so that every record carries a "goroutine" attribute
+// holding the ID of the goroutine that logged it.
+func WithGoroutine(h slog.Handler) slog.Handler {
+	return goroutineHandler{h}
+}
+
+type goroutineHandler struct{ slog.Handler }
+
+func (g goroutineHandler) Handle(ctx context.Context, rec slog.Record) error {
+	rec.AddAttrs(slog.Uint64("goroutine", GoroutineID()))
+	return g.Handler.Handle(ctx, rec)
+}
+
+func (g goroutineHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return goroutineHandler{g.Handler.WithAttrs(attrs)}
+}
+
+func (g goroutineHandler) WithGroup(name string) slog.Handler {
+	return goroutineHandler{g.Handler.WithGroup(name)}
+}
+
+// GoroutineID returns the ID of the calling goroutine, parsed from the header
+// of its stack trace ("goroutine 42 [running]:"). Go deliberately does not
+// expose goroutine IDs; they are only used here to label output.
+func GoroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	field := bytes.Fields(buf[:n])[1]
+	id, _ := strconv.ParseUint(string(field), 10, 64)
+	return id
+}
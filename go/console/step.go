@@ -0,0 +1,114 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Stepper pauses an example at an annotated checkpoint so that a learner can
+// read what just happened before the example continues.
+type Stepper interface {
+	// Checkpoint is called by an example after a step worth explaining.
+	Checkpoint(explanation string)
+}
+
+// Steps is the shared Stepper used by the examples. It forwards to the
+// stepper most recently passed to SetStepper; by default checkpoints do
+// nothing.
+var Steps Stepper = &stepRedirect
+
+var stepRedirect = switchStepper{s: noStepper{}}
+
+// SetStepper changes the destination of Steps and returns the previous one.
+// A nil Stepper turns checkpoints off.
+func SetStepper(s Stepper) Stepper {
+	if s == nil {
+		s = noStepper{}
+	}
+
+	stepRedirect.mu.Lock()
+	defer stepRedirect.mu.Unlock()
+
+	prev := stepRedirect.s
+	stepRedirect.s = s
+	return prev
+}
+
+// switchStepper is a Stepper whose destination can be replaced concurrently
+// with checkpoints. The lock is not held while a checkpoint waits.
+type switchStepper struct {
+	mu sync.Mutex
+	s  Stepper
+}
+
+func (s *switchStepper) Checkpoint(explanation string) {
+	s.mu.Lock()
+	dest := s.s
+	s.mu.Unlock()
+
+	dest.Checkpoint(explanation)
+}
+
+type noStepper struct{}
+
+func (noStepper) Checkpoint(string) {}
+
+// NewPromptStepper returns a Stepper that prints every explanation to w and
+// waits for a line of input from r. Checkpoints reached by several goroutines
+// at once are shown one after another. Once r is exhausted the stepper keeps
+// printing explanations but no longer waits.
+func NewPromptStepper(r io.Reader, w io.Writer) Stepper {
+	return &promptStepper{r: r, w: w}
+}
+
+type promptStepper struct {
+	mu  sync.Mutex
+	r   io.Reader
+	w   io.Writer
+	n   int
+	eof bool
+}
+
+func (p *promptStepper) Checkpoint(explanation string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.n++
+	fmt.Fprintf(p.w, "\n  [step %d] %s\n", p.n, explanation)
+	if p.eof {
+		return
+	}
+
+	fmt.Fprint(p.w, "  Press Enter to continue...")
+	p.eof = !skipLine(p.r)
+}
+
+// skipLine reads up to and including the next newline. It reports false if
+// the reader is exhausted.
+func skipLine(r io.Reader) bool {
+	_, err := ReadLine(r)
+	return err == nil
+}
+
+// ReadLine reads up to and including the next newline one byte at a time, so
+// that nothing beyond the line is consumed from a reader shared with other
+// code, such as os.Stdin. The line is returned without its line ending. At
+// the end of input ReadLine returns any partial line with io.EOF.
+func ReadLine(r io.Reader) (string, error) {
+	var line []byte
+	var b [1]byte
+	for {
+		n, err := r.Read(b[:])
+		if n == 1 {
+			if b[0] == '\n' {
+				return strings.TrimSuffix(string(line), "\r"), nil
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			return string(line), err
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// crashSubcommand is the hidden subcommand that the safe-goroutines example
+// runs this program with, to show an unrecovered panic killing a process
+const crashSubcommand = "crash-in-goroutine"
+
+// crashCommand returns the command that re-runs this program as the crash
+// child
+func crashCommand() *exec.Cmd {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = os.Args[0]
+	}
+	return exec.Command(exe, crashSubcommand)
+}
+
+// runCrash panics in a plain goroutine and blocks until the panic ends the
+// process with status 2
+func runCrash() {
+	go func() {
+		panic("unrecovered panic in a plain goroutine")
+	}()
+	select {}
+}
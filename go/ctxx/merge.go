@@ -0,0 +1,36 @@
+package ctxx
+
+import (
+	"context"
+	"errors"
+)
+
+// MergeContexts returns a context that is done as soon as either a or b is,
+// such as a request's context combined with a server's shutdown context.
+//
+// context.Cause on the merged context returns the cause of whichever parent
+// finished first. The merged deadline is the earlier of the two; when it is
+// b's deadline that passes, the merged context's error and cause are
+// context.DeadlineExceeded. Values are looked up in a only. The returned
+// CancelFunc releases the resources tied to b and must be called, as with
+// context.WithCancel.
+func MergeContexts(a, b context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancelCause := context.WithCancelCause(a)
+	cancelDeadline := func() {}
+	d, hasDeadline := b.Deadline()
+	if hasDeadline {
+		ctx, cancelDeadline = context.WithDeadline(ctx, d)
+	}
+	stop := context.AfterFunc(b, func() {
+		if hasDeadline && errors.Is(b.Err(), context.DeadlineExceeded) {
+			return // The merged context's own timer is due too; let it report the deadline
+		}
+		cancelCause(context.Cause(b))
+	})
+
+	return ctx, func() {
+		stop()
+		cancelCause(context.Canceled)
+		cancelDeadline()
+	}
+}
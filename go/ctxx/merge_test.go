@@ -0,0 +1,83 @@
+package ctxx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	errShutdown   = errors.New("server shutting down")
+	errClientGone = errors.New("client went away")
+)
+
+func TestMergeContextsCause(t *testing.T) {
+	for _, first := range []string{"a", "b"} {
+		a, cancelA := context.WithCancelCause(context.Background())
+		b, cancelB := context.WithCancelCause(context.Background())
+		ctx, cancel := MergeContexts(a, b)
+
+		want := errClientGone
+		if first == "a" {
+			cancelA(errClientGone)
+		} else {
+			want = errShutdown
+			cancelB(errShutdown)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatalf("%s cancelled: merged context not done", first)
+		}
+		cancelA(errClientGone) // The second parent finishing changes nothing
+		cancelB(errShutdown)
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Errorf("%s cancelled first: Err = %v, want %v", first, ctx.Err(), context.Canceled)
+		}
+		if got := context.Cause(ctx); got != want {
+			t.Errorf("%s cancelled first: Cause = %v, want %v", first, got, want)
+		}
+		cancel()
+	}
+}
+
+func TestMergeContextsDeadline(t *testing.T) {
+	a, cancelA := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelA()
+	b, cancelB := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelB()
+
+	ctx, cancel := MergeContexts(a, b)
+	defer cancel()
+
+	bDeadline, _ := b.Deadline()
+	if d, ok := ctx.Deadline(); !ok || !d.Equal(bDeadline) {
+		t.Errorf("Deadline = %v, %v; want b's deadline %v", d, ok, bDeadline)
+	}
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err = %v, want %v", ctx.Err(), context.DeadlineExceeded)
+	}
+}
+
+func TestMergeContextsValuesAndCancel(t *testing.T) {
+	type key struct{}
+	a := context.WithValue(context.Background(), key{}, "from a")
+	b, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	ctx, cancel := MergeContexts(a, b)
+	if v := ctx.Value(key{}); v != "from a" {
+		t.Errorf("Value = %v, want a's value", v)
+	}
+
+	cancel()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("after cancel, Err = %v, want %v", ctx.Err(), context.Canceled)
+	}
+	if b.Err() != nil {
+		t.Errorf("cancelling the merged context cancelled b: %v", b.Err())
+	}
+}
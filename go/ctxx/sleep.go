@@ -0,0 +1,27 @@
+// Package ctxx provides helpers for working with contexts: sleeping that
+// can be cancelled and combining the cancellation of several contexts.
+package ctxx
+
+import (
+	"context"
+	"time"
+)
+
+// Sleep pauses for d or until ctx is done, whichever comes first. It
+// returns nil after a full sleep and ctx.Err() if the sleep was cut short.
+// Unlike a select on time.After, the timer is stopped as soon as Sleep
+// returns.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
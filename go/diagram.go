@@ -0,0 +1,40 @@
+/**
+ * The --diagram flag.
+ *
+ * --diagram=NAME prints the goroutine and channel topology of an example as a
+ * Mermaid flowchart, or as Graphviz DOT with --diagram-format=dot, instead of
+ * running it. Paste Mermaid output into any Markdown viewer that supports it,
+ * or render DOT with `dot -Tsvg`.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"threads/examples"
+)
+
+// printDiagram writes the topology of the named example in the given format
+// and returns an error if there is nothing to draw.
+func printDiagram(name, format string) error {
+	g, ok := examples.Topology(name)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Examples with a diagram:")
+		for _, ex := range examples.WithTopology() {
+			fmt.Fprintf(os.Stderr, "  %-24s %s\n", ex.Name, ex.Title)
+		}
+		return fmt.Errorf("no diagram for %q", name)
+	}
+
+	switch format {
+	case "mermaid":
+		fmt.Print(g.Mermaid())
+	case "dot":
+		fmt.Print(g.DOT())
+	default:
+		return fmt.Errorf("unknown diagram format %q (want mermaid or dot)", format)
+	}
+	return nil
+}
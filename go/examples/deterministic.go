@@ -0,0 +1,42 @@
+package examples
+
+// nondeterministic maps the examples whose output still varies between runs
+// with Options.Deterministic to the reason. Most measure real time or the
+// runtime on purpose; the rest depend on scheduling the clock cannot order.
+var nondeterministic = map[string]string{
+	"scheduling-hints":      "it measures the scheduler's real latency and throughput",
+	"lockfree-queue":        "it benchmarks queues in real time",
+	"spinlock":              "it benchmarks locks in real time",
+	"sharded-counter":       "it benchmarks counters in real time",
+	"select-fairness":       "the runtime picks among ready select cases at random, with its own seed",
+	"timer-ticker-pitfalls": "it shows the behaviour of the time package's own timers",
+	"cpu-vs-io-bound":       "it measures CPU time in real time",
+	"runtime-metrics":       "it reports the runtime's own metrics",
+	"contention-workload":   "it measures lock contention in real time",
+	"instrumented-channels": "chanx.Instrumented measures blocking in real time",
+	"tcp-chat":              "it times a real network connection",
+	"lru-cache":             "it benchmarks caches in real time",
+	"sharded-map":           "it benchmarks maps in real time",
+	"monitor-goroutine":     "it benchmarks a mutex against a monitor goroutine in real time",
+	"gossip":                "thousands of node goroutines race within every round",
+	"raft-replication":      "the raft package runs on real timers",
+	"ping-pong":             "it measures channel round trips in real time",
+	"goroutine-scaling":     "it measures goroutine creation in real time",
+	"thread-bound-api":      "it measures calls into a locked OS thread in real time",
+	"context-afterfunc":     "it times out real network reads",
+	"http-server":           "it times real HTTP requests",
+	"ttl-cache":             "the cache package expires entries in real time",
+	"timeout-helpers":       "ctxx and chanx time out in real time",
+}
+
+// Reproducible reports whether the named example (or menu number) prints the
+// same output on every run with Options.Deterministic. If it does not, the
+// reason says why.
+func Reproducible(nameOrNumber string) (ok bool, reason string) {
+	e, found := lookup(nameOrNumber)
+	if !found {
+		return false, "unknown example"
+	}
+	reason, varies := nondeterministic[e.Name]
+	return !varies, reason
+}
@@ -0,0 +1,115 @@
+package examples
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"threads/clock"
+	"threads/console"
+)
+
+// Output formats for Options.Format.
+const (
+	// FormatText writes the examples' output as it is printed.
+	FormatText = "text"
+	// FormatJSON writes one JSON Event per line of output and per checkpoint.
+	FormatJSON = "json"
+)
+
+// Event is a line of output or a checkpoint of an example, as written in
+// FormatJSON.
+type Event struct {
+	// Time is when the event happened, by the example's clock, so runs with
+	// Options.Deterministic have reproducible timestamps.
+	Time time.Time `json:"time"`
+	// Demo is the name of the example.
+	Demo string `json:"demo"`
+	// Kind is "output" for a printed line or "checkpoint" for an annotated
+	// step.
+	Kind string `json:"kind"`
+	// Step is the number of checkpoints reached so far in the run.
+	Step int `json:"step"`
+	// Goroutine is the ID of the goroutine that printed the line.
+	Goroutine uint64 `json:"goroutine"`
+	// Message is the line, without its newline, or the checkpoint's
+	// explanation.
+	Message string `json:"message"`
+}
+
+// eventWriter turns the output and checkpoints of an example into JSON
+// events. Partial lines are buffered per goroutine, so that lines printed
+// piecewise by several goroutines are not mixed up.
+type eventWriter struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	demo    string
+	next    console.Stepper // Checkpoints are forwarded here, if not nil
+	step    int
+	partial map[uint64][]byte
+}
+
+func newEventWriter(w io.Writer, demo string, next console.Stepper) *eventWriter {
+	return &eventWriter{enc: json.NewEncoder(w), demo: demo, next: next, partial: map[uint64][]byte{}}
+}
+
+func (e *eventWriter) Write(p []byte) (int, error) {
+	id := console.GoroutineID()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	buf := append(e.partial[id], p...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := e.emit("output", id, string(buf[:i])); err != nil {
+			return 0, err
+		}
+		buf = buf[i+1:]
+	}
+
+	if len(buf) > 0 {
+		e.partial[id] = append([]byte(nil), buf...)
+	} else {
+		delete(e.partial, id)
+	}
+	return len(p), nil
+}
+
+func (e *eventWriter) Checkpoint(explanation string) {
+	e.mu.Lock()
+	e.step++
+	e.emit("checkpoint", console.GoroutineID(), explanation)
+	e.mu.Unlock()
+
+	if e.next != nil {
+		e.next.Checkpoint(explanation)
+	}
+}
+
+// flush emits any unterminated lines left at the end of a run
+func (e *eventWriter) flush() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for id, buf := range e.partial {
+		e.emit("output", id, string(buf))
+	}
+	clear(e.partial)
+}
+
+func (e *eventWriter) emit(kind string, id uint64, msg string) error {
+	return e.enc.Encode(Event{
+		Time:      clock.Current.Now(),
+		Demo:      e.demo,
+		Kind:      kind,
+		Step:      e.step,
+		Goroutine: id,
+		Message:   msg,
+	})
+}
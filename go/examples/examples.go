@@ -0,0 +1,166 @@
+// Package examples exposes the concurrency demos as a library.
+//
+// Every demo is registered under a stable, kebab-case name so that other
+// programs (teaching tools, web playgrounds, test suites) can list and run
+// them without going through the interactive CLI:
+//
+//	for _, ex := range examples.List() {
+//		fmt.Println(ex.Name, "-", ex.Title)
+//	}
+//	err := examples.Run(ctx, "fan-out-fan-in", os.Stdout, examples.Options{})
+//
+// The menu numbers used by the CLI are kept as aliases in Example.Number.
+package examples
+
+import (
+	"strconv"
+
+	"threads/advanced"
+	"threads/basic"
+)
+
+// Categories of examples, in the order they are presented.
+const (
+	Basic    = "basic"
+	Advanced = "advanced"
+)
+
+// Example describes a registered demo.
+type Example struct {
+	// Name is the stable identifier used to run the example.
+	Name string
+	// Title is the human-readable name shown in menus.
+	Title string
+	// Category is either Basic or Advanced.
+	Category string
+	// Number is the menu number the CLI has always used for this example.
+	Number int
+}
+
+// entry pairs an example's description with the function that runs it.
+type entry struct {
+	Example
+	run func()
+}
+
+var registry = []entry{
+	{Example{"goroutines", "Goroutines", Basic, 1}, basic.GoroutineDemo},
+	{Example{"channels", "Channels", Basic, 2}, basic.ChannelDemo},
+	{Example{"buffered-channels", "Buffered Channels", Basic, 3}, basic.BufferedChannelDemo},
+	{Example{"wait-group", "WaitGroup", Basic, 4}, basic.WaitGroupDemo},
+	{Example{"select", "Select", Basic, 5}, basic.SelectDemo},
+	{Example{"mutex", "Mutex", Basic, 6}, basic.MutexDemo},
+
+	{Example{"channel-ownership", "Channel Ownership", Advanced, 11}, advanced.ChannelOwnershipDemo},
+	{Example{"fan-out-fan-in", "Fan-out, Fan-in", Advanced, 12}, advanced.FanOutFanInDemo},
+	{Example{"cancellation", "Cancellation Pattern", Advanced, 13}, advanced.CancellationPatternDemo},
+	{Example{"or-channel", "Or-channel Pattern", Advanced, 14}, advanced.OrChannelPatternDemo},
+	{Example{"tee-channel", "Tee Channel Pattern", Advanced, 15}, advanced.TeeChannelPatternDemo},
+	{Example{"dynamic-buffer-sizing", "Dynamic Buffer Sizing", Advanced, 16}, advanced.DynamicBufferSizingDemo},
+	{Example{"channel-semaphore", "Channel as Semaphore", Advanced, 17}, advanced.ChannelSemaphoreDemo},
+	{Example{"dropping-channel", "Dropping Channel", Advanced, 18}, advanced.DroppingChannelDemo},
+	{Example{"ring-buffer", "Ring Buffer", Advanced, 19}, advanced.RingBufferDemo},
+	{Example{"batch-processing", "Batch Processing", Advanced, 20}, advanced.BatchProcessingDemo},
+	{Example{"priority-select", "Priority Select", Advanced, 21}, advanced.PrioritySelectDemo},
+	{Example{"select-send-receive", "Select with Send/Receive", Advanced, 22}, advanced.SelectSendReceiveDemo},
+	{Example{"nil-channel-select", "Nil Channel Select", Advanced, 23}, advanced.NilChannelSelectDemo},
+	{Example{"rwmutex", "RWMutex", Advanced, 24}, advanced.RWMutexDemo},
+	{Example{"atomic-operations", "Atomic Operations", Advanced, 25}, advanced.AtomicOperationsDemo},
+	{Example{"sync-once", "Sync.Once", Advanced, 26}, advanced.SyncOnceDemo},
+	{Example{"try-lock", "Try Lock", Advanced, 27}, advanced.TryLockDemo},
+	{Example{"scheduling-hints", "Scheduling Hints", Advanced, 28}, advanced.SchedulingHintsDemo},
+	{Example{"waitgroup-error-handling", "WaitGroup Error Handling", Advanced, 29}, advanced.WaitGroupErrorHandlingDemo},
+	{Example{"dynamic-waitgroup", "Dynamic WaitGroup", Advanced, 30}, advanced.DynamicWaitGroupDemo},
+	{Example{"waitgroup-timeout", "WaitGroup with Timeout", Advanced, 31}, advanced.WaitGroupTimeoutDemo},
+	{Example{"worker-pool", "Worker Pool", Advanced, 32}, advanced.WorkerPoolDemo},
+	{Example{"parallel-foreach", "Parallel ForEach", Advanced, 33}, advanced.ParallelForEachDemo},
+	{Example{"lockfree-queue", "Lock-free Queue", Advanced, 34}, advanced.LockFreeQueueDemo},
+	{Example{"spinlock", "Spinlock and Ticket Lock", Advanced, 35}, advanced.SpinlockDemo},
+	{Example{"sharded-counter", "Sharded Counter", Advanced, 36}, advanced.ShardedCounterDemo},
+	{Example{"typed-atomics", "Typed Atomics", Advanced, 37}, advanced.TypedAtomicsDemo},
+	{Example{"atomic-value-config", "atomic.Value Config Hot-Reload", Advanced, 38}, advanced.AtomicValueConfigDemo},
+	{Example{"memory-model", "Happens-Before and the Memory Model", Advanced, 39}, advanced.MemoryModelDemo},
+	{Example{"select-fairness", "Select Fairness and Starvation", Advanced, 40}, advanced.SelectFairnessDemo},
+	{Example{"timer-ticker-pitfalls", "Timer and Ticker Pitfalls", Advanced, 41}, advanced.TimerTickerPitfallsDemo},
+	{Example{"adaptive-worker-pool", "Adaptive Worker Pool", Advanced, 42}, advanced.AdaptiveWorkerPoolDemo},
+	{Example{"priority-worker-pool", "Priority Worker Pool", Advanced, 43}, advanced.PriorityWorkerPoolDemo},
+	{Example{"keyed-worker-pool", "Keyed Worker Pool", Advanced, 44}, advanced.KeyedWorkerPoolDemo},
+	{Example{"work-stealing", "Work-Stealing Scheduler", Advanced, 45}, advanced.WorkStealingDemo},
+	{Example{"batch-size-or-timeout", "Batching by Size or Timeout", Advanced, 46}, advanced.BatchSizeOrTimeoutDemo},
+	{Example{"stream-windows", "Tumbling and Sliding Windows", Advanced, 47}, advanced.StreamWindowsDemo},
+	{Example{"conflating-channel", "Conflating Channel", Advanced, 48}, advanced.ConflatingChannelDemo},
+	{Example{"sliding-buffer", "Sliding Buffer", Advanced, 49}, advanced.SlidingBufferDemo},
+	{Example{"backpressure", "Backpressure Propagation", Advanced, 50}, advanced.BackpressureDemo},
+	{Example{"load-shedding", "Load Shedding", Advanced, 51}, advanced.LoadSheddingDemo},
+	{Example{"load-balancer", "Load Balancer", Advanced, 52}, advanced.LoadBalancerDemo},
+	{Example{"supervisor", "Supervisor and Restart Policies", Advanced, 53}, advanced.SupervisorDemo},
+	{Example{"safe-goroutines", "Panic-safe Goroutines", Advanced, 54}, advanced.SafeGoroutinesDemo},
+	{Example{"structured-concurrency", "Structured Concurrency", Advanced, 55}, advanced.StructuredConcurrencyDemo},
+	{Example{"context-cause", "Context Cancellation Causes", Advanced, 56}, advanced.ContextCauseDemo},
+	{Example{"context-afterfunc", "context.AfterFunc and WithoutCancel", Advanced, 57}, advanced.ContextAfterFuncDemo},
+	{Example{"once-helpers", "sync.OnceFunc, OnceValue and OnceValues", Advanced, 58}, advanced.OnceHelpersDemo},
+	{Example{"mutex-trylock", "Standard Library TryLock", Advanced, 59}, advanced.MutexTryLockDemo},
+	{Example{"countdown-latch", "CountDownLatch and Start Gate", Advanced, 60}, advanced.CountDownLatchDemo},
+	{Example{"exchanger", "Rendezvous and Exchanger", Advanced, 61}, advanced.ExchangerDemo},
+	{Example{"cpu-vs-io-bound", "CPU-bound vs IO-bound Scheduling", Advanced, 62}, advanced.CPUvsIOBoundDemo},
+	{Example{"runtime-metrics", "Runtime Metrics Monitoring", Advanced, 63}, advanced.RuntimeMetricsDemo},
+	{Example{"contention-workload", "Contention Workload for Profiling", Advanced, 64}, advanced.ContentionWorkloadDemo},
+	{Example{"instrumented-channels", "Instrumented Channels", Advanced, 65}, advanced.InstrumentedChannelsDemo},
+	{Example{"tcp-chat", "Concurrent TCP Chat Server", Advanced, 66}, advanced.TCPChatDemo},
+	{Example{"http-server", "HTTP Server Concurrency Patterns", Advanced, 67}, advanced.HTTPServerDemo},
+	{Example{"file-pipeline", "Concurrent File Processing Pipeline", Advanced, 68}, advanced.FilePipelineDemo},
+	{Example{"streaming-records", "Streaming JSON/CSV Processing Pipeline", Advanced, 69}, advanced.StreamingRecordsDemo},
+	{Example{"lru-cache", "Concurrency-safe LRU Cache", Advanced, 70}, advanced.LRUCacheDemo},
+	{Example{"ttl-cache", "TTL Cache with a Janitor Goroutine", Advanced, 71}, advanced.TTLCacheDemo},
+	{Example{"sharded-map", "Sharded Map with Striped Locks", Advanced, 72}, advanced.ShardedMapDemo},
+	{Example{"copy-on-write", "Copy-on-Write Snapshots", Advanced, 73}, advanced.CopyOnWriteDemo},
+	{Example{"monitor-goroutine", "Monitor Goroutine (Serial Confinement)", Advanced, 74}, advanced.MonitorGoroutineDemo},
+	{Example{"order-fsm", "Channel-based Finite State Machine", Advanced, 75}, advanced.OrderFSMDemo},
+	{Example{"event-loop", "Event Loop Pattern", Advanced, 76}, advanced.EventLoopDemo},
+	{Example{"result-pipeline", "Result Values in Pipelines", Advanced, 77}, advanced.ResultPipelineDemo},
+	{Example{"channel-closing", "Who Closes the Channel", Advanced, 78}, advanced.ChannelClosingDemo},
+	{Example{"timeout-helpers", "Cancellable Sleep and Timeout Helpers", Advanced, 79}, advanced.TimeoutHelpersDemo},
+	{Example{"merge-contexts", "Merging Contexts", Advanced, 80}, advanced.MergeContextsDemo},
+	{Example{"delay-queue", "Delay Queue", Advanced, 81}, advanced.DelayQueueDemo},
+	{Example{"recurring-scheduler", "Recurring Job Scheduler", Advanced, 82}, advanced.RecurringSchedulerDemo},
+	{Example{"job-queue", "At-least-once Job Queue", Advanced, 83}, advanced.JobQueueDemo},
+	{Example{"outbox-inbox", "Transactional Outbox and Inbox", Advanced, 84}, advanced.OutboxInboxDemo},
+	{Example{"two-phase-commit", "Two-Phase Commit Coordinator", Advanced, 85}, advanced.TwoPhaseCommitDemo},
+	{Example{"leader-election", "Leader Election with the Bully Algorithm", Advanced, 86}, advanced.LeaderElectionDemo},
+	{Example{"gossip", "Gossip Protocol", Advanced, 87}, advanced.GossipDemo},
+	{Example{"raft-replication", "Raft-style Log Replication", Advanced, 88}, advanced.RaftReplicationDemo},
+	{Example{"vector-clocks", "Vector Clocks", Advanced, 89}, advanced.VectorClocksDemo},
+	{Example{"crdt-counter", "CRDT Counters", Advanced, 90}, advanced.CRDTCounterDemo},
+	{Example{"chandy-lamport", "Chandy-Lamport Snapshot Algorithm", Advanced, 91}, advanced.ChandyLamportDemo},
+	{Example{"token-ring", "Token Ring Mutual Exclusion", Advanced, 92}, advanced.TokenRingDemo},
+	{Example{"ping-pong", "Ping-Pong Latency", Advanced, 93}, advanced.PingPongDemo},
+	{Example{"goroutine-scaling", "Goroutine Creation Overhead and Scaling", Advanced, 94}, advanced.GoroutineScalingDemo},
+	{Example{"thread-bound-api", "Calling a Thread-bound API", Advanced, 95}, advanced.ThreadBoundAPIDemo},
+	{Example{"pause-resume", "Pausing and Resuming Goroutines", Advanced, 96}, advanced.PauseResumeDemo},
+	{Example{"quiescence", "Quiescence Detection", Advanced, 97}, advanced.QuiescenceDemo},
+}
+
+// List returns all registered examples in menu order.
+func List() []Example {
+	list := make([]Example, len(registry))
+	for i, e := range registry {
+		list[i] = e.Example
+	}
+	return list
+}
+
+// Lookup finds an example by its name or by its menu number.
+func Lookup(nameOrNumber string) (Example, bool) {
+	e, ok := lookup(nameOrNumber)
+	return e.Example, ok
+}
+
+func lookup(nameOrNumber string) (entry, bool) {
+	num, err := strconv.Atoi(nameOrNumber)
+	for _, e := range registry {
+		if e.Name == nameOrNumber || (err == nil && e.Number == num) {
+			return e, true
+		}
+	}
+	return entry{}, false
+}
@@ -0,0 +1,199 @@
+package examples
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"threads/console"
+	"threads/stats"
+)
+
+// register adds an example running fn for the duration of the test
+func register(t *testing.T, name string, fn func()) {
+	t.Helper()
+	prev := registry
+	registry = append(registry[:len(registry):len(registry)], entry{Example{name, name, Advanced, 1000 + len(registry)}, fn})
+	t.Cleanup(func() { registry = prev })
+}
+
+func TestRunUnknown(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"no-such-example", Options{}, `unknown example "no-such-example"`},
+		{"9999", Options{}, `unknown example "9999"`},
+		{"goroutines", Options{Format: "xml"}, `unknown output format "xml"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Run(context.Background(), tt.name, &out, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Run = %v, want an error containing %q", err, tt.want)
+			}
+			if out.Len() != 0 {
+				t.Errorf("Run wrote %q for an example it did not run", out.String())
+			}
+		})
+	}
+}
+
+func TestRunWritesToWriter(t *testing.T) {
+	register(t, "test-output", func() {
+		fmt.Fprintln(console.Out, "printed")
+		console.Log.Info("logged", "n", 1)
+	})
+
+	prev := console.SetOutput(io.Discard)
+	console.SetOutput(prev)
+
+	var out bytes.Buffer
+	if err := Run(context.Background(), "test-output", &out, Options{}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if want := "printed\nlogged n=1\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+
+	// The shared writer is put back once the example returns
+	if after := console.SetOutput(prev); after != prev {
+		t.Errorf("console output after Run is %T, want it restored to %T", after, prev)
+	}
+}
+
+func TestRunResetsStats(t *testing.T) {
+	register(t, "test-stats", func() { stats.Record("step", time.Millisecond) })
+
+	if err := Run(context.Background(), "test-stats", &bytes.Buffer{}, Options{}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if samples := stats.Take(); len(samples) != 0 {
+		t.Errorf("%d sample(s) left after Run, want none", len(samples))
+	}
+}
+
+func TestRunPanic(t *testing.T) {
+	register(t, "test-panic", func() { panic("boom") })
+
+	err := Run(context.Background(), "test-panic", &bytes.Buffer{}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "test-panic panicked: boom") {
+		t.Errorf("Run = %v, want the panic as an error", err)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	release, finished := make(chan struct{}), make(chan struct{})
+	register(t, "test-stuck", func() {
+		defer close(finished)
+		fmt.Fprintln(console.Out, "started")
+		<-release
+		fmt.Fprintln(console.Out, "too late")
+	})
+	register(t, "test-next", func() { fmt.Fprintln(console.Out, "next") })
+
+	var caller bytes.Buffer
+	prev := console.SetOutput(&caller)
+	defer console.SetOutput(prev)
+
+	var out bytes.Buffer
+	start := time.Now()
+	err := Run(context.Background(), "test-stuck", &out, Options{Timeout: 50 * time.Millisecond})
+
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) {
+		t.Fatalf("Run = %v, want a *TimeoutError", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run returned after %v, want about the 50ms timeout", elapsed)
+	}
+	if timeout.Name != "test-stuck" || timeout.Goroutines < 1 || len(timeout.Stuck) == 0 {
+		t.Errorf("TimeoutError = %+v, want the stuck example's goroutine", timeout)
+	}
+
+	// The abandoned example gave up the runner, so the next one runs now
+	var next bytes.Buffer
+	if err := Run(context.Background(), "test-next", &next, Options{Timeout: 5 * time.Second}); err != nil {
+		t.Fatalf("Run after a timeout: %v", err)
+	}
+	if next.String() != "next\n" {
+		t.Errorf("the next example wrote %q, want %q", next.String(), "next\n")
+	}
+
+	// The console goes back to the caller, but what the abandoned example
+	// prints later is written to neither
+	fmt.Fprintln(console.Out, "caller")
+	close(release)
+	<-finished
+	if out.String() != "started\n" {
+		t.Errorf("abandoned example's output = %q, want %q", out.String(), "started\n")
+	}
+	if caller.String() != "caller\n" {
+		t.Errorf("console output after the timeout = %q, want %q", caller.String(), "caller\n")
+	}
+}
+
+func TestRunSerialized(t *testing.T) {
+	var running, peak, runs atomic.Int32
+	register(t, "test-serial", func() {
+		n := running.Add(1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		runs.Add(1)
+	})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Run(context.Background(), "test-serial", &bytes.Buffer{}, Options{}); err != nil {
+				t.Errorf("Run: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if runs.Load() != 5 {
+		t.Errorf("the example ran %d times, want 5", runs.Load())
+	}
+	if p := peak.Load(); p != 1 {
+		t.Errorf("%d Run calls ran their example at once, want 1", p)
+	}
+}
+
+func TestRunCancelledWhileWaiting(t *testing.T) {
+	release, started := make(chan struct{}), make(chan struct{})
+	register(t, "test-holder", func() {
+		close(started)
+		<-release
+	})
+	register(t, "test-waiter", func() { t.Error("an example ran after its Run was cancelled") })
+
+	held := make(chan error)
+	go func() { held <- Run(context.Background(), "test-holder", &bytes.Buffer{}, Options{}) }()
+	<-started
+
+	// Run waits for the runner and gives up when its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := Run(ctx, "test-waiter", &bytes.Buffer{}, Options{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(release)
+	if err := <-held; err != nil {
+		t.Errorf("Run of the holder: %v", err)
+	}
+}
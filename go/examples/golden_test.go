@@ -0,0 +1,53 @@
+package examples
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// TestGolden runs every reproducible example with Options.Deterministic and
+// compares its output with testdata/golden/<name>.txt. Run it with -update
+// after changing what an example prints.
+func TestGolden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs every example")
+	}
+	if raceEnabled {
+		t.Skip("the race detector shuffles the order goroutines run in")
+	}
+
+	for _, ex := range List() {
+		if ok, _ := Reproducible(ex.Name); !ok {
+			continue
+		}
+		t.Run(ex.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			opts := Options{Deterministic: true, Timeout: time.Minute}
+			if err := Run(context.Background(), ex.Name, &out, opts); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			golden := filepath.Join("testdata", "golden", ex.Name+".txt")
+			if *update {
+				if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(out.Bytes(), want) {
+				t.Errorf("output differs from %s (run with -update if the change is intended)\ngot:\n%s\nwant:\n%s", golden, out.Bytes(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package examples
+
+// Playlist is a lesson plan: a curated, ordered subset of the examples with
+// text introducing each one, for guided workshop sessions.
+type Playlist struct {
+	// Name is the identifier used to select the playlist.
+	Name string
+	// Title is the human-readable name shown when the playlist starts.
+	Title string
+	// Intro is shown before the first lesson.
+	Intro string
+	// Lessons are run in order.
+	Lessons []Lesson
+}
+
+// Lesson is one step of a Playlist.
+type Lesson struct {
+	// Example is the name of the example to run.
+	Example string
+	// Intro is shown before the example runs.
+	Intro string
+}
+
+// Playlists returns every playlist, in the order they are presented.
+func Playlists() []Playlist {
+	return playlists
+}
+
+// LookupPlaylist returns the playlist with the given name.
+func LookupPlaylist(name string) (Playlist, bool) {
+	for _, p := range playlists {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Playlist{}, false
+}
+
+var playlists = []Playlist{
+	{
+		Name:  "channels-101",
+		Title: "Channels 101",
+		Intro: "Goroutines communicate by passing values over channels. This session goes from the " +
+			"first unbuffered send to choosing between several channels with select.",
+		Lessons: []Lesson{
+			{"goroutines", "Start with goroutines themselves: cheap functions running concurrently, " +
+				"and what happens if nobody waits for them."},
+			{"channels", "An unbuffered channel hands a value from one goroutine to another; " +
+				"both sides wait until the other is ready."},
+			{"buffered-channels", "A buffer lets the sender run ahead of the receiver, up to its capacity."},
+			{"channel-ownership", "Who creates, writes and closes a channel? Giving each channel one owner " +
+				"avoids sends on closed channels and double closes."},
+			{"select", "select waits on several channel operations at once and runs whichever is ready first."},
+			{"nil-channel-select", "Setting a channel variable to nil disables its case in a select, " +
+				"a neat way to stop listening to a closed input."},
+		},
+	},
+	{
+		Name:  "sync-primitives",
+		Title: "Sync Primitives",
+		Intro: "Not everything needs a channel. This session tours the sync and sync/atomic packages, " +
+			"from WaitGroup to atomics, and when each one is the right tool.",
+		Lessons: []Lesson{
+			{"wait-group", "A WaitGroup waits for a set of goroutines to finish."},
+			{"mutex", "A Mutex protects shared state so that only one goroutine changes it at a time."},
+			{"rwmutex", "An RWMutex lets many readers in at once but writers in alone."},
+			{"sync-once", "sync.Once runs initialisation exactly once, however many goroutines ask for it."},
+			{"atomic-operations", "Atomics update a single word without a lock."},
+			{"typed-atomics", "The typed atomics (atomic.Int64, atomic.Pointer and friends) are safer to use " +
+				"than the functions on raw integers."},
+			{"memory-model", "Finally, why all of this is needed: what the Go memory model does and does not " +
+				"promise about writes seen by other goroutines."},
+		},
+	},
+	{
+		Name:  "pipelines",
+		Title: "Pipelines and Cancellation",
+		Intro: "Chaining goroutines with channels gives pipelines. This session builds one up and then " +
+			"makes sure it can be stopped cleanly.",
+		Lessons: []Lesson{
+			{"fan-out-fan-in", "Fan out the work to several goroutines, then fan the results back into one channel."},
+			{"tee-channel", "A tee sends every value of one channel to two consumers."},
+			{"cancellation", "A done channel tells every stage of a pipeline to stop."},
+			{"or-channel", "The or-channel combines several done channels into one."},
+			{"context-cause", "context.Context is the standard way to cancel work; a cause records why."},
+			{"structured-concurrency", "Structured concurrency ties goroutines to a scope that waits for them " +
+				"and cancels the rest when one fails."},
+		},
+	},
+	{
+		Name:  "worker-pools",
+		Title: "Worker Pools",
+		Intro: "A fixed number of workers processing a queue of jobs is the workhorse of concurrent Go. " +
+			"This session starts with the basic pool and adds the features real systems need.",
+		Lessons: []Lesson{
+			{"worker-pool", "The basic pool: workers ranging over a jobs channel and a goroutine closing the results."},
+			{"channel-semaphore", "A buffered channel used as a semaphore caps concurrency without a pool."},
+			{"waitgroup-error-handling", "Collect the errors of the workers instead of losing them."},
+			{"adaptive-worker-pool", "Grow and shrink the pool with the length of the queue."},
+			{"priority-worker-pool", "Serve urgent jobs first."},
+			{"keyed-worker-pool", "Send jobs with the same key to the same worker to keep them in order."},
+			{"backpressure", "When producers outpace the pool, slow them down rather than queueing forever."},
+		},
+	},
+}
@@ -0,0 +1,916 @@
+package examples
+
+import "math/rand/v2"
+
+// Question is a multiple-choice question about the pattern an example shows.
+type Question struct {
+	// Prompt is the question itself.
+	Prompt string
+	// Choices are the possible answers, in display order.
+	Choices []string
+	// Answer is the index of the correct choice.
+	Answer int
+	// Explanation is shown after the question has been answered.
+	Explanation string
+}
+
+// Quiz returns the questions for the named example (or menu number). It
+// reports false if the example does not exist or has no questions.
+func Quiz(nameOrNumber string) ([]Question, bool) {
+	e, ok := lookup(nameOrNumber)
+	if !ok {
+		return nil, false
+	}
+
+	qs, ok := quizzes[e.Name]
+	return qs, ok
+}
+
+// Shuffle returns a copy of q with its choices in a random order drawn from
+// r, and Answer moved to wherever the correct choice ended up.
+func (q Question) Shuffle(r *rand.Rand) Question {
+	order := r.Perm(len(q.Choices))
+	choices, answer := make([]string, len(q.Choices)), q.Answer
+	for to, from := range order {
+		choices[to] = q.Choices[from]
+		if from == q.Answer {
+			answer = to
+		}
+	}
+	q.Choices, q.Answer = choices, answer
+	return q
+}
+
+// quizzes maps example names to the questions asked after they run.
+var quizzes = map[string][]Question{
+	"goroutines": {
+		{"What happens to running goroutines when main returns?",
+			[]string{"They keep running in the background", "The program exits and they are stopped", "main waits for them automatically"}, 1,
+			"The program exits as soon as main returns; nothing waits for other goroutines unless you make it."},
+		{"Roughly how large is a new goroutine's initial stack?",
+			[]string{"A few kilobytes, growing on demand", "1 MB, like an OS thread", "Fixed at 64 KB"}, 0,
+			"Goroutines start with a small (2 KB) stack that the runtime grows and shrinks, which is why millions are affordable."},
+	},
+	"channels": {
+		{"What does a send on an unbuffered channel do?",
+			[]string{"Returns immediately", "Blocks until a receiver takes the value", "Panics if nobody is receiving"}, 1,
+			"An unbuffered send is a rendezvous: it completes only when a receiver is ready."},
+		{"What does receiving from a closed, empty channel return?",
+			[]string{"It blocks forever", "It panics", "The zero value immediately, with ok == false"}, 2,
+			"Receives on a closed channel never block; the two-value form reports ok == false once it is drained."},
+	},
+	"buffered-channels": {
+		{"When does a send on a buffered channel block?",
+			[]string{"Always", "Only when the buffer is full", "Only when the buffer is empty"}, 1,
+			"Sends fill the buffer without waiting; only a full buffer makes the sender wait for a receiver."},
+		{"What do len(ch) and cap(ch) report for a buffered channel?",
+			[]string{"Queued values and buffer size", "Senders and receivers waiting", "Total values ever sent and buffer size"}, 0,
+			"len is the number of values currently buffered and cap the buffer size; both are only snapshots under concurrency."},
+	},
+	"wait-group": {
+		{"Where should wg.Add be called?",
+			[]string{"Inside the new goroutine", "Before starting the goroutine", "After wg.Wait"}, 1,
+			"Calling Add inside the goroutine races with Wait, which may see a zero counter and return early."},
+		{"What happens if Done is called more times than Add?",
+			[]string{"Nothing", "Wait returns early", "It panics with a negative counter"}, 2,
+			"A WaitGroup counter must never go negative; doing so panics."},
+	},
+	"select": {
+		{"Which case runs when several select cases are ready at once?",
+			[]string{"The first one in source order", "One chosen uniformly at random", "The one with the most buffered values"}, 1,
+			"Select picks pseudo-randomly among ready cases to avoid starvation."},
+		{"What does a default case do in a select?",
+			[]string{"Runs if no other case is ready, making the select non-blocking", "Runs after every other case", "Runs when a channel is closed"}, 0,
+			"With a default case the select never blocks; default runs when nothing else can proceed."},
+	},
+	"mutex": {
+		{"Why is defer mu.Unlock() commonly used right after mu.Lock()?",
+			[]string{"It is faster", "The mutex is released on every return path, including panics", "Go requires it"}, 1,
+			"Deferring Unlock ensures early returns and panics cannot leave the mutex locked."},
+		{"Is sync.Mutex reentrant (can the holder lock it again)?",
+			[]string{"Yes", "No, locking it again deadlocks", "Only in the same function"}, 1,
+			"Go mutexes are not reentrant; a goroutine that locks a mutex it already holds blocks forever."},
+	},
+	"channel-ownership": {
+		{"Who should close a channel?",
+			[]string{"Any receiver", "The owner that sends on it", "The garbage collector"}, 1,
+			"The owning sender knows when no more values will be sent, so only it can close safely."},
+		{"Why do owner functions typically return a receive-only channel (<-chan T)?",
+			[]string{"It is faster", "Consumers then cannot send on it or close it", "Receive-only channels are buffered"}, 1,
+			"The type system then enforces ownership: consumers can only read."},
+	},
+	"fan-out-fan-in": {
+		{"What is fan-out?",
+			[]string{"Several goroutines reading from the same input channel", "One goroutine writing to many channels", "Closing a channel early"}, 0,
+			"Fan-out spreads work from one channel across several workers."},
+		{"When can the merged output of a fan-in be closed?",
+			[]string{"When the first input closes", "After every forwarding goroutine has finished", "Never"}, 1,
+			"A WaitGroup over the forwarders tells a closer goroutine when no more values can arrive."},
+		{"Does fan-in preserve the order of the input values?",
+			[]string{"Yes", "No, values from different inputs interleave arbitrarily", "Only with buffered channels"}, 1,
+			"Values arrive in whatever order the forwarders run; restore order explicitly if it matters."},
+		{"How does parallel.Group return results in input order?",
+			[]string{"It sorts them", "Go reserves a slot in the results before starting the function", "It runs the functions one at a time"}, 1,
+			"Each function writes its value to its own index, so completion order does not matter."},
+	},
+	"cancellation": {
+		{"What signals cancellation to many goroutines at once?",
+			[]string{"Sending one value on a channel", "Closing a done channel (or cancelling a context)", "Calling runtime.Goexit"}, 1,
+			"A close is observed by every receiver, so it acts as a broadcast."},
+		{"Can a goroutine be killed from outside?",
+			[]string{"Yes, with runtime.Kill", "No, it must notice cancellation and return itself", "Only if it is blocked"}, 1,
+			"Go has no way to stop a goroutine externally; cancellation is cooperative."},
+	},
+	"or-channel": {
+		{"What does an or-channel do?",
+			[]string{"Closes when any of its input channels closes", "Closes when all inputs close", "Merges values from its inputs"}, 0,
+			"It combines several done signals into one that fires on the first."},
+		{"Why is closing the combined channel from several goroutines dangerous?",
+			[]string{"It is slow", "Closing an already closed channel panics", "Readers miss the signal"}, 1,
+			"Exactly one goroutine may close it; sync.Once or a single closer avoids the panic."},
+	},
+	"tee-channel": {
+		{"What does a tee do with each input value?",
+			[]string{"Sends it to one of the outputs", "Sends it to every output", "Drops it if an output is busy"}, 1,
+			"Like the Unix tee command, each value is duplicated to all outputs."},
+		{"How does a slow consumer of one tee output affect the other output?",
+			[]string{"Not at all", "It slows it down, since the tee waits for both sends", "It makes it drop values"}, 1,
+			"The tee blocks until both outputs have taken the value, so outputs advance in lockstep."},
+	},
+	"dynamic-buffer-sizing": {
+		{"Can a channel's buffer be resized after make?",
+			[]string{"Yes, with a resize call", "No, you must create a new channel and switch to it", "Yes, by assigning to cap"}, 1,
+			"Channel capacity is fixed at creation; dynamic sizing means migrating to a new channel."},
+		{"What do larger buffers mainly buy you?",
+			[]string{"Higher steady-state throughput than the consumer allows", "Absorbing bursts without blocking the producer", "Guaranteed ordering"}, 1,
+			"Buffers smooth out bursts; sustained throughput is still set by the slowest side."},
+	},
+	"channel-semaphore": {
+		{"How does a buffered channel act as a semaphore?",
+			[]string{"Send to acquire a slot, receive to release it", "Close to acquire, reopen to release", "len(ch) is the number of waiters"}, 0,
+			"With capacity N, at most N sends can be outstanding, limiting concurrency to N."},
+		{"What does the channel's capacity control?",
+			[]string{"The number of goroutines that can be started", "How many holders may be inside the critical section at once", "The timeout for acquiring"}, 1,
+			"Capacity is the number of permits."},
+		{"What happens to a worker whose context is cancelled while it waits in syncx.Semaphore.Acquire?",
+			[]string{"It gets the next free slot anyway", "Acquire returns the cancellation cause and no slot is taken", "The semaphore is closed"}, 1,
+			"Acquire selects on the slot and ctx.Done, so a waiter can give up without holding anything to release."},
+	},
+	"dropping-channel": {
+		{"How do you send without blocking?",
+			[]string{"select with the send case and a default case", "Use a buffered channel of size 0", "Call ch.TrySend"}, 0,
+			"If the send cannot proceed immediately, default runs and the value is dropped."},
+		{"Which values does a dropping channel keep when it is full?",
+			[]string{"The newest", "The oldest already buffered", "A random sample"}, 1,
+			"New values are discarded, so the buffer holds the oldest; a sliding buffer does the opposite."},
+	},
+	"ring-buffer": {
+		{"What happens to the oldest item when a full ring buffer receives a new one?",
+			[]string{"It is overwritten (evicted)", "The writer blocks", "The buffer grows"}, 0,
+			"A ring buffer keeps the most recent N items by evicting the oldest."},
+		{"Why must eviction and insertion be coordinated when several goroutines write?",
+			[]string{"Otherwise another writer may fill the freed slot first", "Channels are not safe for concurrent use", "They need not be"}, 0,
+			"Receive-then-send is not atomic; chanx.SlidingBuffer retries until its send succeeds."},
+	},
+	"batch-processing": {
+		{"Why process items in batches?",
+			[]string{"To amortise per-operation overhead such as round trips", "To reduce latency for every item", "Because channels require it"}, 0,
+			"Batching trades a little latency for far fewer expensive operations."},
+		{"What should happen to a partial batch when the input ends?",
+			[]string{"Drop it", "Flush it", "Wait for more items forever"}, 1,
+			"Flushing on close ensures no items are lost."},
+	},
+	"priority-select": {
+		{"Does select prefer earlier cases in the source?",
+			[]string{"Yes", "No, ready cases are chosen at random", "Only if they are buffered"}, 1,
+			"Select has no priority, so priority must be built explicitly."},
+		{"How can high-priority messages be preferred?",
+			[]string{"List them first in the select", "Try a non-blocking receive on the high-priority channel first, then select on both", "Use a larger buffer"}, 1,
+			"A nested select with default checks the high-priority channel before waiting on either."},
+	},
+	"select-send-receive": {
+		{"Can one select statement contain both send and receive cases?",
+			[]string{"Yes", "No", "Only with a default case"}, 0,
+			"Any mix of channel operations can be cases; whichever is ready first proceeds."},
+		{"When is the value expression of a send case evaluated?",
+			[]string{"Only if that case is chosen", "On entering the select, for every send case", "After the select finishes"}, 1,
+			"All channel and value expressions are evaluated once, in source order, before select chooses."},
+		{"Why does each correlated request carry its own reply channel?",
+			[]string{"So several requests can be outstanding and each response reaches the caller that asked", "Because channels cannot be shared", "To make the server faster"}, 0,
+			"With one shared responses channel, concurrent callers could receive each other's answers."},
+		{"Why is the reply channel buffered with capacity one?",
+			[]string{"To hold several replies", "So the server's send never blocks, even if the caller has timed out", "Unbuffered channels cannot be sent in a struct"}, 1,
+			"A late reply lands in the buffer and is garbage collected with the request; no goroutine leaks."},
+	},
+	"nil-channel-select": {
+		{"What does an operation on a nil channel do inside select?",
+			[]string{"Panics", "Never becomes ready, disabling the case", "Returns the zero value"}, 1,
+			"Setting a channel variable to nil is the idiomatic way to switch off a select case."},
+		{"Why set a channel to nil after it is closed in a merge loop?",
+			[]string{"To free memory", "A closed channel is always ready and would spin the loop", "To close it again"}, 1,
+			"Receives on a closed channel succeed immediately forever; nil stops the case from firing."},
+	},
+	"rwmutex": {
+		{"How many goroutines may hold an RWMutex read lock at once?",
+			[]string{"One", "Any number, as long as no writer holds it", "Exactly GOMAXPROCS"}, 1,
+			"Readers share the lock; a writer needs exclusive access."},
+		{"When is RWMutex worse than a plain Mutex?",
+			[]string{"When critical sections are tiny or writes are frequent", "Never", "When there are many readers"}, 0,
+			"RWMutex has more bookkeeping; it pays off only for read-heavy workloads with non-trivial reads."},
+	},
+	"atomic-operations": {
+		{"What does CompareAndSwap do?",
+			[]string{"Sets a new value only if the current value equals the expected one", "Swaps two variables", "Compares two atomics"}, 0,
+			"CAS is the building block for lock-free updates: retry until the swap succeeds."},
+		{"Is counter++ safe when several goroutines run it?",
+			[]string{"Yes, for ints", "No, it is a read-modify-write that can lose updates", "Only on 64-bit platforms"}, 1,
+			"Use atomic.Int64.Add or a mutex instead."},
+	},
+	"sync-once": {
+		{"If several goroutines call once.Do(f) at the same time, what happens?",
+			[]string{"f runs once; the others wait for it to finish", "f runs once; the others return immediately", "f runs in each goroutine"}, 0,
+			"Do does not return until f has completed, so callers always see the initialised state."},
+		{"If f panics inside once.Do, is it retried on the next call?",
+			[]string{"Yes", "No, Do considers it done", "Only if recovered"}, 1,
+			"A panic still counts as the one call; sync.OnceValues or your own logic is needed to retry."},
+	},
+	"try-lock": {
+		{"What does TryLock return when the lock is held?",
+			[]string{"It blocks", "false, immediately", "An error"}, 1,
+			"TryLock never waits."},
+		{"Why is TryLock rarely the right tool?",
+			[]string{"It is slow", "It often hides a design problem and encourages busy loops", "It is deprecated"}, 1,
+			"The standard library documents that correct uses are rare."},
+	},
+	"scheduling-hints": {
+		{"What does runtime.Gosched do?",
+			[]string{"Yields the processor so other goroutines can run", "Puts the goroutine to sleep for 1ms", "Locks the goroutine to its thread"}, 0,
+			"It is only a hint; the goroutine is rescheduled later."},
+		{"What does GOMAXPROCS limit?",
+			[]string{"The number of goroutines", "The number of threads executing Go code simultaneously", "The number of OS threads overall"}, 1,
+			"Threads blocked in syscalls do not count against GOMAXPROCS."},
+		{"In a container limited to 2 CPUs on a 64-CPU host, what does GOMAXPROCS default to before Go 1.25?",
+			[]string{"2", "64", "1"}, 1,
+			"runtime.NumCPU counts the host's CPUs; the quota has to be read from cgroups, as package maxprocs does."},
+		{"What does runtime.LockOSThread guarantee?",
+			[]string{"The goroutine runs on one CPU core", "The goroutine always runs on the same OS thread, and no other goroutine runs there until it unlocks", "The goroutine cannot be preempted"}, 1,
+			"The thread IDs in the demo show it: a regular goroutine wakes up on several threads, a locked one on just one."},
+	},
+	"waitgroup-error-handling": {
+		{"How can worker goroutines report errors when a WaitGroup is used?",
+			[]string{"wg.Done(err)", "Send them on a buffered channel or store them under a mutex", "Return them from the goroutine"}, 1,
+			"WaitGroup only counts; errors need their own channel or shared, guarded storage."},
+		{"What does errgroup.Group add over a WaitGroup?",
+			[]string{"The first error is returned from Wait, with optional context cancellation", "Faster scheduling", "Automatic retries"}, 0,
+			"errgroup combines waiting, error propagation and cancellation."},
+		{"What does syncx.WaitGroup report when one of its functions panics?",
+			[]string{"Nothing; the program crashes", "A *safego.PanicError among the errors returned by Wait", "It restarts the function"}, 1,
+			"Go recovers the panic in the goroutine and keeps it, with its stack, for Wait."},
+	},
+	"dynamic-waitgroup": {
+		{"A task spawns subtasks. When must it call wg.Add for them?",
+			[]string{"Before it calls its own wg.Done", "After its own wg.Done", "It does not matter"}, 0,
+			"Adding before Done keeps the counter above zero so Wait cannot return early."},
+		{"Can wg.Add be called while another goroutine is in wg.Wait?",
+			[]string{"Yes, as long as the counter is greater than zero", "Never", "Only with a mutex"}, 0,
+			"Adds that start from a zero counter must happen before Wait."},
+		{"How does syncx.TaskTracker keep a spawned task from being missed?",
+			[]string{"It polls the number of goroutines", "Its Go method counts the task before starting the goroutine", "It waits a grace period after the count reaches zero"}, 1,
+			"The parent is still running and counted when Go counts its child, so the count cannot reach zero in between."},
+	},
+	"waitgroup-timeout": {
+		{"How do you wait for a WaitGroup with a timeout?",
+			[]string{"wg.WaitTimeout(d)", "Close a channel after wg.Wait in a goroutine, then select on it and a timer", "Set a deadline on the WaitGroup"}, 1,
+			"WaitGroup has no timeout; a helper goroutine converts Wait into a channel close."},
+		{"What happens to the workers when the timeout fires?",
+			[]string{"They are stopped", "They keep running unless they are cancelled separately", "They panic"}, 1,
+			"A timeout only stops waiting; pair it with a context to stop the work."},
+		{"Why does the demo use clock.WithTimeout instead of context.WithTimeout?",
+			[]string{"WithTimeout cannot carry a cause", "The timeout then runs on the examples' clock, like the workers' sleeps", "Wait ignores deadlines"}, 1,
+			"With --deterministic the clock is virtual, and a real-time deadline would no longer line up with the workers."},
+	},
+	"worker-pool": {
+		{"Why use a fixed pool of workers instead of one goroutine per job?",
+			[]string{"Goroutines cannot share channels", "It bounds concurrency and resource use", "It is required for ordering"}, 1,
+			"A pool caps how many jobs run at once, protecting CPUs, memory and downstream services."},
+		{"How do workers know there are no more jobs?",
+			[]string{"The jobs channel is closed and their range loop ends", "They poll len(jobs)", "They time out"}, 0,
+			"Closing the jobs channel lets every worker's for-range finish."},
+	},
+	"parallel-foreach": {
+		{"What does bounded parallelism limit?",
+			[]string{"The number of items", "How many items are processed concurrently", "The total run time"}, 1,
+			"A limit keeps throughput high without unbounded goroutines."},
+		{"What should a parallel ForEach do when one item fails and a context is supplied?",
+			[]string{"Ignore it", "Cancel the context so remaining work stops early", "Restart the item"}, 1,
+			"Cancelling on the first error avoids wasted work."},
+	},
+	"lockfree-queue": {
+		{"What do lock-free algorithms use instead of locks?",
+			[]string{"Atomic compare-and-swap loops", "Channels", "runtime.LockOSThread"}, 0,
+			"Progress is made with CAS retries; no goroutine can block others by holding a lock."},
+		{"Is a lock-free queue always faster than a channel or mutex?",
+			[]string{"Yes", "No, it depends on contention and workload; measure", "Only on one CPU"}, 1,
+			"CAS retries and allocation can make lock-free code slower under some loads."},
+	},
+	"spinlock": {
+		{"When can a spinlock beat a mutex?",
+			[]string{"For very short critical sections with low contention", "When holders sleep or do IO", "Always"}, 0,
+			"Spinning wastes CPU while waiting, so it only pays for tiny critical sections."},
+		{"What does a ticket lock guarantee that a simple spinlock does not?",
+			[]string{"FIFO fairness among waiters", "Lower latency", "Reentrancy"}, 0,
+			"Waiters are served in ticket order, so nobody starves."},
+	},
+	"sharded-counter": {
+		{"Why does a single hot atomic counter scale badly across cores?",
+			[]string{"Atomics are slow on one core", "Every update bounces the cache line between cores", "The garbage collector scans it"}, 1,
+			"Cache-line contention serialises writers."},
+		{"What is the trade-off of a sharded counter?",
+			[]string{"Reads must sum all shards", "Writes are not atomic", "It uses locks"}, 0,
+			"Writes become cheap, reads become more expensive and only approximately consistent."},
+	},
+	"typed-atomics": {
+		{"What does atomic.Int64 offer over atomic.AddInt64 on a plain int64?",
+			[]string{"Non-atomic access becomes impossible, and 64-bit alignment is guaranteed", "Faster operations", "Overflow checks"}, 0,
+			"The typed wrappers cannot be read or written without the atomic methods."},
+		{"Which type stores a typed pointer atomically?",
+			[]string{"atomic.Value", "atomic.Pointer[T]", "atomic.Uintptr"}, 1,
+			"atomic.Pointer[T] avoids the type assertions of atomic.Value."},
+	},
+	"atomic-value-config": {
+		{"How is the configuration updated safely while readers use it?",
+			[]string{"Mutate the struct in place", "Build a new copy and atomically store a pointer to it", "Lock every reader"}, 1,
+			"Copy-on-write: readers load a snapshot that is never mutated."},
+		{"What must never be done to a config after it has been stored?",
+			[]string{"Read it", "Modify it", "Load it twice"}, 1,
+			"Modifying a published snapshot reintroduces data races."},
+	},
+	"memory-model": {
+		{"Without synchronisation, is a write in one goroutine guaranteed to be visible to another?",
+			[]string{"Yes, eventually", "No, there is no happens-before edge", "Only for ints"}, 1,
+			"Visibility requires a happens-before relation from channels, locks, atomics and so on."},
+		{"Which of these creates a happens-before edge?",
+			[]string{"time.Sleep", "A send on a channel and the matching receive", "Printing to stdout"}, 1,
+			"A send happens before the corresponding receive completes; sleeps prove nothing."},
+	},
+	"select-fairness": {
+		{"If one channel is always ready, can select starve another ready channel?",
+			[]string{"Yes, always", "No, random choice gives both a fair share", "Only with default"}, 1,
+			"Random selection makes starvation statistically impossible when both are ready."},
+		{"How can you give one channel roughly three times the share of another?",
+			[]string{"List it three times in the select", "Weight the choice explicitly, e.g. with a counter or a nested select", "Use a bigger buffer"}, 1,
+			"Select has no weights; you build them yourself."},
+	},
+	"timer-ticker-pitfalls": {
+		{"Why is time.After in a long-running loop a problem?",
+			[]string{"It panics", "Each call creates a new timer, wasting allocations on every iteration", "It never fires"}, 1,
+			"Reuse one time.Timer with Reset instead."},
+		{"What must you do with a Ticker you no longer need?",
+			[]string{"Nothing", "Call Stop", "Close its channel"}, 1,
+			"Stop releases the ticker; never close its channel yourself."},
+	},
+	"adaptive-worker-pool": {
+		{"What signal does an adaptive pool scale on?",
+			[]string{"CPU temperature", "Queue depth (backlog)", "The number of channels"}, 1,
+			"A growing backlog triggers more workers; idle workers retire."},
+		{"Why keep minimum and maximum worker counts?",
+			[]string{"To avoid thrashing and bound resource use", "Channels require it", "For ordering"}, 0,
+			"Bounds prevent both cold starts and unbounded growth."},
+	},
+	"priority-worker-pool": {
+		{"What is the risk of strict priority scheduling?",
+			[]string{"Low-priority jobs may starve", "High-priority jobs run twice", "Deadlock"}, 0,
+			"A steady flow of urgent work can block everything else forever."},
+		{"How does aging prevent starvation?",
+			[]string{"Old jobs are dropped", "Waiting jobs gain priority over time", "Workers sleep"}, 1,
+			"Raising priority with waiting time guarantees every job eventually runs."},
+	},
+	"keyed-worker-pool": {
+		{"Why route all jobs with the same key to the same worker?",
+			[]string{"To process them in order without locks", "To balance load", "To run them in parallel"}, 0,
+			"One worker per key serialises that key's jobs."},
+		{"What is the downside of key-based routing?",
+			[]string{"Hot keys can overload a single worker", "Jobs are lost", "It needs atomics"}, 0,
+			"Skewed key distributions create hot spots."},
+	},
+	"work-stealing": {
+		{"From which end of a victim's deque does a thief usually steal?",
+			[]string{"The same end the owner works on", "The opposite end from the owner", "The middle"}, 1,
+			"Stealing from the other end reduces contention with the owner."},
+		{"What does work stealing improve?",
+			[]string{"Load balance when tasks have uneven cost", "Strict ordering", "Memory use"}, 0,
+			"Idle workers take work from busy ones; Go's scheduler does the same with run queues."},
+	},
+	"batch-size-or-timeout": {
+		{"Why combine a size limit with a timeout when batching?",
+			[]string{"The timeout bounds latency when traffic is light", "To make batches bigger", "Timers are cheaper than channels"}, 0,
+			"Without a timeout an item could wait forever in a partial batch."},
+		{"When does the batch timer start?",
+			[]string{"When the program starts", "When the first item of a new batch arrives", "After every item"}, 1,
+			"Starting it with the first item bounds every item's wait."},
+	},
+	"stream-windows": {
+		{"How do tumbling windows differ from sliding windows?",
+			[]string{"Tumbling windows do not overlap; sliding windows do", "Tumbling windows are bigger", "There is no difference"}, 0,
+			"Each item belongs to exactly one tumbling window but possibly several sliding ones."},
+		{"What triggers emitting a time-based window?",
+			[]string{"A ticker or timer at the window boundary", "A full buffer", "Closing the program"}, 0,
+			"Windows are flushed on time, even if few items arrived."},
+	},
+	"conflating-channel": {
+		{"What does a conflating channel keep when the consumer is slow?",
+			[]string{"Every value", "Only the latest value", "Only the first value"}, 1,
+			"Intermediate values are overwritten; ideal for state updates such as prices or progress."},
+		{"When is conflation inappropriate?",
+			[]string{"For status updates", "When every message matters, such as orders or payments", "For UI refreshes"}, 1,
+			"Conflation deliberately loses intermediate values."},
+	},
+	"sliding-buffer": {
+		{"A full sliding buffer receives a new value. What happens?",
+			[]string{"The sender blocks", "The oldest value is evicted", "The new value is dropped"}, 1,
+			"Receivers always see the newest N values."},
+		{"Does SlidingBuffer.Send ever block?",
+			[]string{"Yes, when full", "No", "Only when closed"}, 1,
+			"It evicts instead of waiting, so producers never stall."},
+	},
+	"backpressure": {
+		{"In a pipeline with a slow last stage, which stage blocks first?",
+			[]string{"The source", "The stage just before the slow one", "All at the same time"}, 1,
+			"Its output link fills first; pressure then travels upstream."},
+		{"Do bigger buffers increase a pipeline's sustained throughput?",
+			[]string{"Yes", "No, they only absorb bursts; the slowest stage sets throughput", "Only unbuffered links do"}, 1,
+			"Buffers delay backpressure but cannot remove the bottleneck."},
+	},
+	"load-shedding": {
+		{"What is load shedding?",
+			[]string{"Rejecting excess work quickly instead of queueing it", "Moving work to another machine", "Lowering GOMAXPROCS"}, 0,
+			"Fast rejection keeps latency bounded for admitted requests."},
+		{"Why is an unbounded queue dangerous under overload?",
+			[]string{"Latency and memory grow without bound", "It drops requests", "It is not thread-safe"}, 0,
+			"Every queued request waits longer and longer; eventually everything times out."},
+	},
+	"load-balancer": {
+		{"Why does least-pending beat round-robin when one worker is slow?",
+			[]string{"It routes new requests away from the backed-up worker", "It is random", "It uses more workers"}, 0,
+			"Round-robin keeps sending the slow worker its share, inflating tail latency."},
+		{"What does least-pending balancing need that round-robin does not?",
+			[]string{"Completion feedback from workers", "Buffered channels", "Atomics"}, 0,
+			"The balancer must know how many requests each worker still has."},
+	},
+	"supervisor": {
+		{"What does a supervisor do when a worker panics?",
+			[]string{"Crashes the program", "Recovers and restarts it according to a policy", "Ignores it"}, 1,
+			"Supervision trees, popularised by Erlang, restart failed workers."},
+		{"Why add backoff between restarts?",
+			[]string{"To avoid hot crash loops that burn CPU", "To slow down healthy workers", "Go requires it"}, 0,
+			"Exponential backoff gives transient problems time to clear."},
+	},
+	"safe-goroutines": {
+		{"Can recover in main catch a panic in another goroutine?",
+			[]string{"Yes", "No, recover only works in the panicking goroutine", "Only with defer"}, 1,
+			"An unrecovered panic in any goroutine crashes the whole program."},
+		{"Where must the deferred recover be placed?",
+			[]string{"In the function that starts the goroutine", "At the top of the goroutine's own function", "In init"}, 1,
+			"A launcher like safego.Go wraps the goroutine body with the deferred recover."},
+	},
+	"structured-concurrency": {
+		{"What does a nursery (scope) guarantee?",
+			[]string{"No goroutine started in it outlives the scope", "Goroutines run in order", "Goroutines never fail"}, 0,
+			"The scope waits for its children, so concurrency has a clear lifetime."},
+		{"What happens to siblings when one child fails?",
+			[]string{"They keep running", "The shared context is cancelled so they can stop", "They are restarted"}, 1,
+			"Failure propagates as cancellation, like errgroup.WithContext."},
+	},
+	"context-cause": {
+		{"What does context.Cause(ctx) return that ctx.Err() does not?",
+			[]string{"The specific error passed to the cancel function", "The stack trace", "The deadline"}, 0,
+			"ctx.Err() is just Canceled or DeadlineExceeded; Cause carries the reason."},
+		{"Which function creates a context whose cancel takes an error?",
+			[]string{"context.WithCancel", "context.WithCancelCause", "context.WithValue"}, 1,
+			"WithTimeoutCause and WithDeadlineCause do the same for timeouts."},
+	},
+	"context-afterfunc": {
+		{"When does the function registered with context.AfterFunc run?",
+			[]string{"Immediately", "In its own goroutine after the context is done", "When the program exits"}, 1,
+			"The returned stop function unregisters it if it has not run yet."},
+		{"What does context.WithoutCancel keep from its parent?",
+			[]string{"Its values, but not its cancellation or deadline", "Everything", "Only the deadline"}, 0,
+			"Useful for detached work such as audit logs that must outlive a request."},
+	},
+	"once-helpers": {
+		{"What does sync.OnceValue(f) return?",
+			[]string{"A function that calls f once and returns its cached result", "The result of f", "A sync.Once"}, 0,
+			"Later calls return the cached value without calling f again."},
+		{"If f panics, what do later calls of a sync.OnceFunc wrapper do?",
+			[]string{"Call f again", "Panic with the same value", "Return normally"}, 1,
+			"Unlike sync.Once, the helpers re-panic on every call after a panicking first call."},
+	},
+	"mutex-trylock": {
+		{"Which methods can attempt a lock without blocking?",
+			[]string{"Mutex.TryLock, RWMutex.TryLock and RWMutex.TryRLock", "Only Mutex.Lock with a timeout", "None; use channels"}, 0,
+			"They were added in Go 1.18."},
+		{"What should a caller usually do when TryLock fails?",
+			[]string{"Spin in a tight loop", "Skip or defer the optional work", "Panic"}, 1,
+			"Good uses are opportunistic: do the work only if the lock is free."},
+	},
+	"countdown-latch": {
+		{"How does a CountDownLatch differ from a WaitGroup?",
+			[]string{"Its count is fixed up front and it cannot be reused once open", "It is faster", "It counts upwards"}, 0,
+			"A latch opens once when the count reaches zero and stays open."},
+		{"What is a start gate used for?",
+			[]string{"Releasing many goroutines at the same moment", "Limiting concurrency", "Ordering output"}, 0,
+			"Workers wait on a latch of one, and a single CountDown starts them all."},
+	},
+	"exchanger": {
+		{"What does an Exchanger do?",
+			[]string{"Two goroutines meet and swap values", "Broadcasts a value to many goroutines", "Queues values"}, 0,
+			"Each side blocks until its partner arrives, then both return the other's value."},
+		{"Which primitive is an exchanger a two-way version of?",
+			[]string{"An unbuffered channel rendezvous", "A mutex", "A ticker"}, 0,
+			"An unbuffered send and receive also meet, but only pass a value one way."},
+	},
+	"cpu-vs-io-bound": {
+		{"For CPU-bound work, how many workers are usually useful?",
+			[]string{"About the number of CPUs (GOMAXPROCS)", "As many as possible", "One"}, 0,
+			"Extra CPU-bound workers just take turns on the same cores."},
+		{"Why can IO-bound work use many more workers than CPUs?",
+			[]string{"Waiting goroutines do not occupy a CPU", "IO is faster", "The runtime adds CPUs"}, 0,
+			"While goroutines wait on IO, others run."},
+	},
+	"runtime-metrics": {
+		{"Which package exposes scheduler latency and GC pause histograms?",
+			[]string{"runtime/metrics", "expvar", "runtime/debug"}, 0,
+			"Metrics such as /sched/latencies:seconds are read with metrics.Read."},
+		{"What does scheduler latency measure?",
+			[]string{"Time goroutines spend runnable before they get to run", "Time spent in GC", "Time blocked on channels"}, 0,
+			"High scheduler latency means more runnable goroutines than processors."},
+	},
+	"contention-workload": {
+		{"Which profile shows where goroutines wait on channel operations and sync primitives?",
+			[]string{"The CPU profile", "The block profile", "The heap profile"}, 1,
+			"Block profiling must be enabled with runtime.SetBlockProfileRate."},
+		{"What does the mutex profile attribute contention to?",
+			[]string{"The goroutine that waited", "The code that held the lock (the unlock site)", "The garbage collector"}, 1,
+			"It reports where contended locks were released, i.e. who made others wait."},
+	},
+	"instrumented-channels": {
+		{"In a pipeline, where does a slow stage show up?",
+			[]string{"Its input link is full and its upstream blocks on send", "Its output link is full", "Its channel is closed"}, 0,
+			"Blocked sends into, and blocked receives out of, a stage point at the bottleneck."},
+		{"What does a channel that is always empty with blocked receivers suggest?",
+			[]string{"The consumer is the bottleneck", "The producer cannot keep up", "The buffer is too big"}, 1,
+			"Starved receivers mean work upstream is too slow."},
+	},
+	"tcp-chat": {
+		{"Why does the hub send to each client's buffer with a non-blocking select?",
+			[]string{"It is faster than a blocking send", "So that one slow client cannot stall the broadcast to everyone else", "Because the buffers are unbuffered"}, 1,
+			"A blocking send would make the whole room wait for the slowest reader; dropping keeps the hub responsive."},
+		{"Who closes a client's send channel?",
+			[]string{"The client's reader goroutine", "The write pump", "The hub, which owns the set of clients"}, 2,
+			"The goroutine that owns the channel closes it; the write pump ranges over it and closes the connection when it ends."},
+		{"What makes the shutdown graceful?",
+			[]string{"Closing the listener, letting the pumps drain their buffers and waiting for every goroutine", "Calling os.Exit", "Closing every connection immediately"}, 0,
+			"Stop accepting, tell the workers to finish, and wait for them before returning."},
+	},
+	"http-server": {
+		{"How many goroutines does net/http use to serve concurrent requests?",
+			[]string{"One for the whole server", "One per CPU", "One per connection, so handlers run concurrently"}, 2,
+			"Every connection gets its own goroutine, so shared state in handlers needs synchronisation."},
+		{"How does a handler find out that the client has gone away?",
+			[]string{"r.Context() is cancelled", "The handler panics", "ResponseWriter.Write blocks forever"}, 0,
+			"The server cancels the request context when the connection closes, so long work can stop early."},
+		{"What does Server.Shutdown do with requests that are in flight?",
+			[]string{"Aborts them immediately", "Waits for them to finish after closing the listeners", "Restarts them"}, 1,
+			"Shutdown closes the listeners and idle connections and waits for active requests, up to its context's deadline."},
+	},
+	"file-pipeline": {
+		{"Why do more workers speed up hashing files even on a single CPU?",
+			[]string{"Hashing uses several CPUs internally", "The work is I/O-bound: while one worker waits for the file system, others run", "Goroutines share one read buffer"}, 1,
+			"Goroutines blocked on I/O do not use the CPU, so others can make progress meanwhile."},
+		{"What keeps the pipeline's memory use bounded however big the tree is?",
+			[]string{"Small channel buffers and a fixed read buffer per worker", "Reading each file with os.ReadFile", "Collecting all paths before starting"}, 0,
+			"Backpressure from the small channels limits the paths in flight, and files are streamed, not loaded whole."},
+		{"After cancellation, why do the workers keep receiving from paths without working?",
+			[]string{"To count the skipped files", "So that the walker, blocked on a send, can notice cancellation and close the channel", "They don't; they exit at once"}, 1,
+			"Draining avoids leaving the sender stuck; the walker also selects on ctx.Done so it stops promptly."},
+	},
+	"streaming-records": {
+		{"How does the pipeline keep the output in input order when workers finish out of order?",
+			[]string{"Workers take turns by sequence number", "A reorder stage holds early results until every earlier one has been emitted", "The channels are unbuffered"}, 1,
+			"Every record carries its sequence number, and the reorder stage releases them strictly in sequence."},
+		{"What stops the reader from racing far ahead of a slow record?",
+			[]string{"A window of tokens taken by the reader and returned when a record is emitted", "The reader sleeps between records", "Nothing; memory use grows with the stream"}, 0,
+			"The token channel is a semaphore sized to the window, bounding the records in flight."},
+		{"Why are malformed records sent to an error channel instead of ending the job?",
+			[]string{"Errors cannot be returned from goroutines", "So one bad record is reported with its position while the rest of the stream is processed", "To make the output faster"}, 1,
+			"An ETL job usually quarantines bad input and keeps going; the error says which record failed."},
+	},
+	"lru-cache": {
+		{"Why doesn't an RWMutex help an LRU cache's Get?",
+			[]string{"RWMutex is slower than Mutex", "Get moves the entry to the front of the recency list, so it writes", "Maps cannot be read concurrently"}, 1,
+			"Every hit updates the recency order, so lookups need the exclusive lock."},
+		{"What does a sharded LRU give up in exchange for less lock contention?",
+			[]string{"Exact global LRU order: each shard evicts its own oldest entry", "Type safety", "The ability to store more entries"}, 0,
+			"Recency is tracked per shard, so an eviction may not remove the globally oldest entry."},
+		{"When is sharding least likely to pay off?",
+			[]string{"With many cores and many goroutines", "With a single core, where there is little real contention", "With a skewed key distribution"}, 1,
+			"Sharding removes contention; with one core there is little to remove and hashing costs extra."},
+	},
+	"ttl-cache": {
+		{"Why does a TTL cache need a janitor goroutine if Get already ignores expired entries?",
+			[]string{"Get is too slow", "Expired entries nobody asks for again would otherwise stay in memory forever", "To refresh the TTL of popular entries"}, 1,
+			"Lazy expiry hides stale values but only a sweep frees the memory of keys that are never read again."},
+		{"What should Close do besides cancelling the janitor?",
+			[]string{"Nothing else", "Clear the map", "Wait until the janitor goroutine has exited"}, 2,
+			"Waiting on a done channel makes the goroutine's end observable, so callers and leak checks see it gone."},
+		{"How can the janitor be stopped when the owner forgets to call Close?",
+			[]string{"It cannot; the goroutine keeps the cache alive", "The janitor refers only to inner state, so the outer value can be collected and a cleanup cancels the janitor", "The garbage collector stops idle goroutines"}, 1,
+			"If the goroutine referenced the value the caller holds it would never become unreachable; an inner struct breaks that cycle."},
+	},
+	"sharded-map": {
+		{"What does lock striping change compared with a map behind one RWMutex?",
+			[]string{"Each shard has its own lock, so operations on keys in different shards do not contend", "Reads no longer need any lock", "The map becomes lock-free"}, 0,
+			"The key's hash picks a shard, and only that shard's lock is taken."},
+		{"Which workload is sync.Map designed for?",
+			[]string{"Frequent writes to the same keys", "Keys written once and read many times, or disjoint keys per goroutine", "Maps that need ordered iteration"}, 1,
+			"sync.Map avoids locking for stable keys but is slower than a locked map for write-heavy use."},
+		{"What does a sharded map give up?",
+			[]string{"Atomic single-key operations", "Type safety", "A consistent view of the whole map in Len and Range"}, 2,
+			"Whole-map operations visit the shards one at a time, so they can see a mix of before and after."},
+	},
+	"copy-on-write": {
+		{"How does a copy-on-write writer publish a change?",
+			[]string{"It locks the map and edits it in place", "It clones the current value, modifies the clone and stores a pointer to it atomically", "It sends the change to every reader"}, 1,
+			"The published value is never modified, so a single atomic.Pointer store switches readers to the new version."},
+		{"Why do copy-on-write writers still take a mutex?",
+			[]string{"To stop readers", "Atomic stores are not safe without it", "Two concurrent writers would each copy the same version and one update would be lost"}, 2,
+			"The lock serialises the read-copy-store sequence between writers; readers never take it."},
+		{"When is an RWMutex a better choice than copy-on-write?",
+			[]string{"When writes are frequent or the data is large, since every write copies everything", "When reads are frequent", "When readers need a consistent view"}, 0,
+			"Copy-on-write trades a full copy per write for lock-free reads; it suits read-mostly data."},
+	},
+	"monitor-goroutine": {
+		{"Why does the monitor's map need no mutex?",
+			[]string{"Maps are safe for concurrent use", "Only the owner goroutine can reach it; everyone else sends requests", "The channel locks the map"}, 1,
+			"The state is confined to one goroutine, which serves requests one at a time."},
+		{"Why is each request's reply channel buffered?",
+			[]string{"So the owner never blocks on a caller that is slow to receive", "Unbuffered channels cannot carry structs", "To allow several replies per request"}, 0,
+			"A blocked owner would stall every other client; a buffer of one lets it move on at once."},
+		{"What does the monitor make easier than a mutex does?",
+			[]string{"Raw throughput", "Holding back a request until it can be served, without a condition variable", "Sharing the state with other goroutines"}, 1,
+			"Waiting requests are just a queue inside the owner, re-checked after each change."},
+	},
+	"order-fsm": {
+		{"What happens when a command is not valid in the order's current state?",
+			[]string{"The machine panics", "It is rejected with a transitionError and the state is unchanged", "It is queued until it becomes valid"}, 1,
+			"Every command is checked against the transition table before anything changes."},
+		{"A cancel and a ship race on the same paid order. What is the outcome?",
+			[]string{"Exactly one succeeds; the other finds the order in a state where it is invalid", "Both succeed", "Both fail"}, 0,
+			"The owner goroutine applies commands one at a time, so the second sees the first's result."},
+		{"Why does the client API hide the command channels?",
+			[]string{"Channels cannot be exported", "To make it faster", "Callers get plain methods and errors, and the machine's protocol can change freely"}, 2,
+			"The goroutine and its channels are an implementation detail behind Pay, Ship, Deliver and Cancel."},
+	},
+	"event-loop": {
+		{"Why does the loop set its send channel to nil while the outbox is empty?",
+			[]string{"A send on a nil channel blocks forever, so the case is disabled and cannot send an empty event", "To close the channel", "Nil channels are faster"}, 0,
+			"Toggling the channel to nil switches a select case off without restructuring the loop."},
+		{"What goes wrong if a timer callback posts a task to its own loop?",
+			[]string{"Nothing", "The task runs twice", "The loop blocks sending to itself, since it is the only receiver: a deadlock"}, 2,
+			"Code already on the loop goroutine must change the loop's state directly."},
+		{"Why does emit append to an outbox instead of sending on the events channel?",
+			[]string{"Channels cannot carry strings", "A slow consumer would block the loop, stopping timers and tasks too", "To sort the events"}, 1,
+			"The loop must never block on one source; the outbox is drained by a send case when the consumer is ready."},
+	},
+	"result-pipeline": {
+		{"What does sending Result[T] instead of T give a pipeline?",
+			[]string{"Faster channels", "A failure stays attached to its item and the stream carries on", "Automatic retries"}, 1,
+			"Each item comes out as a value or an error, so one bad item does not stop the rest."},
+		{"What should a later stage do with a failed Result it receives?",
+			[]string{"Pass it on unchanged, so the error reaches the end of the pipeline", "Drop it", "Panic"}, 0,
+			"Only successful values are transformed; errors from earlier stages flow through."},
+		{"Why must the value and error channels of a split be received from concurrently?",
+			[]string{"Closed channels panic", "Errors are sent first", "Both are unbuffered, so draining one first can leave the splitter blocked on the other"}, 2,
+			"A single select loop over both channels, disabling each with nil once closed, keeps the split moving."},
+	},
+	"channel-closing": {
+		{"Who should close a channel with several producers?",
+			[]string{"The first producer to finish", "A coordinator that waits for all producers, for example with a WaitGroup", "The receiver"}, 1,
+			"No single producer knows when the others are done; the coordinator closes once after the last send."},
+		{"What happens when a goroutine sends on a closed channel?",
+			[]string{"The send is ignored", "It blocks forever", "It panics"}, 2,
+			"Send on a closed channel panics, which is why a receiver must never close a channel it receives from."},
+		{"How can a channel that several goroutines may close be closed safely?",
+			[]string{"Wrap close in sync.Once", "Check len(ch) first", "Recover from the panic every time"}, 0,
+			"sync.Once makes the close happen exactly once no matter how many goroutines ask for it."},
+	},
+	"timeout-helpers": {
+		{"What does ctxx.Sleep do that time.Sleep cannot?",
+			[]string{"Sleep more precisely", "Return early with the context's error when the context is cancelled", "Sleep without a timer"}, 1,
+			"It selects on the timer and ctx.Done(), so a cancelled loop stops in the middle of its sleep."},
+		{"Why do the helpers stop their timer before returning?",
+			[]string{"So a call in a loop does not leave a pending timer behind for every iteration", "Timers panic if not stopped", "To make the channel unbuffered"}, 0,
+			"A select on time.After keeps its timer until it fires; NewTimer with a deferred Stop releases it at once."},
+		{"How does RecvTimeout tell a timeout from the end of the stream?",
+			[]string{"It cannot", "It returns the zero value for both", "It returns ErrTimeout for one and ErrClosed for the other"}, 2,
+			"Distinct sentinel errors let the caller keep waiting after a timeout and stop when the channel closes."},
+	},
+	"merge-contexts": {
+		{"When is a context from ctxx.MergeContexts(request, server) done?",
+			[]string{"When both parents are done", "As soon as either parent is done", "Only when its CancelFunc is called"}, 1,
+			"The merged context derives from one parent and is cancelled by an AfterFunc on the other."},
+		{"How can the work tell a client disconnect from a server shutdown?",
+			[]string{"context.Cause returns the cause of the parent that finished first", "ctx.Err() returns different errors", "It cannot"}, 0,
+			"Err is just Canceled; the cause passed to the parent's CancelCauseFunc is propagated."},
+		{"Why use context.AfterFunc rather than a goroutine selecting on both Done channels?",
+			[]string{"AfterFunc is faster to cancel", "Goroutines cannot read Done channels", "AfterFunc parks no goroutine, and its stop function unregisters it, so nothing leaks"}, 2,
+			"A watcher goroutine lives until one of the contexts finishes, which may be never."},
+	},
+	"delay-queue": {
+		{"How many timers does the delay queue use, however many items are scheduled?",
+			[]string{"One per item", "One, always set for the earliest item in the heap", "None; it polls"}, 1,
+			"The heap keeps items ordered by due time, so only the earliest needs a timer."},
+		{"Why does Schedule nudge the timer goroutine when the new item is the earliest?",
+			[]string{"The timer is set for a later item and must be re-armed", "To release the item immediately", "Channels need a send to stay open"}, 0,
+			"Without the nudge the goroutine would sleep until the old earliest item and release the new one late."},
+		{"Why is the wake channel buffered with capacity one and sent to with a default case?",
+			[]string{"To count the nudges", "To make nudges blocking", "One pending wake-up is enough, and a caller must never block on the timer goroutine"}, 2,
+			"The goroutine recomputes the whole schedule when it wakes, so extra nudges can be dropped."},
+	},
+	"recurring-scheduler": {
+		{"How does the scheduler know that a job is still running?",
+			[]string{"It asks the job", "Each job has a one-slot semaphore that a run holds; a failed non-blocking acquire means busy", "It counts goroutines"}, 1,
+			"The select with a default case tries the slot without ever blocking the scheduler goroutine."},
+		{"With the coalesce policy, what happens to three runs missed during one long run?",
+			[]string{"They merge into a single run that starts as soon as the current one ends", "All three run afterwards", "They are dropped"}, 0,
+			"A pending flag records that one run is owed; further misses only set it again."},
+		{"What does a clean shutdown of the scheduler involve?",
+			[]string{"Only stopping the ticker", "Closing every job's semaphore", "Cancelling the context, stopping the ticker and waiting for the running jobs"}, 2,
+			"Jobs get the context so they can stop early, and a WaitGroup tells Stop when the last one returned."},
+	},
+	"job-queue": {
+		{"Why does the queue lease a job instead of removing it on delivery?",
+			[]string{"So that a job whose consumer crashes before the ack is delivered again", "To deliver jobs in order", "To save memory"}, 0,
+			"The job stays in the store until acked; an expired lease makes it ready again."},
+		{"When does at-least-once delivery produce a duplicate?",
+			[]string{"When the queue is full", "When a consumer crashes after doing the work but before acking", "When two consumers start at once"}, 1,
+			"The queue cannot tell that the work was done, so it redelivers the job."},
+		{"What makes the idempotent ledger safe against duplicates?",
+			[]string{"It acks twice", "It processes jobs on one goroutine", "It records applied job ids under the same lock as the balance change"}, 2,
+			"Checking and recording the id atomically with the effect turns a redelivery into a no-op."},
+	},
+	"outbox-inbox": {
+		{"What goes wrong with saving an order and then publishing its event directly?",
+			[]string{"The event may arrive before the order is saved", "A crash between the two writes loses the event", "The broker rejects it"}, 1,
+			"Two writes to two systems are not atomic; the outbox makes the event part of the database write."},
+		{"Why can the outbox relay publish an event twice?",
+			[]string{"It crashes after publishing a row but before marking it published", "The broker duplicates messages", "Writers insert rows twice"}, 0,
+			"After a restart the row still looks unpublished, so the relay sends it again."},
+		{"How does the inbox make the consumer's effect exactly once?",
+			[]string{"It acks every message", "It reads the outbox directly", "It records handled event ids in the same step as the effect and skips ids it has seen"}, 2,
+			"Deduplicating by event id atomically with the effect turns at-least-once delivery into an exactly-once effect."},
+	},
+	"two-phase-commit": {
+		{"What does a participant promise when it votes yes in the prepare phase?",
+			[]string{"To commit if told to, so it must keep its locks until the decision arrives", "To commit immediately", "Nothing; it can still abort on its own"}, 0,
+			"The yes vote hands the decision to the coordinator; the participant must be able to honour a commit."},
+		{"What does the coordinator do when a participant does not vote in time?",
+			[]string{"Waits forever", "Treats it as a no and aborts everyone", "Commits without it"}, 1,
+			"Nothing has been committed during prepare, so aborting on a timeout is safe."},
+		{"Why is two-phase commit called a blocking protocol?",
+			[]string{"Its channels are unbuffered", "The coordinator holds a mutex", "If the coordinator fails after the votes, prepared participants must wait for it, holding their locks"}, 2,
+			"A participant in doubt cannot tell whether commit was logged, so it cannot decide alone."},
+	},
+	"leader-election": {
+		{"In the bully algorithm, which node becomes leader?",
+			[]string{"The first node to notice the failure", "The live node with the highest id", "A random node"}, 1,
+			"A node that starts an election asks every higher node; only one with no live higher node declares victory."},
+		{"How do the followers learn that the leader has crashed?",
+			[]string{"The leader's goroutine closes a channel", "They stop hearing its heartbeats for longer than a timeout", "The runtime reports the crash"}, 1,
+			"A crashed node sends nothing, so silence past a timeout is the only signal."},
+		{"Why does each node add random jitter to its leader timeout?",
+			[]string{"So the nodes do not all start elections at the same moment", "To make heartbeats cheaper", "The ticker requires it"}, 0,
+			"Staggered timeouts mean one node usually starts the election, and fewer messages are sent."},
+	},
+	"gossip": {
+		{"How do the rounds needed to inform every node grow with the number of nodes?",
+			[]string{"Linearly", "Roughly with the logarithm of the number of nodes", "They do not depend on it"}, 1,
+			"The informed group multiplies each round, so many more nodes need only a few more rounds."},
+		{"How does the demo start a round for thousands of goroutines at once?",
+			[]string{"It sends one value per node on a shared channel", "It closes a channel they all wait on and replaces it", "Each node has its own ticker"}, 1,
+			"Closing a channel wakes every receiver, which makes it a cheap broadcast."},
+		{"Why does the spread slow down near the end?",
+			[]string{"Most messages go to nodes that already know the rumor", "The inboxes fill up permanently", "The ticker slows down"}, 0,
+			"With random peers, the last uninformed nodes are only found by chance."},
+	},
+	"raft-replication": {
+		{"When is a log entry committed?",
+			[]string{"When every follower stores it", "When a majority of the nodes, counting the leader, store it", "As soon as the leader appends it"}, 1,
+			"A majority is enough, so the cluster keeps committing while a minority is slow or unreachable."},
+		{"Why do the nodes need no mutex around their logs?",
+			[]string{"Each log is owned by one goroutine and changed only in response to messages", "The logs are append-only", "Go slices are safe for concurrent use"}, 0,
+			"Confining state to a goroutine and sharing it by messages removes the data races."},
+		{"How does the leader recover from lost AppendEntries messages?",
+			[]string{"Followers ask for the missing entries by index", "It resends from each follower's next index on every heartbeat", "It cannot; lost entries stay lost"}, 1,
+			"Retrying on the heartbeat, with followers ignoring entries they already have, makes loss cost only time."},
+	},
+	"vector-clocks": {
+		{"What does a process do with its vector clock when it receives a message?",
+			[]string{"Replaces it with the sender's clock", "Takes the maximum of both clocks, entry by entry, and increments its own entry", "Adds the two clocks"}, 1,
+			"The maximum carries everything the sender knew; the increment counts the receive event itself."},
+		{"Two events have clocks [2 0 0] and [0 1 0]. How are they related?",
+			[]string{"The first happened before the second", "The second happened before the first", "They are concurrent"}, 2,
+			"Neither clock is less than or equal to the other in every entry, so no message chain links them."},
+		{"What can a Lamport clock not tell you?",
+			[]string{"Whether two events are concurrent", "Whether x happened before y given that it did", "Anything at all"}, 0,
+			"x -> y implies L(x) < L(y), but L(x) < L(y) holds for concurrent events too."},
+	},
+	"crdt-counter": {
+		{"How does a G-Counter merge two states?",
+			[]string{"It adds them", "It takes the maximum of each replica's count", "It keeps the newer one"}, 1,
+			"Each replica's count only grows, so the maximum is the most recent value, whichever state arrived first."},
+		{"Why does it not matter that a state is delivered twice?",
+			[]string{"The merge is idempotent: merging the same state again changes nothing", "The network removes duplicates", "The replicas use a mutex"}, 0,
+			"Idempotence, with commutativity and associativity, is what lets replicas converge over an unreliable network."},
+		{"Why does a single number merged by maximum lose updates?",
+			[]string{"The maximum is not commutative", "It cannot tell whose increments it has already seen, so concurrent ones are dropped", "It overflows"}, 1,
+			"Keeping one count per replica is what lets the G-Counter add up concurrent increments."},
+	},
+	"chandy-lamport": {
+		{"What does a process do when it receives its first marker?",
+			[]string{"Stops sending transfers until the snapshot ends", "Records its state, records that channel as empty, forwards markers and records its other incoming channels", "Sends its balance to the initiator immediately"}, 1,
+			"Nobody pauses; the marker only fixes the point in each channel where the snapshot is cut."},
+		{"Why does the algorithm need FIFO channels?",
+			[]string{"So a transfer sent before a marker is received before it", "So markers are never lost", "So the balances stay positive"}, 0,
+			"The marker separates the messages before the cut from those after it, which only works if they cannot overtake it."},
+		{"Why did reading the balances one after another give the wrong total?",
+			[]string{"The atomic loads are not precise", "Money moved between the reads, and some was in a channel, counted by nobody", "The balances overflowed"}, 1,
+			"A consistent snapshot must include the messages in transit, which the markers let each receiver record."},
+	},
+	"token-ring": {
+		{"Why is the shared counter safe without a mutex?",
+			[]string{"Only the token holder touches it, and passing the token over a channel orders the accesses", "Integer increments are atomic", "The nodes run on one CPU"}, 0,
+			"The send that hands the token on happens before the next holder's receive, like an Unlock before a Lock."},
+		{"What does the generation number on a token prevent?",
+			[]string{"Tokens being lost", "A late token coming back as a second token after a new one was created", "Nodes entering the critical section twice in a row"}, 1,
+			"Nodes that have heard of a newer generation discard older tokens."},
+		{"What must the loss-detection timeout be longer than?",
+			[]string{"One channel send", "The longest time any node holds the token", "The time of one critical section"}, 1,
+			"Otherwise a slow holder is mistaken for a lost token and two tokens could both be inside the critical section."},
+	},
+	"ping-pong": {
+		{"Why does a buffer of 1 barely change ping-pong latency?",
+			[]string{"Each side still waits for the other's reply, so every round trip parks and wakes a goroutine twice", "Buffered channels are slower by design", "The buffer is too small"}, 0,
+			"Buffers help when a producer can run ahead; in a strict request/reply exchange nobody can."},
+		{"Roughly how does a channel round trip compare with a function call?",
+			[]string{"About the same", "About ten times slower", "Hundreds to thousands of times slower"}, 2,
+			"Hundreds of nanoseconds against under a nanosecond, which is why hot loops should batch work per message."},
+		{"What does a mutex with a condition variable need that a channel does not?",
+			[]string{"A shared flag saying whose turn it is, checked in a loop around Wait", "A buffer", "A goroutine per waiter"}, 0,
+			"Wait can return without the condition holding, so the state and the loop carry the protocol a channel gives for free."},
+	},
+	"goroutine-scaling": {
+		{"What dominates the memory cost of a live goroutine?",
+			[]string{"Its stack, which starts at a few KiB and grows as needed", "A reserved OS thread", "Its channel buffers"}, 0,
+			"The demo measured about 2 KiB of stack per blocked goroutine, plus a small descriptor."},
+		{"Why does the demo keep all the goroutines blocked on one channel before releasing them?",
+			[]string{"To make them run in order", "So they are all alive at once when the memory is measured", "Goroutines cannot start without it"}, 1,
+			"Goroutines doing trivial work would finish as fast as they were created, hiding their memory cost."},
+		{"Why does the demo run fewer goroutines under the race detector?",
+			[]string{"The race detector limits the number of live goroutines to 8128", "Race detection disables goroutines", "The results would be racy"}, 0,
+			"Exceeding the limit makes a race-enabled binary abort."},
+	},
+	"thread-bound-api": {
+		{"Why do calls to a thread-bound library fail from an ordinary goroutine?",
+			[]string{"Goroutines cannot call C code", "The scheduler may resume the goroutine on a different OS thread after it blocks", "The library is not safe for concurrent use"}, 1,
+			"Every sleep or blocking call is a chance for the goroutine to move threads, invisibly to the Go code."},
+		{"How does the demo make every call succeed from eight goroutines?",
+			[]string{"Each caller locks its own thread", "One goroutine locks its thread, owns the library and runs the callers' requests", "A mutex around each call"}, 1,
+			"Only the owner goroutine is pinned; callers send it closures over a channel and wait for them to finish."},
+		{"Why does the caller read the last error inside the same request as the call?",
+			[]string{"Errors are stored per thread, so the read must run on the thread that made the call", "It is faster", "The channel would otherwise deadlock"}, 0,
+			"Reading errno-style state in a separate step could run on another thread and miss the error."},
+	},
+	"pause-resume": {
+		{"Why does Go have no API to suspend another goroutine?",
+			[]string{"Goroutines are too cheap to suspend", "A goroutine suspended at an arbitrary point could be holding a lock others need", "The scheduler cannot stop goroutines"}, 1,
+			"Pausing is cooperative: the worker pauses itself at a point where it holds nothing."},
+		{"How does the controller know the worker has actually paused?",
+			[]string{"It sleeps for one unit of work", "The pause channel is unbuffered, so the send completes only when the worker takes it", "The worker closes a channel"}, 1,
+			"An unbuffered send is an acknowledgement: Pause returns once the worker is between units."},
+		{"Why does the paused worker also select on the quit channel?",
+			[]string{"So a paused worker can be stopped without being resumed first", "To make the pause shorter", "It is required by select"}, 0,
+			"Otherwise stopping a paused pool would need a resume, letting each worker run another unit."},
+	},
+	"quiescence": {
+		{"Why did the detector fire early in the first run?",
+			[]string{"The counter is not thread-safe", "Children counted themselves after starting, so a parent could finish first and the count touched zero", "The ticker stopped it"}, 1,
+			"Nothing waits for a new goroutine to start running, so its own Add can come after its parent's Done."},
+		{"When must a task be added to the in-flight count?",
+			[]string{"By its creator, before it is started and before the creator calls Done", "As the first statement of the task", "After it finishes"}, 0,
+			"The creator is still counted at that point, so the count cannot reach zero in between."},
+		{"What does the zero-notification channel offer over WaitGroup.Wait?",
+			[]string{"It is faster", "It can be used in a select alongside a ticker, timeout or context", "It counts goroutines automatically"}, 1,
+			"The demo samples the in-flight count on a ticker while it waits, which a blocking Wait cannot do."},
+	},
+}
@@ -0,0 +1,45 @@
+package examples
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestQuestionShuffle(t *testing.T) {
+	q := Question{Prompt: "?", Choices: []string{"a", "b", "c", "d"}, Answer: 2}
+	r := rand.New(rand.NewPCG(1, 2))
+
+	positions := map[int]bool{}
+	for range 50 {
+		s := q.Shuffle(r)
+		if s.Choices[s.Answer] != "c" {
+			t.Fatalf("Shuffle moved the answer to %q, want %q", s.Choices[s.Answer], "c")
+		}
+		sorted := slices.Sorted(slices.Values(s.Choices))
+		if !slices.Equal(sorted, q.Choices) {
+			t.Fatalf("Shuffle changed the choices to %q", s.Choices)
+		}
+		positions[s.Answer] = true
+	}
+	if len(positions) < 2 {
+		t.Errorf("the answer was always at %v after 50 shuffles", positions)
+	}
+	// The original question is unchanged
+	if !slices.Equal(q.Choices, []string{"a", "b", "c", "d"}) || q.Answer != 2 {
+		t.Errorf("Shuffle modified the original question: %+v", q)
+	}
+}
+
+func TestQuizAnswersInRange(t *testing.T) {
+	for name, qs := range quizzes {
+		if _, ok := lookup(name); !ok {
+			t.Errorf("quiz for unknown example %q", name)
+		}
+		for _, q := range qs {
+			if q.Answer < 0 || q.Answer >= len(q.Choices) {
+				t.Errorf("%s: %q has answer %d with %d choices", name, q.Prompt, q.Answer, len(q.Choices))
+			}
+		}
+	}
+}
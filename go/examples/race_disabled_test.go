@@ -0,0 +1,6 @@
+//go:build !race
+
+package examples
+
+// raceEnabled reports whether the test binary was built with the race detector
+const raceEnabled = false
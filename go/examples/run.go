@@ -0,0 +1,307 @@
+package examples
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"threads/chaos"
+	"threads/clock"
+	"threads/console"
+	"threads/rng"
+	"threads/stats"
+)
+
+// Options controls how Run executes an example.
+type Options struct {
+	// Runs is the number of times to execute the example. When greater than
+	// one, Run checks for leaked goroutines after every run and finishes with
+	// a table summarising the total and key timings the demo recorded.
+	Runs int
+
+	// Stepper, if not nil, is called at every annotated checkpoint in the
+	// example, for instance to pause until the user presses Enter.
+	Stepper console.Stepper
+
+	// Deterministic runs the example against a virtual clock and a random
+	// source with a fixed seed, with GOMAXPROCS set to 1 so goroutines do not
+	// run in parallel, so that its output is the same on every run. Only
+	// examples that use the injected clock and random source are affected;
+	// see the clock and rng packages, and Reproducible for the exceptions.
+	// A binary built with -race shuffles the order goroutines run in, so
+	// there the output can still vary.
+	Deterministic bool
+
+	// Chaos enables chaos.Default while the example runs, randomly delaying
+	// goroutines at prints and instrumented channel operations.
+	Chaos bool
+
+	// Format is FormatText (the default if empty) or FormatJSON, in which
+	// case every line the example prints and every checkpoint it reaches is
+	// written to w as a JSON Event.
+	Format string
+
+	// Handler, if not nil, receives the records the example logs through
+	// console.Log. By default they are printed as plain lines of output.
+	Handler slog.Handler
+
+	// Timeout, if positive, is how long the example may run. When it expires
+	// Run abandons the example, frees the runner for the next one and returns
+	// a *TimeoutError describing the goroutines the example left behind.
+	Timeout time.Duration
+}
+
+// Settings for deterministic runs
+const deterministicSeed = 42
+
+var deterministicEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// leakGracePeriod is how long to wait for goroutines to wind down after a run
+const leakGracePeriod = 500 * time.Millisecond
+
+// running serializes Run calls, because all demos share console.Out
+var running = make(chan struct{}, 1)
+
+// Run executes the named example (or menu number) and writes its output to w.
+//
+// Each call is recorded as a runtime/trace task named after the example, with
+// a region per run, which shows up when an execution trace is being collected.
+//
+// Only one example runs at a time; concurrent calls wait their turn. The demos
+// themselves are not cancellable, so if ctx is done before the example
+// finishes Run returns ctx's error straight away and the abandoned demo keeps
+// running in the background with its remaining output discarded.
+//
+// An example abandoned because of Options.Timeout also gives up the runner,
+// so the next Run does not wait for it. The console goes back to the
+// caller's writer, and anything the example's goroutines still print is
+// discarded, though it may show up in the output of a later example that is
+// running at the time.
+//
+// The number of goroutines is compared before and after the example, and an
+// example that leaves goroutines running is flagged in its output.
+//
+// A panic in the goroutine running the example is returned as an error. A
+// panic in a goroutine the example started still crashes the program.
+func Run(ctx context.Context, name string, w io.Writer, opts Options) error {
+	e, ok := lookup(name)
+	if !ok {
+		return fmt.Errorf("examples: unknown example %q", name)
+	}
+	if opts.Format != "" && opts.Format != FormatText && opts.Format != FormatJSON {
+		return fmt.Errorf("examples: unknown output format %q", opts.Format)
+	}
+
+	select {
+	case running <- struct{}{}:
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+
+	// The watchdog starts once the example has the runner
+	var timedOut *TimeoutError
+	if opts.Timeout > 0 {
+		timedOut = &TimeoutError{Name: e.Name, Timeout: opts.Timeout}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, opts.Timeout, timedOut)
+		defer cancel()
+	}
+
+	out := &abandonableWriter{w: w}
+	var dest io.Writer = out
+	stepper := opts.Stepper
+	var events *eventWriter
+	if opts.Format == FormatJSON {
+		events = newEventWriter(out, e.Name, opts.Stepper)
+		dest, stepper = events, events
+	}
+	prev := console.SetOutput(dest)
+	prevStepper := console.SetStepper(stepper)
+	prevHandler := console.SetHandler(opts.Handler)
+	restore := func() {}
+	if opts.Deterministic {
+		prevClock := clock.SetCurrent(clock.NewVirtual(deterministicEpoch))
+		prevSource := rng.SetSource(rand.NewPCG(deterministicSeed, deterministicSeed))
+		prevProcs := runtime.GOMAXPROCS(1)
+		restore = func() {
+			clock.SetCurrent(prevClock)
+			rng.SetSource(prevSource)
+			runtime.GOMAXPROCS(prevProcs)
+		}
+	}
+	if opts.Chaos {
+		prevChaos := chaos.Set(&chaos.Default)
+		restoreClock := restore
+		restore = func() {
+			chaos.Set(prevChaos)
+			restoreClock()
+		}
+	}
+
+	// release puts the shared state back and frees the runner, either when
+	// the example returns or when the watchdog gives up on it. The timings
+	// the example recorded are dropped, so they do not pile up in a long
+	// session; runRepeated takes them before then.
+	var releaseOnce sync.Once
+	release := func(output io.Writer) {
+		releaseOnce.Do(func() {
+			stats.Reset()
+			restore()
+			console.SetHandler(prevHandler)
+			console.SetStepper(prevStepper)
+			console.SetOutput(output)
+			<-running
+		})
+	}
+
+	var runErr error
+	done := make(chan struct{})
+	go func() {
+		defer release(prev)
+		defer close(done)
+		if events != nil {
+			defer events.flush()
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				runErr = fmt.Errorf("examples: %s panicked: %v", e.Name, r)
+			}
+		}()
+
+		tctx, task := trace.NewTask(ctx, e.Name)
+		defer task.End()
+		trace.Log(tctx, "title", e.Title)
+
+		before := runtime.NumGoroutine()
+		pprof.Do(tctx, pprof.Labels(exampleLabel, e.Name), func(tctx context.Context) {
+			if opts.Runs > 1 {
+				runRepeated(tctx, dest, e, opts.Runs)
+			} else {
+				trace.WithRegion(tctx, "run", e.run)
+			}
+		})
+		if opts.Runs <= 1 {
+			reportLeaks(dest, e, before, opts.Deterministic)
+		}
+	}()
+
+	select {
+	case <-done:
+		return runErr
+	case <-ctx.Done():
+		cause := context.Cause(ctx)
+		if timedOut == nil || !errors.Is(cause, timedOut) {
+			out.abandoned.Store(true)
+			return cause
+		}
+		select {
+		case <-done:
+			return runErr // Finished just in time
+		default:
+		}
+		out.abandoned.Store(true)
+		timedOut.Goroutines, timedOut.Stuck = stuckGoroutines(e)
+		release(&abandonedOutput{w: prev, name: e.Name})
+		return timedOut
+	}
+}
+
+// abandonableWriter forwards to w until the run it belongs to is abandoned,
+// after which all writes are silently dropped.
+type abandonableWriter struct {
+	w         io.Writer
+	abandoned atomic.Bool
+}
+
+func (a *abandonableWriter) Write(p []byte) (int, error) {
+	if a.abandoned.Load() {
+		return len(p), nil
+	}
+	return a.w.Write(p)
+}
+
+// reportLeaks compares the goroutine count with the count before the example
+// started and, if the example left goroutines behind, flags it and lists
+// where they are. The counts and locations include whatever earlier runs in
+// the same process left behind, so a deterministic run only flags the leak.
+func reportLeaks(w io.Writer, e entry, before int, deterministic bool) {
+	leaked := leakedGoroutines(before)
+	if leaked == 0 {
+		return
+	}
+	if deterministic {
+		fmt.Fprintf(w, "[leak] %s left goroutines running\n\n", e.Name)
+		return
+	}
+	fmt.Fprintf(w, "[leak] %s left %d goroutine(s) running (%d before, %d after):\n",
+		e.Name, leaked, before, before+leaked)
+	_, stuck := stuckGoroutines(e)
+	for _, s := range stuck {
+		fmt.Fprintf(w, "  %s\n", s)
+	}
+	fmt.Fprintln(w)
+}
+
+// runRepeated runs an example n times, checking for leaked goroutines after
+// each run, and prints a summary of the total and recorded key timings.
+func runRepeated(ctx context.Context, w io.Writer, e entry, n int) {
+	var labels []string
+	timings := map[string][]time.Duration{}
+	add := func(label string, d time.Duration) {
+		if _, ok := timings[label]; !ok {
+			labels = append(labels, label)
+		}
+		timings[label] = append(timings[label], d)
+	}
+
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(w, "--- Run %d of %d ---\n", i, n)
+
+		stats.Reset()
+		before := runtime.NumGoroutine()
+		start := time.Now()
+
+		trace.WithRegion(ctx, fmt.Sprintf("run %d", i), e.run)
+
+		add("total", time.Since(start))
+		for _, s := range stats.Take() {
+			add(s.Label, s.Duration)
+		}
+
+		if leaked := leakedGoroutines(before); leaked > 0 {
+			fmt.Fprintf(w, "Run %d: %d goroutine(s) still running after the demo returned\n", i, leaked)
+		}
+	}
+
+	fmt.Fprintf(w, "\nTiming statistics over %d runs:\n", n)
+	fmt.Fprintf(w, "%-32s %12s %12s %12s %12s\n", "timing", "mean", "stddev", "min", "max")
+	for _, label := range labels {
+		s := stats.Summarize(timings[label])
+		fmt.Fprintf(w, "%-32s %12v %12v %12v %12v\n", label,
+			s.Mean.Round(time.Microsecond), s.StdDev.Round(time.Microsecond),
+			s.Min.Round(time.Microsecond), s.Max.Round(time.Microsecond))
+	}
+	fmt.Fprintln(w)
+}
+
+// leakedGoroutines waits up to leakGracePeriod for the goroutine count to
+// return to the given baseline and returns how many goroutines remain above it.
+func leakedGoroutines(baseline int) int {
+	deadline := time.Now().Add(leakGracePeriod)
+	for {
+		extra := runtime.NumGoroutine() - baseline
+		if extra <= 0 || time.Now().After(deadline) {
+			return max(extra, 0)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
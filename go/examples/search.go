@@ -0,0 +1,68 @@
+package examples
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// Match is an example found by Search.
+type Match struct {
+	Example
+	// Snippet is the first comment line in the example's source containing
+	// the search term, or empty if only the name or title matched.
+	Snippet string
+	// Hits is the number of comment lines containing the search term.
+	Hits int
+}
+
+// Search returns the examples whose name, title or source comments (the file
+// and demo descriptions included) contain term, ignoring case, in menu order.
+func Search(term string) []Match {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return nil
+	}
+
+	var matches []Match
+	for _, e := range registry {
+		m := Match{Example: e.Example}
+		for _, line := range commentLines(e) {
+			if strings.Contains(strings.ToLower(line), term) {
+				if m.Hits == 0 {
+					m.Snippet = line
+				}
+				m.Hits++
+			}
+		}
+
+		if m.Hits > 0 || strings.Contains(e.Name, term) || strings.Contains(strings.ToLower(e.Title), term) {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// commentLines returns the non-empty lines of every comment in the source of
+// e, with comment markers and leading asterisks removed.
+func commentLines(e entry) []string {
+	file, src, ok := source(e)
+	if !ok {
+		return nil
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), file, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var lines []string
+	for _, group := range f.Comments {
+		for _, line := range strings.Split(group.Text(), "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*"))
+			if line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,42 @@
+package examples
+
+import (
+	"io/fs"
+	"path"
+	"reflect"
+	"runtime"
+
+	"threads/advanced"
+	"threads/basic"
+)
+
+// Source returns the path (relative to the module root) and the contents of
+// the source file that defines the named example (or menu number).
+func Source(nameOrNumber string) (file string, src []byte, ok bool) {
+	e, ok := lookup(nameOrNumber)
+	if !ok {
+		return "", nil, false
+	}
+	return source(e)
+}
+
+// source finds the file defining e's run function in the embedded sources of
+// its package.
+func source(e entry) (string, []byte, bool) {
+	fn := runtime.FuncForPC(reflect.ValueOf(e.run).Pointer())
+	if fn == nil {
+		return "", nil, false
+	}
+	file, _ := fn.FileLine(fn.Entry())
+	file = path.Base(file)
+
+	var sources fs.FS = advanced.Sources
+	if e.Category == Basic {
+		sources = basic.Sources
+	}
+	src, err := fs.ReadFile(sources, file)
+	if err != nil {
+		return "", nil, false
+	}
+	return path.Join(e.Category, file), src, true
+}
@@ -0,0 +1,77 @@
+package examples
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+)
+
+// StressReport summarises a Stress call.
+type StressReport struct {
+	// Runs is the number of runs that were started.
+	Runs int
+	// Failures counts runs that panicked.
+	Failures int
+	// Leaks counts runs that left goroutines behind.
+	Leaks int
+	// HungAt is the run that did not finish within the timeout, or 0.
+	HungAt int
+	// DistinctOutputs is the number of different outputs observed, a rough
+	// measure of how much the example's behavior depends on scheduling.
+	DistinctOutputs int
+}
+
+// Stress runs the named example up to n times with chaos enabled, checking
+// every run for panics, leaked goroutines and hangs, and prints a line per
+// problem to w. A run that takes longer than timeout is reported as hung and
+// ends the stress test, since the abandoned run still holds the example
+// runner. The returned error is non-nil if any run failed.
+func Stress(ctx context.Context, name string, n int, timeout time.Duration, w io.Writer) (StressReport, error) {
+	var report StressReport
+	if _, ok := lookup(name); !ok {
+		return report, fmt.Errorf("examples: unknown example %q", name)
+	}
+
+	outputs := map[string]bool{}
+	for i := 1; i <= n; i++ {
+		report.Runs = i
+
+		var out bytes.Buffer
+		before := runtime.NumGoroutine()
+
+		runCtx, cancel := context.WithTimeoutCause(ctx, timeout, errHung)
+		err := Run(runCtx, name, &out, Options{Chaos: true})
+		cancel()
+
+		switch {
+		case errors.Is(err, errHung):
+			report.HungAt = i
+			fmt.Fprintf(w, "Run %d: no result after %v (deadlock or livelock?)\n", i, timeout)
+			return report, fmt.Errorf("examples: %s hung in run %d", name, i)
+		case ctx.Err() != nil:
+			return report, context.Cause(ctx)
+		case err != nil:
+			report.Failures++
+			fmt.Fprintf(w, "Run %d: %v\n", i, err)
+		}
+
+		if leaked := leakedGoroutines(before); leaked > 0 {
+			report.Leaks++
+			fmt.Fprintf(w, "Run %d: %d goroutine(s) still running after the demo returned\n", i, leaked)
+		}
+		outputs[out.String()] = true
+	}
+	report.DistinctOutputs = len(outputs)
+
+	if report.Failures > 0 || report.Leaks > 0 {
+		return report, fmt.Errorf("examples: %s failed in %d and leaked in %d of %d runs",
+			name, report.Failures, report.Leaks, report.Runs)
+	}
+	return report, nil
+}
+
+var errHung = errors.New("run timed out")
@@ -0,0 +1,137 @@
+package examples
+
+import "slices"
+
+// Tags group examples by topic. An example may have several tags; the first
+// is its primary topic.
+const (
+	TagChannels    = "channels"
+	TagSync        = "sync"
+	TagScheduling  = "scheduling"
+	TagPatterns    = "patterns"
+	TagPerformance = "performance"
+)
+
+// AllTags returns every tag, in the order topics are presented.
+func AllTags() []string {
+	return []string{TagChannels, TagSync, TagScheduling, TagPatterns, TagPerformance}
+}
+
+// tags maps example names to their tags, primary tag first
+var tags = map[string][]string{
+	"goroutines":        {TagScheduling},
+	"channels":          {TagChannels},
+	"buffered-channels": {TagChannels},
+	"wait-group":        {TagSync},
+	"select":            {TagChannels},
+	"mutex":             {TagSync},
+
+	"channel-ownership":        {TagChannels, TagPatterns},
+	"fan-out-fan-in":           {TagPatterns, TagChannels},
+	"cancellation":             {TagPatterns, TagChannels},
+	"or-channel":               {TagPatterns, TagChannels},
+	"tee-channel":              {TagPatterns, TagChannels},
+	"dynamic-buffer-sizing":    {TagChannels, TagPerformance},
+	"channel-semaphore":        {TagChannels, TagSync},
+	"dropping-channel":         {TagChannels},
+	"ring-buffer":              {TagChannels},
+	"batch-processing":         {TagPatterns, TagPerformance},
+	"priority-select":          {TagChannels},
+	"select-send-receive":      {TagChannels, TagPatterns},
+	"nil-channel-select":       {TagChannels},
+	"rwmutex":                  {TagSync},
+	"atomic-operations":        {TagSync},
+	"sync-once":                {TagSync},
+	"try-lock":                 {TagSync},
+	"scheduling-hints":         {TagScheduling},
+	"waitgroup-error-handling": {TagSync, TagPatterns},
+	"dynamic-waitgroup":        {TagSync},
+	"waitgroup-timeout":        {TagSync},
+	"worker-pool":              {TagPatterns},
+	"parallel-foreach":         {TagPatterns},
+	"lockfree-queue":           {TagPerformance, TagSync},
+	"spinlock":                 {TagSync, TagPerformance},
+	"sharded-counter":          {TagPerformance, TagSync},
+	"typed-atomics":            {TagSync},
+	"atomic-value-config":      {TagSync, TagPatterns},
+	"memory-model":             {TagSync},
+	"select-fairness":          {TagChannels, TagScheduling},
+	"timer-ticker-pitfalls":    {TagChannels, TagPerformance},
+	"adaptive-worker-pool":     {TagPatterns, TagScheduling},
+	"priority-worker-pool":     {TagPatterns, TagScheduling},
+	"keyed-worker-pool":        {TagPatterns},
+	"work-stealing":            {TagScheduling, TagPatterns},
+	"batch-size-or-timeout":    {TagPatterns, TagChannels},
+	"stream-windows":           {TagPatterns, TagChannels},
+	"conflating-channel":       {TagChannels},
+	"sliding-buffer":           {TagChannels},
+	"backpressure":             {TagPatterns, TagChannels, TagPerformance},
+	"load-shedding":            {TagPatterns, TagPerformance},
+	"load-balancer":            {TagPatterns, TagScheduling},
+	"supervisor":               {TagPatterns},
+	"safe-goroutines":          {TagPatterns},
+	"structured-concurrency":   {TagPatterns},
+	"context-cause":            {TagPatterns},
+	"context-afterfunc":        {TagPatterns},
+	"once-helpers":             {TagSync},
+	"mutex-trylock":            {TagSync},
+	"countdown-latch":          {TagSync},
+	"exchanger":                {TagSync, TagChannels},
+	"cpu-vs-io-bound":          {TagScheduling, TagPerformance},
+	"runtime-metrics":          {TagPerformance, TagScheduling},
+	"contention-workload":      {TagPerformance, TagSync},
+	"instrumented-channels":    {TagPerformance, TagChannels},
+	"tcp-chat":                 {TagPatterns, TagChannels},
+	"http-server":              {TagPatterns, TagSync},
+	"file-pipeline":            {TagPatterns, TagScheduling},
+	"streaming-records":        {TagPatterns, TagChannels},
+	"lru-cache":                {TagSync, TagPerformance},
+	"ttl-cache":                {TagPatterns, TagSync},
+	"sharded-map":              {TagSync, TagPerformance},
+	"copy-on-write":            {TagSync, TagPatterns},
+	"monitor-goroutine":        {TagChannels, TagPatterns},
+	"order-fsm":                {TagPatterns, TagChannels},
+	"event-loop":               {TagPatterns, TagChannels},
+	"result-pipeline":          {TagPatterns, TagChannels},
+	"channel-closing":          {TagChannels},
+	"timeout-helpers":          {TagChannels, TagPatterns},
+	"merge-contexts":           {TagPatterns},
+	"delay-queue":              {TagPatterns, TagScheduling},
+	"recurring-scheduler":      {TagPatterns, TagScheduling},
+	"job-queue":                {TagPatterns, TagSync},
+	"outbox-inbox":             {TagPatterns, TagChannels},
+	"two-phase-commit":         {TagPatterns, TagChannels},
+	"leader-election":          {TagPatterns, TagChannels},
+	"gossip":                   {TagPatterns, TagChannels, TagPerformance},
+	"raft-replication":         {TagPatterns, TagChannels},
+	"vector-clocks":            {TagPatterns, TagChannels},
+	"crdt-counter":             {TagPatterns, TagChannels},
+	"chandy-lamport":           {TagPatterns, TagChannels},
+	"token-ring":               {TagPatterns, TagSync},
+	"ping-pong":                {TagChannels, TagPerformance},
+	"goroutine-scaling":        {TagScheduling, TagPerformance},
+	"thread-bound-api":         {TagScheduling, TagPatterns},
+	"pause-resume":             {TagPatterns, TagChannels},
+	"quiescence":               {TagPatterns, TagSync},
+}
+
+// Tags returns the tags of the named example (or menu number), primary tag
+// first.
+func Tags(nameOrNumber string) []string {
+	e, ok := lookup(nameOrNumber)
+	if !ok {
+		return nil
+	}
+	return slices.Clone(tags[e.Name])
+}
+
+// WithTag returns the examples with the given tag, in menu order.
+func WithTag(tag string) []Example {
+	var list []Example
+	for _, e := range registry {
+		if slices.Contains(tags[e.Name], tag) {
+			list = append(list, e.Example)
+		}
+	}
+	return list
+}
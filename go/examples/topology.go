@@ -0,0 +1,52 @@
+package examples
+
+import (
+	"threads/advanced"
+	"threads/topology"
+)
+
+// topologies maps example names to functions describing their goroutine and
+// channel structure. Only patterns with an interesting shape are listed.
+var topologies = map[string]func() *topology.Graph{
+	"channel-ownership":     advanced.ChannelOwnershipTopology,
+	"fan-out-fan-in":        advanced.FanOutFanInTopology,
+	"or-channel":            advanced.OrChannelTopology,
+	"tee-channel":           advanced.TeeChannelTopology,
+	"worker-pool":           advanced.WorkerPoolTopology,
+	"backpressure":          advanced.BackpressureTopology,
+	"load-balancer":         advanced.LoadBalancerTopology,
+	"instrumented-channels": advanced.InstrumentedChannelsTopology,
+	"tcp-chat":              advanced.TCPChatTopology,
+	"file-pipeline":         advanced.FilePipelineTopology,
+	"delay-queue":           advanced.DelayQueueTopology,
+	"outbox-inbox":          advanced.OutboxInboxTopology,
+	"chandy-lamport":        advanced.ChandyLamportTopology,
+}
+
+// Topology returns the goroutine and channel topology of the named example
+// (or menu number). It reports false if the example does not exist or does
+// not describe its topology.
+func Topology(nameOrNumber string) (*topology.Graph, bool) {
+	e, ok := lookup(nameOrNumber)
+	if !ok {
+		return nil, false
+	}
+
+	describe, ok := topologies[e.Name]
+	if !ok {
+		return nil, false
+	}
+	return describe(), true
+}
+
+// WithTopology returns the examples that describe their topology, in menu
+// order.
+func WithTopology() []Example {
+	var list []Example
+	for _, e := range registry {
+		if _, ok := topologies[e.Name]; ok {
+			list = append(list, e.Example)
+		}
+	}
+	return list
+}
@@ -0,0 +1,149 @@
+package examples
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// exampleLabel is the pprof label that Run attaches to an example's
+// goroutines. Goroutines inherit labels from the goroutine that starts them,
+// so every goroutine an example creates carries it.
+const exampleLabel = "example"
+
+// TimeoutError is returned by Run when an example does not finish within
+// Options.Timeout.
+type TimeoutError struct {
+	Name    string
+	Timeout time.Duration
+
+	// Goroutines is how many of the example's goroutines were still running
+	// when it timed out, including the one running the example itself.
+	Goroutines int
+
+	// Stuck describes where those goroutines were, one line per distinct
+	// stack, such as "1 goroutine(s) in time.Sleep, called from
+	// advanced.WaitGroupTimeoutDemo.func1 (26_waitgroup_timeout.go:36)".
+	Stuck []string
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("examples: %s timed out after %v with %d goroutine(s) still running",
+		e.Name, e.Timeout, e.Goroutines)
+}
+
+// stuckGoroutines finds the goroutines labelled with e's name in the
+// goroutine profile and returns their total and a line per distinct stack.
+func stuckGoroutines(e entry) (int, []string) {
+	var profile bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&profile, 1); err != nil {
+		return 0, nil
+	}
+
+	// Where a goroutine is blocked is usually in the standard library, so the
+	// line also names the innermost call from the example's own file
+	var demoFile string
+	if fn := runtime.FuncForPC(reflect.ValueOf(e.run).Pointer()); fn != nil {
+		file, _ := fn.FileLine(fn.Entry())
+		demoFile = filepath.Base(file)
+	}
+
+	// With debug=1 the profile is a header followed by blank-line separated
+	// records: a "count @ pcs" line, an optional "# labels: {...}" line and a
+	// "#\tpc\tfunction+offset\tfile:line" line per frame, innermost first.
+	label := fmt.Sprintf("%q:%q", exampleLabel, e.Name)
+	total := 0
+	var stuck []string
+	for _, record := range strings.Split(profile.String(), "\n\n") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		if len(lines) < 3 || !strings.HasPrefix(lines[1], "# labels: ") || !strings.Contains(lines[1], label) {
+			continue
+		}
+		countField, _, _ := strings.Cut(lines[0], " ")
+		count, err := strconv.Atoi(countField)
+		if err != nil {
+			continue
+		}
+		total += count
+
+		var frames [][2]string // function and file:line
+		for _, line := range lines[2:] {
+			fields := strings.Fields(strings.TrimPrefix(line, "#"))
+			if len(fields) < 3 {
+				continue
+			}
+			function, _, _ := strings.Cut(fields[1], "+")
+			frames = append(frames, [2]string{function[strings.LastIndex(function, "/")+1:], filepath.Base(fields[2])})
+		}
+		if len(frames) == 0 {
+			continue
+		}
+
+		where := fmt.Sprintf("%d goroutine(s) in %s (%s)", count, frames[0][0], frames[0][1])
+		for i, f := range frames {
+			if strings.HasPrefix(f[1], demoFile+":") {
+				if i > 0 {
+					where = fmt.Sprintf("%d goroutine(s) in %s, called from %s (%s)", count, frames[0][0], f[0], f[1])
+				}
+				break
+			}
+		}
+		stuck = append(stuck, where)
+	}
+	return total, stuck
+}
+
+// abandonedOutput is the console output after an example times out. It
+// forwards to the writer the caller had before the example started, except
+// for writes from goroutines still labelled with the abandoned example,
+// which it drops.
+type abandonedOutput struct {
+	w    io.Writer
+	name string
+	gone atomic.Bool // None of the example's goroutines are left
+}
+
+func (a *abandonedOutput) Write(p []byte) (int, error) {
+	if !a.gone.Load() {
+		mine, running := a.writerLabelled()
+		if mine {
+			return len(p), nil
+		}
+		if !running {
+			a.gone.Store(true)
+		}
+	}
+	return a.w.Write(p)
+}
+
+// writerLabelled reports whether the goroutine calling Write carries the
+// abandoned example's label, and whether any goroutine still does. Writes to
+// console.Out are serialized, so the only goroutine in the goroutine profile
+// inside Write is the one calling it.
+func (a *abandonedOutput) writerLabelled() (mine, running bool) {
+	var profile bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&profile, 1); err != nil {
+		return false, true
+	}
+	// The first record follows the profile's header line, so the labels are
+	// looked for anywhere in the record
+	label := fmt.Sprintf("# labels: {%q:%q}", exampleLabel, a.name)
+	for _, record := range strings.Split(profile.String(), "\n\n") {
+		if !strings.Contains(record, label) {
+			continue
+		}
+		running = true
+		if strings.Contains(record, "(*abandonedOutput).Write") {
+			mine = true
+		}
+	}
+	return mine, running
+}
@@ -0,0 +1,54 @@
+/**
+ * The --exercise flag.
+ *
+ * --exercise=N prints the task for "find the bug" exercise N and runs its
+ * test with the race detector. The test fails until the code in the exercises
+ * directory is fixed. The tests are run with the go command, so this mode
+ * needs the Go toolchain and the source tree this binary was built from.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"threads/exercises"
+)
+
+// runExercise runs the test of exercise n and returns the exit code
+func runExercise(n int) int {
+	ex, ok := exercises.Lookup(n)
+	if !ok {
+		fmt.Printf("Unknown exercise %d. Available exercises:\n", n)
+		for _, ex := range exercises.List() {
+			fmt.Printf("  %d. %s (%s)\n", ex.Number, ex.Title, ex.File)
+		}
+		return 2
+	}
+
+	// The exercises live next to this file in the source tree
+	_, self, _, _ := runtime.Caller(0)
+	dir := filepath.Dir(self)
+
+	fmt.Printf("\nExercise %d: %s\n", ex.Number, ex.Title)
+	fmt.Println(ex.Task)
+	fmt.Printf("Fix:  %s\n", filepath.Join(dir, "exercises", ex.File))
+	fmt.Printf("Hint: %s\n\n", ex.Hint)
+
+	cmd := exec.Command("go", "test", "-tags", "exercises", "-race", "-count=1",
+		"-run", "^"+ex.Test+"$", "./exercises")
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		fmt.Printf("\nNot fixed yet (%v). Edit %s and run --exercise=%d again.\n", err, ex.File, ex.Number)
+		return 1
+	}
+	fmt.Println("\nFixed! The test passes.")
+	return 0
+}
@@ -0,0 +1,23 @@
+package exercises
+
+import "time"
+
+// Replica answers queries after a delay.
+type Replica struct {
+	Name  string
+	Delay time.Duration
+}
+
+// First sends query to every replica and returns the first answer.
+//
+// BUG: each call leaks goroutines.
+func First(query string, replicas []Replica) string {
+	answers := make(chan string)
+	for _, r := range replicas {
+		go func() {
+			time.Sleep(r.Delay)
+			answers <- r.Name + ": " + query
+		}()
+	}
+	return <-answers
+}
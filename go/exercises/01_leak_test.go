@@ -0,0 +1,29 @@
+//go:build exercises
+
+package exercises
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLeakyFirst(t *testing.T) {
+	replicas := []Replica{
+		{"fast", time.Millisecond},
+		{"medium", 20 * time.Millisecond},
+		{"slow", 40 * time.Millisecond},
+	}
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		if got := First("ping", replicas); !strings.HasPrefix(got, "fast: ") {
+			t.Fatalf("First = %q, want the fast replica's answer", got)
+		}
+	}
+
+	if leaked := leakedGoroutines(before); leaked > 0 {
+		t.Fatalf("%d goroutine(s) still running after First returned", leaked)
+	}
+}
@@ -0,0 +1,36 @@
+package exercises
+
+import "sync"
+
+// Counter counts events reported by many goroutines.
+//
+// BUG: concurrent calls to Inc race.
+type Counter struct {
+	n int
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() {
+	c.n++
+}
+
+// Value returns the current count.
+func (c *Counter) Value() int {
+	return c.n
+}
+
+// CountConcurrently increments c from workers goroutines, each calling Inc
+// times, and waits for all of them.
+func CountConcurrently(c *Counter, workers, times int) {
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < times; i++ {
+				c.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+}
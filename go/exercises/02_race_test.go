@@ -0,0 +1,18 @@
+//go:build exercises
+
+package exercises
+
+import "testing"
+
+// Run with -race: the race detector fails the test as soon as two
+// goroutines touch the counter without synchronisation.
+func TestRacyCounter(t *testing.T) {
+	const workers, times = 8, 1000
+
+	var c Counter
+	CountConcurrently(&c, workers, times)
+
+	if got := c.Value(); got != workers*times {
+		t.Fatalf("Value = %d, want %d", got, workers*times)
+	}
+}
@@ -0,0 +1,32 @@
+package exercises
+
+import (
+	"sort"
+	"sync"
+)
+
+// SquareAll squares every number in a separate goroutine and returns the
+// squares in ascending order.
+//
+// BUG: never returns.
+func SquareAll(nums []int) []int {
+	results := make(chan int)
+	var wg sync.WaitGroup
+	for _, n := range nums {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- n * n
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+
+	var squares []int
+	for sq := range results {
+		squares = append(squares, sq)
+	}
+	sort.Ints(squares)
+	return squares
+}
@@ -0,0 +1,20 @@
+//go:build exercises
+
+package exercises
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSquareAll(t *testing.T) {
+	var got []int
+	finishWithin(t, 2*time.Second, func() {
+		got = SquareAll([]int{3, 1, 2})
+	})
+
+	if want := []int{1, 4, 9}; !slices.Equal(got, want) {
+		t.Fatalf("SquareAll = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,18 @@
+package exercises
+
+// Merge forwards every value from the inputs to a single output channel,
+// which is closed once all inputs are drained.
+//
+// BUG: panics when there is more than one input.
+func Merge(inputs ...<-chan int) <-chan int {
+	out := make(chan int)
+	for _, in := range inputs {
+		go func() {
+			defer close(out)
+			for v := range in {
+				out <- v
+			}
+		}()
+	}
+	return out
+}
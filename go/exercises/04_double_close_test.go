@@ -0,0 +1,37 @@
+//go:build exercises
+
+package exercises
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// source returns a channel that yields vals and is then closed
+func source(vals ...int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for _, v := range vals {
+			ch <- v
+		}
+	}()
+	return ch
+}
+
+// A panic in one of Merge's goroutines crashes the whole test binary, which
+// is what the bug looks like in production too.
+func TestMerge(t *testing.T) {
+	var got []int
+	finishWithin(t, 2*time.Second, func() {
+		for v := range Merge(source(1, 2, 3), source(4, 5), source(6)) {
+			got = append(got, v)
+		}
+	})
+
+	slices.Sort(got)
+	if want := []int{1, 2, 3, 4, 5, 6}; !slices.Equal(got, want) {
+		t.Fatalf("Merge produced %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+package exercises
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotFound is returned by Store.Get for keys that were never set.
+var ErrNotFound = errors.New("exercises: key not found")
+
+// Store is a map that is safe for concurrent use.
+//
+// BUG: the store stops responding after a lookup of a missing key.
+type Store struct {
+	mu sync.Mutex
+	m  map[string]string
+}
+
+// Set stores value under key.
+func (s *Store) Set(key, value string) {
+	s.mu.Lock()
+	if s.m == nil {
+		s.m = make(map[string]string)
+	}
+	s.m[key] = value
+	s.mu.Unlock()
+}
+
+// Get returns the value stored under key, or ErrNotFound.
+func (s *Store) Get(key string) (string, error) {
+	s.mu.Lock()
+	v, ok := s.m[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	s.mu.Unlock()
+	return v, nil
+}
@@ -0,0 +1,22 @@
+//go:build exercises
+
+package exercises
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	var s Store
+	finishWithin(t, 2*time.Second, func() {
+		if _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+		}
+		s.Set("answer", "42")
+		if v, err := s.Get("answer"); err != nil || v != "42" {
+			t.Errorf("Get(answer) = %q, %v, want 42", v, err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+// Package exercises contains deliberately broken concurrent code for
+// "find the bug" practice.
+//
+// Every exercise is a small function with a classic concurrency bug (a
+// goroutine leak, a data race, a deadlock, a double close, ...) and a test
+// that fails because of it. The tests are behind the exercises build tag so
+// that the normal test suite stays green; run one with
+//
+//	go test -tags exercises -race -run TestLeakyFirst ./exercises
+//
+// or through the CLI with --exercise N, then edit the code until it passes.
+package exercises
+
+// Exercise describes one broken function and the test that exposes it.
+type Exercise struct {
+	// Number is the exercise number used by --exercise.
+	Number int
+	// Title is a short human-readable name.
+	Title string
+	// File is the source file, relative to this package, to fix.
+	File string
+	// Test is the name of the test function that fails until the bug is fixed.
+	Test string
+	// Task describes what the code is meant to do and how it misbehaves.
+	Task string
+	// Hint points towards the fix without giving it away.
+	Hint string
+}
+
+var exercises = []Exercise{
+	{
+		Number: 1,
+		Title:  "Goroutine Leak",
+		File:   "01_leak.go",
+		Test:   "TestLeakyFirst",
+		Task:   "First queries several replicas and returns the fastest answer, but every call leaves goroutines behind.",
+		Hint:   "What happens to the replicas that answer after First has already returned?",
+	},
+	{
+		Number: 2,
+		Title:  "Data Race",
+		File:   "02_race.go",
+		Test:   "TestRacyCounter",
+		Task:   "Counter is incremented from many goroutines; the race detector reports a data race and counts are lost.",
+		Hint:   "Which goroutines read and write n, and what orders those accesses?",
+	},
+	{
+		Number: 3,
+		Title:  "Deadlock",
+		File:   "03_deadlock.go",
+		Test:   "TestSquareAll",
+		Task:   "SquareAll squares numbers in parallel and collects the results, but it never returns.",
+		Hint:   "Who receives from results while the workers are sending, and who is waiting for whom?",
+	},
+	{
+		Number: 4,
+		Title:  "Double Close",
+		File:   "04_double_close.go",
+		Test:   "TestMerge",
+		Task:   "Merge combines several input channels into one, but it panics with \"close of closed channel\".",
+		Hint:   "A channel may be closed only once: which goroutine knows that every sender has finished?",
+	},
+	{
+		Number: 5,
+		Title:  "Forgotten Unlock",
+		File:   "05_forgotten_unlock.go",
+		Test:   "TestStore",
+		Task:   "Store is a mutex-protected map, but after looking up a missing key every later call hangs.",
+		Hint:   "Follow every path out of Get: does each one release the lock?",
+	},
+}
+
+// List returns all exercises in order.
+func List() []Exercise {
+	return append([]Exercise(nil), exercises...)
+}
+
+// Lookup returns the exercise with the given number.
+func Lookup(n int) (Exercise, bool) {
+	for _, ex := range exercises {
+		if ex.Number == n {
+			return ex, true
+		}
+	}
+	return Exercise{}, false
+}
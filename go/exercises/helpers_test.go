@@ -0,0 +1,38 @@
+//go:build exercises
+
+package exercises
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// finishWithin runs fn and fails the test if it does not return within d.
+// A call that hangs is left running in the background.
+func finishWithin(t *testing.T, d time.Duration, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(d):
+		t.Fatalf("did not return within %v: deadlock?", d)
+	}
+}
+
+// leakedGoroutines waits up to a second for the goroutine count to drop back
+// to baseline and returns how many goroutines remain above it.
+func leakedGoroutines(baseline int) int {
+	deadline := time.Now().Add(time.Second)
+	for {
+		extra := runtime.NumGoroutine() - baseline
+		if extra <= 0 || time.Now().After(deadline) {
+			return max(extra, 0)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,100 @@
+// Package lockfree contains data structures that synchronize goroutines with
+// atomic compare-and-swap operations instead of locks.
+package lockfree
+
+import "sync/atomic"
+
+// Queue is an unbounded multi-producer, multi-consumer FIFO queue based on
+// the Michael-Scott algorithm.
+//
+// The queue is a singly linked list with a dummy head node. Producers append
+// at the tail and consumers advance the head, each with a CAS loop; a
+// goroutine that finds the tail lagging behind helps move it forward, so no
+// goroutine can block another indefinitely. Go's garbage collector keeps
+// nodes alive while any goroutine still references them, which rules out the
+// ABA problem that manual-memory implementations have to work around.
+//
+// The zero value is not usable; create queues with NewQueue.
+type Queue[T any] struct {
+	head atomic.Pointer[node[T]]
+	tail atomic.Pointer[node[T]]
+	size atomic.Int64
+}
+
+type node[T any] struct {
+	value T
+	next  atomic.Pointer[node[T]]
+}
+
+// NewQueue returns an empty queue.
+func NewQueue[T any]() *Queue[T] {
+	q := &Queue[T]{}
+	dummy := &node[T]{}
+	q.head.Store(dummy)
+	q.tail.Store(dummy)
+	return q
+}
+
+// Enqueue adds v to the back of the queue. It never blocks.
+func (q *Queue[T]) Enqueue(v T) {
+	n := &node[T]{value: v}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+
+		// Make sure tail and next are a consistent snapshot
+		if tail != q.tail.Load() {
+			continue
+		}
+
+		if next == nil {
+			// Tail really is the last node: try to link the new node after it
+			if tail.next.CompareAndSwap(nil, n) {
+				// Swing the tail forward; failure means another goroutine helped
+				q.tail.CompareAndSwap(tail, n)
+				q.size.Add(1)
+				return
+			}
+		} else {
+			// Tail is lagging behind: help the other producer finish
+			q.tail.CompareAndSwap(tail, next)
+		}
+	}
+}
+
+// Dequeue removes and returns the value at the front of the queue. The
+// boolean is false if the queue was empty. It never blocks.
+func (q *Queue[T]) Dequeue() (T, bool) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+
+		if head != q.head.Load() {
+			continue
+		}
+
+		if head == tail {
+			if next == nil {
+				var zero T
+				return zero, false
+			}
+			// A producer linked a node but has not moved the tail yet
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+
+		// Read the value before the CAS; afterwards another consumer may own next
+		v := next.value
+		if q.head.CompareAndSwap(head, next) {
+			q.size.Add(-1)
+			return v, true
+		}
+	}
+}
+
+// Len returns an approximate number of queued items. It is exact only when
+// no other goroutine is using the queue.
+func (q *Queue[T]) Len() int {
+	return int(max(q.size.Load(), 0))
+}
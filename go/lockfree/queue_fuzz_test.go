@@ -0,0 +1,120 @@
+package lockfree
+
+import (
+	"sync"
+	"testing"
+)
+
+// FuzzQueueSequential applies a random sequence of operations to a Queue and
+// to a slice-based model, checking that they agree after every step. Even
+// bytes enqueue, odd bytes dequeue.
+func FuzzQueueSequential(f *testing.F) {
+	f.Add([]byte{0, 2, 1, 4, 1, 1, 1})
+	f.Add([]byte{1, 1, 0, 0, 0, 1, 6, 8, 1, 1})
+
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		q := NewQueue[byte]()
+		var model []byte
+
+		for i, op := range ops {
+			if op%2 == 0 {
+				q.Enqueue(op)
+				model = append(model, op)
+			} else {
+				got, ok := q.Dequeue()
+				if ok != (len(model) > 0) {
+					t.Fatalf("op %d: Dequeue ok = %v with %d items in the model", i, ok, len(model))
+				}
+				if ok {
+					if got != model[0] {
+						t.Fatalf("op %d: Dequeue = %d, want %d (FIFO order)", i, got, model[0])
+					}
+					model = model[1:]
+				}
+			}
+
+			if q.Len() != len(model) {
+				t.Fatalf("op %d: Len = %d, want %d", i, q.Len(), len(model))
+			}
+		}
+	})
+}
+
+// FuzzQueueConcurrent has several producers enqueue distinct values while
+// consumers dequeue concurrently, then checks that every value was received
+// exactly once and that each producer's values kept their relative order.
+func FuzzQueueConcurrent(f *testing.F) {
+	f.Add(uint8(2), uint8(2), uint16(100))
+	f.Add(uint8(4), uint8(1), uint16(1000))
+
+	f.Fuzz(func(t *testing.T, producers, consumers uint8, perProducer uint16) {
+		np, nc, per := int(producers%8)+1, int(consumers%8)+1, int(perProducer%2000)
+		total := np * per
+
+		type item struct{ producer, seq int }
+		q := NewQueue[item]()
+
+		var wg sync.WaitGroup
+		for p := 0; p < np; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for s := 0; s < per; s++ {
+					q.Enqueue(item{p, s})
+				}
+			}()
+		}
+
+		results := make([][]item, nc)
+		var received sync.WaitGroup
+		var mu sync.Mutex
+		count := 0
+		for c := 0; c < nc; c++ {
+			received.Add(1)
+			go func() {
+				defer received.Done()
+				for {
+					mu.Lock()
+					done := count == total
+					mu.Unlock()
+					if done {
+						return
+					}
+					if v, ok := q.Dequeue(); ok {
+						results[c] = append(results[c], v)
+						mu.Lock()
+						count++
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		received.Wait()
+
+		seen := make(map[item]bool, total)
+		for c, got := range results {
+			last := make([]int, np)
+			for i := range last {
+				last[i] = -1
+			}
+			for _, v := range got {
+				if seen[v] {
+					t.Fatalf("item %+v received twice", v)
+				}
+				seen[v] = true
+				// A single consumer must see each producer's items in order
+				if v.seq <= last[v.producer] {
+					t.Fatalf("consumer %d: producer %d item %d after %d", c, v.producer, v.seq, last[v.producer])
+				}
+				last[v.producer] = v.seq
+			}
+		}
+		if len(seen) != total {
+			t.Fatalf("received %d distinct items, want %d", len(seen), total)
+		}
+		if q.Len() != 0 {
+			t.Fatalf("Len = %d after draining, want 0", q.Len())
+		}
+	})
+}
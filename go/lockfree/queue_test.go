@@ -0,0 +1,139 @@
+package lockfree
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestQueueFIFO(t *testing.T) {
+	q := NewQueue[int]()
+	if _, ok := q.Dequeue(); ok {
+		t.Fatal("Dequeue on a new queue reported a value")
+	}
+	for i := range 5 {
+		q.Enqueue(i)
+	}
+	if q.Len() != 5 {
+		t.Errorf("Len = %d, want 5", q.Len())
+	}
+	for i := range 5 {
+		if v, ok := q.Dequeue(); !ok || v != i {
+			t.Fatalf("Dequeue = %d, %v; want %d, true", v, ok, i)
+		}
+	}
+	if _, ok := q.Dequeue(); ok {
+		t.Error("Dequeue on a drained queue reported a value")
+	}
+}
+
+// TestQueueMPMC has producers and consumers use the queue at the same time
+// and checks that every value comes out exactly once. Run it with -race.
+func TestQueueMPMC(t *testing.T) {
+	const producers, consumers, perProducer = 8, 8, 20000
+	const total = producers * perProducer
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+
+	q := NewQueue[int]()
+	var produced sync.WaitGroup
+	for p := range producers {
+		produced.Add(1)
+		go func() {
+			defer produced.Done()
+			for i := range perProducer {
+				q.Enqueue(p*perProducer + i)
+			}
+		}()
+	}
+
+	// Each value has a slot, so a duplicate or a lost value shows up as a
+	// count other than one
+	counts := make([]atomic.Int32, total)
+	var received atomic.Int64
+	var consumed sync.WaitGroup
+	for range consumers {
+		consumed.Add(1)
+		go func() {
+			defer consumed.Done()
+			for received.Load() < total {
+				if v, ok := q.Dequeue(); ok {
+					counts[v].Add(1)
+					received.Add(1)
+				}
+			}
+		}()
+	}
+	produced.Wait()
+	consumed.Wait()
+
+	for v := range counts {
+		if n := counts[v].Load(); n != 1 {
+			t.Fatalf("value %d was received %d times, want once", v, n)
+		}
+	}
+	if _, ok := q.Dequeue(); ok {
+		t.Error("the queue still had values after all were received")
+	}
+}
+
+// mutexQueue is the baseline for the benchmarks: a slice guarded by a mutex
+type mutexQueue[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+func (q *mutexQueue[T]) Enqueue(v T) {
+	q.mu.Lock()
+	q.items = append(q.items, v)
+	q.mu.Unlock()
+}
+
+func (q *mutexQueue[T]) Dequeue() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var zero T
+	if len(q.items) == 0 {
+		return zero, false
+	}
+	v := q.items[0]
+	q.items[0] = zero
+	q.items = q.items[1:]
+	return v, true
+}
+
+// BenchmarkQueue compares the lock-free queue with a buffered channel and a
+// mutex-guarded slice. Every goroutine enqueues a value and dequeues one, so
+// the queues stay short and the cost is the synchronization.
+func BenchmarkQueue(b *testing.B) {
+	b.Run("lockfree", func(b *testing.B) {
+		q := NewQueue[int]()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				q.Enqueue(1)
+				q.Dequeue()
+			}
+		})
+	})
+
+	b.Run("channel", func(b *testing.B) {
+		ch := make(chan int, 1024) // More than the goroutines RunParallel starts
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				ch <- 1
+				<-ch
+			}
+		})
+	})
+
+	b.Run("mutex", func(b *testing.B) {
+		var q mutexQueue[int]
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				q.Enqueue(1)
+				q.Dequeue()
+			}
+		})
+	})
+}
@@ -8,145 +8,335 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"strconv"
+	"slices"
+	"strings"
 
 	"threads/advanced"
-	"threads/basic"
+	"threads/console"
+	"threads/examples"
+	"threads/maxprocs"
 )
 
+// Non-interactive selection of examples by name
+var (
+	runName  = flag.String("run", "", "run the example with this `name` (or menu number) and exit")
+	list     = flag.Bool("list", false, "list the examples and exit")
+	category = flag.String("category", "", "only list or show examples in `category` (basic or advanced)")
+	tag      = flag.String("tag", "", "only list or show examples with `tag` (channels, sync, scheduling, patterns or performance)")
+	timeout  = flag.Duration("timeout", 0, "give up on an example after `duration` and report the goroutines it left running (0 means no limit)")
+)
+
+// runs is the number of times each selected example is executed
+var runs = flag.Int("runs", 1, "run the selected example N times and report timing statistics")
+
+// traceFile is where the execution trace is written, if set
+var traceFile = flag.String("trace", "", "write a runtime/trace execution trace of the session to `file`")
+
+// step pauses the example at each annotated checkpoint until Enter is pressed
+var step = flag.Bool("step", false, "pause at annotated checkpoints and explain each step")
+
+// deterministic runs examples on a virtual clock with a fixed random seed
+var deterministic = flag.Bool("deterministic", false, "use virtual time and a fixed random seed so output is reproducible")
+
+// automaxprocs matches GOMAXPROCS to a container's CPU quota at start-up
+var automaxprocs = flag.Bool("automaxprocs", false, "set GOMAXPROCS from the container's CPU quota (Linux cgroups) before running")
+
+// once runs a single example and exits instead of returning to the menu
+var once = flag.Bool("once", false, "run a single example, given as an argument or chosen from the menu, and exit")
+
+// format selects plain or JSON output for the examples
+var format = flag.String("format", examples.FormatText, "output `format` of the examples: text or json (one event per line)")
+
+// Structured logging of the examples' events
+var (
+	logFormat = flag.String("log-format", "plain", "log record `format`: plain, text or json")
+	logLevel  = flag.String("log-level", "info", "minimum log `level`: debug, info, warn or error")
+)
+
+// tui replaces the menu with a full-screen terminal UI
+var tui = flag.Bool("tui", false, "browse and run the examples in a full-screen terminal UI")
+
+// quiz asks multiple-choice questions after each example
+var quiz = flag.Bool("quiz", false, "ask a short multiple-choice quiz after each example and keep score")
+
+// stress runs the selected example this many times under chaos
+var stress = flag.Int("stress", 0, "run the selected example `N` times with chaos delays, checking for panics, leaks and hangs")
+
+// show names the example whose annotated source is printed
+var show = flag.String("show", "", "print the annotated source of `example` (a name, number or part of a name)")
+
+// playlist names the lesson plan to run instead of the menu
+var playlist = flag.String("playlist", "", "run the lesson plan `name` (channels-101, sync-primitives, pipelines or worker-pools)")
+
+// exercise is the "find the bug" exercise to check
+var exercise = flag.Int("exercise", 0, "show \"find the bug\" exercise `N` and run its test")
+
+// diagram names the example whose topology is printed instead of running it
+var (
+	diagram       = flag.String("diagram", "", "print the goroutine/channel topology of `example` instead of running it")
+	diagramFormat = flag.String("diagram-format", "mermaid", "diagram output `format`: mermaid or dot")
+)
+
+// profiles holds the paths of the requested pprof profiles
+var profiles profileFlags
+
+func init() {
+	flag.StringVar(&profiles.cpu, "cpuprofile", "", "write a CPU profile of the session to `file`")
+	flag.StringVar(&profiles.mem, "memprofile", "", "write a heap profile to `file` on exit")
+	flag.StringVar(&profiles.block, "blockprofile", "", "write a goroutine blocking profile to `file` on exit")
+	flag.StringVar(&profiles.mutex, "mutexprofile", "", "write a mutex contention profile to `file` on exit")
+}
+
 func main() {
-	fmt.Println("Go Concurrency Examples")
-	fmt.Println("======================")
+	os.Exit(runMain())
+}
+
+// runMain runs the program and returns its exit code. Only main calls
+// os.Exit, so the deferred trace and profile writers run on every path.
+func runMain() int {
+	flag.Parse()
+	advanced.CrashCommand = crashCommand
+
+	if flag.Arg(0) == crashSubcommand {
+		runCrash()
+	}
+
+	if *automaxprocs {
+		if procs, _, err := maxprocs.Set(); err != nil {
+			fmt.Fprintf(os.Stderr, "automaxprocs: %v; GOMAXPROCS stays %d\n", err, procs)
+		} else {
+			fmt.Fprintf(os.Stderr, "automaxprocs: GOMAXPROCS set to %d\n", procs)
+		}
+	}
+
+	if *diagram != "" {
+		if err := printDiagram(*diagram, *diagramFormat); err != nil {
+			fmt.Fprintln(os.Stderr, "diagram:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if *show != "" {
+		if err := runShow(*show); err != nil {
+			fmt.Fprintln(os.Stderr, "show:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if *exercise != 0 {
+		return runExercise(*exercise)
+	}
+
+	if *category != "" && *category != examples.Basic && *category != examples.Advanced {
+		fmt.Fprintf(os.Stderr, "unknown category %q (want %s or %s)\n", *category, examples.Basic, examples.Advanced)
+		return 2
+	}
+
+	if err := setupLogging(*logFormat, *logLevel); err != nil {
+		fmt.Fprintln(os.Stderr, "log:", err)
+		return 2
+	}
+
+	if *tag != "" && !slices.Contains(examples.AllTags(), *tag) {
+		fmt.Fprintf(os.Stderr, "unknown tag %q (want one of %s)\n", *tag, strings.Join(examples.AllTags(), ", "))
+		return 2
+	}
 
-	if len(os.Args) > 1 {
-		// If command line argument is provided, run the specified example
-		runExample(os.Args[1])
+	if *list {
+		listExamples()
+		return 0
+	}
+
+	if *playlist != "" {
+		if err := runPlaylist(*playlist); err != nil {
+			fmt.Fprintln(os.Stderr, "playlist:", err)
+			return 2
+		}
+		return 0
+	}
+
+	switch flag.Arg(0) {
+	case "search":
+		return runSearch(strings.Join(flag.Args()[1:], " "))
+	case "serve":
+		runServe(flag.Arg(1))
+		return 0
+	case "compare":
+		return runCompare(flag.Arg(1))
+	}
+
+	// The example to run can be given with --run or as the first argument
+	name := *runName
+	if name == "" && flag.Arg(0) != "bench" {
+		name = flag.Arg(0)
+	}
+
+	if *tui {
+		if err := runTUI(); err != nil {
+			fmt.Fprintln(os.Stderr, "tui:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if *runName == "" {
+		fmt.Println("Go Concurrency Examples")
+		fmt.Println("======================")
+	}
+
+	if *traceFile != "" {
+		stop, err := startTrace(*traceFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "trace:", err)
+			return 1
+		}
+		defer stop()
+	}
+
+	stopProfiling, err := startProfiling(profiles)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "profile:", err)
+		return 1
+	}
+	defer stopProfiling()
+
+	if *stress > 0 {
+		if name == "" {
+			fmt.Println("--stress needs an example to run")
+			return 2
+		}
+		return runStress(name, *stress)
+	}
+
+	if *runName != "" {
+		// Run a single example without the menu
+		ex, ok := examples.Lookup(*runName)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown example %q (see --list)\n", *runName)
+			return 2
+		}
+		if err := run(ex); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+	} else if flag.Arg(0) == "bench" {
+		runBench(flag.Arg(1))
+	} else if *once {
+		// Run the example given as an argument, or one chosen from the menu
+		return runOnce(name)
 	} else {
-		// Otherwise, show the menu
-		showMenu()
+		// Run the example given as an argument, if any, then the menu loop
+		newREPL(os.Stdin).loop(name)
 	}
+	return 0
 }
 
-func showMenu() {
-	fmt.Println("\nBasic Examples:")
-	fmt.Println("1. Goroutines")
-	fmt.Println("2. Channels")
-	fmt.Println("3. Buffered Channels")
-	fmt.Println("4. WaitGroup")
-	fmt.Println("5. Select")
-	fmt.Println("6. Mutex")
-
-	fmt.Println("\nAdvanced Examples:")
-	fmt.Println("11. Channel Ownership")
-	fmt.Println("12. Fan-out, Fan-in")
-	fmt.Println("13. Cancellation Pattern")
-	fmt.Println("14. Or-channel Pattern")
-	fmt.Println("15. Tee Channel Pattern")
-	fmt.Println("16. Dynamic Buffer Sizing")
-	fmt.Println("17. Channel as Semaphore")
-	fmt.Println("18. Dropping Channel")
-	fmt.Println("19. Ring Buffer")
-	fmt.Println("20. Batch Processing")
-	fmt.Println("21. Priority Select")
-	fmt.Println("22. Select with Send/Receive")
-	fmt.Println("23. Nil Channel Select")
-	fmt.Println("24. RWMutex")
-	fmt.Println("25. Atomic Operations")
-	fmt.Println("26. Sync.Once")
-	fmt.Println("27. Try Lock")
-	fmt.Println("28. Scheduling Hints")
-	fmt.Println("29. WaitGroup Error Handling")
-	fmt.Println("30. Dynamic WaitGroup")
-	fmt.Println("31. WaitGroup with Timeout")
-	fmt.Println("32. Worker Pool")
+// listExamples prints the name, menu number, title and tags of every
+// selected example
+func listExamples() {
+	for _, ex := range selectedExamples() {
+		fmt.Printf("%-28s %3d  %-8s  %-44s %s\n", ex.Name, ex.Number, ex.Category, ex.Title,
+			strings.Join(examples.Tags(ex.Name), ","))
+	}
+}
+
+// selectedExamples returns the examples in the category chosen with
+// --category and with the tag chosen with --tag, or all of them
+func selectedExamples() []examples.Example {
+	var selected []examples.Example
+	for _, ex := range examples.List() {
+		if *category != "" && ex.Category != *category {
+			continue
+		}
+		if *tag != "" && !slices.Contains(examples.Tags(ex.Name), *tag) {
+			continue
+		}
+		selected = append(selected, ex)
+	}
+	return selected
+}
+
+// tagHeadings are the menu headings of the advanced examples' topics
+var tagHeadings = map[string]string{
+	examples.TagChannels:    "Channels",
+	examples.TagSync:        "Synchronization",
+	examples.TagScheduling:  "Scheduling",
+	examples.TagPatterns:    "Patterns",
+	examples.TagPerformance: "Performance",
+}
+
+// printMenu prints the selected examples, grouped by category and topic
+func printMenu() {
+	selected := selectedExamples()
+	printMenuSection("Basic Examples", selected, func(ex examples.Example) bool {
+		return ex.Category == examples.Basic
+	})
+
+	// Advanced examples are grouped by their primary tag
+	for _, tag := range examples.AllTags() {
+		printMenuSection("Advanced Examples ("+tagHeadings[tag]+")", selected, func(ex examples.Example) bool {
+			tags := examples.Tags(ex.Name)
+			return ex.Category == examples.Advanced && len(tags) > 0 && tags[0] == tag
+		})
+	}
 
 	fmt.Println("\n0. Exit")
+}
 
-	fmt.Print("\nEnter your choice: ")
-	var choice string
-	fmt.Scanln(&choice)
+// runOptions builds the options for running an example from the flags
+func runOptions() examples.Options {
+	opts := examples.Options{Runs: *runs, Deterministic: *deterministic, Format: *format, Handler: logHandler, Timeout: *timeout}
+	if *step {
+		opts.Stepper = console.NewPromptStepper(os.Stdin, os.Stdout)
+	}
+	return opts
+}
 
-	runExample(choice)
+// printMenuSection prints the examples matching include under heading,
+// unless there are none
+func printMenuSection(heading string, list []examples.Example, include func(examples.Example) bool) {
+	printed := false
+	for _, ex := range list {
+		if !include(ex) {
+			continue
+		}
+		if !printed {
+			fmt.Printf("\n%s:\n", heading)
+			printed = true
+		}
+		fmt.Printf("%d. %s\n", ex.Number, ex.Title)
+	}
 }
 
-func runExample(choice string) {
-	num, err := strconv.Atoi(choice)
-	if err != nil {
-		fmt.Println("Invalid choice. Please enter a number.")
-		return
-	}
-
-	fmt.Println()
-
-	switch num {
-	// Basic examples
-	case 1:
-		basic.GoroutineDemo()
-	case 2:
-		basic.ChannelDemo()
-	case 3:
-		basic.BufferedChannelDemo()
-	case 4:
-		basic.WaitGroupDemo()
-	case 5:
-		basic.SelectDemo()
-	case 6:
-		basic.MutexDemo()
-
-	// Advanced examples
-	case 11:
-		advanced.ChannelOwnershipDemo()
-	case 12:
-		advanced.FanOutFanInDemo()
-	case 13:
-		advanced.CancellationPatternDemo()
-	case 14:
-		advanced.OrChannelPatternDemo()
-	case 15:
-		advanced.TeeChannelPatternDemo()
-	case 16:
-		advanced.DynamicBufferSizingDemo()
-	case 17:
-		advanced.ChannelSemaphoreDemo()
-	case 18:
-		advanced.DroppingChannelDemo()
-	case 19:
-		advanced.RingBufferDemo()
-	case 20:
-		advanced.BatchProcessingDemo()
-	case 21:
-		advanced.PrioritySelectDemo()
-	case 22:
-		advanced.SelectSendReceiveDemo()
-	case 23:
-		advanced.NilChannelSelectDemo()
-	case 24:
-		advanced.RWMutexDemo()
-	case 25:
-		advanced.AtomicOperationsDemo()
-	case 26:
-		advanced.SyncOnceDemo()
-	case 27:
-		advanced.TryLockDemo()
-	case 28:
-		advanced.WaitGroupErrorHandlingDemo()
-	case 29:
-		advanced.DynamicWaitGroupDemo()
-	case 30:
-		advanced.WaitGroupTimeoutDemo()
-	case 31:
-		advanced.WorkerPoolDemo()
-
-	case 0:
-		fmt.Println("Exiting...")
-		return
-
-	default:
-		fmt.Println("Invalid choice. Please try again.")
-	}
-
-	// After running an example, show the menu again
-	showMenu()
+// run runs an example with the options from the flags. If it times out, the
+// goroutines it left running are listed before the error is returned.
+func run(ex examples.Example) error {
+	if ok, reason := examples.Reproducible(ex.Name); *deterministic && !ok {
+		fmt.Fprintf(os.Stderr, "deterministic: the output of %s still varies between runs: %s\n", ex.Name, reason)
+	}
+	err := examples.Run(context.Background(), ex.Name, os.Stdout, runOptions())
+	fmt.Print(stuckReport(err))
+	return err
+}
+
+// stuckReport describes the goroutines left running by an example that timed
+// out, or returns "" if err is not a timeout
+func stuckReport(err error) string {
+	var timedOut *examples.TimeoutError
+	if !errors.As(err, &timedOut) {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n[timed out] %s was abandoned after %v; goroutines left running:\n", timedOut.Name, timedOut.Timeout)
+	for _, s := range timedOut.Stuck {
+		fmt.Fprintf(&b, "  %s\n", s)
+	}
+	return b.String()
 }
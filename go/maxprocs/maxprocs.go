@@ -0,0 +1,127 @@
+// Package maxprocs sets GOMAXPROCS from the CPU quota of a Linux container,
+// as go.uber.org/automaxprocs does.
+//
+// In a container limited to, say, two CPUs, runtime.NumCPU still counts
+// every CPU of the host, and GOMAXPROCS defaults to that. The runtime then
+// runs more threads than the quota allows; the kernel throttles the whole
+// process once the quota for a period is spent, which shows up as latency
+// spikes and lost throughput. Go 1.25 adjusts GOMAXPROCS itself, but only
+// when the main module declares go 1.25 or later; this package covers
+// older language versions, such as this module's.
+package maxprocs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNoQuota is returned when the process has no CPU quota, or none can be
+// found, as on operating systems other than Linux.
+var ErrNoQuota = errors.New("maxprocs: no CPU quota")
+
+// ErrEnvSet is returned by Set when the GOMAXPROCS environment variable is
+// set, since it takes precedence.
+var ErrEnvSet = errors.New("maxprocs: GOMAXPROCS is set in the environment")
+
+// CPUQuota returns the CPU limit of the process's cgroup in CPUs, such as
+// 1.5 for a quota of 150ms per 100ms period. It reads cpu.max for cgroup v2
+// and cpu.cfs_quota_us with cpu.cfs_period_us for cgroup v1, at the
+// locations containers normally mount them.
+func CPUQuota() (float64, error) {
+	return cpuQuota(os.DirFS("/"))
+}
+
+// Procs returns the GOMAXPROCS for a quota: rounded down, so the process
+// is not throttled, but at least 1.
+func Procs(quota float64) int {
+	return max(1, int(math.Floor(quota)))
+}
+
+// Set sets GOMAXPROCS to Procs of the CPU quota, at most runtime.NumCPU. It
+// returns the new value and a function that restores the previous one.
+// Without a quota, or with GOMAXPROCS set in the environment, it changes
+// nothing and returns the current value with the error.
+func Set() (int, func(), error) {
+	current := runtime.GOMAXPROCS(0)
+	if _, ok := os.LookupEnv("GOMAXPROCS"); ok {
+		return current, func() {}, ErrEnvSet
+	}
+	quota, err := CPUQuota()
+	if err != nil {
+		return current, func() {}, err
+	}
+	procs := min(Procs(quota), runtime.NumCPU())
+	runtime.GOMAXPROCS(procs)
+	return procs, func() { runtime.GOMAXPROCS(current) }, nil
+}
+
+// cpuQuota finds the process's cgroups in /proc/self/cgroup and reads the
+// quota of the first one that has a CPU controller
+func cpuQuota(fsys fs.FS) (float64, error) {
+	data, err := fs.ReadFile(fsys, "proc/self/cgroup")
+	if err != nil {
+		return 0, ErrNoQuota
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		// id:controllers:path, where v2 has id 0 and no controllers
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		controllers, dir := fields[1], fields[2]
+		switch {
+		case fields[0] == "0" && controllers == "":
+			for _, d := range []string{path.Join("sys/fs/cgroup", dir), "sys/fs/cgroup"} {
+				if cpuMax, err := fs.ReadFile(fsys, d+"/cpu.max"); err == nil {
+					return parseCPUMax(string(cpuMax))
+				}
+			}
+		case slices.Contains(strings.Split(controllers, ","), "cpu"):
+			for _, d := range []string{path.Join("sys/fs/cgroup", controllers, dir), path.Join("sys/fs/cgroup", controllers)} {
+				quota, err1 := fs.ReadFile(fsys, d+"/cpu.cfs_quota_us")
+				period, err2 := fs.ReadFile(fsys, d+"/cpu.cfs_period_us")
+				if err1 == nil && err2 == nil {
+					return parseCFS(string(quota), string(period))
+				}
+			}
+		}
+	}
+	return 0, ErrNoQuota
+}
+
+// parseCPUMax parses cgroup v2's "quota period", where quota may be "max"
+func parseCPUMax(s string) (float64, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("maxprocs: malformed cpu.max %q", s)
+	}
+	if fields[0] == "max" {
+		return 0, ErrNoQuota
+	}
+	return parseCFS(fields[0], fields[1])
+}
+
+// parseCFS divides a quota by a period, both in microseconds; a negative
+// quota means no limit
+func parseCFS(quota, period string) (float64, error) {
+	q, err := strconv.ParseInt(strings.TrimSpace(quota), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("maxprocs: malformed quota: %w", err)
+	}
+	p, err := strconv.ParseInt(strings.TrimSpace(period), 10, 64)
+	if err != nil || p <= 0 {
+		return 0, fmt.Errorf("maxprocs: malformed period %q", period)
+	}
+	if q < 0 {
+		return 0, ErrNoQuota
+	}
+	return float64(q) / float64(p), nil
+}
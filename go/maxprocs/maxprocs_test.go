@@ -0,0 +1,69 @@
+package maxprocs
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCPUQuota(t *testing.T) {
+	file := func(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    float64
+		wantErr error
+	}{
+		{"v2 two CPUs", fstest.MapFS{
+			"proc/self/cgroup":      file("0::/\n"),
+			"sys/fs/cgroup/cpu.max": file("200000 100000\n"),
+		}, 2, nil},
+		{"v2 fraction", fstest.MapFS{
+			"proc/self/cgroup":      file("0::/\n"),
+			"sys/fs/cgroup/cpu.max": file("150000 100000\n"),
+		}, 1.5, nil},
+		{"v2 unlimited", fstest.MapFS{
+			"proc/self/cgroup":      file("0::/\n"),
+			"sys/fs/cgroup/cpu.max": file("max 100000\n"),
+		}, 0, ErrNoQuota},
+		{"v2 nested path", fstest.MapFS{
+			"proc/self/cgroup":                    file("0::/kubepods/pod1\n"),
+			"sys/fs/cgroup/kubepods/pod1/cpu.max": file("50000 100000\n"),
+		}, 0.5, nil},
+		{"v1", fstest.MapFS{
+			"proc/self/cgroup":                            file("3:memory:/\n2:cpu,cpuacct:/\n"),
+			"sys/fs/cgroup/cpu,cpuacct/cpu.cfs_quota_us":  file("400000\n"),
+			"sys/fs/cgroup/cpu,cpuacct/cpu.cfs_period_us": file("100000\n"),
+		}, 4, nil},
+		{"v1 unlimited", fstest.MapFS{
+			"proc/self/cgroup":                    file("1:cpu:/\n0::/\n"),
+			"sys/fs/cgroup/cpu/cpu.cfs_quota_us":  file("-1\n"),
+			"sys/fs/cgroup/cpu/cpu.cfs_period_us": file("100000\n"),
+		}, 0, ErrNoQuota},
+		{"no cgroups", fstest.MapFS{}, 0, ErrNoQuota},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cpuQuota(tt.fsys)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("cpuQuota = %v, %v; want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	for _, s := range []string{"", "100000", "abc 100000", "100000 0"} {
+		if _, err := parseCPUMax(s); err == nil || errors.Is(err, ErrNoQuota) {
+			t.Errorf("parseCPUMax(%q) = %v, want a parse error", s, err)
+		}
+	}
+}
+
+func TestProcs(t *testing.T) {
+	for quota, want := range map[float64]int{0.5: 1, 1: 1, 1.9: 1, 2: 2, 7.5: 7} {
+		if got := Procs(quota); got != want {
+			t.Errorf("Procs(%v) = %d, want %d", quota, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package parallel provides helpers for running work concurrently while
+// keeping the number of goroutines bounded and collecting their results.
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"threads/syncx"
+)
+
+// ForEach calls fn for every element of items using at most limit goroutines
+// at a time. A limit of zero or less runs every item in its own goroutine.
+//
+// Errors returned by fn do not stop the remaining items; they are collected
+// and returned together via errors.Join. If ctx is cancelled, ForEach stops
+// starting new items, waits for the ones already running and includes the
+// cancellation cause in the returned error.
+func ForEach[T any](ctx context.Context, items []T, limit int, fn func(ctx context.Context, item T) error) error {
+	if limit <= 0 || limit > len(items) {
+		limit = len(items)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	// One slot per goroutine; max keeps an empty items slice valid
+	sem := syncx.NewSemaphore(max(limit, 1))
+
+	for _, item := range items {
+		// Acquire fails as soon as the context is cancelled, even if a slot is free
+		if sem.Acquire(ctx) != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(item T) {
+			defer wg.Done()
+			defer sem.Release()
+
+			if err := fn(ctx, item); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(item)
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		errs = append(errs, context.Cause(ctx))
+	}
+
+	return errors.Join(errs...)
+}
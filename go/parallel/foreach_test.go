@@ -0,0 +1,157 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEachLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		items int
+		limit int
+		want  int32 // Highest number of items allowed to run at once
+	}{
+		{"limit 1", 10, 1, 1},
+		{"limit 3", 10, 3, 3},
+		{"limit above items", 4, 10, 4},
+		{"zero is unbounded", 8, 0, 8},
+		{"no items", 0, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, peak, calls atomic.Int32
+			err := ForEach(context.Background(), make([]int, tt.items), tt.limit, func(ctx context.Context, _ int) error {
+				n := running.Add(1)
+				for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+				}
+				time.Sleep(time.Millisecond)
+				running.Add(-1)
+				calls.Add(1)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("ForEach = %v, want nil", err)
+			}
+			if int(calls.Load()) != tt.items {
+				t.Errorf("fn was called %d times, want %d", calls.Load(), tt.items)
+			}
+			if p := peak.Load(); p > tt.want {
+				t.Errorf("%d items ran at once, want at most %d", p, tt.want)
+			}
+		})
+	}
+}
+
+func TestForEachCollectsErrors(t *testing.T) {
+	var calls atomic.Int32
+	err := ForEach(context.Background(), []int{1, 2, 3, 4, 5, 6}, 2, func(ctx context.Context, n int) error {
+		calls.Add(1)
+		if n%3 == 0 {
+			return fmt.Errorf("item %d", n)
+		}
+		return nil
+	})
+
+	// Errors do not stop the remaining items
+	if calls.Load() != 6 {
+		t.Errorf("fn was called %d times, want 6", calls.Load())
+	}
+	for _, want := range []string{"item 3", "item 6"} {
+		if err == nil || !slices.Contains(strings.Split(err.Error(), "\n"), want) {
+			t.Errorf("ForEach = %v, want it to include %q", err, want)
+		}
+	}
+}
+
+func TestForEachFirstErrorCancels(t *testing.T) {
+	boom := errors.New("boom")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	// fn cancels the context on its first error, so no new items start
+	var started atomic.Int32
+	err := ForEach(ctx, make([]int, 100), 2, func(ctx context.Context, _ int) error {
+		if started.Add(1) == 3 {
+			cancel(boom)
+			return boom
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Millisecond):
+		}
+		return nil
+	})
+
+	if !errors.Is(err, boom) {
+		t.Errorf("ForEach = %v, want %v", err, boom)
+	}
+	// The error is started while two slots are busy, so at most one more
+	// item can have been let in before the cancellation was seen
+	if n := started.Load(); n > 4 {
+		t.Errorf("%d items started, want no new items after the error", n)
+	}
+}
+
+func TestForEachContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	err := ForEach(ctx, make([]int, 10), 2, func(ctx context.Context, _ int) error {
+		calls.Add(1)
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForEach = %v, want %v", err, context.Canceled)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("fn was called %d times on a cancelled context, want 0", calls.Load())
+	}
+}
+
+func TestForEachContextCancelledWhileRunning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	err := ForEach(ctx, make([]int, 10), 2, func(ctx context.Context, _ int) error {
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+		<-ctx.Done()
+		return nil
+	})
+
+	// Items already running are waited for and the cause is returned
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForEach = %v, want %v", err, context.Canceled)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("fn was called %d times, want 2", n)
+	}
+}
+
+// BenchmarkForEach runs short items under different limits. The work sleeps
+// briefly, like I/O, so higher limits overlap more of it.
+func BenchmarkForEach(b *testing.B) {
+	items := make([]int, 64)
+	for _, limit := range []int{1, 4, 16, 0} {
+		name := fmt.Sprintf("limit=%d", limit)
+		if limit == 0 {
+			name = "unbounded"
+		}
+		b.Run(name, func(b *testing.B) {
+			for range b.N {
+				ForEach(context.Background(), items, limit, func(ctx context.Context, _ int) error {
+					time.Sleep(10 * time.Microsecond)
+					return nil
+				})
+			}
+		})
+	}
+}
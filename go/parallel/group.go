@@ -0,0 +1,82 @@
+package parallel
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs functions that return a value and an error, like errgroup, and
+// collects their values. Wait returns the values in the order the functions
+// were passed to Go, however they finish, together with the first error.
+// The zero value runs every function without a limit and cancels nothing.
+type Group[T any] struct {
+	cancel context.CancelCauseFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	results []T
+	err     error
+}
+
+// GroupWithContext returns a Group and a context derived from ctx. The
+// context is cancelled, with the error as its cause, when a function first
+// returns an error or when Wait returns.
+func GroupWithContext[T any](ctx context.Context) (*Group[T], context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group[T]{cancel: cancel}, ctx
+}
+
+// SetLimit limits the group to n functions running at a time; Go blocks
+// until one of them returns. A limit of zero or less means no limit. It
+// must be called before the first Go.
+func (g *Group[T]) SetLimit(n int) {
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs fn in a new goroutine, reserving its place in the results.
+func (g *Group[T]) Go(fn func() (T, error)) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.mu.Lock()
+	i := len(g.results)
+	var zero T
+	g.results = append(g.results, zero)
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		v, err := fn()
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.results[i] = v
+		if err != nil && g.err == nil {
+			g.err = err
+			if g.cancel != nil {
+				g.cancel(err)
+			}
+		}
+	}()
+}
+
+// Wait waits for every function and returns their values in submission
+// order and the first error any of them returned. A function that failed
+// contributes whatever value it returned alongside its error.
+func (g *Group[T]) Wait() ([]T, error) {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.results, g.err
+}
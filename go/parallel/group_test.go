@@ -0,0 +1,91 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupOrder(t *testing.T) {
+	var g Group[int]
+	for i := range 5 {
+		g.Go(func() (int, error) {
+			// Later functions finish first
+			time.Sleep(time.Duration(5-i) * 5 * time.Millisecond)
+			return i * i, nil
+		})
+	}
+	got, err := g.Wait()
+	if want := []int{0, 1, 4, 9, 16}; !slices.Equal(got, want) || err != nil {
+		t.Errorf("Wait = %v, %v; want %v, nil", got, err, want)
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	var g Group[struct{}]
+	g.SetLimit(3)
+	var running, peak atomic.Int32
+	for range 20 {
+		g.Go(func() (struct{}, error) {
+			n := running.Add(1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return struct{}{}, nil
+		})
+	}
+	if got, _ := g.Wait(); len(got) != 20 {
+		t.Errorf("Wait returned %d results, want 20", len(got))
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("%d functions ran at once, want at most 3", p)
+	}
+}
+
+func TestGroupWithContextCancelsOnError(t *testing.T) {
+	boom := errors.New("boom")
+	g, ctx := GroupWithContext[string](context.Background())
+	g.Go(func() (string, error) { return "ok", nil })
+	g.Go(func() (string, error) { return "", boom })
+	g.Go(func() (string, error) {
+		select {
+		case <-ctx.Done():
+			return "cancelled", nil
+		case <-time.After(time.Second):
+			return "not cancelled", nil
+		}
+	})
+
+	got, err := g.Wait()
+	if !errors.Is(err, boom) {
+		t.Fatalf("Wait error = %v, want %v", err, boom)
+	}
+	if want := []string{"ok", "", "cancelled"}; !slices.Equal(got, want) {
+		t.Errorf("Wait values = %q, want %q", got, want)
+	}
+	if cause := context.Cause(ctx); !errors.Is(cause, boom) {
+		t.Errorf("context cause = %v, want %v", cause, boom)
+	}
+}
+
+func TestGroupFirstErrorWithoutContext(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+	var g Group[int]
+	g.Go(func() (int, error) { return 1, first })
+	g.Go(func() (int, error) {
+		time.Sleep(10 * time.Millisecond)
+		return 2, second
+	})
+	got, err := g.Wait()
+	if !errors.Is(err, first) {
+		t.Errorf("Wait error = %v, want %v", err, first)
+	}
+	// Without a context nothing is cancelled: both ran to completion
+	if want := []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("Wait values = %v, want %v", got, want)
+	}
+}
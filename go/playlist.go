@@ -0,0 +1,71 @@
+/**
+ * The --playlist flag.
+ *
+ * --playlist NAME runs a lesson plan for a guided workshop session: a curated
+ * subset of the examples in a fixed order, with an introduction before each
+ * one. The session waits for Enter before every lesson (q stops it early) and
+ * combines with --quiz and --step like the menu does.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"threads/console"
+	"threads/examples"
+)
+
+// runPlaylist runs the lessons of the named playlist in order
+func runPlaylist(name string) error {
+	p, ok := examples.LookupPlaylist(name)
+	if !ok {
+		var names []string
+		for _, p := range examples.Playlists() {
+			names = append(names, p.Name)
+		}
+		return fmt.Errorf("unknown playlist %q (want one of %s)", name, strings.Join(names, ", "))
+	}
+
+	fmt.Println(p.Title)
+	fmt.Println(strings.Repeat("=", len(p.Title)))
+	fmt.Printf("\n%s\n\nLessons:\n", p.Intro)
+	for i, lesson := range p.Lessons {
+		ex, _ := examples.Lookup(lesson.Example)
+		fmt.Printf("%d. %s\n", i+1, ex.Title)
+	}
+
+	interactive := true
+	for i, lesson := range p.Lessons {
+		ex, ok := examples.Lookup(lesson.Example)
+		if !ok {
+			return fmt.Errorf("playlist %s: unknown example %q", p.Name, lesson.Example)
+		}
+
+		fmt.Printf("\n--- Lesson %d of %d: %s ---\n%s\n", i+1, len(p.Lessons), ex.Title, lesson.Intro)
+		if interactive {
+			fmt.Print("\nPress Enter to run it (q to stop)... ")
+			line, err := console.ReadLine(os.Stdin)
+			if strings.TrimSpace(line) == "q" {
+				fmt.Println("\nStopped.")
+				return nil
+			}
+			interactive = err == nil // At the end of input, run the rest without waiting
+		}
+		fmt.Println()
+
+		if err := run(ex); err != nil {
+			fmt.Println("Error:", err)
+		} else if *quiz {
+			askQuiz(ex.Name, os.Stdin, os.Stdout)
+		}
+	}
+
+	fmt.Printf("\nThat's the end of %s.\n", p.Title)
+	if *quiz {
+		fmt.Printf("Quiz score: %s\n", formatScore())
+	}
+	return nil
+}
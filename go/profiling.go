@@ -0,0 +1,84 @@
+/**
+ * pprof profiling for the selected example.
+ *
+ * --cpuprofile records a CPU profile for the whole session and --memprofile
+ * writes a heap profile when the program exits. --blockprofile and
+ * --mutexprofile switch on blocking and mutex contention sampling and write
+ * those profiles on exit; the contention-workload example is designed to
+ * give them something to show. Inspect any of them with `go tool pprof`.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// profileFlags holds the output paths of the requested profiles
+type profileFlags struct {
+	cpu, mem, block, mutex string
+}
+
+// startProfiling starts the requested profiles and returns a function that
+// writes them out. It returns an error if a profile file cannot be created.
+func startProfiling(p profileFlags) (stop func(), err error) {
+	var cpuFile *os.File
+	if p.cpu != "" {
+		if cpuFile, err = os.Create(p.cpu); err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(cpuFile); err != nil {
+			cpuFile.Close()
+			return nil, err
+		}
+	}
+
+	if p.block != "" {
+		runtime.SetBlockProfileRate(1)
+	}
+	if p.mutex != "" {
+		runtime.SetMutexProfileFraction(1)
+	}
+
+	return func() {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			reportProfile("CPU", p.cpu, cpuFile.Close())
+		}
+		if p.mem != "" {
+			runtime.GC() // Get up-to-date allocation statistics
+			reportProfile("Heap", p.mem, writeProfile("allocs", p.mem))
+		}
+		if p.block != "" {
+			reportProfile("Block", p.block, writeProfile("block", p.block))
+		}
+		if p.mutex != "" {
+			reportProfile("Mutex", p.mutex, writeProfile("mutex", p.mutex))
+		}
+	}, nil
+}
+
+// writeProfile writes the named runtime profile to path
+func writeProfile(name, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := pprof.Lookup(name).WriteTo(f, 0); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func reportProfile(kind, path string, err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s profile: %v\n", kind, err)
+		return
+	}
+	fmt.Printf("%s profile written to %s; inspect it with: go tool pprof %s\n", kind, path, path)
+}
@@ -0,0 +1,92 @@
+/**
+ * The --quiz flag.
+ *
+ * With --quiz, every example is followed by a few multiple-choice questions
+ * about the pattern it showed. The score is kept for the whole session and
+ * printed when the menu is exited.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"threads/console"
+	"threads/examples"
+	"threads/rng"
+)
+
+// quizScore is the running score of the session
+var quizScore struct {
+	asked, correct int
+}
+
+// askQuiz asks the questions for the named example on w, reading answers
+// from r, and adds the results to the session score. The choices are
+// shuffled each time so the answer's position gives nothing away.
+func askQuiz(name string, r io.Reader, w io.Writer) {
+	questions, ok := examples.Quiz(name)
+	if !ok {
+		return
+	}
+	shuffle := rng.New()
+
+	fmt.Fprintln(w, "\nQuiz")
+	fmt.Fprintln(w, "----")
+	for i, q := range questions {
+		q = q.Shuffle(shuffle)
+		fmt.Fprintf(w, "\n%d. %s\n", i+1, q.Prompt)
+		for j, choice := range q.Choices {
+			fmt.Fprintf(w, "   %c) %s\n", 'a'+j, choice)
+		}
+
+		answer, ok := readChoice(r, w, len(q.Choices))
+		if !ok {
+			break // The input ended; the question is not scored
+		}
+		quizScore.asked++
+		if answer == q.Answer {
+			quizScore.correct++
+			fmt.Fprintln(w, "Correct!", q.Explanation)
+		} else {
+			fmt.Fprintf(w, "Not quite, the answer is %c. %s\n", 'a'+q.Answer, q.Explanation)
+		}
+	}
+
+	fmt.Fprintf(w, "\nSession score: %s\n", formatScore())
+}
+
+// readChoice prompts until a valid choice (a letter or a number) is entered
+// and returns its index. It reads a whole line per answer, so extra words do
+// not spill into the next prompt, and reports false if the input ends first.
+func readChoice(r io.Reader, w io.Writer, n int) (int, bool) {
+	for {
+		fmt.Fprint(w, "Your answer: ")
+		line, err := console.ReadLine(r)
+
+		input := strings.ToLower(strings.TrimSpace(line))
+		if len(input) == 1 && input[0] >= 'a' && int(input[0]-'a') < n {
+			return int(input[0] - 'a'), true
+		}
+		if num, err := strconv.Atoi(input); err == nil && num >= 1 && num <= n {
+			return num - 1, true
+		}
+		if err != nil {
+			fmt.Fprintln(w)
+			return -1, false
+		}
+		fmt.Fprintf(w, "Please enter a letter from a to %c.\n", 'a'+n-1)
+	}
+}
+
+// formatScore formats the session score as "correct/asked (percent)"
+func formatScore() string {
+	if quizScore.asked == 0 {
+		return "no questions answered"
+	}
+	return fmt.Sprintf("%d/%d (%d%%)", quizScore.correct, quizScore.asked,
+		100*quizScore.correct/quizScore.asked)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadChoice(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   int
+		wantOK bool
+	}{
+		{"letter", "b\n", 1, true},
+		{"upper case", "C\n", 2, true},
+		{"number", "3\n", 2, true},
+		{"retry after invalid", "z\n\n4\na\n", 0, true},
+		{"extra words are rejected", "a b\nb\n", 1, true},
+		{"answer without newline", "c", 2, true},
+		{"end of input", "", -1, false},
+		{"end of input after invalid", "x\n", -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := readChoice(strings.NewReader(tt.input), io.Discard, 3)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("readChoice(%q) = %d, %v; want %d, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestReadChoiceReadsOneLine(t *testing.T) {
+	r := strings.NewReader("a\nb\n")
+	if got, _ := readChoice(r, io.Discard, 3); got != 0 {
+		t.Fatalf("first answer = %d, want 0", got)
+	}
+	if got, _ := readChoice(r, io.Discard, 3); got != 1 {
+		t.Errorf("second answer = %d, want 1, read from the next line", got)
+	}
+}
+
+func TestAskQuizStopsAtEndOfInput(t *testing.T) {
+	prev := quizScore
+	t.Cleanup(func() { quizScore = prev })
+	quizScore.asked, quizScore.correct = 0, 0
+
+	askQuiz("goroutines", strings.NewReader(""), io.Discard)
+	if quizScore.asked != 0 {
+		t.Errorf("%d question(s) scored after the input ended, want 0", quizScore.asked)
+	}
+}
@@ -0,0 +1,47 @@
+package raft
+
+import (
+	"sync/atomic"
+
+	"threads/chaos"
+	"threads/rng"
+)
+
+// message is an appendEntries or an appendReply in flight
+type message struct {
+	from, to int
+	body     any
+}
+
+// network connects the nodes through buffered inboxes. It loses messages
+// at random, when an inbox is full, and to or from an isolated node; it
+// never blocks the sender.
+type network struct {
+	inboxes  []chan message
+	dropRate atomic.Int64 // Percent
+	isolated []atomic.Bool
+	sent     atomic.Int64
+	dropped  atomic.Int64
+}
+
+func newNetwork(nodes, buffer int) *network {
+	n := &network{inboxes: make([]chan message, nodes), isolated: make([]atomic.Bool, nodes)}
+	for i := range n.inboxes {
+		n.inboxes[i] = make(chan message, buffer)
+	}
+	return n
+}
+
+func (n *network) send(from, to int, body any) {
+	n.sent.Add(1)
+	chaos.Point()
+	if n.isolated[from].Load() || n.isolated[to].Load() || rng.IntN(100) < int(n.dropRate.Load()) {
+		n.dropped.Add(1)
+		return
+	}
+	select {
+	case n.inboxes[to] <- message{from: from, to: to, body: body}:
+	default:
+		n.dropped.Add(1)
+	}
+}
@@ -0,0 +1,163 @@
+package raft
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// node is the state owned by one node's goroutine
+type node struct {
+	id          int
+	c           *Cluster
+	log         []Entry
+	commitIndex int
+	lastApplied int
+}
+
+// termAt returns the term of the entry at index, 0 for index 0 and -1 if
+// the log is too short
+func (n *node) termAt(index int) int {
+	switch {
+	case index == 0:
+		return 0
+	case index > len(n.log):
+		return -1
+	}
+	return n.log[index-1].Term
+}
+
+// applyCommitted applies the entries up to commitIndex
+func (n *node) applyCommitted() {
+	if n.lastApplied >= n.commitIndex {
+		return
+	}
+	var commands []string
+	for _, e := range n.log[n.lastApplied:n.commitIndex] {
+		commands = append(commands, e.Command)
+	}
+	n.lastApplied = n.commitIndex
+	n.c.machines[n.id].apply(commands...)
+}
+
+// follow handles AppendEntries until ctx is done
+func (n *node) follow(ctx context.Context) {
+	inbox := n.c.net.inboxes[n.id]
+	for {
+		var m message
+		select {
+		case m = <-inbox:
+		case <-ctx.Done():
+			return
+		}
+		req := m.body.(appendEntries)
+
+		if n.termAt(req.prevIndex) != req.prevTerm {
+			n.c.net.send(n.id, m.from, appendReply{matchIndex: len(n.log)})
+			continue
+		}
+		// Append, truncating only on a conflict: a delayed, shorter message
+		// must not erase entries that a later one already added
+		for i, e := range req.entries {
+			at := req.prevIndex + 1 + i
+			if n.termAt(at) == e.Term {
+				continue
+			}
+			n.log = append(n.log[:at-1], req.entries[i:]...)
+			break
+		}
+		match := req.prevIndex + len(req.entries)
+		if c := min(req.leaderCommit, match); c > n.commitIndex {
+			n.commitIndex = c
+			n.applyCommitted()
+		}
+		n.c.net.send(n.id, m.from, appendReply{success: true, matchIndex: match})
+	}
+}
+
+// lead accepts proposals, replicates the log and advances the commit index
+// until ctx is done
+func (n *node) lead(ctx context.Context) {
+	peers := len(n.c.machines)
+	next := make([]int, peers)  // Next index to send to each follower
+	match := make([]int, peers) // Highest index known to be stored on each node
+	for i := range next {
+		next[i] = 1
+	}
+	waiting := map[int]chan int{} // Proposals by log index
+
+	replicate := func(to int) {
+		prev := next[to] - 1
+		end := min(len(n.log), prev+maxBatch)
+		n.c.net.send(n.id, to, appendEntries{
+			prevIndex:    prev,
+			prevTerm:     n.termAt(prev),
+			entries:      slices.Clone(n.log[prev:end]),
+			leaderCommit: n.commitIndex,
+		})
+	}
+	advanceCommit := func() {
+		for index := len(n.log); index > n.commitIndex; index-- {
+			count := 0
+			for _, m := range match {
+				if m >= index {
+					count++
+				}
+			}
+			if count*2 > peers && n.termAt(index) == term {
+				for i := n.commitIndex + 1; i <= index; i++ {
+					if ch, ok := waiting[i]; ok {
+						ch <- i
+						delete(waiting, i)
+					}
+				}
+				n.commitIndex = index
+				n.applyCommitted()
+				return
+			}
+		}
+	}
+
+	ticker := time.NewTicker(n.c.heartbeat)
+	defer ticker.Stop()
+	inbox := n.c.net.inboxes[n.id]
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case p := <-n.c.proposals:
+			n.log = append(n.log, Entry{Index: len(n.log) + 1, Term: term, Command: p.command})
+			match[n.id] = len(n.log)
+			waiting[len(n.log)] = p.index
+			advanceCommit() // A cluster of one commits at once
+			for to := range peers {
+				if to != n.id && next[to] == len(n.log) {
+					replicate(to) // Followers already behind get the entry with the next heartbeat
+				}
+			}
+
+		case m := <-inbox:
+			reply := m.body.(appendReply)
+			if reply.success {
+				match[m.from] = max(match[m.from], reply.matchIndex)
+				next[m.from] = max(next[m.from], reply.matchIndex+1)
+				advanceCommit()
+			} else {
+				// Back up to just after the follower's log, or by one if a
+				// stale reply says otherwise
+				next[m.from] = max(1, min(next[m.from]-1, reply.matchIndex+1))
+			}
+			if next[m.from] <= len(n.log) {
+				replicate(m.from)
+			}
+
+		case <-ticker.C:
+			for to := range peers {
+				if to != n.id {
+					replicate(to) // Doubles as the heartbeat and the retry of lost messages
+				}
+			}
+		}
+	}
+}
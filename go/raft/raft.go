@@ -0,0 +1,196 @@
+// Package raft is a toy version of Raft's log replication, built from
+// goroutines and channels.
+//
+// A Cluster has a fixed leader, node 0, and a single term: there are no
+// elections. Clients propose commands to the leader, which appends them to
+// its log and sends them to the followers in AppendEntries messages. A
+// follower accepts entries only if its log matches the leader's just
+// before them; otherwise the leader backs up and resends. An entry is
+// committed once a majority of the nodes store it, and every node applies
+// committed entries, in log order, to its state machine: here a list of
+// commands. The network between the nodes can lose messages, which the
+// leader repairs by resending on every heartbeat.
+package raft
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Entry is one command in the replicated log. Index starts at 1.
+type Entry struct {
+	Index   int
+	Term    int
+	Command string
+}
+
+// ErrStopped is returned by Propose after the cluster has stopped.
+var ErrStopped = errors.New("raft: cluster stopped")
+
+const (
+	term             = 1  // The only term
+	maxBatch         = 16 // Entries per AppendEntries message
+	inboxBuffer      = 64
+	leader           = 0
+	defaultHeartbeat = 5 * time.Millisecond
+)
+
+// appendEntries asks a follower to store entries after prevIndex
+type appendEntries struct {
+	prevIndex, prevTerm int
+	entries             []Entry
+	leaderCommit        int
+}
+
+// appendReply reports the follower's last matching index on success, or
+// the length of its log as a hint on failure
+type appendReply struct {
+	success    bool
+	matchIndex int
+}
+
+// proposal is a client command waiting for its entry to commit
+type proposal struct {
+	command string
+	index   chan int
+}
+
+// stateMachine holds a node's applied commands. changed is closed and
+// replaced on every apply, waking anyone waiting for progress.
+type stateMachine struct {
+	mu      sync.Mutex
+	applied []string
+	changed chan struct{}
+}
+
+func (s *stateMachine) apply(commands ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applied = append(s.applied, commands...)
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Cluster is a group of nodes replicating one log.
+type Cluster struct {
+	net       *network
+	machines  []*stateMachine
+	proposals chan proposal
+	heartbeat time.Duration
+	stopped   chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewCluster returns a cluster of size nodes whose leader sends a heartbeat
+// every heartbeat (5ms if zero). Run starts it.
+func NewCluster(size int, heartbeat time.Duration) *Cluster {
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+	c := &Cluster{
+		net:       newNetwork(size, inboxBuffer),
+		machines:  make([]*stateMachine, size),
+		proposals: make(chan proposal),
+		heartbeat: heartbeat,
+		stopped:   make(chan struct{}),
+	}
+	for i := range c.machines {
+		c.machines[i] = &stateMachine{changed: make(chan struct{})}
+	}
+	return c
+}
+
+// Run starts a goroutine per node. The nodes stop when ctx is done; Wait
+// waits for them.
+func (c *Cluster) Run(ctx context.Context) {
+	for id := range c.machines {
+		n := &node{id: id, c: c}
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			if id == leader {
+				n.lead(ctx)
+			} else {
+				n.follow(ctx)
+			}
+		}()
+	}
+	go func() {
+		c.wg.Wait()
+		close(c.stopped)
+	}()
+}
+
+// Wait blocks until every node has stopped.
+func (c *Cluster) Wait() {
+	<-c.stopped
+}
+
+// Propose submits command to the leader and waits until it is committed,
+// returning its log index.
+func (c *Cluster) Propose(ctx context.Context, command string) (int, error) {
+	p := proposal{command: command, index: make(chan int, 1)}
+	select {
+	case c.proposals <- p:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-c.stopped:
+		return 0, ErrStopped
+	}
+	select {
+	case i := <-p.index:
+		return i, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-c.stopped:
+		return 0, ErrStopped
+	}
+}
+
+// SetDropRate makes the network lose percent of all messages.
+func (c *Cluster) SetDropRate(percent int) {
+	c.net.dropRate.Store(int64(percent))
+}
+
+// Isolate cuts node id off from the others, or reconnects it.
+func (c *Cluster) Isolate(id int, isolated bool) {
+	c.net.isolated[id].Store(isolated)
+}
+
+// Messages returns the number of messages sent and how many were lost.
+func (c *Cluster) Messages() (sent, dropped int64) {
+	return c.net.sent.Load(), c.net.dropped.Load()
+}
+
+// Applied returns a copy of the commands node id has applied, in order.
+func (c *Cluster) Applied(id int) []string {
+	s := c.machines[id]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.applied)
+}
+
+// WaitApplied waits until every node has applied at least count commands.
+func (c *Cluster) WaitApplied(ctx context.Context, count int) error {
+	for _, s := range c.machines {
+		for {
+			s.mu.Lock()
+			n, changed := len(s.applied), s.changed
+			s.mu.Unlock()
+			if n >= count {
+				break
+			}
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-c.stopped:
+				return ErrStopped
+			}
+		}
+	}
+	return nil
+}
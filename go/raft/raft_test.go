@@ -0,0 +1,116 @@
+package raft
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// propose submits count commands from clients goroutines and returns them
+func propose(t *testing.T, ctx context.Context, c *Cluster, clients, count int) []string {
+	t.Helper()
+	var mu sync.Mutex
+	var commands []string
+	var wg sync.WaitGroup
+	for client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range count / clients {
+				cmd := fmt.Sprintf("c%d-%d", client, i)
+				if _, err := c.Propose(ctx, cmd); err != nil {
+					t.Errorf("Propose(%q) = %v", cmd, err)
+					return
+				}
+				mu.Lock()
+				commands = append(commands, cmd)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return commands
+}
+
+// checkLogs checks that every node applied the same commands, which are
+// exactly want in some order
+func checkLogs(t *testing.T, c *Cluster, nodes int, want []string) {
+	t.Helper()
+	first := c.Applied(0)
+	for id := 1; id < nodes; id++ {
+		if got := c.Applied(id); !slices.Equal(got, first) {
+			t.Errorf("node %d applied %v, node 0 applied %v", id, got, first)
+		}
+	}
+	got := slices.Sorted(slices.Values(first))
+	if want = slices.Sorted(slices.Values(want)); !slices.Equal(got, want) {
+		t.Errorf("applied commands %v, want %v", got, want)
+	}
+}
+
+func TestReplication(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := NewCluster(3, time.Millisecond)
+	c.Run(ctx)
+
+	for i := range 10 {
+		index, err := c.Propose(ctx, fmt.Sprint(i))
+		if index != i+1 || err != nil {
+			t.Fatalf("Propose(%d) = %d, %v; want %d, nil", i, index, err, i+1)
+		}
+	}
+	if err := c.WaitApplied(ctx, 10); err != nil {
+		t.Fatalf("WaitApplied = %v", err)
+	}
+	checkLogs(t, c, 3, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"})
+
+	cancel()
+	c.Wait()
+	if _, err := c.Propose(context.Background(), "late"); err != ErrStopped {
+		t.Errorf("Propose after stopping = %v, want %v", err, ErrStopped)
+	}
+}
+
+func TestReplicationWithDrops(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	c := NewCluster(5, time.Millisecond)
+	c.SetDropRate(30)
+	c.Run(ctx)
+
+	want := propose(t, ctx, c, 4, 100)
+	if err := c.WaitApplied(ctx, len(want)); err != nil {
+		t.Fatalf("WaitApplied = %v", err)
+	}
+	checkLogs(t, c, 5, want)
+	if _, dropped := c.Messages(); dropped == 0 {
+		t.Error("no messages were dropped")
+	}
+	cancel()
+	c.Wait()
+}
+
+func TestIsolatedFollowerCatchesUp(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	c := NewCluster(3, time.Millisecond)
+	c.Run(ctx)
+
+	c.Isolate(2, true)
+	want := propose(t, ctx, c, 1, 40) // Commits with nodes 0 and 1
+	if got := len(c.Applied(2)); got != 0 {
+		t.Errorf("isolated node applied %d commands, want 0", got)
+	}
+
+	c.Isolate(2, false)
+	if err := c.WaitApplied(ctx, len(want)); err != nil {
+		t.Fatalf("WaitApplied = %v", err)
+	}
+	checkLogs(t, c, 3, want)
+	cancel()
+	c.Wait()
+}
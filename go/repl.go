@@ -0,0 +1,177 @@
+/**
+ * The interactive menu.
+ *
+ * The menu is a read-eval-print loop: each line read is an example to run
+ * (by number or name) or a command. The session keeps a history of the
+ * examples run, which can be listed and re-run, and ends on "quit" or at the
+ * end of input. With --once a single example is run and the program exits.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"threads/console"
+	"threads/examples"
+)
+
+const replHelp = `Commands:
+  N or NAME   run the example with menu number N or name NAME
+  !!          run the previous example again
+  !N          run entry N of the history again
+  h, history  list the examples run in this session
+  m, menu     show the menu
+  ?, help     show this help
+  0, q, quit  exit`
+
+// repl is the state of an interactive session
+type repl struct {
+	in      io.Reader
+	history []string // Names of the examples run, oldest first
+}
+
+// newREPL returns a session reading its input from in. Input is read one
+// line at a time without buffering, because examples run with --step and
+// --quiz read from the same input.
+func newREPL(in io.Reader) *repl {
+	return &repl{in: in}
+}
+
+// loop runs first (if not empty), then reads and handles lines until the
+// user quits or the input ends
+func (r *repl) loop(first string) {
+	if first != "" && !r.handle(first) {
+		return
+	}
+
+	printMenu()
+	for {
+		fmt.Print("\nEnter your choice (? for help): ")
+		line, err := console.ReadLine(r.in)
+		if err != nil && line == "" {
+			fmt.Println()
+			r.quit()
+			return
+		}
+		if !r.handle(strings.TrimSpace(line)) {
+			return
+		}
+	}
+}
+
+// handle runs an example or command and reports whether the session
+// continues
+func (r *repl) handle(choice string) bool {
+	switch choice {
+	case "":
+		return true
+	case "0", "q", "quit", "exit":
+		r.quit()
+		return false
+	case "m", "menu":
+		printMenu()
+		return true
+	case "h", "history":
+		r.printHistory()
+		return true
+	case "?", "help":
+		fmt.Println(replHelp)
+		return true
+	}
+
+	if strings.HasPrefix(choice, "!") {
+		name, ok := r.recall(choice[1:])
+		if !ok {
+			fmt.Printf("No history entry %q. Use h to list the history.\n", choice)
+			return true
+		}
+		choice = name
+	}
+
+	ex, ok := examples.Lookup(choice)
+	if !ok {
+		fmt.Printf("Invalid choice %q. Enter a number, an example name or ? for help.\n", choice)
+		return true
+	}
+
+	r.history = append(r.history, ex.Name)
+	fmt.Println()
+	runInteractive(ex)
+	return true
+}
+
+// recall returns the example name for a history reference: "!" for the
+// latest entry, or an entry number
+func (r *repl) recall(ref string) (string, bool) {
+	if len(r.history) == 0 {
+		return "", false
+	}
+	if ref == "!" {
+		return r.history[len(r.history)-1], true
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil || n < 1 || n > len(r.history) {
+		return "", false
+	}
+	return r.history[n-1], true
+}
+
+func (r *repl) printHistory() {
+	if len(r.history) == 0 {
+		fmt.Println("No examples run yet.")
+		return
+	}
+	for i, name := range r.history {
+		ex, _ := examples.Lookup(name)
+		fmt.Printf("%3d  %s (%d. %s)\n", i+1, name, ex.Number, ex.Title)
+	}
+}
+
+func (r *repl) quit() {
+	if *quiz {
+		fmt.Println("Final quiz score:", formatScore())
+	}
+	fmt.Println("Exiting...")
+}
+
+// runInteractive runs an example chosen in the menu, followed by the quiz
+// if --quiz is set. It prints and returns the error if the example failed.
+func runInteractive(ex examples.Example) error {
+	err := run(ex)
+	if err != nil {
+		fmt.Println("Error:", err)
+	} else if *quiz {
+		askQuiz(ex.Name, os.Stdin, os.Stdout)
+	}
+	return err
+}
+
+// runOnce runs the named example, or asks for one from the menu if name is
+// empty, and returns the exit code
+func runOnce(name string) int {
+	if name == "" {
+		printMenu()
+		fmt.Print("\nEnter your choice: ")
+		line, _ := console.ReadLine(os.Stdin)
+		name = strings.TrimSpace(line)
+	}
+	if name == "" || name == "0" {
+		return 0
+	}
+
+	ex, ok := examples.Lookup(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown example %q (see --list)\n", name)
+		return 2
+	}
+	fmt.Println()
+	if err := runInteractive(ex); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+// Package rng is the random number source shared by the examples.
+//
+// By default it is seeded randomly, like the math/rand top-level functions.
+// SetSource installs a different source, for instance a fixed-seed PCG, so
+// that runs can be reproduced. The functions are safe for concurrent use,
+// but the sequence each goroutine sees still depends on scheduling. A
+// goroutine that needs its own reproducible sequence takes a generator from
+// New before it starts.
+package rng
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+var shared = struct {
+	mu  sync.Mutex
+	src rand.Source
+	r   *rand.Rand
+}{}
+
+func init() {
+	src := rand.NewPCG(rand.Uint64(), rand.Uint64())
+	shared.src, shared.r = src, rand.New(src)
+}
+
+// SetSource changes the shared random source and returns the previous one.
+func SetSource(src rand.Source) rand.Source {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+
+	prev := shared.src
+	shared.src, shared.r = src, rand.New(src)
+	return prev
+}
+
+// IntN returns a pseudo-random int in [0, n). It panics if n <= 0.
+func IntN(n int) int {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	return shared.r.IntN(n)
+}
+
+// Float64 returns a pseudo-random float64 in [0.0, 1.0).
+func Float64() float64 {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	return shared.r.Float64()
+}
+
+// New returns a generator seeded from the shared source. Generators created
+// in the same order after the same SetSource produce the same sequences. The
+// generator is not safe for concurrent use.
+func New() *rand.Rand {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	return rand.New(rand.NewPCG(shared.r.Uint64(), shared.r.Uint64()))
+}
@@ -0,0 +1,65 @@
+package rng
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestSetSource(t *testing.T) {
+	draw := func() []int {
+		var got []int
+		for range 10 {
+			got = append(got, IntN(1000))
+		}
+		return got
+	}
+
+	prev := SetSource(rand.NewPCG(1, 2))
+	defer SetSource(prev)
+	first := draw()
+	SetSource(rand.NewPCG(1, 2))
+	if second := draw(); !slices.Equal(first, second) {
+		t.Errorf("the same seed gave %v, then %v", first, second)
+	}
+}
+
+func TestRanges(t *testing.T) {
+	for range 1000 {
+		if n := IntN(3); n < 0 || n >= 3 {
+			t.Fatalf("IntN(3) = %d, want 0, 1 or 2", n)
+		}
+		if f := Float64(); f < 0 || f >= 1 {
+			t.Fatalf("Float64 = %v, want [0, 1)", f)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	draw := func() [][]int {
+		var got [][]int
+		for range 3 {
+			r := New()
+			var seq []int
+			for range 5 {
+				seq = append(seq, r.IntN(1000))
+			}
+			got = append(got, seq)
+		}
+		return got
+	}
+
+	prev := SetSource(rand.NewPCG(3, 4))
+	defer SetSource(prev)
+	first := draw()
+	SetSource(rand.NewPCG(3, 4))
+	second := draw()
+	for i := range first {
+		if !slices.Equal(first[i], second[i]) {
+			t.Errorf("generator %d gave %v, then %v after the same seed", i, first[i], second[i])
+		}
+	}
+	if slices.Equal(first[0], first[1]) {
+		t.Errorf("two generators gave the same sequence %v", first[0])
+	}
+}
@@ -0,0 +1,77 @@
+// Package safego launches goroutines that cannot crash the program.
+//
+// A panic that is not recovered in the goroutine where it happens terminates
+// the whole process, no matter which goroutine started it. Go and GoReport
+// recover such panics, log them with their stack trace and optionally hand
+// them to the caller as errors.
+package safego
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// logger receives a message for every recovered panic, or nil
+var logger atomic.Pointer[log.Logger]
+
+func init() {
+	logger.Store(log.Default())
+}
+
+// SetLogger changes the logger that receives a message for every recovered
+// panic and returns the previous one. A nil logger disables logging. It is
+// safe to call while wrapped goroutines are running; each panic is logged
+// to the logger set when it is recovered.
+func SetLogger(l *log.Logger) *log.Logger {
+	return logger.Swap(l)
+}
+
+// PanicError is the error reported for a recovered panic.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is and
+// errors.As can see through a PanicError.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Go runs fn in a new goroutine, recovering and logging any panic.
+func Go(fn func()) {
+	GoReport(nil, fn)
+}
+
+// GoReport runs fn in a new goroutine, recovering and logging any panic. If
+// errs is not nil, a *PanicError is sent on it; the send blocks until it is
+// received, so use a buffered channel or drain it.
+func GoReport(errs chan<- error, fn func()) {
+	go func() {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			pe := &PanicError{Value: r, Stack: debug.Stack()}
+			if l := logger.Load(); l != nil {
+				l.Printf("recovered %v\n%s", pe, pe.Stack)
+			}
+			if errs != nil {
+				errs <- pe
+			}
+		}()
+
+		fn()
+	}()
+}
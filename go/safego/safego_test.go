@@ -0,0 +1,145 @@
+package safego
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitFor receives from errs or fails the test after a second
+func waitFor(t *testing.T, errs <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-errs:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("no error was reported")
+		return nil
+	}
+}
+
+func TestGoReportRecovers(t *testing.T) {
+	prev := SetLogger(nil)
+	defer SetLogger(prev)
+
+	errs := make(chan error, 1)
+	GoReport(errs, func() { panic("boom") })
+
+	var pe *PanicError
+	if err := waitFor(t, errs); !errors.As(err, &pe) {
+		t.Fatalf("reported %v, want a *PanicError", err)
+	}
+	if pe.Value != "boom" {
+		t.Errorf("Value = %v, want boom", pe.Value)
+	}
+	if !bytes.Contains(pe.Stack, []byte("safego_test.go")) {
+		t.Errorf("Stack does not include the panicking function:\n%s", pe.Stack)
+	}
+}
+
+func TestGoReportNoPanic(t *testing.T) {
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	GoReport(errs, func() { close(done) })
+	<-done
+
+	select {
+	case err := <-errs:
+		t.Errorf("reported %v for a function that returned normally", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	cause := io.ErrUnexpectedEOF
+	tests := []struct {
+		name   string
+		value  any
+		msg    string
+		unwrap error
+	}{
+		{"string", "boom", "panic: boom", nil},
+		{"error", cause, "panic: unexpected EOF", cause},
+		{"int", 42, "panic: 42", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pe := &PanicError{Value: tt.value}
+			if got := pe.Error(); got != tt.msg {
+				t.Errorf("Error = %q, want %q", got, tt.msg)
+			}
+			if got := pe.Unwrap(); got != tt.unwrap {
+				t.Errorf("Unwrap = %v, want %v", got, tt.unwrap)
+			}
+			if tt.unwrap != nil && !errors.Is(pe, tt.unwrap) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", pe, tt.unwrap)
+			}
+		})
+	}
+}
+
+// syncWriter is a bytes.Buffer that is safe to read while a logger writes
+type syncWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *syncWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestLogger(t *testing.T) {
+	var out syncWriter
+	prev := SetLogger(log.New(&out, "", 0))
+	defer SetLogger(prev)
+
+	// The logger is called before the error is reported
+	errs := make(chan error, 1)
+	GoReport(errs, func() { panic("logged") })
+	waitFor(t, errs)
+
+	got := out.String()
+	if !strings.HasPrefix(got, "recovered panic: logged\n") {
+		t.Errorf("logged %q, want it to start with the panic", got)
+	}
+	if !strings.Contains(got, "safego_test.go") {
+		t.Errorf("logged %q, want it to include the stack", got)
+	}
+
+	// With no logger, nothing is written
+	SetLogger(nil)
+	GoReport(errs, func() { panic("not logged") })
+	waitFor(t, errs)
+	if strings.Contains(out.String(), "not logged") {
+		t.Errorf("logged %q after SetLogger(nil)", out.String())
+	}
+}
+
+func TestSetLoggerWhileRunning(t *testing.T) {
+	prev := SetLogger(log.New(io.Discard, "", 0))
+	defer SetLogger(prev)
+
+	// Swapping the logger while panics are being logged is not a data race
+	errs := make(chan error, 10)
+	for range 10 {
+		Go(func() { panic("racing") })
+		GoReport(errs, func() { panic("racing") })
+		SetLogger(log.New(io.Discard, "", 0))
+	}
+	for range 10 {
+		waitFor(t, errs)
+	}
+}
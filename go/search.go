@@ -0,0 +1,49 @@
+/**
+ * The search command.
+ *
+ * `search TERM` lists the examples whose name, title or source comments
+ * mention TERM, with the first matching comment line as a snippet, so a
+ * pattern can be found without scanning the whole menu.
+ */
+
+package main
+
+import (
+	"fmt"
+
+	"threads/examples"
+)
+
+// maxSnippet is the longest snippet printed, in bytes
+const maxSnippet = 100
+
+// runSearch prints the examples matching term and returns the exit code
+func runSearch(term string) int {
+	if term == "" {
+		fmt.Println("Usage: search TERM")
+		return 2
+	}
+
+	matches := examples.Search(term)
+	if len(matches) == 0 {
+		fmt.Printf("No examples mention %q\n", term)
+		return 0
+	}
+
+	fmt.Printf("%d example(s) mention %q:\n", len(matches), term)
+	for _, m := range matches {
+		fmt.Printf("\n%d. %s (%s)\n", m.Number, m.Title, m.Name)
+		if m.Snippet == "" {
+			continue
+		}
+		snippet := m.Snippet
+		if len(snippet) > maxSnippet {
+			snippet = snippet[:maxSnippet-3] + "..."
+		}
+		fmt.Printf("   %s\n", snippet)
+		if m.Hits > 1 {
+			fmt.Printf("   (+%d more)\n", m.Hits-1)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,182 @@
+/**
+ * The serve command.
+ *
+ * `serve [ADDR]` starts an HTTP playground (on :8080 by default) so that
+ * workshops can run the examples from a browser. The index page lists every
+ * example; /demos/{name} runs one and streams its output as Server-Sent
+ * Events: an "output" event per line, then a "done" event whose data is
+ * "ok" or the error. Examples share the console, so only one runs at a
+ * time; other requests wait their turn.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"threads/examples"
+)
+
+const defaultServeAddr = ":8080"
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Go Concurrency Examples</title>
+<style>
+body { font-family: sans-serif; display: flex; gap: 1em; margin: 1em; }
+nav { flex: 0 0 22em; height: 95vh; overflow-y: auto; }
+nav button { display: block; width: 100%; text-align: left; margin: 1px 0; }
+main { flex: 1; }
+pre { background: #111; color: #ddd; padding: 1em; height: 85vh; overflow-y: auto; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<nav>
+<h2>Examples</h2>
+{{range .}}<button data-name="{{.Name}}">{{.Number}}. {{.Title}}</button>
+{{end}}</nav>
+<main>
+<h2 id="status">Pick an example</h2>
+<pre id="output"></pre>
+</main>
+<script>
+let source;
+const status = document.getElementById("status");
+const output = document.getElementById("output");
+document.querySelectorAll("nav button").forEach(button => {
+	button.onclick = () => {
+		if (source) source.close();
+		output.textContent = "";
+		status.textContent = "Running " + button.textContent + "...";
+		source = new EventSource("/demos/" + button.dataset.name);
+		source.addEventListener("output", e => {
+			output.textContent += e.data + "\n";
+			output.scrollTop = output.scrollHeight;
+		});
+		source.addEventListener("done", e => {
+			status.textContent = button.textContent + (e.data === "ok" ? ": finished" : ": " + e.data);
+			source.close();
+		});
+	};
+});
+</script>
+</body>
+</html>
+`))
+
+func runServe(addr string) {
+	if addr == "" {
+		addr = defaultServeAddr
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", serveIndex)
+	mux.HandleFunc("GET /demos/{name}", serveDemo)
+
+	host := addr
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+	log.Printf("Serving the examples on http://%s/", host)
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
+
+func serveIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := indexTemplate.Execute(w, examples.List()); err != nil {
+		log.Println("index:", err)
+	}
+}
+
+// serveDemo runs an example and streams its output as Server-Sent Events.
+// If the client goes away, the example is abandoned (see examples.Run).
+func serveDemo(w http.ResponseWriter, r *http.Request) {
+	ex, ok := examples.Lookup(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	opts := runOptions()
+	opts.Stepper = nil // Nobody can answer step prompts over HTTP
+
+	out := &sseWriter{w: w, flusher: flusher}
+	err := examples.Run(r.Context(), ex.Name, out, opts)
+	fmt.Fprint(out, stuckReport(err))
+
+	done := "ok"
+	if err != nil {
+		done = err.Error()
+	}
+	out.finish(done)
+}
+
+// sseWriter sends every complete line written to it as an "output" event
+type sseWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	partial []byte
+	closed  bool // Set once the handler is done with the response
+}
+
+func (s *sseWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return len(p), nil // An abandoned example is still printing
+	}
+	s.partial = append(s.partial, p...)
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 {
+			break
+		}
+		s.send("output", string(s.partial[:i]))
+		s.partial = s.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// finish sends any unterminated last line and the "done" event, after which
+// writes are discarded
+func (s *sseWriter) finish(done string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.partial) > 0 {
+		s.send("output", string(s.partial))
+		s.partial = nil
+	}
+	s.send("done", done)
+	s.closed = true
+}
+
+// send writes one event; the caller holds s.mu
+func (s *sseWriter) send(name, data string) {
+	fmt.Fprintf(s.w, "event: %s\n", name)
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(s.w, "data: %s\n", strings.TrimSuffix(line, "\r"))
+	}
+	fmt.Fprint(s.w, "\n")
+	s.flusher.Flush()
+}
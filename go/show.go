@@ -0,0 +1,144 @@
+/**
+ * The --show flag.
+ *
+ * --show=NAME prints the annotated source of an example: its file header,
+ * explanatory comments and code, without the package clause and imports.
+ * Line numbers match the file in the repository, and comments are
+ * highlighted when printing to a terminal. NAME may be an example name, a
+ * menu number or any unambiguous part of a name, such as "fan-in".
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"strings"
+
+	"threads/examples"
+)
+
+// ANSI escapes used to highlight comments
+const (
+	commentColor = "\x1b[32m"
+	resetColor   = "\x1b[0m"
+)
+
+func runShow(term string) error {
+	ex, err := findExample(term)
+	if err != nil {
+		return err
+	}
+
+	file, src, ok := examples.Source(ex.Name)
+	if !ok {
+		return fmt.Errorf("source of %q is not available", ex.Name)
+	}
+
+	if isTerminal(os.Stdout) {
+		src = highlightComments(src)
+	}
+	lines := strings.Split(strings.TrimRight(string(src), "\n"), "\n")
+
+	fmt.Printf("%s (%s)\n\n", ex.Title, file)
+	pkg, imports := boilerplate(file, src)
+	blank := false
+	for i, line := range lines {
+		n := i + 1
+		if n >= pkg.start && n <= pkg.end || n >= imports.start && n <= imports.end {
+			continue
+		}
+		// Removing the boilerplate leaves runs of blank lines behind
+		if line == "" && blank {
+			continue
+		}
+		blank = line == ""
+		fmt.Printf("%4d  %s\n", n, line)
+	}
+	return nil
+}
+
+// findExample resolves a name, menu number or unambiguous part of a name
+func findExample(term string) (examples.Example, error) {
+	if ex, ok := examples.Lookup(term); ok {
+		return ex, nil
+	}
+
+	var candidates []examples.Example
+	for _, ex := range examples.List() {
+		if strings.Contains(ex.Name, strings.ToLower(term)) {
+			candidates = append(candidates, ex)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return examples.Example{}, fmt.Errorf("no example matches %q (see --list)", term)
+	case 1:
+		return candidates[0], nil
+	}
+
+	names := make([]string, len(candidates))
+	for i, ex := range candidates {
+		names[i] = ex.Name
+	}
+	return examples.Example{}, fmt.Errorf("%q matches several examples: %s", term, strings.Join(names, ", "))
+}
+
+// lineRange is an inclusive range of line numbers; the zero value is empty
+type lineRange struct{ start, end int }
+
+// boilerplate returns the lines of the package clause and of the imports
+func boilerplate(file string, src []byte) (pkg, imports lineRange) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, parser.ImportsOnly)
+	if err != nil {
+		return
+	}
+
+	pkg.start = fset.Position(f.Package).Line
+	pkg.end = pkg.start
+	if len(f.Decls) > 0 {
+		imports.start = fset.Position(f.Decls[0].Pos()).Line
+		imports.end = fset.Position(f.Decls[len(f.Decls)-1].End()).Line
+	}
+	return
+}
+
+// highlightComments wraps every comment in src in color escapes, line by
+// line, so that the result can still be split into lines
+func highlightComments(src []byte) []byte {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, scanner.ScanComments)
+
+	var out bytes.Buffer
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.COMMENT {
+			continue
+		}
+
+		offset := file.Offset(pos)
+		out.Write(src[last:offset])
+		colored := strings.ReplaceAll(lit, "\n", resetColor+"\n"+commentColor)
+		out.WriteString(commentColor + colored + resetColor)
+		last = offset + len(lit)
+	}
+	out.Write(src[last:])
+	return out.Bytes()
+}
+
+// isTerminal reports whether f is a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,101 @@
+// Package stats lets demos record their key timings so that a runner can
+// aggregate them across repeated executions.
+//
+// Demos call Record with a stable label for each measurement they print.
+// Recording is cheap and safe for concurrent use; when nothing collects the
+// samples they are simply discarded on the next Reset, which examples.Run
+// calls after every example.
+package stats
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+var (
+	mu      sync.Mutex
+	order   []string
+	samples = map[string][]time.Duration{}
+)
+
+// Record stores a timing sample under the given label.
+func Record(label string, d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := samples[label]; !ok {
+		order = append(order, label)
+	}
+	samples[label] = append(samples[label], d)
+}
+
+// Reset discards all recorded samples.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	order = nil
+	samples = map[string][]time.Duration{}
+}
+
+// Sample is a labelled timing captured by Record.
+type Sample struct {
+	Label    string
+	Duration time.Duration
+}
+
+// Take returns all samples recorded since the last Reset in recording order
+// of their labels, and clears them.
+func Take() []Sample {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var out []Sample
+	for _, label := range order {
+		for _, d := range samples[label] {
+			out = append(out, Sample{Label: label, Duration: d})
+		}
+	}
+
+	order = nil
+	samples = map[string][]time.Duration{}
+	return out
+}
+
+// Summary describes the distribution of a set of timings.
+type Summary struct {
+	N      int
+	Mean   time.Duration
+	StdDev time.Duration
+	Min    time.Duration
+	Max    time.Duration
+}
+
+// Summarize computes mean, sample standard deviation, min and max of ds.
+func Summarize(ds []time.Duration) Summary {
+	if len(ds) == 0 {
+		return Summary{}
+	}
+
+	s := Summary{N: len(ds), Min: ds[0], Max: ds[0]}
+	var sum float64
+	for _, d := range ds {
+		sum += float64(d)
+		s.Min = min(s.Min, d)
+		s.Max = max(s.Max, d)
+	}
+	mean := sum / float64(len(ds))
+	s.Mean = time.Duration(mean)
+
+	if len(ds) > 1 {
+		var sq float64
+		for _, d := range ds {
+			diff := float64(d) - mean
+			sq += diff * diff
+		}
+		s.StdDev = time.Duration(math.Sqrt(sq / float64(len(ds)-1)))
+	}
+
+	return s
+}
@@ -0,0 +1,55 @@
+/**
+ * The --stress flag.
+ *
+ * --stress=N runs the selected example N times with chaos enabled: prints and
+ * instrumented channel operations randomly yield or sleep, so orderings that
+ * rarely happen naturally show up. Every run is checked for panics, leaked
+ * goroutines and hangs. Data races are only reported when the binary is
+ * built with the race detector, so build or run with -race.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"threads/examples"
+)
+
+// stressTimeout is how long a single stress run may take before it is
+// reported as hung
+const stressTimeout = 30 * time.Second
+
+// runStress runs the example n times and returns the exit code
+func runStress(name string, n int) int {
+	ex, ok := examples.Lookup(name)
+	if !ok {
+		fmt.Printf("Unknown example %q\n", name)
+		return 1
+	}
+
+	fmt.Printf("\nStress testing %q: %d runs with chaos enabled\n", ex.Title, n)
+	if !raceEnabled {
+		fmt.Println("Warning: built without -race, data races will not be detected (use: go run -race . --stress=N ...)")
+	}
+
+	start := time.Now()
+	report, err := examples.Stress(context.Background(), ex.Name, n, stressTimeout, os.Stdout)
+
+	fmt.Printf("\n%d runs in %v: %d panicked, %d leaked goroutines", report.Runs,
+		time.Since(start).Round(time.Millisecond), report.Failures, report.Leaks)
+	if report.HungAt > 0 {
+		fmt.Printf(", run %d hung", report.HungAt)
+	}
+	fmt.Printf("\n%d distinct output(s) observed\n", report.DistinctOutputs)
+
+	if err != nil {
+		fmt.Println("FAIL:", err)
+		return 1
+	}
+	fmt.Println("PASS")
+	return 0
+}
@@ -0,0 +1,44 @@
+package syncx
+
+import "sync/atomic"
+
+// cacheLineSize is a common L1 cache line size on amd64 and arm64
+const cacheLineSize = 64
+
+// ShardedCounter is a counter split into shards, each padded to its own cache
+// line. Writers that use different shards never write to the same cache line,
+// so increments scale with cores; Load pays for this by summing every shard
+// and is only eventually consistent while writers are active.
+type ShardedCounter struct {
+	shards []counterShard
+}
+
+type counterShard struct {
+	n atomic.Int64
+	_ [cacheLineSize - 8]byte // Keeps neighbouring shards off the same cache line
+}
+
+// NewShardedCounter returns a zero counter with the given number of shards.
+// It panics if shards is less than one.
+func NewShardedCounter(shards int) *ShardedCounter {
+	if shards < 1 {
+		panic("syncx: ShardedCounter needs at least one shard")
+	}
+	return &ShardedCounter{shards: make([]counterShard, shards)}
+}
+
+// Add adds delta to the given shard. Callers usually pass a worker index so
+// that each goroutine keeps to its own shard; any int is valid.
+func (c *ShardedCounter) Add(shard int, delta int64) {
+	n := uint(shard) % uint(len(c.shards))
+	c.shards[n].n.Add(delta)
+}
+
+// Load returns the sum of all shards.
+func (c *ShardedCounter) Load() int64 {
+	var total int64
+	for i := range c.shards {
+		total += c.shards[i].n.Load()
+	}
+	return total
+}
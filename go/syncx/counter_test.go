@@ -0,0 +1,58 @@
+package syncx
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestShardedCounter(t *testing.T) {
+	tests := []struct {
+		name    string
+		shards  int
+		workers int
+	}{
+		{"one shard", 1, 8},
+		{"shard per worker", 8, 8},
+		{"more workers than shards", 4, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const perWorker = 1000
+			c := NewShardedCounter(tt.shards)
+			var wg sync.WaitGroup
+			for w := range tt.workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range perWorker {
+						c.Add(w, 1)
+					}
+				}()
+			}
+			wg.Wait()
+			if got, want := c.Load(), int64(tt.workers*perWorker); got != want {
+				t.Errorf("Load = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestShardedCounterAnyShard(t *testing.T) {
+	c := NewShardedCounter(3)
+	for _, shard := range []int{0, 2, 3, 100, -1, -7} {
+		c.Add(shard, 2)
+	}
+	c.Add(1, -5)
+	if got := c.Load(); got != 7 {
+		t.Errorf("Load = %d, want 7", got)
+	}
+}
+
+func TestNewShardedCounterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewShardedCounter(0) did not panic")
+		}
+	}()
+	NewShardedCounter(0)
+}
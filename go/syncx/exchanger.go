@@ -0,0 +1,64 @@
+package syncx
+
+import (
+	"context"
+	"sync"
+)
+
+// Exchanger is a synchronisation point where pairs of goroutines swap
+// values. Each call to Exchange blocks until another goroutine calls it too,
+// then both return the other's value. The zero value is ready to use.
+type Exchanger[T any] struct {
+	mu      sync.Mutex
+	waiting *exchangeOffer[T]
+}
+
+// exchangeOffer is a value waiting for a partner, with a buffered channel
+// for the partner's reply
+type exchangeOffer[T any] struct {
+	value T
+	reply chan T
+}
+
+// Exchange waits for a partner, gives it v and returns the partner's value.
+func (e *Exchanger[T]) Exchange(v T) T {
+	got, _ := e.ExchangeContext(context.Background(), v)
+	return got
+}
+
+// ExchangeContext is like Exchange but gives up when ctx is done, returning
+// the context's cancellation cause. If a partner has already taken v by
+// then, the exchange completes and no error is returned.
+func (e *Exchanger[T]) ExchangeContext(ctx context.Context, v T) (T, error) {
+	e.mu.Lock()
+	if partner := e.waiting; partner != nil {
+		e.waiting = nil
+		e.mu.Unlock()
+		partner.reply <- v
+		return partner.value, nil
+	}
+
+	offer := &exchangeOffer[T]{value: v, reply: make(chan T, 1)}
+	e.waiting = offer
+	e.mu.Unlock()
+
+	select {
+	case got := <-offer.reply:
+		return got, nil
+	case <-ctx.Done():
+	}
+
+	// Withdraw the offer unless a partner claimed it in the meantime
+	e.mu.Lock()
+	withdrawn := e.waiting == offer
+	if withdrawn {
+		e.waiting = nil
+	}
+	e.mu.Unlock()
+
+	if withdrawn {
+		var zero T
+		return zero, context.Cause(ctx)
+	}
+	return <-offer.reply, nil
+}
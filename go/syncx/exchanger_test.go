@@ -0,0 +1,83 @@
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExchangerPair(t *testing.T) {
+	var e Exchanger[string]
+	got := make(chan string)
+	go func() { got <- e.Exchange("left") }()
+
+	if v := e.Exchange("right"); v != "left" {
+		t.Errorf("second caller got %q, want %q", v, "left")
+	}
+	if v := <-got; v != "right" {
+		t.Errorf("first caller got %q, want %q", v, "right")
+	}
+}
+
+// TestExchangerManyPairs checks that every value goes to exactly one partner
+// and that partners swap with each other. Run it with -race.
+func TestExchangerManyPairs(t *testing.T) {
+	const n = 100 // Even, so everyone finds a partner
+	var e Exchanger[int]
+	got := make([]int, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = e.Exchange(i)
+		}()
+	}
+	wg.Wait()
+
+	for i, partner := range got {
+		if partner == i || got[partner] != i {
+			t.Errorf("%d got %d, which got %d; want a mutual swap", i, partner, got[partner])
+		}
+	}
+}
+
+func TestExchangerContext(t *testing.T) {
+	stop := errors.New("stop")
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{"cancelled with a cause", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancelCause(context.Background())
+			cancel(stop)
+			return ctx, func() {}
+		}, stop},
+		{"deadline", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 10*time.Millisecond)
+		}, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e Exchanger[int]
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			v, err := e.ExchangeContext(ctx, 1)
+			if !errors.Is(err, tt.wantErr) || v != 0 {
+				t.Errorf("ExchangeContext = %d, %v; want 0, %v", v, err, tt.wantErr)
+			}
+
+			// The withdrawn offer is not picked up by the next pair
+			got := make(chan int)
+			go func() { got <- e.Exchange(2) }()
+			if v := e.Exchange(3); v != 2 {
+				t.Errorf("next pair swapped %d, want 2", v)
+			}
+			<-got
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Package syncx provides synchronisation primitives that the sync package
+// does not, such as latches, exchangers and task trackers.
+package syncx
+
+import (
+	"context"
+	"sync"
+)
+
+// CountDownLatch blocks waiters until CountDown has been called a fixed
+// number of times. Once the count reaches zero the latch stays open; it
+// cannot be reset.
+type CountDownLatch struct {
+	mu    sync.Mutex
+	count int
+	done  chan struct{}
+}
+
+// NewCountDownLatch returns a latch that opens after n calls to CountDown.
+// A latch created with n <= 0 is already open.
+func NewCountDownLatch(n int) *CountDownLatch {
+	l := &CountDownLatch{count: max(n, 0), done: make(chan struct{})}
+	if l.count == 0 {
+		close(l.done)
+	}
+	return l
+}
+
+// CountDown decrements the count, opening the latch when it reaches zero.
+// Calls after the latch has opened have no effect.
+func (l *CountDownLatch) CountDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count == 0 {
+		return
+	}
+	l.count--
+	if l.count == 0 {
+		close(l.done)
+	}
+}
+
+// Count returns the number of CountDown calls still needed to open the latch.
+func (l *CountDownLatch) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count
+}
+
+// Done returns a channel that is closed when the latch opens, for use in
+// select statements.
+func (l *CountDownLatch) Done() <-chan struct{} {
+	return l.done
+}
+
+// Wait blocks until the latch opens.
+func (l *CountDownLatch) Wait() {
+	<-l.done
+}
+
+// WaitContext blocks until the latch opens or ctx is done. It returns nil if
+// the latch opened and the context's cancellation cause otherwise.
+func (l *CountDownLatch) WaitContext(ctx context.Context) error {
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
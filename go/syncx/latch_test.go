@@ -0,0 +1,114 @@
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// isOpen reports whether the latch's Done channel is closed
+func isOpen(l *CountDownLatch) bool {
+	select {
+	case <-l.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestCountDownLatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          int
+		countDowns int
+		wantCount  int
+		wantOpen   bool
+	}{
+		{"zero is open", 0, 0, 0, true},
+		{"negative is open", -3, 0, 0, true},
+		{"not yet", 3, 2, 1, false},
+		{"exactly", 3, 3, 0, true},
+		{"extra calls are ignored", 2, 5, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewCountDownLatch(tt.n)
+			for range tt.countDowns {
+				l.CountDown()
+			}
+			if l.Count() != tt.wantCount {
+				t.Errorf("Count = %d, want %d", l.Count(), tt.wantCount)
+			}
+			if isOpen(l) != tt.wantOpen {
+				t.Errorf("open = %v, want %v", isOpen(l), tt.wantOpen)
+			}
+		})
+	}
+}
+
+func TestCountDownLatchReleasesAllWaiters(t *testing.T) {
+	const waiters = 5
+	l := NewCountDownLatch(3)
+
+	var released sync.WaitGroup
+	for range waiters {
+		released.Add(1)
+		go func() {
+			defer released.Done()
+			l.Wait()
+		}()
+	}
+
+	// Concurrent CountDown calls open the latch exactly once
+	var counters sync.WaitGroup
+	for range 3 {
+		counters.Add(1)
+		go func() {
+			defer counters.Done()
+			l.CountDown()
+		}()
+	}
+	counters.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		released.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("waiters were not released when the count reached zero")
+	}
+}
+
+func TestCountDownLatchWaitContext(t *testing.T) {
+	stop := errors.New("stop")
+	tests := []struct {
+		name    string
+		open    bool
+		cancel  error // Cause to cancel with, or nil to leave the context alone
+		wantErr error
+	}{
+		{"open latch", true, nil, nil},
+		{"cancelled", false, stop, stop},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewCountDownLatch(1)
+			if tt.open {
+				l.CountDown()
+			}
+			ctx, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+			if tt.cancel != nil {
+				cancel(tt.cancel)
+			}
+			if err := l.WaitContext(ctx); !errors.Is(err, tt.wantErr) {
+				t.Errorf("WaitContext = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package syncx
+
+import "context"
+
+// Semaphore is a counting semaphore: at most n holders at a time. It is the
+// buffered channel pattern with a name, where a slot taken by a send is held
+// until a receive gives it back, plus a context-aware Acquire.
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore returns a semaphore with n free slots. It panics if n is less
+// than one.
+func NewSemaphore(n int) *Semaphore {
+	if n < 1 {
+		panic("syncx: Semaphore needs at least one slot")
+	}
+	return &Semaphore{slots: make(chan struct{}, n)}
+}
+
+// Acquire takes a slot, waiting until one is free or ctx is done. It returns
+// nil once the slot is held and the context's cancellation cause otherwise,
+// in which case no slot is taken.
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	// Checked first so a cancelled context never wins against a free slot
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// TryAcquire takes a slot if one is free and reports whether it did. It
+// never waits.
+func (s *Semaphore) TryAcquire() bool {
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release gives back a slot taken by Acquire or TryAcquire. It panics if no
+// slot is held.
+func (s *Semaphore) Release() {
+	select {
+	case <-s.slots:
+	default:
+		panic("syncx: Semaphore.Release called without a held slot")
+	}
+}
+
+// Held returns the number of slots currently held. Like len on a channel it
+// is only a snapshot.
+func (s *Semaphore) Held() int {
+	return len(s.slots)
+}
+
+// Cap returns the number of slots.
+func (s *Semaphore) Cap() int {
+	return cap(s.slots)
+}
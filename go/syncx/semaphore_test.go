@@ -0,0 +1,102 @@
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSemaphoreLimit(t *testing.T) {
+	sem := NewSemaphore(3)
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sem.Acquire(context.Background()); err != nil {
+				t.Errorf("Acquire = %v, want nil", err)
+				return
+			}
+			defer sem.Release()
+			n := running.Add(1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if p := peak.Load(); p > 3 {
+		t.Errorf("%d holders at once, want at most 3", p)
+	}
+	if n := sem.Held(); n != 0 {
+		t.Errorf("Held after every Release = %d, want 0", n)
+	}
+}
+
+func TestSemaphoreTryAcquire(t *testing.T) {
+	sem := NewSemaphore(2)
+	if !sem.TryAcquire() || !sem.TryAcquire() {
+		t.Fatal("TryAcquire on a semaphore with free slots = false, want true")
+	}
+	if sem.TryAcquire() {
+		t.Error("TryAcquire on a full semaphore = true, want false")
+	}
+	if n := sem.Held(); n != 2 {
+		t.Errorf("Held = %d, want 2", n)
+	}
+
+	sem.Release()
+	if !sem.TryAcquire() {
+		t.Error("TryAcquire after a Release = false, want true")
+	}
+}
+
+func TestSemaphoreAcquireCancelled(t *testing.T) {
+	sem := NewSemaphore(1)
+	sem.Acquire(context.Background())
+
+	// Cancelled while waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := sem.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire on a full semaphore = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Acquire returned after %v, want it to stop at the deadline", elapsed)
+	}
+
+	// A waiter that gave up must not have taken the slot when it was freed
+	sem.Release()
+	if n := sem.Held(); n != 0 {
+		t.Errorf("Held after the only holder released = %d, want 0", n)
+	}
+
+	// Cancelled before the call, with a cause: never takes even a free slot
+	cause := errors.New("shutting down")
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+	cancelCause(cause)
+	for range 100 {
+		if err := sem.Acquire(ctx); !errors.Is(err, cause) {
+			t.Fatalf("Acquire with a cancelled context = %v, want %v", err, cause)
+		}
+	}
+	if n := sem.Held(); n != 0 {
+		t.Errorf("Held after cancelled Acquires = %d, want 0", n)
+	}
+}
+
+func TestSemaphoreReleaseWithoutAcquire(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Release without a held slot did not panic")
+		}
+	}()
+	NewSemaphore(1).Release()
+}
@@ -0,0 +1,120 @@
+package syncx
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// ShardedMap is a concurrent map split into shards, each a plain map with
+// its own RWMutex (lock striping). A key's shard is chosen by its hash, so
+// goroutines working on keys in different shards never contend for a lock.
+// Operations on a single key are atomic; Len and Range visit the shards one
+// at a time and so do not see a consistent snapshot of the whole map.
+type ShardedMap[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []mapShard[K, V]
+}
+
+type mapShard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+	_  [64]byte // Keeps neighbouring shards' locks off the same cache line
+}
+
+// NewShardedMap returns an empty map with the given number of shards. It
+// panics if shards is less than one.
+func NewShardedMap[K comparable, V any](shards int) *ShardedMap[K, V] {
+	if shards < 1 {
+		panic("syncx: ShardedMap needs at least one shard")
+	}
+	sm := &ShardedMap[K, V]{seed: maphash.MakeSeed(), shards: make([]mapShard[K, V], shards)}
+	for i := range sm.shards {
+		sm.shards[i].m = map[K]V{}
+	}
+	return sm
+}
+
+func (sm *ShardedMap[K, V]) shard(key K) *mapShard[K, V] {
+	return &sm.shards[maphash.Comparable(sm.seed, key)%uint64(len(sm.shards))]
+}
+
+// Load returns the value stored under key, if any.
+func (sm *ShardedMap[K, V]) Load(key K) (V, bool) {
+	s := sm.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[key]
+	return v, ok
+}
+
+// Store sets the value for key.
+func (sm *ShardedMap[K, V]) Store(key K, value V) {
+	s := sm.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[key] = value
+}
+
+// LoadOrStore returns the existing value for key if present. Otherwise it
+// stores value and returns it. The loaded result is true if the value was
+// already present.
+func (sm *ShardedMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := sm.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[key]; ok {
+		return v, true
+	}
+	s.m[key] = value
+	return value, false
+}
+
+// Update replaces the value for key with fn applied to the current value
+// (the zero value and false if there is none), holding the shard's lock so
+// that the read-modify-write is atomic, and returns the new value.
+func (sm *ShardedMap[K, V]) Update(key K, fn func(V, bool) V) V {
+	s := sm.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[key]
+	v = fn(v, ok)
+	s.m[key] = v
+	return v
+}
+
+// Delete removes key from the map.
+func (sm *ShardedMap[K, V]) Delete(key K) {
+	s := sm.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, key)
+}
+
+// Len returns the number of entries. Shards are counted one at a time, so
+// the result is approximate while other goroutines modify the map.
+func (sm *ShardedMap[K, V]) Len() int {
+	n := 0
+	for i := range sm.shards {
+		s := &sm.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Range calls fn for every entry until fn returns false. Each shard is read
+// locked while it is visited, so fn must not modify the map.
+func (sm *ShardedMap[K, V]) Range(fn func(K, V) bool) {
+	for i := range sm.shards {
+		s := &sm.shards[i]
+		s.mu.RLock()
+		for k, v := range s.m {
+			if !fn(k, v) {
+				s.mu.RUnlock()
+				return
+			}
+		}
+		s.mu.RUnlock()
+	}
+}
@@ -0,0 +1,87 @@
+package syncx
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+)
+
+// concurrentMap is the subset of map operations the benchmark exercises
+type concurrentMap interface {
+	Load(key int) (int, bool)
+	Store(key, value int)
+}
+
+// rwMap is a plain map behind a single RWMutex
+type rwMap struct {
+	mu sync.RWMutex
+	m  map[int]int
+}
+
+func (m *rwMap) Load(key int) (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.m[key]
+	return v, ok
+}
+
+func (m *rwMap) Store(key, value int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.m[key] = value
+}
+
+// syncMap adapts sync.Map to concurrentMap
+type syncMap struct{ m sync.Map }
+
+func (m *syncMap) Load(key int) (int, bool) {
+	v, ok := m.m.Load(key)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (m *syncMap) Store(key, value int) { m.m.Store(key, value) }
+
+// BenchmarkMap compares a single RWMutex map, sync.Map and sharded maps
+// under parallel load, for several percentages of reads. sync.Map is built
+// for read-mostly keys that are written once; striped locks do better when
+// writes are common.
+func BenchmarkMap(b *testing.B) {
+	const keys = 4096
+
+	maps := []struct {
+		name string
+		new  func() concurrentMap
+	}{
+		{"rwmutex", func() concurrentMap { return &rwMap{m: map[int]int{}} }},
+		{"sync.Map", func() concurrentMap { return &syncMap{} }},
+		{"sharded-16", func() concurrentMap { return NewShardedMap[int, int](16) }},
+		{"sharded-64", func() concurrentMap { return NewShardedMap[int, int](64) }},
+	}
+
+	for _, readPct := range []int{50, 90, 99} {
+		for _, m := range maps {
+			b.Run(fmt.Sprintf("reads=%d%%/%s", readPct, m.name), func(b *testing.B) {
+				cm := m.new()
+				for i := range keys {
+					cm.Store(i, i)
+				}
+
+				b.RunParallel(func(pb *testing.PB) {
+					r := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+					for pb.Next() {
+						key := r.IntN(keys)
+						if r.IntN(100) < readPct {
+							cm.Load(key)
+						} else {
+							cm.Store(key, key)
+						}
+					}
+				})
+			})
+		}
+	}
+}
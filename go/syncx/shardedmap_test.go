@@ -0,0 +1,129 @@
+package syncx
+
+import (
+	"maps"
+	"sync"
+	"testing"
+)
+
+func TestShardedMapOperations(t *testing.T) {
+	sm := NewShardedMap[string, int](4)
+	if _, ok := sm.Load("a"); ok {
+		t.Fatal("Load on an empty map reported a value")
+	}
+
+	sm.Store("a", 1)
+	sm.Store("b", 2)
+	sm.Store("a", 3) // Replaces
+	if v, ok := sm.Load("a"); !ok || v != 3 {
+		t.Errorf("Load(a) = %d, %v; want 3, true", v, ok)
+	}
+
+	if v, loaded := sm.LoadOrStore("b", 20); !loaded || v != 2 {
+		t.Errorf("LoadOrStore(b) = %d, %v; want the existing 2, true", v, loaded)
+	}
+	if v, loaded := sm.LoadOrStore("c", 30); loaded || v != 30 {
+		t.Errorf("LoadOrStore(c) = %d, %v; want the stored 30, false", v, loaded)
+	}
+
+	add := func(v int, ok bool) int {
+		if !ok {
+			return 100
+		}
+		return v + 1
+	}
+	if v := sm.Update("c", add); v != 31 {
+		t.Errorf("Update(c) = %d, want 31", v)
+	}
+	if v := sm.Update("d", add); v != 100 {
+		t.Errorf("Update(d) on a missing key = %d, want 100", v)
+	}
+
+	if sm.Len() != 4 {
+		t.Errorf("Len = %d, want 4", sm.Len())
+	}
+	sm.Delete("a")
+	sm.Delete("missing") // Deleting a missing key is a no-op
+	if _, ok := sm.Load("a"); ok {
+		t.Error("Load found a deleted key")
+	}
+	if sm.Len() != 3 {
+		t.Errorf("Len after Delete = %d, want 3", sm.Len())
+	}
+}
+
+func TestShardedMapRange(t *testing.T) {
+	sm := NewShardedMap[int, int](8)
+	want := map[int]int{}
+	for i := range 100 {
+		sm.Store(i, i*i)
+		want[i] = i * i
+	}
+
+	got := map[int]int{}
+	sm.Range(func(k, v int) bool {
+		if _, dup := got[k]; dup {
+			t.Errorf("Range visited %d twice", k)
+		}
+		got[k] = v
+		return true
+	})
+	if !maps.Equal(got, want) {
+		t.Errorf("Range visited %d entries, want all %d with their values", len(got), len(want))
+	}
+
+	// Returning false stops the iteration
+	visited := 0
+	sm.Range(func(k, v int) bool {
+		visited++
+		return visited < 10
+	})
+	if visited != 10 {
+		t.Errorf("Range called fn %d times after it returned false, want 10", visited)
+	}
+}
+
+func TestNewShardedMapPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewShardedMap(0) did not panic")
+		}
+	}()
+	NewShardedMap[int, int](0)
+}
+
+// TestShardedMapConcurrent mixes every operation across goroutines; run it
+// with -race. Update is atomic per key, so no increment is lost.
+func TestShardedMapConcurrent(t *testing.T) {
+	const goroutines, keys, rounds = 8, 32, 500
+	sm := NewShardedMap[int, int](4)
+
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rounds {
+				key := (g + i) % keys
+				sm.Update(key, func(v int, _ bool) int { return v + 1 })
+				sm.Load(key)
+				sm.LoadOrStore(keys+key, key)
+				if i%50 == 0 {
+					sm.Range(func(int, int) bool { return true })
+					sm.Delete(keys + key)
+					sm.Len()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for key := range keys {
+		v, _ := sm.Load(key)
+		total += v
+	}
+	if total != goroutines*rounds {
+		t.Errorf("counters sum to %d, want %d", total, goroutines*rounds)
+	}
+}
@@ -0,0 +1,101 @@
+package syncx
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// trackerIdle marks a TaskTracker whose Done channel has been closed
+const trackerIdle = -1
+
+// TaskTracker waits for a set of goroutines that may start more goroutines,
+// without knowing their number in advance. Go counts a task before starting
+// it, so a task that spawns children from inside Go keeps the count above
+// zero until they have all finished.
+//
+// Go may be called from outside the tracked tasks only before the first
+// call to Wait, WaitContext or Done, the same rule as for
+// sync.WaitGroup.Add. Once the count then reaches zero the tracker is idle
+// for good and Go panics. A call from outside that races with the last task
+// finishing either extends the work or panics; it never starts a task the
+// waiter does not wait for.
+type TaskTracker struct {
+	n       atomic.Int64 // Tasks in flight, or trackerIdle
+	waiting atomic.Bool
+	done    chan struct{}
+}
+
+// NewTaskTracker returns a tracker with no tasks.
+func NewTaskTracker() *TaskTracker {
+	return &TaskTracker{done: make(chan struct{})}
+}
+
+// Go runs fn in a new goroutine, counting it until fn returns. It panics if
+// the tracker has already gone idle.
+func (t *TaskTracker) Go(fn func()) {
+	for {
+		n := t.n.Load()
+		if n == trackerIdle {
+			panic("syncx: TaskTracker.Go called after the tracker went idle")
+		}
+		if t.n.CompareAndSwap(n, n+1) {
+			break
+		}
+	}
+	go func() {
+		defer t.finish()
+		fn()
+	}()
+}
+
+func (t *TaskTracker) finish() {
+	// Either this sees waiting set or the waiter's tryIdle sees the zero
+	if t.n.Add(-1) == 0 && t.waiting.Load() {
+		t.tryIdle()
+	}
+}
+
+// wait marks the tracker as waited on and closes done if no task is in
+// flight
+func (t *TaskTracker) wait() {
+	t.waiting.Store(true)
+	t.tryIdle()
+}
+
+// tryIdle closes done if no task is in flight. Only the caller that moves
+// the count from zero to trackerIdle closes it, and a Go that got there
+// first keeps the tracker running.
+func (t *TaskTracker) tryIdle() {
+	if t.n.CompareAndSwap(0, trackerIdle) {
+		close(t.done)
+	}
+}
+
+// Count returns the number of tasks in flight.
+func (t *TaskTracker) Count() int {
+	return int(max(t.n.Load(), 0))
+}
+
+// Done returns a channel that is closed once every task has finished, for
+// use in select statements. Like Wait, it marks the tracker as waited on.
+func (t *TaskTracker) Done() <-chan struct{} {
+	t.wait()
+	return t.done
+}
+
+// Wait blocks until every task, including tasks started by other tasks,
+// has finished.
+func (t *TaskTracker) Wait() {
+	<-t.Done()
+}
+
+// WaitContext is like Wait but gives up when ctx is done. It returns nil if
+// every task finished and the context's cancellation cause otherwise.
+func (t *TaskTracker) WaitContext(ctx context.Context) error {
+	select {
+	case <-t.Done():
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
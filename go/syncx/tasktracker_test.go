@@ -0,0 +1,99 @@
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTaskTrackerTree(t *testing.T) {
+	// A full tree with three children per task, five levels below the
+	// root: 1 + 3 + 9 + 27 + 81 + 243 tasks
+	const want = 364
+
+	for range 50 {
+		tr := NewTaskTracker()
+		var finished atomic.Int64
+		var task func(depth int)
+		task = func(depth int) {
+			defer finished.Add(1)
+			if depth == 0 {
+				return
+			}
+			for range 3 {
+				tr.Go(func() { task(depth - 1) })
+			}
+		}
+		tr.Go(func() { task(5) })
+		tr.Wait()
+
+		if n := finished.Load(); n != want {
+			t.Fatalf("Wait returned after %d tasks finished, want %d", n, want)
+		}
+		if n := tr.Count(); n != 0 {
+			t.Fatalf("Count after Wait = %d, want 0", n)
+		}
+	}
+}
+
+func TestTaskTrackerGoBeforeWait(t *testing.T) {
+	tr := NewTaskTracker()
+	var finished atomic.Int64
+	tr.Go(func() { finished.Add(1) })
+	time.Sleep(10 * time.Millisecond) // Let the count drop to zero
+
+	// The count has touched zero, but nobody was waiting yet
+	tr.Go(func() {
+		time.Sleep(10 * time.Millisecond)
+		finished.Add(1)
+	})
+	tr.Wait()
+	if n := finished.Load(); n != 2 {
+		t.Errorf("Wait returned after %d tasks finished, want 2", n)
+	}
+}
+
+func TestTaskTrackerNoTasks(t *testing.T) {
+	tr := NewTaskTracker()
+	select {
+	case <-tr.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done was not closed for a tracker without tasks")
+	}
+	tr.Wait()
+}
+
+func TestTaskTrackerGoAfterIdle(t *testing.T) {
+	tr := NewTaskTracker()
+	tr.Go(func() {})
+	tr.Wait()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Go after Wait returned did not panic")
+		}
+	}()
+	tr.Go(func() {})
+}
+
+func TestTaskTrackerWaitContext(t *testing.T) {
+	tr := NewTaskTracker()
+	release := make(chan struct{})
+	tr.Go(func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tr.WaitContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitContext with a task running = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := tr.Count(); n != 1 {
+		t.Errorf("Count = %d, want 1", n)
+	}
+
+	close(release)
+	if err := tr.WaitContext(context.Background()); err != nil {
+		t.Errorf("WaitContext after the task finished = %v, want nil", err)
+	}
+}
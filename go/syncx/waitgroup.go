@@ -0,0 +1,71 @@
+package syncx
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+
+	"threads/safego"
+)
+
+// WaitGroup is a sync.WaitGroup that starts the goroutines itself and
+// collects what they report. Go does the Add and Done, a function's error
+// and a recovered panic are both kept for Wait, and Wait takes a context so
+// the caller can stop waiting. The zero value is ready to use.
+type WaitGroup struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go runs fn in a new goroutine. An error it returns, or a panic it raises
+// (as a *safego.PanicError), is reported by Wait.
+func (g *WaitGroup) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				g.report(&safego.PanicError{Value: r, Stack: debug.Stack()})
+			}
+		}()
+		if err := fn(); err != nil {
+			g.report(err)
+		}
+	}()
+}
+
+func (g *WaitGroup) report(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.errs = append(g.errs, err)
+}
+
+// Wait blocks until every function has returned or ctx is done. It returns
+// the functions' errors joined with errors.Join, in the order they were
+// reported, or the context's cancellation cause if ctx ended the wait.
+// Functions still running then are not stopped; they are only no longer
+// waited for, and a later Wait can wait for them again.
+//
+// Wait blocks on the underlying sync.WaitGroup in a helper goroutine. When
+// ctx ends the wait, that goroutine stays alive until every function has
+// returned, so a Wait that gives up on functions that never return leaks
+// one goroutine.
+func (g *WaitGroup) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
@@ -0,0 +1,68 @@
+package syncx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"threads/safego"
+)
+
+func TestWaitGroupErrors(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	var g WaitGroup
+	g.Go(func() error { return errA })
+	g.Go(func() error { return nil })
+	g.Go(func() error { return errB })
+
+	err := g.Wait(context.Background())
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Wait = %v, want it to contain %v and %v", err, errA, errB)
+	}
+}
+
+func TestWaitGroupNoErrors(t *testing.T) {
+	var g WaitGroup
+	for range 10 {
+		g.Go(func() error { return nil })
+	}
+	if err := g.Wait(context.Background()); err != nil {
+		t.Errorf("Wait = %v, want nil", err)
+	}
+}
+
+func TestWaitGroupPanic(t *testing.T) {
+	var g WaitGroup
+	g.Go(func() error { panic("boom") })
+
+	err := g.Wait(context.Background())
+	var pe *safego.PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Wait = %v, want a *safego.PanicError", err)
+	}
+	if pe.Value != "boom" || len(pe.Stack) == 0 {
+		t.Errorf("PanicError = %v with a %d-byte stack, want the value boom and a stack", pe.Value, len(pe.Stack))
+	}
+}
+
+func TestWaitGroupTimeout(t *testing.T) {
+	var g WaitGroup
+	release := make(chan struct{})
+	g.Go(func() error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait with a blocked function = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// The function is still tracked and a second Wait sees it finish
+	close(release)
+	if err := g.Wait(context.Background()); err != nil {
+		t.Errorf("Wait after the function returned = %v, want nil", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Package topology describes the goroutines and channels that make up a
+// concurrency pattern and renders them as Mermaid or Graphviz DOT diagrams.
+//
+// Demos build a Graph with a small fluent API:
+//
+//	g := topology.New("Fan-out, Fan-in").
+//		Goroutine("gen", "generator").
+//		Channel("in", "input").
+//		Flow("gen", "in", "")
+//
+// Goroutines are drawn as rounded boxes and channels as slanted boxes; flows
+// are arrows in the direction values travel.
+package topology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind is the kind of a node in a topology graph.
+type Kind int
+
+const (
+	// Goroutine is a node that runs code.
+	Goroutine Kind = iota
+	// Channel is a node values pass through.
+	Channel
+)
+
+type node struct {
+	id, label string
+	kind      Kind
+}
+
+type flow struct {
+	from, to, label string
+}
+
+// Graph is the topology of one concurrency pattern.
+type Graph struct {
+	title string
+	nodes []node
+	flows []flow
+}
+
+// New returns an empty graph with the given title.
+func New(title string) *Graph {
+	return &Graph{title: title}
+}
+
+// Title returns the graph's title.
+func (g *Graph) Title() string {
+	return g.title
+}
+
+// Goroutine adds a goroutine node and returns g.
+func (g *Graph) Goroutine(id, label string) *Graph {
+	g.nodes = append(g.nodes, node{id, label, Goroutine})
+	return g
+}
+
+// Channel adds a channel node and returns g.
+func (g *Graph) Channel(id, label string) *Graph {
+	g.nodes = append(g.nodes, node{id, label, Channel})
+	return g
+}
+
+// Flow adds an arrow from one node to another, with an optional label, and
+// returns g.
+func (g *Graph) Flow(from, to, label string) *Graph {
+	g.flows = append(g.flows, flow{from, to, label})
+	return g
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntitle: %s\n---\nflowchart LR\n", g.title)
+	for _, n := range g.nodes {
+		switch n.kind {
+		case Goroutine:
+			fmt.Fprintf(&b, "    %s([%q])\n", n.id, n.label)
+		case Channel:
+			fmt.Fprintf(&b, "    %s[/%q/]\n", n.id, n.label)
+		}
+	}
+	for _, f := range g.flows {
+		if f.label == "" {
+			fmt.Fprintf(&b, "    %s --> %s\n", f.from, f.to)
+		} else {
+			fmt.Fprintf(&b, "    %s -->|%q| %s\n", f.from, f.label, f.to)
+		}
+	}
+	return b.String()
+}
+
+// DOT renders the graph in the Graphviz DOT language.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n    label=%q;\n    rankdir=LR;\n", g.title, g.title)
+	for _, n := range g.nodes {
+		switch n.kind {
+		case Goroutine:
+			fmt.Fprintf(&b, "    %q [label=%q, shape=box, style=rounded];\n", n.id, n.label)
+		case Channel:
+			fmt.Fprintf(&b, "    %q [label=%q, shape=parallelogram];\n", n.id, n.label)
+		}
+	}
+	for _, f := range g.flows {
+		if f.label == "" {
+			fmt.Fprintf(&b, "    %q -> %q;\n", f.from, f.to)
+		} else {
+			fmt.Fprintf(&b, "    %q -> %q [label=%q];\n", f.from, f.to, f.label)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,39 @@
+/**
+ * Execution tracing for the selected example.
+ *
+ * With --trace=FILE the whole session is recorded with runtime/trace. Every
+ * example run shows up as a user task named after the example, with one
+ * region per run, so `go tool trace FILE` can filter the goroutine timeline
+ * down to a single pattern.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/trace"
+)
+
+// startTrace starts the execution tracer writing to path and returns a
+// function that stops it and closes the file.
+func startTrace(path string) (stop func(), err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := trace.Start(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() {
+		trace.Stop()
+		if err := f.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, "trace:", err)
+			return
+		}
+		fmt.Printf("Execution trace written to %s; inspect it with: go tool trace %s\n", path, path)
+	}, nil
+}
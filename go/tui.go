@@ -0,0 +1,332 @@
+//go:build !js
+
+/**
+ * The --tui flag.
+ *
+ * --tui replaces the line-based menu with a full-screen terminal UI: a
+ * scrollable list of the examples grouped like the menu, an output pane that
+ * shows the selected example's output as it is printed, the elapsed time of
+ * the current run and a key to cancel it. Built with Bubble Tea.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"threads/examples"
+)
+
+// listWidth is the width of the example list, including its border
+const listWidth = 44
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	headingStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	dimStyle      = lipgloss.NewStyle().Faint(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+const tuiHelp = "↑/↓ select • enter run • c cancel • pgup/pgdn scroll output • q quit"
+
+// tuiRow is a line of the example list: a heading or an example
+type tuiRow struct {
+	heading string
+	ex      examples.Example
+}
+
+// Messages delivered to the model
+type (
+	outputMsg string // Text printed by the running example
+	doneMsg   struct {
+		run int // Which run finished, so stale results are ignored
+		err error
+	}
+	tickMsg time.Time
+)
+
+type tuiModel struct {
+	program *tea.Program // Set before the program starts, used by the output writer
+
+	rows   []tuiRow
+	cursor int // Index into rows, always on an example
+	top    int // First visible row
+
+	width, height int
+
+	// Current or last run
+	run     int
+	running bool
+	name    string
+	started time.Time
+	elapsed time.Duration
+	cancel  context.CancelFunc
+	status  string
+	output  []string // Completed lines, plus the partial last line
+	scroll  int      // Lines scrolled up from the bottom of the output
+}
+
+func runTUI() error {
+	m := &tuiModel{rows: tuiRows()}
+	for m.cursor < len(m.rows) && m.rows[m.cursor].heading != "" {
+		m.cursor++
+	}
+	if m.cursor == len(m.rows) {
+		return fmt.Errorf("no examples match the filters")
+	}
+
+	m.program = tea.NewProgram(m, tea.WithAltScreen())
+	_, err := m.program.Run()
+	if m.cancel != nil {
+		m.cancel()
+	}
+	return err
+}
+
+// tuiRows groups the selected examples under the same headings as the menu
+func tuiRows() []tuiRow {
+	var rows []tuiRow
+	add := func(heading string, include func(examples.Example) bool) {
+		first := true
+		for _, ex := range selectedExamples() {
+			if !include(ex) {
+				continue
+			}
+			if first {
+				rows = append(rows, tuiRow{heading: heading})
+				first = false
+			}
+			rows = append(rows, tuiRow{ex: ex})
+		}
+	}
+
+	add("Basic", func(ex examples.Example) bool { return ex.Category == examples.Basic })
+	for _, tag := range examples.AllTags() {
+		add(tagHeadings[tag], func(ex examples.Example) bool {
+			tags := examples.Tags(ex.Name)
+			return ex.Category == examples.Advanced && len(tags) > 0 && tags[0] == tag
+		})
+	}
+	return rows
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clampList()
+
+	case tea.KeyMsg:
+		return m, m.handleKey(msg.String())
+
+	case outputMsg:
+		m.appendOutput(string(msg))
+
+	case tickMsg:
+		if m.running {
+			m.elapsed = time.Since(m.started)
+			return m, tick()
+		}
+
+	case doneMsg:
+		if msg.run != m.run || !m.running {
+			break
+		}
+		m.running = false
+		m.elapsed = time.Since(m.started)
+		m.cancel()
+		var timedOut *examples.TimeoutError
+		switch {
+		case errors.As(msg.err, &timedOut):
+			m.status = errorStyle.Render(fmt.Sprintf("Timed out after %v", timedOut.Timeout))
+			m.appendOutput(stuckReport(msg.err))
+		case errors.Is(msg.err, context.Canceled):
+			m.status = fmt.Sprintf("Cancelled after %v", m.elapsed.Round(time.Millisecond))
+		case msg.err != nil:
+			m.status = errorStyle.Render("Error: " + msg.err.Error())
+		default:
+			m.status = fmt.Sprintf("Finished in %v", m.elapsed.Round(time.Millisecond))
+		}
+	}
+	return m, nil
+}
+
+func (m *tuiModel) handleKey(key string) tea.Cmd {
+	switch key {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "pgup":
+		m.scroll = min(m.scroll+m.outputHeight()/2, max(len(m.output)-m.outputHeight(), 0))
+	case "pgdown":
+		m.scroll = max(m.scroll-m.outputHeight()/2, 0)
+	case "c", "esc":
+		if m.running {
+			m.cancel()
+		}
+	case "enter":
+		if !m.running {
+			return m.start(m.rows[m.cursor].ex)
+		}
+	}
+	return nil
+}
+
+// move moves the cursor by delta examples, skipping headings
+func (m *tuiModel) move(delta int) {
+	for i := m.cursor + delta; i >= 0 && i < len(m.rows); i += delta {
+		if m.rows[i].heading == "" {
+			m.cursor = i
+			break
+		}
+	}
+	m.clampList()
+}
+
+// clampList scrolls the list so that the cursor (and, at the top, its
+// heading) is visible
+func (m *tuiModel) clampList() {
+	h := m.listHeight()
+	if m.cursor-1 < m.top {
+		m.top = max(m.cursor-1, 0)
+	}
+	if m.cursor >= m.top+h {
+		m.top = m.cursor - h + 1
+	}
+}
+
+// start runs ex in the background, streaming its output to the model
+func (m *tuiModel) start(ex examples.Example) tea.Cmd {
+	m.run++
+	m.running = true
+	m.name = ex.Name
+	m.started = time.Now()
+	m.elapsed = 0
+	m.status = ""
+	m.output = []string{""}
+	m.scroll = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	run, program := m.run, m.program
+	opts := runOptions()
+	opts.Stepper = nil // Nothing can answer step prompts from inside the TUI
+	w := tuiWriter{program}
+
+	return tea.Batch(tick(), func() tea.Msg {
+		return doneMsg{run: run, err: examples.Run(ctx, ex.Name, w, opts)}
+	})
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// tuiWriter forwards an example's output to the TUI
+type tuiWriter struct {
+	program *tea.Program
+}
+
+func (w tuiWriter) Write(p []byte) (int, error) {
+	w.program.Send(outputMsg(p))
+	return len(p), nil
+}
+
+func (m *tuiModel) appendOutput(s string) {
+	if len(m.output) == 0 {
+		m.output = []string{""}
+	}
+	s = strings.ReplaceAll(s, "\t", "    ")
+	lines := strings.Split(s, "\n")
+	m.output[len(m.output)-1] += lines[0]
+	m.output = append(m.output, lines[1:]...)
+	if m.scroll > 0 {
+		m.scroll += len(lines) - 1 // Keep the scrolled-to lines in place
+	}
+}
+
+func (m *tuiModel) listHeight() int {
+	return max(m.height-3, 1) // Title, blank line and help
+}
+
+func (m *tuiModel) outputHeight() int {
+	return max(m.height-5, 1) // Title, blank line, status, rule and help
+}
+
+func (m *tuiModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Go Concurrency Examples") + "\n\n")
+
+	left := m.listLines()
+	right := m.outputLines()
+	for i := 0; i < m.listHeight(); i++ {
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		b.WriteString(lipgloss.NewStyle().Width(listWidth).MaxWidth(listWidth).Render(l))
+		b.WriteString(dimStyle.Render("│ "))
+		b.WriteString(lipgloss.NewStyle().MaxWidth(max(m.width-listWidth-2, 1)).Render(r))
+		b.WriteString("\n")
+	}
+	b.WriteString(dimStyle.Render(tuiHelp))
+	return b.String()
+}
+
+func (m *tuiModel) listLines() []string {
+	var lines []string
+	for i := m.top; i < len(m.rows) && len(lines) < m.listHeight(); i++ {
+		row := m.rows[i]
+		switch {
+		case row.heading != "":
+			lines = append(lines, headingStyle.Render(row.heading))
+		case i == m.cursor:
+			lines = append(lines, selectedStyle.Render(fmt.Sprintf("%3d. %s", row.ex.Number, row.ex.Title)))
+		default:
+			lines = append(lines, fmt.Sprintf("%3d. %s", row.ex.Number, row.ex.Title))
+		}
+	}
+	return lines
+}
+
+func (m *tuiModel) outputLines() []string {
+	var status string
+	switch {
+	case m.running:
+		status = fmt.Sprintf("Running %s… %v", m.name, m.elapsed.Round(100*time.Millisecond))
+	case m.name == "":
+		status = "Select an example and press enter"
+	default:
+		status = fmt.Sprintf("%s: %s", m.name, m.status)
+	}
+	lines := []string{titleStyle.Render(status), dimStyle.Render(strings.Repeat("─", max(m.width-listWidth-2, 1)))}
+
+	// The newest lines fill the pane, unless scrolled up
+	h := m.outputHeight()
+	end := max(len(m.output)-m.scroll, 0)
+	start := max(end-h, 0)
+	return append(lines, m.output[start:end]...)
+}
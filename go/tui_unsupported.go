@@ -0,0 +1,11 @@
+//go:build js
+
+package main
+
+import "errors"
+
+// runTUI reports that the terminal UI is not available: Bubble Tea needs a
+// real terminal, which js/wasm does not have
+func runTUI() error {
+	return errors.New("the terminal UI is not supported on js/wasm")
+}
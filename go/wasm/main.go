@@ -0,0 +1,94 @@
+//go:build js && wasm
+
+/**
+ * The js/wasm entry point.
+ *
+ * Built with GOOS=js GOARCH=wasm (see `make wasm`), this program runs the
+ * examples client-side in a browser playground. It exposes a global
+ * goExamples object to JavaScript and then waits for calls:
+ *
+ *	goExamples.list()
+ *		returns [{name, title, category, number, tags}, ...]
+ *	goExamples.run(name, onOutput, onDone, {deterministic: true})
+ *		runs an example, calling onOutput(text) as it prints and
+ *		onDone(error or null) when it finishes; returns a function that
+ *		cancels the run
+ *
+ * index.html in this directory is a loader page that uses both.
+ */
+
+package main
+
+import (
+	"context"
+	"syscall/js"
+
+	"threads/examples"
+)
+
+func main() {
+	js.Global().Set("goExamples", js.ValueOf(map[string]any{
+		"list": js.FuncOf(list),
+		"run":  js.FuncOf(run),
+	}))
+	select {} // Keep the callbacks alive
+}
+
+func list(this js.Value, args []js.Value) any {
+	var out []any
+	for _, ex := range examples.List() {
+		var tags []any
+		for _, tag := range examples.Tags(ex.Name) {
+			tags = append(tags, tag)
+		}
+		out = append(out, map[string]any{
+			"name":     ex.Name,
+			"title":    ex.Title,
+			"category": ex.Category,
+			"number":   ex.Number,
+			"tags":     tags,
+		})
+	}
+	return out
+}
+
+func run(this js.Value, args []js.Value) any {
+	if len(args) < 3 {
+		return js.Global().Get("Error").New("usage: goExamples.run(name, onOutput, onDone[, options])")
+	}
+	name, onOutput, onDone := args[0].String(), args[1], args[2]
+
+	var opts examples.Options
+	if len(args) > 3 && args[3].Type() == js.TypeObject {
+		opts.Deterministic = args[3].Get("deterministic").Truthy()
+	}
+
+	// A JavaScript callback must not block, so the example runs in its own
+	// goroutine and reports back through the callbacks
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer cancel()
+		err := examples.Run(ctx, name, jsWriter{onOutput}, opts)
+		if err != nil {
+			onDone.Invoke(err.Error())
+			return
+		}
+		onDone.Invoke(js.Null())
+	}()
+
+	// The cancel function is never released, so calling it late is harmless
+	return js.FuncOf(func(js.Value, []js.Value) any {
+		cancel()
+		return nil
+	})
+}
+
+// jsWriter passes an example's output to a JavaScript callback
+type jsWriter struct {
+	fn js.Value
+}
+
+func (w jsWriter) Write(p []byte) (int, error) {
+	w.fn.Invoke(string(p))
+	return len(p), nil
+}